@@ -0,0 +1,130 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// cacheablePaths are the GET paths whose responses WithResponseCache caches. /version isn't
+// cached, as it is how callers check that Autogen is still up.
+var cacheablePaths = []string{"/models"}
+
+// WithResponseCache caches the successful responses of GET requests to endpoints that don't
+// change while a process runs, like the /models list of supported models, for ttl. Responses
+// are keyed by URL, and aren't cached if the server sends Cache-Control: no-store.
+func WithResponseCache(ttl time.Duration) Option {
+	return func(c *client) {
+		c.cache = &responseCache{
+			ttl:     ttl,
+			now:     time.Now,
+			entries: map[string]cachedResponse{},
+		}
+	}
+}
+
+type responseCache struct {
+	ttl time.Duration
+	now func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]cachedResponse
+}
+
+type cachedResponse struct {
+	status    string
+	header    http.Header
+	body      []byte
+	expiresAt time.Time
+}
+
+// isCacheable reports whether the response of a request may be cached
+func isCacheable(method, path string) bool {
+	if method != http.MethodGet {
+		return false
+	}
+	path, _, _ = strings.Cut(path, "?")
+	for _, cacheable := range cacheablePaths {
+		if path == cacheable {
+			return true
+		}
+	}
+	return false
+}
+
+// doCachedRequest is doRequestWithHeaders for GET requests with a cacheable path
+func (c *client) doCachedRequest(ctx context.Context, path string, header http.Header, result interface{}) error {
+	key := c.BaseURL + path
+	if resp, ok := c.cache.get(key); ok {
+		return decodeResponse(resp, nil, result)
+	}
+
+	resp, err := c.startRequestWithHeaders(ctx, http.MethodGet, path, nil, header)
+	if err == nil {
+		if err := c.cache.store(key, resp); err != nil {
+			return fmt.Errorf("error reading response: %w", err)
+		}
+	}
+	return decodeResponse(resp, err, result)
+}
+
+// get returns a copy of the cached response for url, if it hasn't expired
+func (rc *responseCache) get(url string) (*http.Response, bool) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	entry, ok := rc.entries[url]
+	if !ok {
+		return nil, false
+	}
+	if !rc.now().Before(entry.expiresAt) {
+		delete(rc.entries, url)
+		return nil, false
+	}
+	return &http.Response{
+		Status:     entry.status,
+		StatusCode: http.StatusOK,
+		Header:     entry.header.Clone(),
+		Body:       io.NopCloser(bytes.NewReader(entry.body)),
+	}, true
+}
+
+// store caches a successful response for url unless the server opted it out. It reads the
+// body, and replaces it so that the response can still be decoded.
+func (rc *responseCache) store(url string, resp *http.Response) error {
+	if resp.StatusCode != http.StatusOK || hasNoStore(resp.Header) {
+		return nil
+	}
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	rc.entries[url] = cachedResponse{
+		status:    resp.Status,
+		header:    resp.Header.Clone(),
+		body:      body,
+		expiresAt: rc.now().Add(rc.ttl),
+	}
+	return nil
+}
+
+func hasNoStore(header http.Header) bool {
+	for _, value := range header.Values("Cache-Control") {
+		for _, directive := range strings.Split(value, ",") {
+			if strings.EqualFold(strings.TrimSpace(directive), "no-store") {
+				return true
+			}
+		}
+	}
+	return false
+}
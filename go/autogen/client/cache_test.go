@@ -0,0 +1,99 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResponseCache(t *testing.T) {
+	var requests atomic.Int32
+	noStore := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		if noStore {
+			w.Header().Set("Cache-Control", "private, no-store")
+		}
+		switch r.URL.Path {
+		case "/models":
+			_, _ = w.Write([]byte(`{"status":true,"data":{"openai":[{"name":"gpt-4o"}]}}`))
+		default:
+			_, _ = w.Write([]byte(`{"status":true,"data":[]}`))
+		}
+	}))
+	defer server.Close()
+
+	now := time.Now()
+	newClient := func() *client {
+		c := New(server.URL, WithResponseCache(time.Minute)).(*client)
+		c.cache.now = func() time.Time { return now }
+		return c
+	}
+	ctx := context.Background()
+
+	t.Run("cached within the ttl and refreshed after", func(t *testing.T) {
+		requests.Store(0)
+		c := newClient()
+
+		for i := 0; i < 3; i++ {
+			models, err := c.ListSupportedModels()
+			require.NoError(t, err)
+			assert.Len(t, (*models)["openai"], 1)
+		}
+		assert.Equal(t, int32(1), requests.Load())
+
+		now = now.Add(time.Minute)
+		_, err := c.ListSupportedModels()
+		require.NoError(t, err)
+		assert.Equal(t, int32(2), requests.Load())
+	})
+
+	t.Run("only caches whitelisted paths", func(t *testing.T) {
+		requests.Store(0)
+		c := newClient()
+
+		for i := 0; i < 2; i++ {
+			_, err := c.ListSessions("alice")
+			require.NoError(t, err)
+		}
+		assert.Equal(t, int32(2), requests.Load())
+	})
+
+	t.Run("doesn't cache the version", func(t *testing.T) {
+		requests.Store(0)
+		c := newClient()
+
+		for i := 0; i < 2; i++ {
+			_, _ = c.GetVersion(ctx)
+		}
+		assert.Equal(t, int32(2), requests.Load(), "the version is how a dead backend is detected")
+	})
+
+	t.Run("respects no-store", func(t *testing.T) {
+		requests.Store(0)
+		noStore = true
+		defer func() { noStore = false }()
+		c := newClient()
+
+		for i := 0; i < 2; i++ {
+			_, err := c.ListSupportedModels()
+			require.NoError(t, err)
+		}
+		assert.Equal(t, int32(2), requests.Load())
+	})
+}
+
+func TestIsCacheable(t *testing.T) {
+	assert.True(t, isCacheable(http.MethodGet, "/models"))
+	assert.True(t, isCacheable(http.MethodGet, "/models?user_id=alice"))
+	assert.False(t, isCacheable(http.MethodPost, "/models"))
+	assert.False(t, isCacheable(http.MethodGet, "/version"))
+	assert.False(t, isCacheable(http.MethodGet, "/models/gpt-4o"))
+	assert.False(t, isCacheable(http.MethodGet, "/sessions/"))
+}
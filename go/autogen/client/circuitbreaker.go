@@ -0,0 +1,108 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen is returned by invoke requests while the circuit breaker is open
+var ErrCircuitOpen = errors.New("circuit breaker is open: the backend has been failing, try again later")
+
+// WithCircuitBreaker makes invoke requests fail fast with ErrCircuitOpen for cooldown after
+// failureThreshold consecutive failures within window. After the cooldown a single request
+// is let through to probe the backend; its success closes the breaker and its failure opens
+// it again. Transport errors and 5xx responses count as failures. Other requests, such as
+// health and version checks, are not affected.
+func WithCircuitBreaker(failureThreshold int, window, cooldown time.Duration) Option {
+	return func(c *client) {
+		c.breaker = &circuitBreaker{
+			failureThreshold: failureThreshold,
+			window:           window,
+			cooldown:         cooldown,
+			now:              time.Now,
+		}
+	}
+}
+
+type circuitBreaker struct {
+	failureThreshold int
+	window           time.Duration
+	cooldown         time.Duration
+	now              func() time.Time
+
+	mu           sync.Mutex
+	failures     int
+	firstFailure time.Time
+	openedAt     time.Time
+	open         bool
+	probing      bool
+}
+
+// allow returns ErrCircuitOpen if the request should not be sent
+func (b *circuitBreaker) allow() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if !b.open {
+		return nil
+	}
+	if b.probing || b.now().Sub(b.openedAt) < b.cooldown {
+		return ErrCircuitOpen
+	}
+	b.probing = true
+	return nil
+}
+
+// record updates the breaker with the outcome of a request let through by allow
+func (b *circuitBreaker) record(ctx context.Context, resp *http.Response, err error) {
+	// Requests cancelled by the caller say nothing about the backend
+	if err != nil && ctx.Err() != nil {
+		b.mu.Lock()
+		b.probing = false
+		b.mu.Unlock()
+		return
+	}
+	failed := err != nil || resp.StatusCode >= http.StatusInternalServerError
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := b.now()
+	if !failed {
+		b.failures = 0
+		b.open = false
+		b.probing = false
+		return
+	}
+
+	if b.probing {
+		b.probing = false
+		b.openedAt = now
+		return
+	}
+	if b.failures == 0 || now.Sub(b.firstFailure) > b.window {
+		b.failures = 0
+		b.firstFailure = now
+	}
+	b.failures++
+	if b.failures >= b.failureThreshold {
+		b.open = true
+		b.openedAt = now
+	}
+}
+
+// startInvokeRequest starts an invoke request through the circuit breaker, if one is set
+func (c *client) startInvokeRequest(ctx context.Context, method, path string, body interface{}, header http.Header) (*http.Response, error) {
+	if c.breaker == nil {
+		return c.startRequestWithHeaders(ctx, method, path, body, header)
+	}
+	if err := c.breaker.allow(); err != nil {
+		return nil, err
+	}
+	resp, err := c.startRequestWithHeaders(ctx, method, path, body, header)
+	c.breaker.record(ctx, resp, err)
+	return resp, err
+}
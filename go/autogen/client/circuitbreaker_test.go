@@ -0,0 +1,105 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCircuitBreaker(t *testing.T) {
+	var mu sync.Mutex
+	healthy := false
+	requests := 0
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		requests++
+		if r.URL.Path == "/version" {
+			_, _ = w.Write([]byte(`{"status":true,"data":{"version":"1.0.0"}}`))
+			return
+		}
+		if !healthy {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte(`{"status":true,"data":{}}`))
+	}))
+	defer server.Close()
+
+	setHealthy := func(value bool) {
+		mu.Lock()
+		defer mu.Unlock()
+		healthy = value
+	}
+	requestCount := func() int {
+		mu.Lock()
+		defer mu.Unlock()
+		return requests
+	}
+
+	now := time.Now()
+	c := New(server.URL, WithCircuitBreaker(2, time.Minute, 30*time.Second)).(*client)
+	c.breaker.now = func() time.Time { return now }
+
+	ctx := context.Background()
+	invoke := func() error {
+		_, err := c.InvokeTask(ctx, &InvokeTaskRequest{Task: "hello"})
+		return err
+	}
+
+	// Two consecutive failures open the breaker
+	require.Error(t, invoke())
+	require.Error(t, invoke())
+	assert.Equal(t, 2, requestCount())
+
+	err := invoke()
+	assert.True(t, errors.Is(err, ErrCircuitOpen))
+	_, err = c.InvokeSessionStream(ctx, 1, "alice", &InvokeRequest{Task: "hello"})
+	assert.True(t, errors.Is(err, ErrCircuitOpen))
+	assert.Equal(t, 2, requestCount(), "no request is sent while open")
+
+	// Version checks are exempt
+	_, err = c.GetVersion(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 3, requestCount())
+
+	// A failed probe after the cooldown opens the breaker again
+	now = now.Add(31 * time.Second)
+	require.False(t, errors.Is(invoke(), ErrCircuitOpen))
+	assert.Equal(t, 4, requestCount())
+	assert.True(t, errors.Is(invoke(), ErrCircuitOpen))
+
+	// A successful probe closes it
+	setHealthy(true)
+	now = now.Add(31 * time.Second)
+	require.NoError(t, invoke())
+	require.NoError(t, invoke())
+	assert.Equal(t, 6, requestCount())
+}
+
+func TestCircuitBreakerWindow(t *testing.T) {
+	now := time.Now()
+	breaker := &circuitBreaker{failureThreshold: 2, window: time.Minute, cooldown: time.Minute, now: func() time.Time { return now }}
+	failure := &http.Response{StatusCode: http.StatusBadGateway}
+
+	breaker.record(context.Background(), failure, nil)
+	now = now.Add(2 * time.Minute)
+	breaker.record(context.Background(), failure, nil)
+	assert.NoError(t, breaker.allow(), "failures outside the window don't add up")
+
+	breaker.record(context.Background(), failure, nil)
+	assert.ErrorIs(t, breaker.allow(), ErrCircuitOpen)
+
+	// Client errors don't count as backend failures
+	healthy := &circuitBreaker{failureThreshold: 1, window: time.Minute, cooldown: time.Minute, now: time.Now}
+	healthy.record(context.Background(), &http.Response{StatusCode: http.StatusBadRequest}, nil)
+	assert.NoError(t, healthy.allow())
+}
@@ -3,10 +3,12 @@ package client
 import (
 	"bytes"
 	"context"
+	"crypto/tls"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"strings"
 	"time"
 
@@ -16,10 +18,36 @@ import (
 type client struct {
 	BaseURL    string
 	HTTPClient *http.Client
+	// Token is sent as a bearer token on every request if set
+	Token string
+	// UserID fills in the user_id query parameter of requests that leave it empty
+	UserID string
+	// userIDMode selects how the user ID of requests is passed, see WithUserIDMode
+	userIDMode UserIDMode
+	// MaxRetries is the number of times idempotent requests are retried on transport errors
+	// and 502, 503 and 504 responses
+	MaxRetries   int
+	RetryBackoff time.Duration
+
+	// Settings applied to a copy of HTTPClient once all options have been applied
+	timeout            time.Duration
+	proxyURL           *url.URL
+	tlsConfig          *tls.Config
+	insecureSkipVerify *bool
+
+	// breaker guards invoke requests if set by WithCircuitBreaker
+	breaker *circuitBreaker
+	// tokens replaces Token if set by WithTokenSource
+	tokens *tokenSource
+	// cache holds the responses of cacheable GET requests if set by WithResponseCache
+	cache *responseCache
+	// maxDuration sends X-Max-Duration on invocations if set by WithMaxDurationHeader
+	maxDuration bool
 }
 
 type Client interface {
 	CreateFeedback(feedback *FeedbackSubmission) error
+	CreateFeedbackBatch(ctx context.Context, feedback []*FeedbackSubmission) (*FeedbackBatchResult, error)
 	CreateRun(req *CreateRunRequest) (*CreateRunResult, error)
 	CreateSession(session *CreateSession) (*Session, error)
 	CreateTeam(team *Team) error
@@ -28,54 +56,67 @@ type Client interface {
 	DeleteSession(sessionID int, userID string) error
 	DeleteTeam(teamID int, userID string) error
 	DeleteToolServer(serverID *int, userID string) error
+	ExportRun(ctx context.Context, sessionID int, runID int, userID string, format ExportFormat) ([]byte, error)
+	GetAgent(ctx context.Context, ref string, userID string) (*Team, error)
 	GetRun(runID int) (*Run, error)
 	GetRunMessages(runID uuid.UUID) ([]*RunMessage, error)
 	GetSession(sessionLabel string, userID string) (*Session, error)
 	GetSessionById(sessionID int, userID string) (*Session, error)
+	GetSessionStats(ctx context.Context, sessionID int, userID string) (*SessionStats, error)
+	// Deprecated: use GetAgent
 	GetTeam(teamLabel string, userID string) (*Team, error)
 	GetTeamByID(teamID int, userID string) (*Team, error)
 	GetTool(provider string, userID string) (*Tool, error)
 	GetToolServer(serverID int, userID string) (*ToolServer, error)
 	GetToolServerByLabel(toolServerLabel string, userID string) (*ToolServer, error)
 	GetVersion(ctx context.Context) (string, error)
-	InvokeSession(sessionID int, userID string, request *InvokeRequest) (*TeamResult, error)
-	InvokeSessionStream(sessionID int, userID string, request *InvokeRequest) (<-chan *SseEvent, error)
-	InvokeTask(req *InvokeTaskRequest) (*InvokeTaskResult, error)
-	InvokeTaskStream(req *InvokeTaskRequest) (<-chan *SseEvent, error)
+	GetVersionInfo(ctx context.Context) (*VersionResponse, error)
+	InvokeSession(ctx context.Context, sessionID int, userID string, request *InvokeRequest) (*TeamResult, error)
+	InvokeSessionStream(ctx context.Context, sessionID int, userID string, request *InvokeRequest) (<-chan *SseEvent, error)
+	InvokeTask(ctx context.Context, req *InvokeTaskRequest) (*InvokeTaskResult, error)
+	InvokeTaskStream(ctx context.Context, req *InvokeTaskRequest) (<-chan *SseEvent, error)
 	ListFeedback(userID string) ([]*FeedbackSubmission, error)
 	ListRuns(userID string) ([]*Run, error)
+	ListRunsByStatus(status string) ([]*Run, error)
 	ListSessionRuns(sessionID int, userID string) ([]*Run, error)
 	ListSessions(userID string) ([]*Session, error)
+	ListSessionsByLastActivity(userID string) ([]*Session, error)
 	ListSupportedModels() (*ProviderModels, error)
 	ListTeams(userID string) ([]*Team, error)
 	ListToolServers(userID string) ([]*ToolServer, error)
 	ListTools(userID string) ([]*Tool, error)
 	ListToolsForServer(serverID *int, userID string) ([]*Tool, error)
+	PatchSession(sessionID int, userID string, patch []byte) (*Session, error)
 	RefreshToolServer(serverID int, userID string) error
 	RefreshTools(serverID *int, userID string) error
+	RerunSessionRun(ctx context.Context, sessionID int, runID int, userID string, request *RerunRequest) (*Run, error)
+	ToolServerHealth(ctx context.Context, userID string, opts ToolServerHealthOptions) ([]*ToolServerHealth, error)
+	UpdateRunStatus(runID int, status string, errorMessage string) error
 	UpdateSession(sessionID int, userID string, session *Session) (*Session, error)
 	UpdateToolServer(server *ToolServer, userID string) error
 	Validate(req *ValidationRequest) (*ValidationResponse, error)
 }
 
-func New(baseURL string) Client {
+func New(baseURL string, opts ...Option) Client {
 	// Ensure baseURL doesn't end with a slash
 	baseURL = strings.TrimRight(baseURL, "/")
 
-	return &client{
+	c := &client{
 		BaseURL: baseURL,
 		HTTPClient: &http.Client{
 			Timeout: time.Minute * 30,
 		},
+		RetryBackoff: defaultRetryBackoff,
+	}
+	for _, opt := range opts {
+		opt(c)
 	}
+	c.applyHTTPOptions()
+	return c
 }
 
 func (c *client) GetVersion(ctx context.Context) (string, error) {
-	var result struct {
-		Version string `json:"version"`
-	}
-
-	err := c.doRequest(context.Background(), "GET", "/version", nil, &result)
+	result, err := c.GetVersionInfo(ctx)
 	if err != nil {
 		return "", err
 	}
@@ -83,14 +124,30 @@ func (c *client) GetVersion(ctx context.Context) (string, error) {
 	return result.Version, nil
 }
 
+// GetVersionInfo returns the version of the server, and the optional features it has
+// enabled if it's a kagent controller
+func (c *client) GetVersionInfo(ctx context.Context) (*VersionResponse, error) {
+	var result VersionResponse
+	err := c.doRequest(ctx, "GET", "/version", nil, &result)
+	if err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
 func (c *client) startRequest(ctx context.Context, method, path string, body interface{}) (*http.Response, error) {
-	var bodyReader *bytes.Reader
+	return c.startRequestWithHeaders(ctx, method, path, body, nil)
+}
+
+func (c *client) startRequestWithHeaders(ctx context.Context, method, path string, body interface{}, header http.Header) (*http.Response, error) {
+	var bodyBytes []byte
 	if body != nil {
-		bodyBytes, err := json.Marshal(body)
+		var err error
+		bodyBytes, err = json.Marshal(body)
 		if err != nil {
 			return nil, fmt.Errorf("error marshaling request body: %w", err)
 		}
-		bodyReader = bytes.NewReader(bodyBytes)
 	}
 
 	// Ensure path starts with a slash
@@ -98,31 +155,104 @@ func (c *client) startRequest(ctx context.Context, method, path string, body int
 		path = "/" + path
 	}
 
-	url := c.BaseURL + path
+	url, headerUserID := c.withUserID(c.BaseURL + path)
 
-	var req *http.Request
-	var err error
-	if bodyReader != nil {
-		req, err = http.NewRequestWithContext(ctx, method, url, bodyReader)
-	} else {
-		req, err = http.NewRequestWithContext(ctx, method, url, nil)
+	retries := 0
+	if isIdempotent(method) {
+		retries = c.MaxRetries
 	}
-	if err != nil {
-		return nil, fmt.Errorf("error creating request: %w", err)
+
+	token := c.Token
+	if c.tokens != nil {
+		var err error
+		if token, err = c.tokens.get(ctx); err != nil {
+			return nil, fmt.Errorf("error getting token: %w", err)
+		}
 	}
+	refreshedToken := false
+
+	for attempt := 0; ; attempt++ {
+		var req *http.Request
+		var err error
+		if bodyBytes != nil {
+			req, err = http.NewRequestWithContext(ctx, method, url, bytes.NewReader(bodyBytes))
+		} else {
+			req, err = http.NewRequestWithContext(ctx, method, url, nil)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error creating request: %w", err)
+		}
 
-	req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Content-Type", "application/json")
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		for key, values := range header {
+			for _, value := range values {
+				req.Header.Add(key, value)
+			}
+		}
+		if headerUserID != "" {
+			req.Header.Set(UserIDHeader, headerUserID)
+		}
+
+		resp, err := c.HTTPClient.Do(req)
+		if err == nil && resp.StatusCode == http.StatusUnauthorized && c.tokens != nil {
+			resp.Body.Close()
+			if refreshedToken {
+				return nil, ErrUnauthorized
+			}
+			if token, err = c.tokens.refresh(ctx, token); err != nil {
+				return nil, fmt.Errorf("error refreshing token: %w", err)
+			}
+			refreshedToken = true
+			// The retry with the new token doesn't use up one of the retries
+			attempt--
+			continue
+		}
+		if attempt >= retries || !isRetryable(resp, err) || ctx.Err() != nil {
+			return resp, err
+		}
+		if resp != nil {
+			resp.Body.Close()
+		}
 
-	return c.HTTPClient.Do(req)
+		select {
+		case <-time.After(time.Duration(attempt+1) * c.RetryBackoff):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
 }
 
 func (c *client) doRequest(ctx context.Context, method, path string, body interface{}, result interface{}) error {
-	resp, err := c.startRequest(ctx, method, path, body)
+	return c.doRequestWithHeaders(ctx, method, path, body, nil, result)
+}
+
+func (c *client) doRequestWithHeaders(ctx context.Context, method, path string, body interface{}, header http.Header, result interface{}) error {
+	if c.cache != nil && body == nil && isCacheable(method, path) {
+		return c.doCachedRequest(ctx, path, header, result)
+	}
+	resp, err := c.startRequestWithHeaders(ctx, method, path, body, header)
+	return decodeResponse(resp, err, result)
+}
+
+func (c *client) doInvokeRequest(ctx context.Context, method, path string, body interface{}, header http.Header, result interface{}) error {
+	resp, err := c.startInvokeRequest(ctx, method, path, body, header)
+	return decodeResponse(resp, err, result)
+}
+
+// decodeResponse decodes the response of a request started with startRequest into result
+func decodeResponse(resp *http.Response, err error, result interface{}) error {
 	if err != nil {
 		return fmt.Errorf("error making request: %w", err)
 	}
 	defer resp.Body.Close()
 
+	if resp.StatusCode == http.StatusNotFound {
+		// Autogen also responds 404 for objects of other users
+		return fmt.Errorf("request failed with status: %s: %w", resp.Status, NotFoundError)
+	}
 	if resp.StatusCode >= 400 {
 		return fmt.Errorf("request failed with status: %s", resp.Status)
 	}
@@ -0,0 +1,52 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetVersionInfo(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     string
+		expected *VersionResponse
+	}{
+		{
+			name:     "autogen",
+			body:     `{"status":true,"data":{"version":"0.4.0"}}`,
+			expected: &VersionResponse{Version: "0.4.0"},
+		},
+		{
+			name: "kagent controller",
+			body: `{"version":"0.5.0","git_commit":"abc123","features":{"strict_json":true,"quotas":false}}`,
+			expected: &VersionResponse{
+				Version:   "0.5.0",
+				GitCommit: "abc123",
+				Features:  map[string]bool{"strict_json": true, "quotas": false},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "/version", r.URL.Path)
+				_, _ = w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			c := New(server.URL)
+			version, err := c.GetVersionInfo(context.Background())
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, version)
+
+			versionString, err := c.GetVersion(context.Background())
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected.Version, versionString)
+		})
+	}
+}
@@ -0,0 +1,154 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// ExportFormat is a format runs can be exported in
+type ExportFormat string
+
+const (
+	ExportFormatMarkdown ExportFormat = "md"
+	ExportFormatJSON     ExportFormat = "json"
+)
+
+// RunExport is a run exported in the JSON format
+type RunExport struct {
+	SessionID int    `json:"session_id"`
+	RunID     int    `json:"run_id"`
+	Status    string `json:"status"`
+	CreatedAt string `json:"created_at"`
+	Task      string `json:"task"`
+	// Messages are the events of the run, as stored by Autogen
+	Messages []map[string]interface{} `json:"messages"`
+	// FinalAnswer is the content of the last text message of an agent
+	FinalAnswer string `json:"final_answer,omitempty"`
+}
+
+// ExportRun returns the messages of the run rendered in the format. The run is only found if
+// the session belongs to userID.
+func (c *client) ExportRun(ctx context.Context, sessionID, runID int, userID string, format ExportFormat) ([]byte, error) {
+	run, err := findSessionRun(c, sessionID, runID, userID)
+	if err != nil {
+		return nil, err
+	}
+	return FormatRunExport(sessionID, run, format)
+}
+
+// FormatRunExport renders the messages of the run in the format. In Markdown, tool calls and
+// their results are rendered as fenced blocks.
+func FormatRunExport(sessionID int, run *Run, format ExportFormat) ([]byte, error) {
+	export := &RunExport{
+		SessionID: sessionID,
+		RunID:     run.ID,
+		Status:    run.Status,
+		CreatedAt: run.CreatedAt,
+		Task:      taskText(run.Task),
+		Messages:  make([]map[string]interface{}, 0, len(run.Messages)),
+	}
+	for _, message := range run.Messages {
+		export.Messages = append(export.Messages, message.Config)
+		if source, _ := message.Config["source"].(string); source != "user" && message.Config["type"] == TextMessageLabel {
+			export.FinalAnswer, _ = message.Config["content"].(string)
+		}
+	}
+
+	switch format {
+	case ExportFormatJSON:
+		return json.MarshalIndent(export, "", "  ")
+	case ExportFormatMarkdown:
+		return formatRunMarkdown(export), nil
+	}
+	return nil, fmt.Errorf("unknown export format %q: must be %s or %s", format, ExportFormatMarkdown, ExportFormatJSON)
+}
+
+func formatRunMarkdown(export *RunExport) []byte {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "# Run %d\n\n", export.RunID)
+	fmt.Fprintf(&buf, "- **Session:** %d\n- **Status:** %s\n- **Created:** %s\n\n", export.SessionID, export.Status, export.CreatedAt)
+	fmt.Fprintf(&buf, "## Task\n\n%s\n\n## Messages\n", export.Task)
+
+	for _, message := range export.Messages {
+		data, err := json.Marshal(message)
+		if err != nil {
+			continue
+		}
+		source, _ := message["source"].(string)
+		event, err := ParseEvent(data)
+		if err != nil {
+			// Other events, like handoffs, are rendered with their content as is
+			fmt.Fprintf(&buf, "\n### %s (%v)\n\n", source, message["type"])
+			if content, ok := message["content"].(string); ok {
+				fmt.Fprintf(&buf, "%s\n", content)
+			} else {
+				writeFenced(&buf, "json", prettyJSON(message["content"]))
+			}
+			continue
+		}
+
+		switch event := event.(type) {
+		case *TextMessage:
+			fmt.Fprintf(&buf, "\n### %s\n\n%s\n", source, event.Content)
+		case *ToolCallRequestEvent:
+			for _, call := range event.Content {
+				fmt.Fprintf(&buf, "\n### %s called %s\n\n", source, call.Name)
+				writeFenced(&buf, "json", prettyJSONString(call.Arguments))
+			}
+		case *ToolCallExecutionEvent:
+			for _, result := range event.Content {
+				fmt.Fprintf(&buf, "\n### Result of %s\n\n", result.CallID)
+				writeFenced(&buf, "", result.Content)
+			}
+		case *ToolCallSummaryMessage:
+			fmt.Fprintf(&buf, "\n### %s (tool call summary)\n\n", source)
+			content, _ := message["content"].(string)
+			writeFenced(&buf, "", content)
+		case *MemoryQueryEvent:
+			fmt.Fprintf(&buf, "\n### %s (memory query)\n\n", source)
+			writeFenced(&buf, "json", prettyJSON(event.Content))
+		}
+	}
+
+	if export.FinalAnswer != "" {
+		fmt.Fprintf(&buf, "\n## Final answer\n\n%s\n", export.FinalAnswer)
+	}
+	return buf.Bytes()
+}
+
+// writeFenced writes content in a fenced code block, with a fence longer than any run of
+// backticks in content so it can't end the block early
+func writeFenced(buf *bytes.Buffer, lang, content string) {
+	fence := "```"
+	for strings.Contains(content, fence) {
+		fence += "`"
+	}
+	fmt.Fprintf(buf, "%s%s\n%s\n%s\n", fence, lang, strings.TrimRight(content, "\n"), fence)
+}
+
+func taskText(task Task) string {
+	if content, ok := task.Content.(string); ok {
+		return content
+	}
+	return prettyJSON(task.Content)
+}
+
+func prettyJSON(v interface{}) string {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(data)
+}
+
+// prettyJSONString indents s if it's JSON, like the arguments of tool calls
+func prettyJSONString(s string) string {
+	var buf bytes.Buffer
+	if err := json.Indent(&buf, []byte(s), "", "  "); err != nil {
+		return s
+	}
+	return buf.String()
+}
@@ -0,0 +1,38 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExportRun(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/sessions/3/runs/", r.URL.Path)
+		assert.Equal(t, "alice", r.URL.Query().Get("user_id"))
+		_, _ = w.Write([]byte(`{"status":true,"data":{"runs":[{"id":7,"status":"complete","task":{"source":"user","content":"show the code"},"messages":[
+			{"config":{"type":"TextMessage","source":"user","content":"show the code"}},
+			{"config":{"type":"ToolCallExecutionEvent","source":"agent","content":[{"call_id":"call_1","content":"uses ` + "```" + ` fences"}]}},
+			{"config":{"type":"HandoffMessage","source":"planner","content":"over to you","target":"agent"}}
+		]}]}}`))
+	}))
+	defer server.Close()
+	c := New(server.URL)
+
+	export, err := c.ExportRun(context.Background(), 3, 7, "alice", ExportFormatMarkdown)
+	require.NoError(t, err)
+	assert.Contains(t, string(export), "# Run 7")
+	assert.Contains(t, string(export), "````\nuses ``` fences\n````", "the fence is longer than the backticks in the content")
+	assert.Contains(t, string(export), "### planner (HandoffMessage)\n\nover to you")
+	assert.NotContains(t, string(export), "## Final answer", "only agents give the final answer")
+
+	_, err = c.ExportRun(context.Background(), 3, 8, "alice", ExportFormatMarkdown)
+	assert.ErrorIs(t, err, NotFoundError)
+
+	_, err = c.ExportRun(context.Background(), 3, 7, "alice", "pdf")
+	assert.Error(t, err)
+}
@@ -4,15 +4,32 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"slices"
+	"sort"
 	"sync"
+	"time"
 
 	"github.com/google/uuid"
 	autogen_client "github.com/kagent-dev/kagent/go/autogen/client"
 )
 
+// InMemoryAutogenClient must keep implementing the whole client interface as it grows
+var _ autogen_client.Client = (*InMemoryAutogenClient)(nil)
+
 type InMemoryAutogenClient struct {
 	mu sync.RWMutex
 
+	// InvokeDelay makes InvokeSession and InvokeTask take this long, or until their context is done
+	InvokeDelay time.Duration
+	// VersionError makes GetVersion fail, as if the backend were unreachable
+	VersionError error
+	// UnsupportedProviders makes Validate reject components with these providers, as if Autogen
+	// couldn't import them
+	UnsupportedProviders []string
+
+	// invokeResponses are the scripted invoke results by task, set with SetInvokeResponse
+	invokeResponses map[string]*InvokeResponse
+
 	// Storage maps
 	sessions           map[int]*autogen_client.Session
 	sessionsByLabel    map[string]*autogen_client.Session
@@ -34,6 +51,31 @@ type InMemoryAutogenClient struct {
 	nextToolServerID int
 }
 
+// InvokeResponse scripts how the invoke methods respond to a task
+type InvokeResponse struct {
+	// TaskResult is returned by InvokeSession and InvokeTask
+	TaskResult autogen_client.TaskResult
+	// Events are sent by the streaming invoke methods. If nil, each message of TaskResult
+	// is sent as a message event instead.
+	Events []*autogen_client.SseEvent
+	// EventDelay is waited before each event is sent, or until the stream's context is done
+	EventDelay time.Duration
+	// Err is returned by all invoke methods instead of a result
+	Err error
+}
+
+// events returns the events the streaming invoke methods send
+func (r *InvokeResponse) events() []*autogen_client.SseEvent {
+	if r.Events != nil {
+		return r.Events
+	}
+	events := make([]*autogen_client.SseEvent, 0, len(r.TaskResult.Messages))
+	for _, message := range r.TaskResult.Messages {
+		events = append(events, &autogen_client.SseEvent{Event: "message", Data: message})
+	}
+	return events
+}
+
 func NewInMemoryAutogenClient() *InMemoryAutogenClient {
 	return &InMemoryAutogenClient{
 		sessions:           make(map[int]*autogen_client.Session),
@@ -68,6 +110,7 @@ func (m *InMemoryAutogenClient) CreateSession(req *autogen_client.CreateSession)
 		ID:     m.nextSessionID,
 		Name:   req.Name,
 		UserID: req.UserID,
+		TeamID: req.TeamID,
 	}
 
 	m.sessions[session.ID] = session
@@ -87,6 +130,7 @@ func (m *InMemoryAutogenClient) CreateRun(req *autogen_client.CreateRunRequest)
 	run := &autogen_client.Run{
 		ID:        m.nextRunID,
 		SessionID: req.SessionID,
+		UserID:    req.UserID,
 	}
 
 	m.runs[run.ID] = run
@@ -109,7 +153,18 @@ func (m *InMemoryAutogenClient) GetTeamByID(teamID int, userID string) (*autogen
 	return team, nil
 }
 
-func (m *InMemoryAutogenClient) InvokeTask(req *autogen_client.InvokeTaskRequest) (*autogen_client.InvokeTaskResult, error) {
+func (m *InMemoryAutogenClient) InvokeTask(ctx context.Context, req *autogen_client.InvokeTaskRequest) (*autogen_client.InvokeTaskResult, error) {
+	if err := m.waitInvokeDelay(ctx); err != nil {
+		return nil, err
+	}
+
+	if response := m.invokeResponse(req.Task); response != nil {
+		if response.Err != nil {
+			return nil, response.Err
+		}
+		return &autogen_client.InvokeTaskResult{TaskResult: response.TaskResult}, nil
+	}
+
 	// For in-memory implementation, return a basic result with properly formatted TextMessage
 	return &autogen_client.InvokeTaskResult{
 		TaskResult: autogen_client.TaskResult{
@@ -125,21 +180,37 @@ func (m *InMemoryAutogenClient) GetSession(sessionLabel string, userID string) (
 	defer m.mu.RUnlock()
 
 	session, exists := m.sessionsByLabel[sessionLabel]
-	if !exists {
+	if !exists || !ownedBy(session.UserID, userID) {
 		return nil, autogen_client.NotFoundError
 	}
 	return session, nil
 }
 
-func (m *InMemoryAutogenClient) InvokeSession(sessionID int, userID string, request *autogen_client.InvokeRequest) (*autogen_client.TeamResult, error) {
-	m.mu.RLock()
-	defer m.mu.RUnlock()
+func (m *InMemoryAutogenClient) InvokeSession(ctx context.Context, sessionID int, userID string, request *autogen_client.InvokeRequest) (*autogen_client.TeamResult, error) {
+	// Like Autogen, the invocation runs in a new run of the session, which is left active if
+	// the invocation is cancelled
+	run, err := m.createInvokeRun(sessionID, userID, request.Task)
+	if err != nil {
+		return nil, err
+	}
+	if err := m.waitInvokeDelay(ctx); err != nil {
+		return nil, err
+	}
 
-	_, exists := m.sessions[sessionID]
-	if !exists {
-		return nil, fmt.Errorf("session with ID %d not found", sessionID)
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if response := m.lookupInvokeResponse(request.Task); response != nil {
+		if response.Err != nil {
+			run.Status = autogen_client.RunStatusError
+			run.ErrorMessage = response.Err.Error()
+			return nil, response.Err
+		}
+		run.Status = autogen_client.RunStatusComplete
+		return &autogen_client.TeamResult{TaskResult: response.TaskResult}, nil
 	}
 
+	run.Status = autogen_client.RunStatusComplete
 	return &autogen_client.TeamResult{
 		TaskResult: autogen_client.TaskResult{
 			Messages: []json.RawMessage{
@@ -149,6 +220,28 @@ func (m *InMemoryAutogenClient) InvokeSession(sessionID int, userID string, requ
 	}, nil
 }
 
+// createInvokeRun creates the active run of an invocation of task in the user's session
+func (m *InMemoryAutogenClient) createInvokeRun(sessionID int, userID string, task string) (*autogen_client.Run, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, err := m.userSession(sessionID, userID); err != nil {
+		return nil, err
+	}
+
+	run := &autogen_client.Run{
+		ID:        m.nextRunID,
+		SessionID: sessionID,
+		UserID:    userID,
+		Status:    autogen_client.RunStatusActive,
+		Task:      autogen_client.Task{Source: "user", Content: task},
+	}
+	m.runs[run.ID] = run
+	m.runsByUUID[uuid.New()] = run
+	m.nextRunID++
+	return run, nil
+}
+
 func (m *InMemoryAutogenClient) CreateFeedback(feedback *autogen_client.FeedbackSubmission) error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
@@ -157,6 +250,11 @@ func (m *InMemoryAutogenClient) CreateFeedback(feedback *autogen_client.Feedback
 	return nil
 }
 
+// CreateFeedbackBatch submits the feedback one by one, like the real client
+func (m *InMemoryAutogenClient) CreateFeedbackBatch(ctx context.Context, feedback []*autogen_client.FeedbackSubmission) (*autogen_client.FeedbackBatchResult, error) {
+	return autogen_client.SubmitFeedbackBatch(ctx, m, feedback)
+}
+
 func (m *InMemoryAutogenClient) CreateTeam(team *autogen_client.Team) error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
@@ -211,9 +309,9 @@ func (m *InMemoryAutogenClient) DeleteSession(sessionID int, userID string) erro
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
-	session, exists := m.sessions[sessionID]
-	if !exists {
-		return fmt.Errorf("session with ID %d not found", sessionID)
+	session, err := m.userSession(sessionID, userID)
+	if err != nil {
+		return err
 	}
 
 	delete(m.sessions, sessionID)
@@ -259,6 +357,11 @@ func (m *InMemoryAutogenClient) DeleteToolServer(serverID *int, userID string) e
 		delete(m.toolServersByLabel, toolServer.Component.Label)
 	}
 	delete(m.toolsByServer, *serverID)
+	for provider, tool := range m.tools {
+		if tool.ServerID != nil && *tool.ServerID == *serverID {
+			delete(m.tools, provider)
+		}
+	}
 
 	return nil
 }
@@ -291,12 +394,25 @@ func (m *InMemoryAutogenClient) GetSessionById(sessionID int, userID string) (*a
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 
-	session, exists := m.sessions[sessionID]
-	if !exists {
-		return nil, fmt.Errorf("session with ID %d not found", sessionID)
+	return m.userSession(sessionID, userID)
+}
+
+func (m *InMemoryAutogenClient) GetAgent(ctx context.Context, ref string, userID string) (*autogen_client.Team, error) {
+	if _, _, err := autogen_client.ParseAgentRef(ref); err != nil {
+		return nil, err
 	}
 
-	return session, nil
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	teams := make([]*autogen_client.Team, 0)
+	for _, team := range m.teams {
+		if team.UserID == "" || team.UserID == userID {
+			teams = append(teams, team)
+		}
+	}
+	sortByCreation(teams, teamKey)
+	return autogen_client.FindAgentByRef(teams, ref)
 }
 
 func (m *InMemoryAutogenClient) GetTeam(teamLabel string, userID string) (*autogen_client.Team, error) {
@@ -304,8 +420,8 @@ func (m *InMemoryAutogenClient) GetTeam(teamLabel string, userID string) (*autog
 	defer m.mu.RUnlock()
 
 	team, exists := m.teamsByLabel[teamLabel]
-	if !exists {
-		return nil, fmt.Errorf("team with label %s not found", teamLabel)
+	if !exists || (team.UserID != "" && team.UserID != userID) {
+		return nil, autogen_client.NotFoundError
 	}
 
 	return team, nil
@@ -341,55 +457,73 @@ func (m *InMemoryAutogenClient) GetToolServerByLabel(toolServerLabel string, use
 
 	toolServer, exists := m.toolServersByLabel[toolServerLabel]
 	if !exists {
-		return nil, fmt.Errorf("tool server with label %s not found", toolServerLabel)
+		return nil, fmt.Errorf("tool server with label %s: %w", toolServerLabel, autogen_client.NotFoundError)
 	}
 
 	return toolServer, nil
 }
 
-func (m *InMemoryAutogenClient) GetVersion(_ context.Context) (string, error) {
-	return "1.0.0-inmemory", nil
+func (m *InMemoryAutogenClient) GetVersion(ctx context.Context) (string, error) {
+	version, err := m.GetVersionInfo(ctx)
+	if err != nil {
+		return "", err
+	}
+	return version.Version, nil
+}
+
+func (m *InMemoryAutogenClient) GetVersionInfo(_ context.Context) (*autogen_client.VersionResponse, error) {
+	if m.VersionError != nil {
+		return nil, m.VersionError
+	}
+	return &autogen_client.VersionResponse{Version: "1.0.0-inmemory"}, nil
 }
 
-func (m *InMemoryAutogenClient) InvokeSessionStream(sessionID int, userID string, request *autogen_client.InvokeRequest) (<-chan *autogen_client.SseEvent, error) {
+func (m *InMemoryAutogenClient) InvokeSessionStream(ctx context.Context, sessionID int, userID string, request *autogen_client.InvokeRequest) (<-chan *autogen_client.SseEvent, error) {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 
-	_, exists := m.sessions[sessionID]
-	if !exists {
-		return nil, fmt.Errorf("session with ID %d not found", sessionID)
+	if _, err := m.userSession(sessionID, userID); err != nil {
+		return nil, err
 	}
 
-	ch := make(chan *autogen_client.SseEvent, 1)
-	go func() {
-		defer close(ch)
-		ch <- &autogen_client.SseEvent{
-			Event: "message",
-			Data:  []byte(fmt.Sprintf(`{"type": "TextMessage", "content": "Session stream task completed: %s", "source": "assistant"}`, request.Task)),
+	if response := m.lookupInvokeResponse(request.Task); response != nil {
+		if response.Err != nil {
+			return nil, response.Err
 		}
-	}()
+		return sendEvents(ctx, response.events(), response.EventDelay), nil
+	}
 
-	return ch, nil
+	return sendEvents(ctx, []*autogen_client.SseEvent{{
+		Event: "message",
+		Data:  []byte(fmt.Sprintf(`{"type": "TextMessage", "content": "Session stream task completed: %s", "source": "assistant"}`, request.Task)),
+	}}, 0), nil
 }
 
-func (m *InMemoryAutogenClient) InvokeTaskStream(req *autogen_client.InvokeTaskRequest) (<-chan *autogen_client.SseEvent, error) {
-	ch := make(chan *autogen_client.SseEvent, 1)
-	go func() {
-		defer close(ch)
-		ch <- &autogen_client.SseEvent{
-			Event: "message",
-			Data:  []byte(fmt.Sprintf(`{"type": "TextMessage", "content": "Task stream completed: %s", "source": "assistant"}`, req.Task)),
+func (m *InMemoryAutogenClient) InvokeTaskStream(ctx context.Context, req *autogen_client.InvokeTaskRequest) (<-chan *autogen_client.SseEvent, error) {
+	if response := m.invokeResponse(req.Task); response != nil {
+		if response.Err != nil {
+			return nil, response.Err
 		}
-	}()
+		return sendEvents(ctx, response.events(), response.EventDelay), nil
+	}
 
-	return ch, nil
+	return sendEvents(ctx, []*autogen_client.SseEvent{{
+		Event: "message",
+		Data:  []byte(fmt.Sprintf(`{"type": "TextMessage", "content": "Task stream completed: %s", "source": "assistant"}`, req.Task)),
+	}}, 0), nil
 }
 
 func (m *InMemoryAutogenClient) ListFeedback(userID string) ([]*autogen_client.FeedbackSubmission, error) {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 
-	return m.feedback, nil
+	feedback := make([]*autogen_client.FeedbackSubmission, 0, len(m.feedback))
+	for _, submission := range m.feedback {
+		if ownedBy(submission.UserID, userID) {
+			feedback = append(feedback, submission)
+		}
+	}
+	return feedback, nil
 }
 
 func (m *InMemoryAutogenClient) ListRuns(userID string) ([]*autogen_client.Run, error) {
@@ -398,16 +532,53 @@ func (m *InMemoryAutogenClient) ListRuns(userID string) ([]*autogen_client.Run,
 
 	runs := make([]*autogen_client.Run, 0, len(m.runs))
 	for _, run := range m.runs {
+		// Runs are only scoped to users through their sessions
+		if session, exists := m.sessions[run.SessionID]; !exists || !ownedBy(session.UserID, userID) {
+			continue
+		}
 		runs = append(runs, run)
 	}
 
+	sortByCreation(runs, runKey)
 	return runs, nil
 }
 
+func (m *InMemoryAutogenClient) ListRunsByStatus(status string) ([]*autogen_client.Run, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	runs := make([]*autogen_client.Run, 0, len(m.runs))
+	for _, run := range m.runs {
+		if run.Status == status {
+			runs = append(runs, run)
+		}
+	}
+
+	sortByCreation(runs, runKey)
+	return runs, nil
+}
+
+func (m *InMemoryAutogenClient) ExportRun(ctx context.Context, sessionID int, runID int, userID string, format autogen_client.ExportFormat) ([]byte, error) {
+	runs, err := m.ListSessionRuns(sessionID, userID)
+	if err != nil {
+		return nil, err
+	}
+	for _, run := range runs {
+		if run.ID == runID {
+			return autogen_client.FormatRunExport(sessionID, run, format)
+		}
+	}
+	return nil, fmt.Errorf("run %d of session %d: %w", runID, sessionID, autogen_client.NotFoundError)
+}
+
 func (m *InMemoryAutogenClient) ListSessionRuns(sessionID int, userID string) ([]*autogen_client.Run, error) {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 
+	if session, exists := m.sessions[sessionID]; exists && !ownedBy(session.UserID, userID) {
+		return nil, fmt.Errorf("session with ID %d not found: %w", sessionID, autogen_client.NotFoundError)
+	}
+
 	runs := make([]*autogen_client.Run, 0)
 	for _, run := range m.runs {
 		if run.SessionID == sessionID {
@@ -415,9 +586,18 @@ func (m *InMemoryAutogenClient) ListSessionRuns(sessionID int, userID string) ([
 		}
 	}
 
+	sortByCreation(runs, runKey)
 	return runs, nil
 }
 
+func (m *InMemoryAutogenClient) GetSessionStats(ctx context.Context, sessionID int, userID string) (*autogen_client.SessionStats, error) {
+	runs, err := m.ListSessionRuns(sessionID, userID)
+	if err != nil {
+		return nil, err
+	}
+	return autogen_client.ComputeSessionStats(sessionID, runs), nil
+}
+
 func (m *InMemoryAutogenClient) ListSessions(userID string) ([]*autogen_client.Session, error) {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
@@ -429,9 +609,23 @@ func (m *InMemoryAutogenClient) ListSessions(userID string) ([]*autogen_client.S
 		}
 	}
 
+	sortByCreation(sessions, sessionKey)
 	return sessions, nil
 }
 
+// ListSessionsByLastActivity sorts the sessions of the user like the real client
+func (m *InMemoryAutogenClient) ListSessionsByLastActivity(userID string) ([]*autogen_client.Session, error) {
+	sessions, err := m.ListSessions(userID)
+	if err != nil {
+		return nil, err
+	}
+	runs, err := m.ListRuns(userID)
+	if err != nil {
+		return nil, err
+	}
+	return autogen_client.SortSessionsByLastActivity(sessions, runs), nil
+}
+
 func (m *InMemoryAutogenClient) ListSupportedModels() (*autogen_client.ProviderModels, error) {
 	providerModels := autogen_client.ProviderModels{
 		"openai": []autogen_client.ModelInfo{
@@ -457,6 +651,7 @@ func (m *InMemoryAutogenClient) ListTeams(userID string) ([]*autogen_client.Team
 		}
 	}
 
+	sortByCreation(teams, teamKey)
 	return teams, nil
 }
 
@@ -469,6 +664,7 @@ func (m *InMemoryAutogenClient) ListToolServers(userID string) ([]*autogen_clien
 		toolServers = append(toolServers, toolServer)
 	}
 
+	sortByCreation(toolServers, toolServerKey)
 	return toolServers, nil
 }
 
@@ -481,9 +677,24 @@ func (m *InMemoryAutogenClient) ListTools(userID string) ([]*autogen_client.Tool
 		tools = append(tools, tool)
 	}
 
+	// Tools are stored by provider and may share an id, so ties are ordered by provider
+	sort.Slice(tools, func(i, j int) bool { return tools[i].Component.Provider < tools[j].Component.Provider })
+	sortByCreation(tools, toolKey)
 	return tools, nil
 }
 
+// AddToolsForServer stores tools as if they had been discovered from the given tool server
+func (m *InMemoryAutogenClient) AddToolsForServer(serverID int, tools ...*autogen_client.Tool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, tool := range tools {
+		tool.ServerID = &serverID
+		m.tools[tool.Component.Provider] = tool
+		m.toolsByServer[serverID] = append(m.toolsByServer[serverID], tool)
+	}
+}
+
 func (m *InMemoryAutogenClient) ListToolsForServer(serverID *int, userID string) ([]*autogen_client.Tool, error) {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
@@ -518,13 +729,85 @@ func (m *InMemoryAutogenClient) RefreshTools(serverID *int, userID string) error
 	return nil
 }
 
-func (m *InMemoryAutogenClient) UpdateSession(sessionID int, userID string, session *autogen_client.Session) (*autogen_client.Session, error) {
+// ToolServerHealth connects to the URLs of the stored tool servers, like the real client
+func (m *InMemoryAutogenClient) ToolServerHealth(ctx context.Context, userID string, opts autogen_client.ToolServerHealthOptions) ([]*autogen_client.ToolServerHealth, error) {
+	return autogen_client.CheckToolServerHealth(ctx, m, userID, opts)
+}
+
+func (m *InMemoryAutogenClient) RerunSessionRun(ctx context.Context, sessionID int, runID int, userID string, request *autogen_client.RerunRequest) (*autogen_client.Run, error) {
+	if err := m.waitInvokeDelay(ctx); err != nil {
+		return nil, err
+	}
+
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
-	existingSession, exists := m.sessions[sessionID]
+	if _, err := m.userSession(sessionID, userID); err != nil {
+		return nil, err
+	}
+	parent, exists := m.runs[runID]
+	if !exists || parent.SessionID != sessionID {
+		return nil, fmt.Errorf("run with ID %d not found: %w", runID, autogen_client.NotFoundError)
+	}
+
+	task := request.Task
+	if task == "" {
+		content, ok := parent.Task.Content.(string)
+		if !ok || content == "" {
+			return nil, fmt.Errorf("run %d has no text task to re-run", runID)
+		}
+		task = content
+	}
+
+	run := &autogen_client.Run{
+		ID:          m.nextRunID,
+		SessionID:   sessionID,
+		UserID:      parent.UserID,
+		Status:      autogen_client.RunStatusComplete,
+		Task:        autogen_client.Task{Source: "user", Content: task},
+		ParentRunID: &parent.ID,
+	}
+	if response := m.lookupInvokeResponse(task); response != nil {
+		if response.Err != nil {
+			run.Status = autogen_client.RunStatusError
+			run.ErrorMessage = response.Err.Error()
+		} else {
+			run.TeamResult = autogen_client.TeamResult{TaskResult: response.TaskResult}
+		}
+	}
+	m.runs[run.ID] = run
+	m.runsByUUID[uuid.New()] = run
+	m.nextRunID++
+
+	return run, nil
+}
+
+func (m *InMemoryAutogenClient) UpdateRunStatus(runID int, status string, errorMessage string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	run, exists := m.runs[runID]
 	if !exists {
-		return nil, fmt.Errorf("session with ID %d not found", sessionID)
+		return fmt.Errorf("run with ID %d not found: %w", runID, autogen_client.NotFoundError)
+	}
+	run.Status = status
+	if errorMessage != "" {
+		run.ErrorMessage = errorMessage
+	}
+	return nil
+}
+
+func (m *InMemoryAutogenClient) PatchSession(sessionID int, userID string, patch []byte) (*autogen_client.Session, error) {
+	return autogen_client.MergePatchSession(m, sessionID, userID, patch)
+}
+
+func (m *InMemoryAutogenClient) UpdateSession(sessionID int, userID string, session *autogen_client.Session) (*autogen_client.Session, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	existingSession, err := m.userSession(sessionID, userID)
+	if err != nil {
+		return nil, err
 	}
 
 	// Remove old label mapping if it exists
@@ -534,6 +817,7 @@ func (m *InMemoryAutogenClient) UpdateSession(sessionID int, userID string, sess
 
 	// Update the session
 	session.ID = sessionID
+	session.UserID = existingSession.UserID
 	m.sessions[sessionID] = session
 
 	// Add new label mapping if it exists
@@ -574,9 +858,130 @@ func (m *InMemoryAutogenClient) UpdateToolServer(server *autogen_client.ToolServ
 }
 
 func (m *InMemoryAutogenClient) Validate(req *autogen_client.ValidationRequest) (*autogen_client.ValidationResponse, error) {
+	if req.Component != nil && slices.Contains(m.UnsupportedProviders, req.Component.Provider) {
+		return &autogen_client.ValidationResponse{
+			IsValid: false,
+			Errors: []*autogen_client.ValidationError{
+				{Field: "provider", Error: fmt.Sprintf("Could not import provider %s", req.Component.Provider)},
+			},
+			Warnings: []*autogen_client.ValidationError{},
+		}, nil
+	}
 	return &autogen_client.ValidationResponse{
 		IsValid:  true,
 		Errors:   []*autogen_client.ValidationError{},
 		Warnings: []*autogen_client.ValidationError{},
 	}, nil
 }
+
+// SetInvokeResponse makes the invoke methods respond to task with response instead of the
+// canned completion message. A response for the empty task applies to tasks without their own.
+func (m *InMemoryAutogenClient) SetInvokeResponse(task string, response *InvokeResponse) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.invokeResponses == nil {
+		m.invokeResponses = make(map[string]*InvokeResponse)
+	}
+	m.invokeResponses[task] = response
+}
+
+func (m *InMemoryAutogenClient) invokeResponse(task string) *InvokeResponse {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return m.lookupInvokeResponse(task)
+}
+
+// lookupInvokeResponse returns the scripted response to task, if any. The caller must hold m.mu.
+func (m *InMemoryAutogenClient) lookupInvokeResponse(task string) *InvokeResponse {
+	if response, ok := m.invokeResponses[task]; ok {
+		return response
+	}
+	return m.invokeResponses[""]
+}
+
+// sendEvents returns a channel that is sent events, each after delay, and closed, or closed
+// early when ctx is done
+func sendEvents(ctx context.Context, events []*autogen_client.SseEvent, delay time.Duration) <-chan *autogen_client.SseEvent {
+	ch := make(chan *autogen_client.SseEvent)
+	go func() {
+		defer close(ch)
+		for _, event := range events {
+			if delay > 0 {
+				select {
+				case <-time.After(delay):
+				case <-ctx.Done():
+					return
+				}
+			}
+			select {
+			case ch <- event:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return ch
+}
+
+func (m *InMemoryAutogenClient) waitInvokeDelay(ctx context.Context) error {
+	if m.InvokeDelay == 0 {
+		return nil
+	}
+	select {
+	case <-time.After(m.InvokeDelay):
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// userSession returns the session if it belongs to userID. Like Autogen, the sessions of
+// other users are reported as not found. The caller must hold the lock.
+func (m *InMemoryAutogenClient) userSession(sessionID int, userID string) (*autogen_client.Session, error) {
+	session, exists := m.sessions[sessionID]
+	if !exists || !ownedBy(session.UserID, userID) {
+		return nil, fmt.Errorf("session with ID %d not found: %w", sessionID, autogen_client.NotFoundError)
+	}
+	return session, nil
+}
+
+// ownedBy reports whether an object with the owner is visible to userID. Objects created
+// without a user are visible to everyone.
+func ownedBy(owner, userID string) bool {
+	return owner == "" || owner == userID
+}
+
+// sortByCreation orders items by creation time and then id, so that list results don't depend
+// on the iteration order of the storage maps
+func sortByCreation[T any](items []T, key func(T) (string, int)) {
+	sort.SliceStable(items, func(i, j int) bool {
+		createdI, idI := key(items[i])
+		createdJ, idJ := key(items[j])
+		if createdI != createdJ {
+			return createdI < createdJ
+		}
+		return idI < idJ
+	})
+}
+
+func sessionKey(session *autogen_client.Session) (string, int) {
+	return session.CreatedAt, session.ID
+}
+
+func runKey(run *autogen_client.Run) (string, int) {
+	return run.CreatedAt, run.ID
+}
+
+func teamKey(team *autogen_client.Team) (string, int) {
+	return team.CreatedAt, team.Id
+}
+
+func toolServerKey(toolServer *autogen_client.ToolServer) (string, int) {
+	return toolServer.CreatedAt, toolServer.Id
+}
+
+func toolKey(tool *autogen_client.Tool) (string, int) {
+	return tool.CreatedAt, tool.Id
+}
@@ -0,0 +1,94 @@
+package fake
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	autogen_client "github.com/kagent-dev/kagent/go/autogen/client"
+)
+
+func TestListOrderIsDeterministic(t *testing.T) {
+	client := NewInMemoryAutogenClient()
+	for i := 0; i < 20; i++ {
+		session, err := client.CreateSession(&autogen_client.CreateSession{UserID: "alice"})
+		require.NoError(t, err)
+		_, err = client.CreateRun(&autogen_client.CreateRunRequest{SessionID: session.ID, UserID: "alice"})
+		require.NoError(t, err)
+	}
+
+	sessionIDs := func() []int {
+		sessions, err := client.ListSessions("alice")
+		require.NoError(t, err)
+		ids := make([]int, len(sessions))
+		for i, session := range sessions {
+			ids[i] = session.ID
+		}
+		return ids
+	}
+	runIDs := func() []int {
+		runs, err := client.ListRuns("alice")
+		require.NoError(t, err)
+		ids := make([]int, len(runs))
+		for i, run := range runs {
+			ids[i] = run.ID
+		}
+		return ids
+	}
+
+	firstSessions, firstRuns := sessionIDs(), runIDs()
+	assert.IsIncreasing(t, firstSessions)
+	assert.IsIncreasing(t, firstRuns)
+	for i := 0; i < 10; i++ {
+		assert.Equal(t, firstSessions, sessionIDs())
+		assert.Equal(t, firstRuns, runIDs())
+	}
+}
+
+func TestInvokeTaskStreamStopsOnCancel(t *testing.T) {
+	client := NewInMemoryAutogenClient()
+	client.SetInvokeResponse("slow", &InvokeResponse{
+		Events: []*autogen_client.SseEvent{
+			{Event: "message", Data: []byte("first")},
+			{Event: "message", Data: []byte("second")},
+		},
+		EventDelay: 50 * time.Millisecond,
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	ch, err := client.InvokeTaskStream(ctx, &autogen_client.InvokeTaskRequest{Task: "slow"})
+	require.NoError(t, err)
+
+	event := <-ch
+	assert.Equal(t, []byte("first"), event.Data)
+	cancel()
+
+	select {
+	case event, ok := <-ch:
+		assert.False(t, ok, "expected the channel to be closed, got %v", event)
+	case <-time.After(time.Second):
+		t.Fatal("stream was not closed after the context was cancelled")
+	}
+}
+
+func TestInvokeTaskStreamCancelledWithoutReading(t *testing.T) {
+	client := NewInMemoryAutogenClient()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	ch, err := client.InvokeTaskStream(ctx, &autogen_client.InvokeTaskRequest{Task: "hello"})
+	require.NoError(t, err)
+	cancel()
+
+	// The stream may still send the event it was blocked on, but must then close
+	require.Eventually(t, func() bool {
+		select {
+		case _, ok := <-ch:
+			return !ok
+		default:
+			return false
+		}
+	}, time.Second, 10*time.Millisecond)
+}
@@ -23,3 +23,52 @@ func (c *client) ListFeedback(userID string) ([]*FeedbackSubmission, error) {
 
 	return response, nil
 }
+
+// FeedbackBatchItem is the result of one feedback of a batch
+type FeedbackBatchItem struct {
+	// Index is the position of the feedback in the batch
+	Index   int    `json:"index"`
+	Success bool   `json:"success"`
+	Error   string `json:"error,omitempty"`
+}
+
+// FeedbackBatchResult is the result of a batch of feedback, with one item per feedback in the
+// order of the batch
+type FeedbackBatchResult struct {
+	Succeeded int                 `json:"succeeded"`
+	Failed    int                 `json:"failed"`
+	Items     []FeedbackBatchItem `json:"items"`
+}
+
+// Add records the result of the feedback at index
+func (r *FeedbackBatchResult) Add(index int, err error) {
+	item := FeedbackBatchItem{Index: index, Success: err == nil}
+	if err != nil {
+		item.Error = err.Error()
+		r.Failed++
+	} else {
+		r.Succeeded++
+	}
+	r.Items = append(r.Items, item)
+}
+
+// CreateFeedbackBatch submits the feedback, see SubmitFeedbackBatch
+func (c *client) CreateFeedbackBatch(ctx context.Context, feedback []*FeedbackSubmission) (*FeedbackBatchResult, error) {
+	return SubmitFeedbackBatch(ctx, c, feedback)
+}
+
+// SubmitFeedbackBatch submits each feedback with CreateFeedback, as Autogen has no batch
+// endpoint. A feedback that fails doesn't stop the others; its error is reported in its item.
+// If ctx is done before the end of the batch, the remaining feedback is reported as failed and
+// the context's error is returned with the result.
+func SubmitFeedbackBatch(ctx context.Context, c Client, feedback []*FeedbackSubmission) (*FeedbackBatchResult, error) {
+	result := &FeedbackBatchResult{Items: make([]FeedbackBatchItem, 0, len(feedback))}
+	for i, submission := range feedback {
+		if err := ctx.Err(); err != nil {
+			result.Add(i, err)
+			continue
+		}
+		result.Add(i, c.CreateFeedback(submission))
+	}
+	return result, ctx.Err()
+}
@@ -0,0 +1,54 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateFeedbackBatch(t *testing.T) {
+	var received []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var feedback FeedbackSubmission
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&feedback))
+		received = append(received, feedback.FeedbackText)
+		if feedback.FeedbackText == "fails" {
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			return
+		}
+		_, _ = w.Write([]byte(`{"status":true}`))
+	}))
+	defer server.Close()
+
+	c := New(server.URL)
+	batch := []*FeedbackSubmission{{FeedbackText: "one"}, {FeedbackText: "fails"}, {FeedbackText: "three"}}
+
+	t.Run("reports failures per feedback", func(t *testing.T) {
+		result, err := c.CreateFeedbackBatch(context.Background(), batch)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"one", "fails", "three"}, received, "a failure doesn't stop the batch")
+		assert.Equal(t, 2, result.Succeeded)
+		assert.Equal(t, 1, result.Failed)
+		require.Len(t, result.Items, 3)
+		assert.True(t, result.Items[0].Success)
+		assert.Equal(t, 1, result.Items[1].Index)
+		assert.Contains(t, result.Items[1].Error, "422")
+		assert.True(t, result.Items[2].Success)
+	})
+
+	t.Run("stops when the context is done", func(t *testing.T) {
+		received = nil
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		result, err := c.CreateFeedbackBatch(ctx, batch)
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Empty(t, received)
+		assert.Equal(t, 3, result.Failed)
+	})
+}
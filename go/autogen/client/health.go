@@ -0,0 +1,217 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/kagent-dev/kagent/go/autogen/api"
+)
+
+// Tool server health statuses
+const (
+	ToolServerStatusHealthy     = "healthy"
+	ToolServerStatusUnreachable = "unreachable"
+	// ToolServerStatusUnknown is reported for stdio servers, which only Autogen can start
+	ToolServerStatusUnknown = "unknown"
+)
+
+const (
+	defaultToolServerHealthTimeout     = 5 * time.Second
+	defaultToolServerHealthConcurrency = 8
+)
+
+// errProbeUnsupported is returned by probeToolServer for servers it can't connect to
+var errProbeUnsupported = errors.New("health checks are only supported for SSE and streamable HTTP servers")
+
+// ToolServerHealth is the result of a connection attempt to a tool server
+type ToolServerHealth struct {
+	ServerID int    `json:"server_id"`
+	Label    string `json:"label"`
+	Status   string `json:"status"`
+	Error    string `json:"error,omitempty"`
+	// LatencyMs is how long the server took to respond, for reachable servers
+	LatencyMs     int64  `json:"latency_ms,omitempty"`
+	LastConnected string `json:"last_connected,omitempty"`
+}
+
+// ToolServerHealthOptions configures CheckToolServerHealth
+type ToolServerHealthOptions struct {
+	// Timeout bounds the connection attempt to each server. It defaults to 5s.
+	Timeout time.Duration
+	// Concurrency is the number of servers checked at once. It defaults to 8.
+	Concurrency int
+	// HTTPClient connects to the servers. It defaults to http.DefaultClient, and shouldn't be
+	// the client of Autogen, which may send Autogen's credentials.
+	HTTPClient *http.Client
+}
+
+// ToolServerHealth checks the tool servers of the user, see CheckToolServerHealth
+func (c *client) ToolServerHealth(ctx context.Context, userID string, opts ToolServerHealthOptions) ([]*ToolServerHealth, error) {
+	return CheckToolServerHealth(ctx, c, userID, opts)
+}
+
+// CheckToolServerHealth attempts a connection to each tool server of the user, and records
+// the time of the successful ones as the servers' last_connected, unless the server changed
+// during the check. The servers are checked
+// concurrently, each with its own timeout, so a slow server only delays the results by the
+// timeout. The results are in the order of ListToolServers.
+func CheckToolServerHealth(ctx context.Context, c Client, userID string, opts ToolServerHealthOptions) ([]*ToolServerHealth, error) {
+	timeout := opts.Timeout
+	if timeout <= 0 {
+		timeout = defaultToolServerHealthTimeout
+	}
+	concurrency := opts.Concurrency
+	if concurrency <= 0 {
+		concurrency = defaultToolServerHealthConcurrency
+	}
+	httpClient := opts.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	servers, err := c.ListToolServers(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tool servers: %w", err)
+	}
+
+	results := make([]*ToolServerHealth, len(servers))
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i, server := range servers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			select {
+			case sem <- struct{}{}:
+				defer func() { <-sem }()
+			case <-ctx.Done():
+				results[i] = unreachable(server, ctx.Err())
+				return
+			}
+			results[i] = checkToolServer(ctx, c, httpClient, server, userID, timeout)
+		}()
+	}
+	wg.Wait()
+
+	return results, nil
+}
+
+func checkToolServer(ctx context.Context, c Client, httpClient *http.Client, server *ToolServer, userID string, timeout time.Duration) *ToolServerHealth {
+	probeCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	start := time.Now()
+	err := probeToolServer(probeCtx, httpClient, server)
+	latency := time.Since(start)
+	if errors.Is(err, errProbeUnsupported) {
+		return &ToolServerHealth{
+			ServerID:      server.Id,
+			Label:         server.Component.Label,
+			Status:        ToolServerStatusUnknown,
+			Error:         err.Error(),
+			LastConnected: server.LastConnected,
+		}
+	}
+	if err != nil {
+		return unreachable(server, err)
+	}
+
+	health := &ToolServerHealth{
+		ServerID:  server.Id,
+		Label:     server.Component.Label,
+		Status:    ToolServerStatusHealthy,
+		LatencyMs: latency.Milliseconds(),
+	}
+
+	lastConnected, err := recordConnection(c, server, userID)
+	if err != nil {
+		health.Error = fmt.Sprintf("failed to record the connection: %v", err)
+	}
+	health.LastConnected = lastConnected
+	return health
+}
+
+// recordConnection sets the last_connected of a reachable server to now and returns the
+// value to report. Autogen only updates whole servers, so the server is fetched again right
+// before the update and is left untouched if it changed since it was listed, rather than
+// overwriting a concurrent update with what the health check saw.
+func recordConnection(c Client, server *ToolServer, userID string) (string, error) {
+	current, err := c.GetToolServer(server.Id, userID)
+	if err != nil {
+		return server.LastConnected, err
+	}
+	if current.UpdatedAt != server.UpdatedAt {
+		return current.LastConnected, nil
+	}
+
+	// Update a copy, as the fetched server may be shared, e.g. by an in-memory client
+	updated := *current
+	updated.LastConnected = time.Now().UTC().Format(time.RFC3339)
+	if err := c.UpdateToolServer(&updated, userID); err != nil {
+		return current.LastConnected, err
+	}
+	return updated.LastConnected, nil
+}
+
+func unreachable(server *ToolServer, err error) *ToolServerHealth {
+	return &ToolServerHealth{
+		ServerID:      server.Id,
+		Label:         server.Component.Label,
+		Status:        ToolServerStatusUnreachable,
+		Error:         err.Error(),
+		LastConnected: server.LastConnected,
+	}
+}
+
+// probeToolServer opens a connection to the URL of an SSE or streamable HTTP server. Any
+// response other than a server error means it's reachable: SSE servers answer GET with their
+// stream, and streamable HTTP servers may reject GET. The body isn't read.
+func probeToolServer(ctx context.Context, httpClient *http.Client, server *ToolServer) error {
+	var url string
+	var headers map[string]interface{}
+	switch server.Component.Provider {
+	case "kagent.tool_servers.SseMcpToolServer":
+		config := &api.SseMcpServerConfig{}
+		if err := config.FromConfig(server.Component.Config); err != nil {
+			return fmt.Errorf("invalid server config: %w", err)
+		}
+		url, headers = config.URL, config.Headers
+	case "kagent.tool_servers.StreamableHttpMcpToolServer":
+		config := &api.StreamableHttpServerConfig{}
+		if err := config.FromConfig(server.Component.Config); err != nil {
+			return fmt.Errorf("invalid server config: %w", err)
+		}
+		url, headers = config.URL, config.Headers
+	default:
+		return errProbeUnsupported
+	}
+	if url == "" {
+		return fmt.Errorf("server config has no url")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return fmt.Errorf("invalid server url: %w", err)
+	}
+	req.Header.Set("Accept", "text/event-stream")
+	for name, value := range headers {
+		if value, ok := value.(string); ok {
+			req.Header.Set(name, value)
+		}
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusInternalServerError {
+		return fmt.Errorf("server responded with status %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,116 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/kagent-dev/kagent/go/autogen/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestToolServerHealth(t *testing.T) {
+	reachable := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}))
+	defer reachable.Close()
+
+	release := make(chan struct{})
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer slow.Close()
+	defer close(release)
+
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer failing.Close()
+
+	servers := []*ToolServer{
+		{Id: 1, Component: api.Component{
+			Provider: "kagent.tool_servers.StreamableHttpMcpToolServer",
+			Label:    "kagent/reachable",
+			Config:   map[string]interface{}{"url": reachable.URL, "headers": map[string]interface{}{"Authorization": "Bearer token"}},
+		}},
+		{Id: 2, Component: api.Component{
+			Provider: "kagent.tool_servers.SseMcpToolServer",
+			Label:    "kagent/slow",
+			Config:   map[string]interface{}{"url": slow.URL},
+		}, LastConnected: "2025-01-01T00:00:00"},
+		{Id: 3, Component: api.Component{
+			Provider: "kagent.tool_servers.SseMcpToolServer",
+			Label:    "kagent/failing",
+			Config:   map[string]interface{}{"url": failing.URL},
+		}},
+		{Id: 4, Component: api.Component{
+			Provider: "kagent.tool_servers.StdioMcpToolServer",
+			Label:    "kagent/stdio",
+			Config:   map[string]interface{}{"command": "python"},
+		}},
+		{Id: 5, Component: api.Component{
+			Provider: "kagent.tool_servers.StreamableHttpMcpToolServer",
+			Label:    "kagent/edited",
+			Config:   map[string]interface{}{"url": reachable.URL, "headers": map[string]interface{}{"Authorization": "Bearer token"}},
+		}, UpdatedAt: "2025-01-01T00:00:00"},
+	}
+	// The edited server is updated by someone else while it's being checked
+	current := map[int]*ToolServer{1: servers[0]}
+	edited := *servers[4]
+	edited.UpdatedAt = "2025-01-02T00:00:00"
+	edited.Component.Description = "edited"
+	current[5] = &edited
+
+	var mu sync.Mutex
+	updated := map[int]string{}
+	autogen := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == "GET" && r.URL.Path == "/toolservers/":
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"status": true, "data": servers})
+		case r.Method == "GET" && r.URL.Path == "/toolservers/1":
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"status": true, "data": current[1]})
+		case r.Method == "GET" && r.URL.Path == "/toolservers/5":
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"status": true, "data": current[5]})
+		case r.Method == "PUT":
+			var server ToolServer
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&server))
+			mu.Lock()
+			updated[server.Id] = server.LastConnected
+			mu.Unlock()
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"status": true, "data": server})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer autogen.Close()
+
+	c := New(autogen.URL)
+	start := time.Now()
+	health, err := c.ToolServerHealth(context.Background(), "alice", ToolServerHealthOptions{Timeout: 200 * time.Millisecond, Concurrency: 2})
+	require.NoError(t, err)
+	assert.Less(t, time.Since(start), 2*time.Second, "the slow server only costs its timeout")
+
+	require.Len(t, health, 5)
+	assert.Equal(t, ToolServerStatusHealthy, health[0].Status)
+	assert.NotEmpty(t, health[0].LastConnected)
+	assert.Equal(t, ToolServerStatusUnreachable, health[1].Status)
+	assert.Equal(t, "2025-01-01T00:00:00", health[1].LastConnected, "a failed check keeps the last connection")
+	assert.Equal(t, ToolServerStatusUnreachable, health[2].Status)
+	assert.Contains(t, health[2].Error, "502")
+	assert.Equal(t, ToolServerStatusUnknown, health[3].Status)
+	assert.Equal(t, ToolServerStatusHealthy, health[4].Status)
+	assert.Empty(t, health[4].LastConnected)
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, map[int]string{1: health[0].LastConnected}, updated, "only reachable servers that didn't change are updated")
+}
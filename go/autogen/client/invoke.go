@@ -17,17 +17,12 @@ type InvokeTaskResult struct {
 	Usage      string     `json:"usage"`
 }
 
-func (c *client) InvokeTask(req *InvokeTaskRequest) (*InvokeTaskResult, error) {
+func (c *client) InvokeTask(ctx context.Context, req *InvokeTaskRequest) (*InvokeTaskResult, error) {
 	var invoke InvokeTaskResult
-	err := c.doRequest(context.Background(), "POST", "/invoke", req, &invoke)
+	err := c.doInvokeRequest(ctx, "POST", "/invoke", req, c.maxDurationHeader(ctx), &invoke)
 	return &invoke, err
 }
 
-func (c *client) InvokeTaskStream(req *InvokeTaskRequest) (<-chan *SseEvent, error) {
-	resp, err := c.startRequest(context.Background(), "POST", "/invoke/stream", req)
-	if err != nil {
-		return nil, err
-	}
-	ch := streamSseResponse(resp.Body)
-	return ch, nil
+func (c *client) InvokeTaskStream(ctx context.Context, req *InvokeTaskRequest) (<-chan *SseEvent, error) {
+	return c.startStream(ctx, "POST", "/invoke/stream", req, true)
 }
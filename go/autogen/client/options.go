@@ -0,0 +1,297 @@
+package client
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"time"
+)
+
+const (
+	// EnvAPIURL is the environment variable holding the API base URL
+	EnvAPIURL = "KAGENT_API_URL"
+	// EnvUserID is the environment variable holding the default user ID
+	EnvUserID = "KAGENT_USER_ID"
+	// EnvToken is the environment variable holding the bearer token
+	EnvToken = "KAGENT_TOKEN"
+	// EnvTimeout is the environment variable holding the request timeout, e.g. "30s"
+	EnvTimeout = "KAGENT_TIMEOUT"
+	// EnvMaxRetries is the environment variable holding the number of retries for idempotent requests
+	EnvMaxRetries = "KAGENT_MAX_RETRIES"
+
+	// MaxDurationHeader asks the server to bound an invocation to the given duration
+	MaxDurationHeader = "X-Max-Duration"
+	// UserIDHeader carries the user ID of requests with UserIDModeHeader
+	UserIDHeader = "X-User-ID"
+
+	defaultRetryBackoff = 500 * time.Millisecond
+)
+
+// Option configures a client
+type Option func(*client)
+
+// UserIDMode selects how the client passes the user ID of requests to the server
+type UserIDMode string
+
+const (
+	// UserIDModeQuery passes the user ID in the user_id query parameter. It is the default,
+	// and the only mode Autogen understands.
+	UserIDModeQuery UserIDMode = "query"
+	// UserIDModeHeader passes the user ID in the X-User-ID header, for servers that read it
+	// from there
+	UserIDModeHeader UserIDMode = "header"
+	// UserIDModeNone doesn't pass the user ID, for servers that identify the user from the
+	// token
+	UserIDModeNone UserIDMode = "none"
+)
+
+// WithHTTPClient sets the HTTP client used for requests. WithTimeout, WithProxy,
+// WithTLSConfig and WithInsecureSkipVerify apply on top of it whatever the order of the
+// options, without modifying the given client. The transport options are ignored if the
+// client's transport is neither nil nor an *http.Transport.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *client) {
+		c.HTTPClient = httpClient
+	}
+}
+
+// WithTimeout sets the timeout of each request
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *client) {
+		c.timeout = timeout
+	}
+}
+
+// WithMaxDurationHeader sends an X-Max-Duration header on invocations, derived from the
+// request timeout and the context deadline, so the kagent controller returns a partial result
+// before the client gives up. Autogen doesn't read the header: the controller bounds its own
+// invocations with the context deadline, so only clients of the controller API should set it.
+func WithMaxDurationHeader() Option {
+	return func(c *client) {
+		c.maxDuration = true
+	}
+}
+
+// WithToken sends the token as a bearer token on every request
+func WithToken(token string) Option {
+	return func(c *client) {
+		c.Token = token
+	}
+}
+
+// WithUserID sets the user ID used by requests that are called with an empty user ID
+func WithUserID(userID string) Option {
+	return func(c *client) {
+		c.UserID = userID
+	}
+}
+
+// WithUserIDMode sets how the user ID of requests is passed to the server. Unknown modes
+// behave like UserIDModeQuery.
+func WithUserIDMode(mode UserIDMode) Option {
+	return func(c *client) {
+		c.userIDMode = mode
+	}
+}
+
+// WithProxy sends requests through the given proxy
+func WithProxy(proxyURL *url.URL) Option {
+	return func(c *client) {
+		c.proxyURL = proxyURL
+	}
+}
+
+// WithTLSConfig sets the TLS configuration used for requests, e.g. to trust a custom CA
+func WithTLSConfig(tlsConfig *tls.Config) Option {
+	return func(c *client) {
+		c.tlsConfig = tlsConfig
+	}
+}
+
+// WithInsecureSkipVerify disables verification of the server certificate.
+// It takes precedence over the InsecureSkipVerify field of WithTLSConfig.
+func WithInsecureSkipVerify(skip bool) Option {
+	return func(c *client) {
+		c.insecureSkipVerify = &skip
+	}
+}
+
+// WithRetries retries idempotent requests up to maxRetries times on transport errors and
+// 502, 503 and 504 responses, waiting backoff times the attempt number between tries
+func WithRetries(maxRetries int, backoff time.Duration) Option {
+	return func(c *client) {
+		c.MaxRetries = maxRetries
+		c.RetryBackoff = backoff
+	}
+}
+
+// NewFromEnv creates a client configured from the KAGENT_API_URL, KAGENT_USER_ID,
+// KAGENT_TOKEN, KAGENT_TIMEOUT and KAGENT_MAX_RETRIES environment variables.
+// KAGENT_API_URL is required; the other variables are optional.
+func NewFromEnv(opts ...Option) (Client, error) {
+	apiURL := os.Getenv(EnvAPIURL)
+	if apiURL == "" {
+		return nil, fmt.Errorf("%s is not set", EnvAPIURL)
+	}
+	parsed, err := url.Parse(apiURL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return nil, fmt.Errorf("%s must be an absolute http or https URL, got %q", EnvAPIURL, apiURL)
+	}
+
+	var envOpts []Option
+	if userID := os.Getenv(EnvUserID); userID != "" {
+		envOpts = append(envOpts, WithUserID(userID))
+	}
+	if token := os.Getenv(EnvToken); token != "" {
+		envOpts = append(envOpts, WithToken(token))
+	}
+	if timeoutStr := os.Getenv(EnvTimeout); timeoutStr != "" {
+		timeout, err := time.ParseDuration(timeoutStr)
+		if err != nil || timeout <= 0 {
+			return nil, fmt.Errorf("%s must be a positive duration such as 30s, got %q", EnvTimeout, timeoutStr)
+		}
+		envOpts = append(envOpts, WithTimeout(timeout))
+	}
+	if retriesStr := os.Getenv(EnvMaxRetries); retriesStr != "" {
+		retries, err := strconv.Atoi(retriesStr)
+		if err != nil || retries < 0 {
+			return nil, fmt.Errorf("%s must be a non-negative integer, got %q", EnvMaxRetries, retriesStr)
+		}
+		envOpts = append(envOpts, WithRetries(retries, defaultRetryBackoff))
+	}
+
+	// Explicit options take precedence over the environment
+	return New(apiURL, append(envOpts, opts...)...), nil
+}
+
+// applyHTTPOptions applies the timeout and transport options to a copy of the HTTP client
+func (c *client) applyHTTPOptions() {
+	hasTransportOptions := c.proxyURL != nil || c.tlsConfig != nil || c.insecureSkipVerify != nil
+	if c.timeout == 0 && !hasTransportOptions {
+		return
+	}
+
+	httpClient := *c.HTTPClient
+	if c.timeout != 0 {
+		httpClient.Timeout = c.timeout
+	}
+
+	if hasTransportOptions {
+		var transport *http.Transport
+		switch t := httpClient.Transport.(type) {
+		case nil:
+			transport = http.DefaultTransport.(*http.Transport).Clone()
+		case *http.Transport:
+			transport = t.Clone()
+		}
+
+		if transport != nil {
+			if c.proxyURL != nil {
+				transport.Proxy = http.ProxyURL(c.proxyURL)
+			}
+			if c.tlsConfig != nil {
+				transport.TLSClientConfig = c.tlsConfig.Clone()
+			}
+			if c.insecureSkipVerify != nil {
+				if transport.TLSClientConfig == nil {
+					transport.TLSClientConfig = &tls.Config{}
+				}
+				transport.TLSClientConfig.InsecureSkipVerify = *c.insecureSkipVerify
+			}
+			httpClient.Transport = transport
+		}
+	}
+
+	c.HTTPClient = &httpClient
+}
+
+// withDefaultUserID sets the user_id query parameter of rawURL to the client's user ID
+// if the parameter is present but empty
+func (c *client) withDefaultUserID(rawURL string) string {
+	if c.UserID == "" {
+		return rawURL
+	}
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return rawURL
+	}
+	query := parsed.Query()
+	if values, ok := query["user_id"]; ok && (len(values) == 0 || values[0] == "") {
+		query.Set("user_id", c.UserID)
+		parsed.RawQuery = query.Encode()
+		return parsed.String()
+	}
+	return rawURL
+}
+
+// withUserID fills in the user_id query parameter of rawURL like withDefaultUserID, then
+// removes it from the query unless the user ID is passed there. It returns the URL and, with
+// UserIDModeHeader, the user ID to send in the X-User-ID header.
+func (c *client) withUserID(rawURL string) (string, string) {
+	rawURL = c.withDefaultUserID(rawURL)
+	if c.userIDMode != UserIDModeHeader && c.userIDMode != UserIDModeNone {
+		return rawURL, ""
+	}
+
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return rawURL, ""
+	}
+	query := parsed.Query()
+	if _, ok := query["user_id"]; !ok {
+		return rawURL, ""
+	}
+	userID := query.Get("user_id")
+	query.Del("user_id")
+	parsed.RawQuery = query.Encode()
+
+	if c.userIDMode == UserIDModeHeader {
+		return parsed.String(), userID
+	}
+	return parsed.String(), ""
+}
+
+// maxDurationHeader returns an X-Max-Duration header asking the server to finish within the
+// request timeout set by WithTimeout, or the context deadline if that is sooner. A margin is
+// kept so the server's partial result arrives before the client gives up. It's nil unless
+// WithMaxDurationHeader is set.
+func (c *client) maxDurationHeader(ctx context.Context) http.Header {
+	if !c.maxDuration {
+		return nil
+	}
+	budget := c.timeout
+	if deadline, ok := ctx.Deadline(); ok {
+		if remaining := time.Until(deadline); budget == 0 || remaining < budget {
+			budget = remaining
+		}
+	}
+	if budget <= 0 {
+		return nil
+	}
+
+	budget = budget * 9 / 10
+	return http.Header{MaxDurationHeader: {budget.Round(time.Millisecond).String()}}
+}
+
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodPut, http.MethodDelete, http.MethodOptions:
+		return true
+	}
+	return false
+}
+
+func isRetryable(resp *http.Response, err error) bool {
+	if err != nil {
+		return true
+	}
+	switch resp.StatusCode {
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
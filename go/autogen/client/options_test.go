@@ -0,0 +1,250 @@
+package client
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewFromEnv(t *testing.T) {
+	t.Run("missing url", func(t *testing.T) {
+		t.Setenv(EnvAPIURL, "")
+		_, err := NewFromEnv()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), EnvAPIURL)
+	})
+
+	t.Run("invalid url", func(t *testing.T) {
+		t.Setenv(EnvAPIURL, "localhost:8081")
+		_, err := NewFromEnv()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "absolute http or https URL")
+	})
+
+	t.Run("invalid timeout", func(t *testing.T) {
+		t.Setenv(EnvAPIURL, "http://localhost:8081/api")
+		t.Setenv(EnvTimeout, "soon")
+		_, err := NewFromEnv()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), EnvTimeout)
+	})
+
+	t.Run("invalid retries", func(t *testing.T) {
+		t.Setenv(EnvAPIURL, "http://localhost:8081/api")
+		t.Setenv(EnvMaxRetries, "-1")
+		_, err := NewFromEnv()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), EnvMaxRetries)
+	})
+
+	t.Run("applies settings", func(t *testing.T) {
+		t.Setenv(EnvAPIURL, "http://localhost:8081/api/")
+		t.Setenv(EnvUserID, "alice")
+		t.Setenv(EnvToken, "secret")
+		t.Setenv(EnvTimeout, "15s")
+		t.Setenv(EnvMaxRetries, "2")
+
+		c, err := NewFromEnv()
+		require.NoError(t, err)
+		impl := c.(*client)
+		assert.Equal(t, "http://localhost:8081/api", impl.BaseURL)
+		assert.Equal(t, "alice", impl.UserID)
+		assert.Equal(t, "secret", impl.Token)
+		assert.Equal(t, 15*time.Second, impl.HTTPClient.Timeout)
+		assert.Equal(t, 2, impl.MaxRetries)
+	})
+}
+
+func TestClientOptions(t *testing.T) {
+	var mu sync.Mutex
+	var requests []*http.Request
+	failures := 2
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		requests = append(requests, r)
+		if failures > 0 {
+			failures--
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte(`[]`))
+	}))
+	defer server.Close()
+
+	recorded := func() []*http.Request {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]*http.Request(nil), requests...)
+	}
+
+	c := New(server.URL, WithToken("secret"), WithUserID("alice"), WithRetries(2, time.Millisecond))
+
+	_, err := c.ListSessions("")
+	require.NoError(t, err)
+
+	require.Len(t, recorded(), 3)
+	last := recorded()[2]
+	assert.Equal(t, "Bearer secret", last.Header.Get("Authorization"))
+	assert.Equal(t, "alice", last.URL.Query().Get("user_id"))
+
+	// An explicit user ID is kept
+	_, err = c.ListSessions("bob")
+	require.NoError(t, err)
+	assert.Equal(t, "bob", recorded()[3].URL.Query().Get("user_id"))
+
+	// POST requests are not retried
+	mu.Lock()
+	failures = 1
+	mu.Unlock()
+	err = c.CreateTeam(&Team{})
+	require.Error(t, err)
+	assert.Len(t, recorded(), 5)
+}
+
+func TestTransportOptions(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`[]`))
+	}))
+	defer server.Close()
+
+	t.Run("untrusted certificate fails by default", func(t *testing.T) {
+		c := New(server.URL)
+		_, err := c.ListSessions("alice")
+		require.Error(t, err)
+	})
+
+	t.Run("insecure skip verify", func(t *testing.T) {
+		c := New(server.URL, WithInsecureSkipVerify(true))
+		_, err := c.ListSessions("alice")
+		require.NoError(t, err)
+	})
+
+	t.Run("custom CA", func(t *testing.T) {
+		pool := x509.NewCertPool()
+		pool.AddCert(server.Certificate())
+		c := New(server.URL, WithTLSConfig(&tls.Config{RootCAs: pool}))
+		_, err := c.ListSessions("alice")
+		require.NoError(t, err)
+	})
+
+	t.Run("composes with http client and timeout in any order", func(t *testing.T) {
+		httpClient := &http.Client{Timeout: time.Minute}
+		c := New(server.URL, WithInsecureSkipVerify(true), WithTimeout(5*time.Second), WithHTTPClient(httpClient))
+		_, err := c.ListSessions("alice")
+		require.NoError(t, err)
+
+		impl := c.(*client)
+		assert.Equal(t, 5*time.Second, impl.HTTPClient.Timeout)
+		// The given client is not modified
+		assert.Equal(t, time.Minute, httpClient.Timeout)
+		assert.Nil(t, httpClient.Transport)
+	})
+}
+
+func TestWithProxy(t *testing.T) {
+	var proxiedHost string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxiedHost = r.URL.Host
+		_, _ = w.Write([]byte(`[]`))
+	}))
+	defer proxy.Close()
+
+	proxyURL, err := url.Parse(proxy.URL)
+	require.NoError(t, err)
+
+	c := New("http://kagent.example.com/api", WithProxy(proxyURL))
+	_, err = c.ListSessions("alice")
+	require.NoError(t, err)
+	assert.Equal(t, "kagent.example.com", proxiedHost)
+}
+
+func TestMaxDurationHeader(t *testing.T) {
+	var header string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header.Get(MaxDurationHeader)
+		_, _ = w.Write([]byte(`{"status":true,"data":{}}`))
+	}))
+	defer server.Close()
+
+	t.Run("unset without the option", func(t *testing.T) {
+		_, err := New(server.URL, WithTimeout(10*time.Second)).InvokeTask(context.Background(), &InvokeTaskRequest{Task: "hello"})
+		require.NoError(t, err)
+		assert.Empty(t, header)
+	})
+
+	t.Run("unset without a timeout", func(t *testing.T) {
+		_, err := New(server.URL, WithMaxDurationHeader()).InvokeTask(context.Background(), &InvokeTaskRequest{Task: "hello"})
+		require.NoError(t, err)
+		assert.Empty(t, header)
+	})
+
+	t.Run("derived from the request timeout", func(t *testing.T) {
+		_, err := New(server.URL, WithMaxDurationHeader(), WithTimeout(10*time.Second)).InvokeTask(context.Background(), &InvokeTaskRequest{Task: "hello"})
+		require.NoError(t, err)
+		assert.Equal(t, "9s", header)
+	})
+
+	t.Run("bounded by the context deadline", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+		_, err := New(server.URL, WithMaxDurationHeader(), WithTimeout(time.Minute)).InvokeSession(ctx, 1, "alice", &InvokeRequest{Task: "hello"})
+		require.NoError(t, err)
+
+		budget, err := time.ParseDuration(header)
+		require.NoError(t, err)
+		assert.LessOrEqual(t, budget, 1800*time.Millisecond)
+		assert.Greater(t, budget, time.Second)
+	})
+}
+
+func TestWithUserIDMode(t *testing.T) {
+	var last *http.Request
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		last = r
+		_, _ = w.Write([]byte(`[]`))
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		mode       UserIDMode
+		wantQuery  string
+		wantHeader string
+	}{
+		{"", "alice", ""},
+		{UserIDModeQuery, "alice", ""},
+		{UserIDModeHeader, "", "alice"},
+		{UserIDModeNone, "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.mode), func(t *testing.T) {
+			c := New(server.URL, WithUserID("alice"), WithUserIDMode(tt.mode))
+
+			_, err := c.ListSessions("")
+			require.NoError(t, err)
+			_, hasQuery := last.URL.Query()["user_id"]
+			assert.Equal(t, tt.wantQuery != "", hasQuery)
+			assert.Equal(t, tt.wantQuery, last.URL.Query().Get("user_id"))
+			assert.Equal(t, tt.wantHeader, last.Header.Get(UserIDHeader))
+		})
+	}
+
+	t.Run("explicit user ID in a header", func(t *testing.T) {
+		c := New(server.URL, WithUserID("alice"), WithUserIDMode(UserIDModeHeader))
+
+		_, err := c.ListToolServers("bob")
+		require.NoError(t, err)
+		assert.Equal(t, "bob", last.Header.Get(UserIDHeader))
+		assert.Empty(t, last.URL.RawQuery)
+	})
+}
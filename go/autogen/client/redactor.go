@@ -0,0 +1,106 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+)
+
+// DefaultRedactionReplacement replaces the matches of a RegexRedactor created without a replacement
+const DefaultRedactionReplacement = "[REDACTED]"
+
+// MessageRedactor transforms message content before it is sent to Autogen, which stores it
+type MessageRedactor interface {
+	Redact(content string) string
+}
+
+// NoopRedactor leaves content unchanged
+type NoopRedactor struct{}
+
+func (NoopRedactor) Redact(content string) string {
+	return content
+}
+
+// RegexRedactor replaces the matches of its patterns
+type RegexRedactor struct {
+	patterns    []*regexp.Regexp
+	replacement string
+}
+
+// NewRegexRedactor returns a redactor replacing the matches of the patterns with replacement,
+// or DefaultRedactionReplacement if empty
+func NewRegexRedactor(replacement string, patterns ...string) (*RegexRedactor, error) {
+	if replacement == "" {
+		replacement = DefaultRedactionReplacement
+	}
+	redactor := &RegexRedactor{replacement: replacement}
+	for _, pattern := range patterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid redaction pattern %q: %w", pattern, err)
+		}
+		redactor.patterns = append(redactor.patterns, re)
+	}
+	return redactor, nil
+}
+
+func (r *RegexRedactor) Redact(content string) string {
+	for _, re := range r.patterns {
+		content = re.ReplaceAllLiteralString(content, r.replacement)
+	}
+	return content
+}
+
+// redactingClient redacts the tasks and feedback sent through it before they are serialized
+type redactingClient struct {
+	Client
+	redactor MessageRedactor
+}
+
+// NewRedactingClient returns a client redacting the tasks of invocations and the text of
+// feedback with redactor before they reach c. Agents see the redacted tasks too, as the task
+// sent to Autogen is both run and stored. Agent output is stored by Autogen as produced.
+func NewRedactingClient(c Client, redactor MessageRedactor) Client {
+	return &redactingClient{Client: c, redactor: redactor}
+}
+
+func (c *redactingClient) InvokeSession(ctx context.Context, sessionID int, userID string, request *InvokeRequest) (*TeamResult, error) {
+	redacted := *request
+	redacted.Task = c.redactor.Redact(request.Task)
+	return c.Client.InvokeSession(ctx, sessionID, userID, &redacted)
+}
+
+func (c *redactingClient) InvokeSessionStream(ctx context.Context, sessionID int, userID string, request *InvokeRequest) (<-chan *SseEvent, error) {
+	redacted := *request
+	redacted.Task = c.redactor.Redact(request.Task)
+	return c.Client.InvokeSessionStream(ctx, sessionID, userID, &redacted)
+}
+
+func (c *redactingClient) InvokeTask(ctx context.Context, req *InvokeTaskRequest) (*InvokeTaskResult, error) {
+	redacted := *req
+	redacted.Task = c.redactor.Redact(req.Task)
+	return c.Client.InvokeTask(ctx, &redacted)
+}
+
+func (c *redactingClient) InvokeTaskStream(ctx context.Context, req *InvokeTaskRequest) (<-chan *SseEvent, error) {
+	redacted := *req
+	redacted.Task = c.redactor.Redact(req.Task)
+	return c.Client.InvokeTaskStream(ctx, &redacted)
+}
+
+func (c *redactingClient) RerunSessionRun(ctx context.Context, sessionID int, runID int, userID string, request *RerunRequest) (*Run, error) {
+	redacted := *request
+	redacted.Task = c.redactor.Redact(request.Task)
+	return c.Client.RerunSessionRun(ctx, sessionID, runID, userID, &redacted)
+}
+
+func (c *redactingClient) CreateFeedback(feedback *FeedbackSubmission) error {
+	redacted := *feedback
+	redacted.FeedbackText = c.redactor.Redact(feedback.FeedbackText)
+	return c.Client.CreateFeedback(&redacted)
+}
+
+// CreateFeedbackBatch submits the feedback through c, so that each feedback is redacted
+func (c *redactingClient) CreateFeedbackBatch(ctx context.Context, feedback []*FeedbackSubmission) (*FeedbackBatchResult, error) {
+	return SubmitFeedbackBatch(ctx, c, feedback)
+}
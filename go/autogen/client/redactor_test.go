@@ -0,0 +1,65 @@
+package client
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegexRedactor(t *testing.T) {
+	redactor, err := NewRegexRedactor("", `sk-[A-Za-z0-9]{8,}`, `[\w.+-]+@[\w-]+\.[\w.]+`)
+	require.NoError(t, err)
+
+	assert.Equal(t, "use key [REDACTED] and mail [REDACTED]", redactor.Redact("use key sk-abcdef123456 and mail jane.doe@example.com"))
+	assert.Equal(t, "nothing to hide", redactor.Redact("nothing to hide"))
+	assert.Equal(t, "sk-abc", NoopRedactor{}.Redact("sk-abc"))
+
+	_, err = NewRegexRedactor("", `(unclosed`)
+	assert.Error(t, err)
+}
+
+func TestRedactingClient(t *testing.T) {
+	const secret = "sk-abcdef123456"
+
+	var mu sync.Mutex
+	var bodies []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		bodies = append(bodies, string(body))
+		mu.Unlock()
+		_, _ = w.Write([]byte(`{"status":true,"data":{}}`))
+	}))
+	defer server.Close()
+
+	redactor, err := NewRegexRedactor("***", `sk-[A-Za-z0-9]{8,}`)
+	require.NoError(t, err)
+	c := NewRedactingClient(New(server.URL), redactor)
+
+	sessionRequest := &InvokeRequest{Task: "deploy with key " + secret}
+	_, err = c.InvokeSession(context.Background(), 1, "alice", sessionRequest)
+	require.NoError(t, err)
+	_, err = c.InvokeTask(context.Background(), &InvokeTaskRequest{Task: "deploy with key " + secret})
+	require.NoError(t, err)
+	_, err = c.RerunSessionRun(context.Background(), 1, 2, "alice", &RerunRequest{Task: "deploy with key " + secret})
+	require.NoError(t, err)
+	require.NoError(t, c.CreateFeedback(&FeedbackSubmission{FeedbackText: "it leaked " + secret}))
+	batch, err := c.CreateFeedbackBatch(context.Background(), []*FeedbackSubmission{{FeedbackText: "it leaked " + secret + " again"}})
+	require.NoError(t, err)
+	require.Len(t, batch.Items, 1)
+
+	mu.Lock()
+	defer mu.Unlock()
+	require.Len(t, bodies, 5)
+	for _, body := range bodies {
+		assert.NotContains(t, body, secret)
+		assert.Contains(t, body, "***")
+	}
+	assert.Equal(t, "deploy with key "+secret, sessionRequest.Task, "the caller's request is left unchanged")
+}
@@ -3,6 +3,7 @@ package client
 import (
 	"context"
 	"fmt"
+	"net/url"
 
 	"github.com/google/uuid"
 )
@@ -40,6 +41,22 @@ func (c *client) ListRuns(userID string) ([]*Run, error) {
 	return runs, nil
 }
 
+// ListRunsByStatus lists the runs of all users with the given status, through the internal
+// routes of Autogen
+func (c *client) ListRunsByStatus(status string) ([]*Run, error) {
+	var runs []*Run
+	err := c.doRequest(context.Background(), "GET", fmt.Sprintf("/internal/runs/?status=%s", url.QueryEscape(status)), nil, &runs)
+	return runs, err
+}
+
+// UpdateRunStatus sets the status of a run, through the internal routes of Autogen
+func (c *client) UpdateRunStatus(runID int, status string, errorMessage string) error {
+	return c.doRequest(context.Background(), "PUT", fmt.Sprintf("/internal/runs/%d/status", runID), &UpdateRunStatusRequest{
+		Status:       status,
+		ErrorMessage: errorMessage,
+	}, nil)
+}
+
 func (c *client) GetRunMessages(runID uuid.UUID) ([]*RunMessage, error) {
 	var messages []*RunMessage
 	err := c.doRequest(context.Background(), "GET", fmt.Sprintf("/runs/%s/messages", runID), nil, &messages)
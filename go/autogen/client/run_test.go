@@ -0,0 +1,38 @@
+package client
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunsOfAllUsers(t *testing.T) {
+	var lastBody UpdateRunStatusRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == "GET" && r.URL.Path == "/internal/runs/":
+			assert.Equal(t, "active", r.URL.Query().Get("status"))
+			_, _ = w.Write([]byte(`{"status":true,"data":[{"id":1,"session_id":7,"status":"active"}]}`))
+		case r.Method == "PUT" && r.URL.Path == "/internal/runs/1/status":
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&lastBody))
+			_, _ = w.Write([]byte(`{"status":true,"data":{"run_id":1,"status":"error"}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	c := New(server.URL)
+
+	runs, err := c.ListRunsByStatus(RunStatusActive)
+	require.NoError(t, err)
+	require.Len(t, runs, 1)
+	assert.Equal(t, 7, runs[0].SessionID)
+
+	require.NoError(t, c.UpdateRunStatus(1, RunStatusError, "interrupted"))
+	assert.Equal(t, UpdateRunStatusRequest{Status: RunStatusError, ErrorMessage: "interrupted"}, lastBody)
+}
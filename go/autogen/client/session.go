@@ -1,16 +1,76 @@
 package client
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"sort"
+
+	jsonpatch "github.com/evanphx/json-patch/v5"
 )
 
+// ErrInvalidPatch is returned by PatchSession for patches that aren't a JSON merge patch of
+// a session
+var ErrInvalidPatch = errors.New("invalid merge patch")
+
 func (c *client) ListSessions(userID string) ([]*Session, error) {
 	var sessions []*Session
 	err := c.doRequest(context.Background(), "GET", fmt.Sprintf("/sessions/?user_id=%s", userID), nil, &sessions)
 	return sessions, err
 }
 
+// ListSessionsByLastActivity lists the sessions of the user, most recently active first, see
+// SortSessionsByLastActivity
+func (c *client) ListSessionsByLastActivity(userID string) ([]*Session, error) {
+	sessions, err := c.ListSessions(userID)
+	if err != nil {
+		return nil, err
+	}
+	runs, err := c.ListRuns(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list runs: %w", err)
+	}
+	return SortSessionsByLastActivity(sessions, runs), nil
+}
+
+// SessionLastActivity returns the time each session was last active: the created_at of its
+// latest run, or of the session if it has no runs
+func SessionLastActivity(sessions []*Session, runs []*Run) map[int]string {
+	lastActivity := make(map[int]string, len(sessions))
+	for _, session := range sessions {
+		lastActivity[session.ID] = session.CreatedAt
+	}
+	latestRun := make(map[int]string, len(sessions))
+	for _, run := range runs {
+		if run.CreatedAt > latestRun[run.SessionID] {
+			latestRun[run.SessionID] = run.CreatedAt
+		}
+	}
+	for sessionID, createdAt := range latestRun {
+		if _, ok := lastActivity[sessionID]; ok {
+			lastActivity[sessionID] = createdAt
+		}
+	}
+	return lastActivity
+}
+
+// SortSessionsByLastActivity returns a copy of the sessions sorted by SessionLastActivity,
+// most recent first, and by decreasing ID for sessions active at the same time
+func SortSessionsByLastActivity(sessions []*Session, runs []*Run) []*Session {
+	lastActivity := SessionLastActivity(sessions, runs)
+	sorted := append([]*Session{}, sessions...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		a, b := lastActivity[sorted[i].ID], lastActivity[sorted[j].ID]
+		if a != b {
+			return a > b
+		}
+		return sorted[i].ID > sorted[j].ID
+	})
+	return sorted
+}
+
 func (c *client) CreateSession(session *CreateSession) (*Session, error) {
 	var result Session
 	err := c.doRequest(context.Background(), "POST", "/sessions/", session, &result)
@@ -38,19 +98,25 @@ func (c *client) GetSession(sessionLabel string, userID string) (*Session, error
 	return nil, NotFoundError
 }
 
-func (c *client) InvokeSession(sessionID int, userID string, request *InvokeRequest) (*TeamResult, error) {
+func (c *client) InvokeSession(ctx context.Context, sessionID int, userID string, request *InvokeRequest) (*TeamResult, error) {
 	var result TeamResult
-	err := c.doRequest(context.Background(), "POST", fmt.Sprintf("/sessions/%d/invoke?user_id=%s", sessionID, userID), request, &result)
+	err := c.doInvokeRequest(ctx, "POST", fmt.Sprintf("/sessions/%d/invoke?user_id=%s", sessionID, userID), request, c.maxDurationHeader(ctx), &result)
 	return &result, err
 }
 
-func (c *client) InvokeSessionStream(sessionID int, userID string, request *InvokeRequest) (<-chan *SseEvent, error) {
-	resp, err := c.startRequest(context.Background(), "POST", fmt.Sprintf("/sessions/%d/invoke/stream?user_id=%s", sessionID, userID), request)
-	if err != nil {
-		return nil, err
-	}
-	ch := streamSseResponse(resp.Body)
-	return ch, nil
+// InvokeSessionStream runs the task in the session and streams its events. With request.Raw
+// the stream isn't resumed if it drops, see InvokeRequest.
+func (c *client) InvokeSessionStream(ctx context.Context, sessionID int, userID string, request *InvokeRequest) (<-chan *SseEvent, error) {
+	path := fmt.Sprintf("/sessions/%d/invoke/stream?user_id=%s", sessionID, userID)
+	return c.startStream(ctx, "POST", path, request, !request.Raw)
+}
+
+// RerunSessionRun runs the task of a run of the session again, or request.Task if set, in a
+// new run and returns it. The new run links to the re-run one with ParentRunID.
+func (c *client) RerunSessionRun(ctx context.Context, sessionID int, runID int, userID string, request *RerunRequest) (*Run, error) {
+	var run Run
+	err := c.doInvokeRequest(ctx, "POST", fmt.Sprintf("/sessions/%d/runs/%d/rerun?user_id=%s", sessionID, runID, userID), request, c.maxDurationHeader(ctx), &run)
+	return &run, err
 }
 
 func (c *client) DeleteSession(sessionID int, userID string) error {
@@ -76,3 +142,38 @@ func (c *client) UpdateSession(sessionID int, userID string, session *Session) (
 	err := c.doRequest(context.Background(), "PUT", fmt.Sprintf("/sessions/%d?user_id=%s", sessionID, userID), session, &updatedSession)
 	return &updatedSession, err
 }
+
+// PatchSession applies a JSON merge patch to the session, see MergePatchSession
+func (c *client) PatchSession(sessionID int, userID string, patch []byte) (*Session, error) {
+	return MergePatchSession(c, sessionID, userID, patch)
+}
+
+// MergePatchSession applies a JSON merge patch (RFC 7396) to the stored session and saves
+// the result, as Autogen only supports replacing sessions. Fields absent from the patch are
+// kept, and fields set to null are cleared. The ID and user of the session can't be changed.
+func MergePatchSession(c Client, sessionID int, userID string, patch []byte) (*Session, error) {
+	session, err := c.GetSessionById(sessionID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	current, err := json.Marshal(session)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal session: %w", err)
+	}
+	merged, err := jsonpatch.MergePatch(current, patch)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPatch, err)
+	}
+
+	var patched Session
+	decoder := json.NewDecoder(bytes.NewReader(merged))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&patched); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPatch, err)
+	}
+	patched.ID = session.ID
+	patched.UserID = session.UserID
+
+	return c.UpdateSession(sessionID, userID, &patched)
+}
@@ -0,0 +1,134 @@
+package client
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSortSessionsByLastActivity(t *testing.T) {
+	sessions := []*Session{
+		{ID: 1, CreatedAt: "2025-01-01T00:00:00"},
+		{ID: 2, CreatedAt: "2025-01-02T00:00:00"},
+		{ID: 3, CreatedAt: "2025-01-03T00:00:00"},
+		{ID: 4, CreatedAt: "2025-01-03T00:00:00"},
+	}
+	runs := []*Run{
+		{ID: 1, SessionID: 1, CreatedAt: "2025-01-04T00:00:00"},
+		{ID: 2, SessionID: 1, CreatedAt: "2025-01-05T00:00:00"},
+		{ID: 3, SessionID: 2, CreatedAt: "2025-01-02T12:00:00"},
+		// Runs of sessions that aren't listed are ignored
+		{ID: 4, SessionID: 9, CreatedAt: "2025-01-09T00:00:00"},
+	}
+
+	assert.Equal(t, map[int]string{
+		1: "2025-01-05T00:00:00",
+		2: "2025-01-02T12:00:00",
+		3: "2025-01-03T00:00:00",
+		4: "2025-01-03T00:00:00",
+	}, SessionLastActivity(sessions, runs))
+
+	var ids []int
+	for _, session := range SortSessionsByLastActivity(sessions, runs) {
+		ids = append(ids, session.ID)
+	}
+	assert.Equal(t, []int{1, 4, 3, 2}, ids, "sessions without runs fall back to created_at, ties by newest ID")
+	assert.Equal(t, 1, sessions[0].ID, "the given sessions aren't reordered")
+}
+
+func TestPatchSession(t *testing.T) {
+	// The stub stores sessions like the Autogen backend does: a PUT replaces the name and team
+	// of the stored session and keeps its id, user and timestamps.
+	var stored Session
+	reset := func() {
+		teamID := 3
+		stored = Session{ID: 7, UserID: "alice", Name: "debug", CreatedAt: "2025-01-01T00:00:00", TeamID: &teamID}
+	}
+	var updated map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == "GET" && r.URL.Path == "/sessions/7":
+			require.NoError(t, json.NewEncoder(w).Encode(map[string]interface{}{"status": true, "data": stored}))
+		case r.Method == "PUT" && r.URL.Path == "/sessions/7":
+			updated = nil
+			body, err := io.ReadAll(r.Body)
+			require.NoError(t, err)
+			require.NoError(t, json.Unmarshal(body, &updated))
+			var session Session
+			require.NoError(t, json.Unmarshal(body, &session))
+			stored.Name = session.Name
+			stored.TeamID = session.TeamID
+			require.NoError(t, json.NewEncoder(w).Encode(map[string]interface{}{"status": true, "data": stored}))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	c := New(server.URL)
+
+	t.Run("omitted fields are kept", func(t *testing.T) {
+		reset()
+		session, err := c.PatchSession(7, "alice", []byte(`{"name":"renamed"}`))
+		require.NoError(t, err)
+		assert.Equal(t, "renamed", updated["name"])
+		assert.Equal(t, float64(3), updated["team_id"])
+		assert.Equal(t, "2025-01-01T00:00:00", updated["created_at"])
+		assert.Equal(t, "renamed", stored.Name)
+		require.NotNil(t, stored.TeamID)
+		assert.Equal(t, 3, *stored.TeamID)
+		assert.Equal(t, stored, *session)
+	})
+
+	t.Run("the team is stored", func(t *testing.T) {
+		reset()
+		session, err := c.PatchSession(7, "alice", []byte(`{"team_id":5}`))
+		require.NoError(t, err)
+		require.NotNil(t, stored.TeamID)
+		assert.Equal(t, 5, *stored.TeamID)
+		assert.Equal(t, "debug", stored.Name)
+		require.NotNil(t, session.TeamID)
+		assert.Equal(t, 5, *session.TeamID)
+	})
+
+	t.Run("null clears a field", func(t *testing.T) {
+		reset()
+		_, err := c.PatchSession(7, "alice", []byte(`{"team_id":null}`))
+		require.NoError(t, err)
+		assert.Contains(t, updated, "team_id")
+		assert.Nil(t, updated["team_id"])
+		assert.Nil(t, stored.TeamID)
+		assert.Equal(t, "debug", stored.Name)
+	})
+
+	t.Run("the id and user can't be changed", func(t *testing.T) {
+		reset()
+		_, err := c.PatchSession(7, "alice", []byte(`{"id":8,"user_id":"bob"}`))
+		require.NoError(t, err)
+		assert.Equal(t, float64(7), updated["id"])
+		assert.Equal(t, "alice", updated["user_id"])
+		assert.Equal(t, 7, stored.ID)
+		assert.Equal(t, "alice", stored.UserID)
+	})
+
+	t.Run("invalid patches", func(t *testing.T) {
+		for _, patch := range []string{`not json`, `{"unknown":true}`, `{"name":5}`} {
+			reset()
+			updated = nil
+			_, err := c.PatchSession(7, "alice", []byte(patch))
+			assert.ErrorIs(t, err, ErrInvalidPatch, patch)
+			assert.Nil(t, updated, "the session isn't updated")
+			assert.Equal(t, "debug", stored.Name)
+		}
+	})
+
+	t.Run("missing session", func(t *testing.T) {
+		_, err := c.PatchSession(9, "alice", []byte(`{"name":"renamed"}`))
+		assert.ErrorIs(t, err, NotFoundError)
+	})
+}
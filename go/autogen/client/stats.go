@@ -0,0 +1,84 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// SessionStats are aggregate statistics of the runs of a session. A session without runs has
+// zero statistics.
+type SessionStats struct {
+	SessionID     int `json:"session_id"`
+	RunCount      int `json:"run_count"`
+	CompletedRuns int `json:"completed_runs"`
+	FailedRuns    int `json:"failed_runs"`
+	// SuccessRate is the fraction of the finished runs that completed, 0 if none has finished
+	SuccessRate      float64 `json:"success_rate"`
+	PromptTokens     int     `json:"prompt_tokens"`
+	CompletionTokens int     `json:"completion_tokens"`
+	TotalTokens      int     `json:"total_tokens"`
+	// AverageDuration is the mean duration in seconds of the runs Autogen recorded one for
+	AverageDuration float64 `json:"average_duration"`
+}
+
+// GetSessionStats returns the statistics of the runs of the session. The runs are only found
+// if the session belongs to userID.
+func (c *client) GetSessionStats(ctx context.Context, sessionID int, userID string) (*SessionStats, error) {
+	runs, err := c.ListSessionRuns(sessionID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list runs of session %d: %w", sessionID, err)
+	}
+	return ComputeSessionStats(sessionID, runs), nil
+}
+
+// ComputeSessionStats aggregates the statuses, token usage and durations of the runs
+func ComputeSessionStats(sessionID int, runs []*Run) *SessionStats {
+	stats := &SessionStats{SessionID: sessionID, RunCount: len(runs)}
+	usage := &ModelsUsage{}
+	finished, timed := 0, 0
+	totalDuration := 0.0
+	for _, run := range runs {
+		switch run.Status {
+		case RunStatusComplete:
+			stats.CompletedRuns++
+			finished++
+		case RunStatusError:
+			stats.FailedRuns++
+			finished++
+		case RunStatusStopped:
+			finished++
+		}
+		if run.TeamResult.Duration > 0 {
+			totalDuration += run.TeamResult.Duration
+			timed++
+		}
+		for _, message := range run.Messages {
+			usage.Add(messageUsage(message))
+		}
+	}
+
+	if finished > 0 {
+		stats.SuccessRate = float64(stats.CompletedRuns) / float64(finished)
+	}
+	if timed > 0 {
+		stats.AverageDuration = totalDuration / float64(timed)
+	}
+	stats.PromptTokens = usage.PromptTokens
+	stats.CompletionTokens = usage.CompletionTokens
+	stats.TotalTokens = usage.PromptTokens + usage.CompletionTokens
+	return stats
+}
+
+// messageUsage returns the models usage recorded on the message, if any
+func messageUsage(message *RunMessage) *ModelsUsage {
+	data, err := json.Marshal(message.Config)
+	if err != nil {
+		return nil
+	}
+	var chatMessage BaseChatMessage
+	if err := json.Unmarshal(data, &chatMessage); err != nil {
+		return nil
+	}
+	return chatMessage.ModelsUsage
+}
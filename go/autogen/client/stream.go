@@ -0,0 +1,68 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+const (
+	// maxStreamReconnects is the number of consecutive reconnects attempted after a stream drops
+	maxStreamReconnects = 3
+	// streamReconnectDelay is multiplied by the attempt number to back off between reconnects
+	streamReconnectDelay = 500 * time.Millisecond
+)
+
+// startStream starts a streaming request and returns its events. If the connection drops
+// with a transport error after the server has sent event ids, the request is retried with
+// a Last-Event-ID header so the server can resume the stream after the last event received.
+// Servers that don't send event ids are never retried, since retrying would start a new run,
+// and neither are streams that aren't resumable.
+func (c *client) startStream(ctx context.Context, method, path string, body interface{}, resumable bool) (<-chan *SseEvent, error) {
+	resp, err := c.startInvokeRequest(ctx, method, path, body, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	ch := make(chan *SseEvent, 10)
+	go func() {
+		defer close(ch)
+
+		lastEventID := ""
+		reconnects := 0
+		for {
+			previousID := lastEventID
+			var readErr error
+			lastEventID, readErr = readSseEvents(ctx, resp.Body, ch, lastEventID)
+			resp.Body.Close()
+
+			if readErr == nil || !resumable || lastEventID == "" || ctx.Err() != nil {
+				return
+			}
+			// Only count consecutive reconnects that made no progress
+			if lastEventID != previousID {
+				reconnects = 0
+			}
+			if reconnects >= maxStreamReconnects {
+				return
+			}
+			reconnects++
+
+			select {
+			case <-time.After(time.Duration(reconnects) * streamReconnectDelay):
+			case <-ctx.Done():
+				return
+			}
+
+			resp, err = c.startRequestWithHeaders(ctx, method, path, body, http.Header{"Last-Event-ID": {lastEventID}})
+			if err != nil {
+				return
+			}
+			if resp.StatusCode >= 400 {
+				resp.Body.Close()
+				return
+			}
+		}
+	}()
+	return ch, nil
+}
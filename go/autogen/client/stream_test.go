@@ -0,0 +1,136 @@
+package client
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// dropConnection writes the frames and then kills the connection without terminating the chunked body
+func dropConnection(t *testing.T, w http.ResponseWriter, frames string) {
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write([]byte(frames))
+	w.(http.Flusher).Flush()
+
+	conn, _, err := http.NewResponseController(w).Hijack()
+	require.NoError(t, err)
+	conn.Close()
+}
+
+func collectEvents(ch <-chan *SseEvent) []*SseEvent {
+	var events []*SseEvent
+	for event := range ch {
+		events = append(events, event)
+	}
+	return events
+}
+
+func TestStartStreamResumesAfterDrop(t *testing.T) {
+	var mu sync.Mutex
+	var lastEventIDs []string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		lastEventIDs = append(lastEventIDs, r.Header.Get("Last-Event-ID"))
+		attempt := len(lastEventIDs)
+		mu.Unlock()
+
+		if attempt == 1 {
+			dropConnection(t, w, "id:1\nevent:event\ndata:first\n\n")
+			return
+		}
+		w.Header().Set("Content-Type", "text/event-stream")
+		_, _ = w.Write([]byte("id:2\nevent:event\ndata:second\n\nid:3\nevent:completion\ndata:done\n\n"))
+	}))
+	defer server.Close()
+
+	c := New(server.URL).(*client)
+	ch, err := c.startStream(context.Background(), "POST", "/stream", nil, true)
+	require.NoError(t, err)
+
+	events := collectEvents(ch)
+	require.Len(t, events, 3)
+	assert.Equal(t, []byte("first"), events[0].Data)
+	assert.Equal(t, "1", events[0].ID)
+	assert.Equal(t, []byte("second"), events[1].Data)
+	assert.Equal(t, []byte("done"), events[2].Data)
+	assert.Equal(t, []string{"", "1"}, lastEventIDs)
+}
+
+func TestStartStreamDoesNotRetryWithoutEventIDs(t *testing.T) {
+	var mu sync.Mutex
+	attempts := 0
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		attempts++
+		mu.Unlock()
+		dropConnection(t, w, "event:event\ndata:first\n\n")
+	}))
+	defer server.Close()
+
+	c := New(server.URL).(*client)
+	ch, err := c.startStream(context.Background(), "POST", "/stream", nil, true)
+	require.NoError(t, err)
+
+	events := collectEvents(ch)
+	require.Len(t, events, 1)
+	assert.Equal(t, 1, attempts)
+}
+
+func TestStartStreamGivesUpAfterMaxReconnects(t *testing.T) {
+	var mu sync.Mutex
+	attempts := 0
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		attempts++
+		first := attempts == 1
+		mu.Unlock()
+		if first {
+			dropConnection(t, w, "id:1\nevent:event\ndata:first\n\n")
+			return
+		}
+		// Drop every reconnect before sending anything
+		dropConnection(t, w, "")
+	}))
+	defer server.Close()
+
+	c := New(server.URL).(*client)
+	ch, err := c.startStream(context.Background(), "POST", "/stream", nil, true)
+	require.NoError(t, err)
+
+	collectEvents(ch)
+	assert.Equal(t, 1+maxStreamReconnects, attempts)
+}
+
+func TestInvokeSessionStreamRaw(t *testing.T) {
+	var mu sync.Mutex
+	var queries []string
+	var body []byte
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		queries = append(queries, r.URL.RawQuery)
+		body, _ = io.ReadAll(r.Body)
+		mu.Unlock()
+		dropConnection(t, w, "id:1\nevent:event\ndata:first\n\n")
+	}))
+	defer server.Close()
+
+	c := New(server.URL)
+	ch, err := c.InvokeSessionStream(context.Background(), 1, "alice", &InvokeRequest{Task: "hello", Raw: true})
+	require.NoError(t, err)
+
+	events := collectEvents(ch)
+	require.Len(t, events, 1)
+	assert.Equal(t, []string{"user_id=alice"}, queries, "raw streams aren't resumed")
+	assert.Contains(t, string(body), `"raw":true`)
+}
@@ -3,11 +3,16 @@ package client
 import (
 	"context"
 	"fmt"
+	"strings"
 )
 
 func (c *client) ListTeams(userID string) ([]*Team, error) {
+	return c.listTeams(context.Background(), userID)
+}
+
+func (c *client) listTeams(ctx context.Context, userID string) ([]*Team, error) {
 	var teams []*Team
-	err := c.doRequest(context.Background(), "GET", fmt.Sprintf("/teams/?user_id=%s", userID), nil, &teams)
+	err := c.doRequest(ctx, "GET", fmt.Sprintf("/teams/?user_id=%s", userID), nil, &teams)
 	return teams, err
 }
 
@@ -21,6 +26,66 @@ func (c *client) GetTeamByID(teamID int, userID string) (*Team, error) {
 	return team, err
 }
 
+// GetAgent returns the agent with the given ref, either "namespace/name" or a name that
+// is unique across namespaces
+func (c *client) GetAgent(ctx context.Context, ref string, userID string) (*Team, error) {
+	if _, _, err := ParseAgentRef(ref); err != nil {
+		return nil, err
+	}
+	teams, err := c.listTeams(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return FindAgentByRef(teams, ref)
+}
+
+// ParseAgentRef splits an agent ref of the form "namespace/name" or "name".
+// The namespace is empty for the latter.
+func ParseAgentRef(ref string) (namespace string, name string, err error) {
+	parts := strings.Split(ref, "/")
+	switch {
+	case len(parts) == 1 && parts[0] != "":
+		return "", parts[0], nil
+	case len(parts) == 2 && parts[0] != "" && parts[1] != "":
+		return parts[0], parts[1], nil
+	}
+	return "", "", fmt.Errorf("invalid agent ref %q: expected name or namespace/name", ref)
+}
+
+// FindAgentByRef returns the team whose label matches the agent ref. A ref without a
+// namespace must match exactly one team.
+func FindAgentByRef(teams []*Team, ref string) (*Team, error) {
+	namespace, name, err := ParseAgentRef(ref)
+	if err != nil {
+		return nil, err
+	}
+
+	var matches []*Team
+	for _, team := range teams {
+		if team.Component == nil {
+			continue
+		}
+		teamNamespace, teamName, err := ParseAgentRef(team.Component.Label)
+		if err != nil || teamName != name {
+			continue
+		}
+		if namespace == "" || teamNamespace == namespace {
+			matches = append(matches, team)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return nil, NotFoundError
+	case 1:
+		return matches[0], nil
+	}
+	return nil, fmt.Errorf("agent ref %q is ambiguous: %d agents named %s, use namespace/name", ref, len(matches), name)
+}
+
+// GetTeam returns the team with the exact label.
+//
+// Deprecated: use GetAgent, which also accepts a name without a namespace.
 func (c *client) GetTeam(teamLabel string, userID string) (*Team, error) {
 	allTeams, err := c.ListTeams(userID)
 	if err != nil {
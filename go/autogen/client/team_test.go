@@ -0,0 +1,95 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kagent-dev/kagent/go/autogen/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseAgentRef(t *testing.T) {
+	tests := []struct {
+		ref       string
+		namespace string
+		name      string
+		wantErr   bool
+	}{
+		{ref: "k8s-agent", name: "k8s-agent"},
+		{ref: "kagent/k8s-agent", namespace: "kagent", name: "k8s-agent"},
+		{ref: "", wantErr: true},
+		{ref: "kagent/", wantErr: true},
+		{ref: "/k8s-agent", wantErr: true},
+		{ref: "a/b/c", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.ref, func(t *testing.T) {
+			namespace, name, err := ParseAgentRef(tt.ref)
+			if tt.wantErr {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), "expected name or namespace/name")
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.namespace, namespace)
+			assert.Equal(t, tt.name, name)
+		})
+	}
+}
+
+func TestGetAgent(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"status":true,"data":[
+			{"id":1,"component":{"label":"kagent/k8s-agent"}},
+			{"id":2,"component":{"label":"kagent/helm-agent"}},
+			{"id":3,"component":{"label":"other/helm-agent"}}
+		]}`))
+	}))
+	defer server.Close()
+
+	c := New(server.URL)
+	ctx := context.Background()
+
+	t.Run("namespace/name", func(t *testing.T) {
+		team, err := c.GetAgent(ctx, "other/helm-agent", "alice")
+		require.NoError(t, err)
+		assert.Equal(t, 3, team.Id)
+	})
+
+	t.Run("unique name", func(t *testing.T) {
+		team, err := c.GetAgent(ctx, "k8s-agent", "alice")
+		require.NoError(t, err)
+		assert.Equal(t, 1, team.Id)
+	})
+
+	t.Run("ambiguous name", func(t *testing.T) {
+		_, err := c.GetAgent(ctx, "helm-agent", "alice")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "ambiguous")
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		_, err := c.GetAgent(ctx, "kagent/missing", "alice")
+		assert.Equal(t, NotFoundError, err)
+	})
+
+	t.Run("malformed ref", func(t *testing.T) {
+		_, err := c.GetAgent(ctx, "kagent/k8s-agent/extra", "alice")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid agent ref")
+	})
+}
+
+func TestFindAgentByRefSkipsTeamsWithoutComponent(t *testing.T) {
+	teams := []*Team{
+		{BaseObject: BaseObject{Id: 1}},
+		{BaseObject: BaseObject{Id: 2}, Component: &api.Component{Label: "kagent/k8s-agent"}},
+	}
+	team, err := FindAgentByRef(teams, "k8s-agent")
+	require.NoError(t, err)
+	assert.Equal(t, 2, team.Id)
+}
@@ -0,0 +1,61 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// ErrUnauthorized is returned when a request is still rejected with a 401 after the token
+// was refreshed
+var ErrUnauthorized = errors.New("unauthorized: request rejected after refreshing the token")
+
+// WithTokenSource gets the bearer token from source instead of a fixed Token. The token is
+// fetched on the first request and cached. When a request gets a 401, source is called again
+// and the request is retried once with the new token; this retry does not count towards
+// MaxRetries. If the retry is also rejected the request fails with ErrUnauthorized.
+func WithTokenSource(source func(ctx context.Context) (string, error)) Option {
+	return func(c *client) {
+		c.tokens = &tokenSource{source: source}
+	}
+}
+
+type tokenSource struct {
+	source func(ctx context.Context) (string, error)
+
+	mu    sync.Mutex
+	token string
+}
+
+// get returns the cached token, fetching it first if there is none
+func (s *tokenSource) get(ctx context.Context) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.token != "" {
+		return s.token, nil
+	}
+	return s.fetch(ctx)
+}
+
+// refresh replaces the cached token unless it has already changed from stale, in which
+// case a concurrent request refreshed it first
+func (s *tokenSource) refresh(ctx context.Context, stale string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.token != stale && s.token != "" {
+		return s.token, nil
+	}
+	return s.fetch(ctx)
+}
+
+// fetch must be called with mu held
+func (s *tokenSource) fetch(ctx context.Context) (string, error) {
+	token, err := s.source(ctx)
+	if err != nil {
+		return "", err
+	}
+	s.token = token
+	return token, nil
+}
@@ -0,0 +1,112 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTokenSource(t *testing.T) {
+	var mu sync.Mutex
+	validToken := "token-2"
+	var received []string
+	// failures makes the server answer the next requests with a 503
+	failures := 0
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		received = append(received, r.Header.Get("Authorization"))
+		if failures > 0 {
+			failures--
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		if r.Header.Get("Authorization") != "Bearer "+validToken {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_, _ = w.Write([]byte(`{"status":true,"data":{"version":"1.0.0"}}`))
+	}))
+	defer server.Close()
+
+	fetches := 0
+	source := func(ctx context.Context) (string, error) {
+		fetches++
+		return fmt.Sprintf("token-%d", fetches), nil
+	}
+	reset := func(token string, failing int) {
+		mu.Lock()
+		defer mu.Unlock()
+		validToken = token
+		received = nil
+		failures = failing
+	}
+	requests := func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return received
+	}
+
+	c := New(server.URL, WithTokenSource(source), WithRetries(1, time.Millisecond))
+	ctx := context.Background()
+
+	t.Run("refreshes an expired token and retries once", func(t *testing.T) {
+		version, err := c.GetVersion(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, "1.0.0", version)
+		assert.Equal(t, []string{"Bearer token-1", "Bearer token-2"}, requests())
+	})
+
+	t.Run("reuses the refreshed token", func(t *testing.T) {
+		reset("token-2", 0)
+		_, err := c.GetVersion(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"Bearer token-2"}, requests())
+	})
+
+	t.Run("returns ErrUnauthorized if the refreshed token is rejected", func(t *testing.T) {
+		reset("never-valid", 0)
+		_, err := c.GetVersion(ctx)
+		assert.True(t, errors.Is(err, ErrUnauthorized))
+		assert.Equal(t, []string{"Bearer token-2", "Bearer token-3"}, requests())
+	})
+
+	t.Run("the refresh does not use up a retry", func(t *testing.T) {
+		reset("token-4", 1)
+		_, err := c.GetVersion(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"Bearer token-3", "Bearer token-3", "Bearer token-4"}, requests())
+	})
+
+	t.Run("token source errors are returned", func(t *testing.T) {
+		c := New(server.URL, WithTokenSource(func(ctx context.Context) (string, error) {
+			return "", errors.New("no credentials")
+		}))
+		_, err := c.GetVersion(ctx)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "no credentials")
+	})
+}
+
+func TestUnauthorizedWithoutTokenSource(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	_, err := New(server.URL, WithToken("static")).GetVersion(context.Background())
+	require.Error(t, err)
+	assert.False(t, errors.Is(err, ErrUnauthorized))
+	assert.Equal(t, 1, requests)
+}
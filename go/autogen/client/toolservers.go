@@ -41,7 +41,7 @@ func (c *client) GetToolServerByLabel(toolServerLabel, userID string) (*ToolServ
 		}
 	}
 
-	return nil, fmt.Errorf("tool server with label %s not found", toolServerLabel)
+	return nil, fmt.Errorf("tool server with label %s: %w", toolServerLabel, NotFoundError)
 }
 
 func (c *client) DeleteToolServer(serverID *int, userID string) error {
@@ -69,7 +69,7 @@ func (c *client) RefreshToolServer(serverID int, userID string) error {
 	)
 }
 
-// CreateToolServer creates a new server
+// UpdateToolServer updates an existing server and decodes the stored server back into it
 func (c *client) UpdateToolServer(server *ToolServer, userID string) error {
 	return c.doRequest(context.Background(), "PUT", fmt.Sprintf(
 		"/toolservers/%v?user_id=%s",
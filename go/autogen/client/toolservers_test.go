@@ -0,0 +1,78 @@
+package client
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kagent-dev/kagent/go/autogen/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestToolServers(t *testing.T) {
+	var lastMethod, lastPath, lastUserID string
+	var lastBody ToolServer
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lastMethod, lastPath, lastUserID = r.Method, r.URL.Path, r.URL.Query().Get("user_id")
+		switch {
+		case r.Method == "GET" && r.URL.Path == "/toolservers/7":
+			_, _ = w.Write([]byte(`{"status":true,"data":{"id":7,"component":{"label":"kagent/tools"},"last_connected":"2025-01-01T00:00:00"}}`))
+		case r.Method == "PUT" && r.URL.Path == "/toolservers/7":
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&lastBody))
+			_, _ = w.Write([]byte(`{"status":true,"data":{"id":7,"component":{"label":"kagent/tools"},"updated_at":"2025-01-02T00:00:00"}}`))
+		case r.Method == "POST" && r.URL.Path == "/toolservers/7/refresh":
+			_, _ = w.Write([]byte(`{"status":true,"message":"Tools refreshed"}`))
+		case r.Method == "GET" && r.URL.Path == "/toolservers/7/tools":
+			_, _ = w.Write([]byte(`{"status":true,"data":[
+				{"id":1,"server_id":7,"component":{"provider":"autogen_ext.tools.mcp.SseMcpToolAdapter","config":{"tool":{"name":"search"}}}}
+			]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	c := New(server.URL)
+
+	t.Run("get", func(t *testing.T) {
+		toolServer, err := c.GetToolServer(7, "alice")
+		require.NoError(t, err)
+		assert.Equal(t, "alice", lastUserID)
+		assert.Equal(t, 7, toolServer.Id)
+		assert.Equal(t, "kagent/tools", toolServer.Component.Label)
+		assert.Equal(t, "2025-01-01T00:00:00", toolServer.LastConnected)
+	})
+
+	t.Run("update", func(t *testing.T) {
+		toolServer := &ToolServer{Id: 7, Component: api.Component{Label: "kagent/tools", Description: "updated"}}
+		require.NoError(t, c.UpdateToolServer(toolServer, "alice"))
+		assert.Equal(t, "PUT", lastMethod)
+		assert.Equal(t, "updated", lastBody.Component.Description)
+		assert.Equal(t, "2025-01-02T00:00:00", toolServer.UpdatedAt, "the stored server is decoded back")
+	})
+
+	t.Run("refresh", func(t *testing.T) {
+		require.NoError(t, c.RefreshToolServer(7, "alice"))
+		assert.Equal(t, "POST", lastMethod)
+		assert.Equal(t, "/toolservers/7/refresh", lastPath)
+	})
+
+	t.Run("list discovered tools", func(t *testing.T) {
+		serverID := 7
+		tools, err := c.ListToolsForServer(&serverID, "alice")
+		require.NoError(t, err)
+		require.Len(t, tools, 1)
+		require.NotNil(t, tools[0].ServerID)
+		assert.Equal(t, 7, *tools[0].ServerID)
+		assert.Equal(t, "autogen_ext.tools.mcp.SseMcpToolAdapter", tools[0].Component.Provider)
+		assert.Equal(t, map[string]interface{}{"name": "search"}, tools[0].Component.Config["tool"])
+	})
+
+	t.Run("missing server", func(t *testing.T) {
+		_, err := c.GetToolServer(8, "alice")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "404")
+	})
+}
@@ -3,10 +3,12 @@ package client
 import (
 	"bufio"
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
+	"strings"
 
 	"github.com/kagent-dev/kagent/go/autogen/api"
 )
@@ -108,12 +110,36 @@ type SessionRuns struct {
 type Run struct {
 	ID           int           `json:"id"`
 	SessionID    int           `json:"session_id"`
+	UserID       string        `json:"user_id,omitempty"`
 	CreatedAt    string        `json:"created_at"`
 	Status       string        `json:"status"`
 	Task         Task          `json:"task"`
 	TeamResult   TeamResult    `json:"team_result"`
 	Messages     []*RunMessage `json:"messages"`
 	ErrorMessage string        `json:"error_message"`
+	// ParentRunID is the run this run re-runs, if any
+	ParentRunID *int `json:"parent_run_id,omitempty"`
+}
+
+// Run statuses, as stored by Autogen
+const (
+	RunStatusCreated  = "created"
+	RunStatusActive   = "active"
+	RunStatusComplete = "complete"
+	RunStatusError    = "error"
+	RunStatusStopped  = "stopped"
+)
+
+// RerunRequest is the body of run re-runs. An empty Task re-runs the task of the parent run.
+type RerunRequest struct {
+	Task       string         `json:"task,omitempty"`
+	TeamConfig *api.Component `json:"team_config"`
+}
+
+// UpdateRunStatusRequest is the body of run status updates
+type UpdateRunStatusRequest struct {
+	Status       string `json:"status"`
+	ErrorMessage string `json:"error_message,omitempty"`
 }
 
 type Task struct {
@@ -136,6 +162,15 @@ type TaskResult struct {
 }
 
 // APIResponse is the common response wrapper for all API responses
+// VersionResponse is the response of the version endpoint. Features is only set by kagent
+// controllers, and maps each optional feature to whether it's enabled.
+type VersionResponse struct {
+	Version   string          `json:"version"`
+	GitCommit string          `json:"git_commit,omitempty"`
+	BuildDate string          `json:"build_date,omitempty"`
+	Features  map[string]bool `json:"features,omitempty"`
+}
+
 type APIResponse struct {
 	Status  bool        `json:"status"`
 	Message string      `json:"message"`
@@ -168,6 +203,8 @@ type ModelInfo struct {
 }
 
 type SseEvent struct {
+	// ID is the SSE event id, set by servers that support resuming a stream
+	ID    string `json:"id,omitempty"`
 	Event string `json:"event"`
 	Data  []byte `json:"data"`
 }
@@ -176,6 +213,10 @@ type SseEvent struct {
 type InvokeRequest struct {
 	Task       string         `json:"task"`
 	TeamConfig *api.Component `json:"team_config"`
+	// Raw asks the kagent controller to stream the events of Autogen unmodified, with
+	// Autogen's event ids instead of kagent's. It's a controller option: Autogen doesn't
+	// read it, and the controller clears it before forwarding the request to Autogen.
+	Raw bool `json:"raw,omitempty"`
 }
 
 var (
@@ -183,25 +224,43 @@ var (
 )
 
 func streamSseResponse(r io.ReadCloser) chan *SseEvent {
-	scanner := bufio.NewScanner(r)
 	ch := make(chan *SseEvent, 10)
 	go func() {
 		defer close(ch)
 		defer r.Close()
-		currentEvent := &SseEvent{}
-		for scanner.Scan() {
-			line := scanner.Bytes()
-			if bytes.HasPrefix(line, []byte("event:")) {
-				currentEvent.Event = string(bytes.TrimPrefix(line, []byte("event:")))
+		_, _ = readSseEvents(context.Background(), r, ch, "")
+	}()
+	return ch
+}
+
+// readSseEvents reads events from r into ch until r is exhausted or ctx is done.
+// It returns the id of the last event read, or lastEventID if no event carried an id,
+// together with the error that ended the read.
+func readSseEvents(ctx context.Context, r io.Reader, ch chan<- *SseEvent, lastEventID string) (string, error) {
+	scanner := bufio.NewScanner(r)
+	currentEvent := &SseEvent{}
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if bytes.HasPrefix(line, []byte("id:")) {
+			currentEvent.ID = strings.TrimSpace(string(bytes.TrimPrefix(line, []byte("id:"))))
+		}
+		if bytes.HasPrefix(line, []byte("event:")) {
+			currentEvent.Event = string(bytes.TrimPrefix(line, []byte("event:")))
+		}
+		if bytes.HasPrefix(line, []byte("data:")) {
+			currentEvent.Data = bytes.TrimPrefix(line, []byte("data:"))
+			select {
+			case ch <- currentEvent:
+			case <-ctx.Done():
+				return lastEventID, ctx.Err()
 			}
-			if bytes.HasPrefix(line, []byte("data:")) {
-				currentEvent.Data = bytes.TrimPrefix(line, []byte("data:"))
-				ch <- currentEvent
-				currentEvent = &SseEvent{}
+			if currentEvent.ID != "" {
+				lastEventID = currentEvent.ID
 			}
+			currentEvent = &SseEvent{}
 		}
-	}()
-	return ch
+	}
+	return lastEventID, scanner.Err()
 }
 
 // FeedbackIssueType represents the category of feedback issue
@@ -0,0 +1,81 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+const (
+	defaultPollInterval    = 500 * time.Millisecond
+	defaultMaxPollInterval = 10 * time.Second
+)
+
+// ErrRunFailed is returned by WaitForRun when the run ends with status error or stopped
+var ErrRunFailed = errors.New("run failed")
+
+// PollOptions configures how WaitForRun polls
+type PollOptions struct {
+	// Interval is the delay before the second poll. It doubles after each poll, up to
+	// MaxInterval. It defaults to 500ms.
+	Interval time.Duration
+	// MaxInterval defaults to 10s
+	MaxInterval time.Duration
+}
+
+// WaitForRun polls the runs of the session with backoff until the run reaches a terminal
+// status, and returns the final run. A run that ends with status error or stopped is returned
+// with an error wrapping ErrRunFailed. If ctx is done first, the last run seen is returned
+// with the context's error, e.g. context.DeadlineExceeded.
+func WaitForRun(ctx context.Context, c Client, sessionID, runID int, userID string, opts PollOptions) (*Run, error) {
+	interval := opts.Interval
+	if interval <= 0 {
+		interval = defaultPollInterval
+	}
+	maxInterval := opts.MaxInterval
+	if maxInterval <= 0 {
+		maxInterval = defaultMaxPollInterval
+	}
+
+	var last *Run
+	for {
+		run, err := findSessionRun(c, sessionID, runID, userID)
+		if err != nil {
+			return last, err
+		}
+		last = run
+
+		switch run.Status {
+		case RunStatusComplete:
+			return run, nil
+		case RunStatusError, RunStatusStopped:
+			if run.ErrorMessage != "" {
+				return run, fmt.Errorf("%w: run %d is %s: %s", ErrRunFailed, runID, run.Status, run.ErrorMessage)
+			}
+			return run, fmt.Errorf("%w: run %d is %s", ErrRunFailed, runID, run.Status)
+		}
+
+		select {
+		case <-ctx.Done():
+			return last, ctx.Err()
+		case <-time.After(interval):
+		}
+		interval = min(interval*2, maxInterval)
+	}
+}
+
+// findSessionRun returns the run of the session, listing the session's runs so the run is
+// only found if the session belongs to userID
+func findSessionRun(c Client, sessionID, runID int, userID string) (*Run, error) {
+	runs, err := c.ListSessionRuns(sessionID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list runs of session %d: %w", sessionID, err)
+	}
+	for _, run := range runs {
+		if run.ID == runID {
+			return run, nil
+		}
+	}
+	return nil, fmt.Errorf("run %d of session %d: %w", runID, sessionID, NotFoundError)
+}
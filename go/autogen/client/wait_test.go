@@ -0,0 +1,71 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWaitForRun(t *testing.T) {
+	// runsServer serves run 5 of session 3 with the statuses in turn, repeating the last one
+	runsServer := func(t *testing.T, statuses ...string) (*httptest.Server, *int32) {
+		var polls int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			require.Equal(t, "/sessions/3/runs/", r.URL.Path)
+			require.Equal(t, "alice", r.URL.Query().Get("user_id"))
+			i := int(atomic.AddInt32(&polls, 1)) - 1
+			status := statuses[min(i, len(statuses)-1)]
+			fmt.Fprintf(w, `{"status":true,"data":{"runs":[{"id":4,"status":"complete"},{"id":5,"status":%q,"error_message":"model unavailable"}]}}`, status)
+		}))
+		t.Cleanup(server.Close)
+		return server, &polls
+	}
+	opts := PollOptions{Interval: time.Millisecond, MaxInterval: 5 * time.Millisecond}
+
+	t.Run("returns the run once it completes", func(t *testing.T) {
+		server, polls := runsServer(t, RunStatusCreated, RunStatusActive, RunStatusActive, RunStatusComplete)
+
+		run, err := WaitForRun(context.Background(), New(server.URL), 3, 5, "alice", opts)
+		require.NoError(t, err)
+		assert.Equal(t, 5, run.ID)
+		assert.Equal(t, RunStatusComplete, run.Status)
+		assert.Equal(t, int32(4), atomic.LoadInt32(polls))
+	})
+
+	t.Run("reports runs that end with an error", func(t *testing.T) {
+		server, _ := runsServer(t, RunStatusActive, RunStatusError)
+
+		run, err := WaitForRun(context.Background(), New(server.URL), 3, 5, "alice", opts)
+		require.ErrorIs(t, err, ErrRunFailed)
+		assert.Contains(t, err.Error(), "model unavailable")
+		assert.Equal(t, RunStatusError, run.Status)
+		assert.False(t, errors.Is(err, context.DeadlineExceeded))
+	})
+
+	t.Run("returns the last run seen when the context expires", func(t *testing.T) {
+		server, _ := runsServer(t, RunStatusActive)
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		run, err := WaitForRun(ctx, New(server.URL), 3, 5, "alice", opts)
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.False(t, errors.Is(err, ErrRunFailed))
+		require.NotNil(t, run)
+		assert.Equal(t, RunStatusActive, run.Status)
+	})
+
+	t.Run("reports a run missing from the session as not found", func(t *testing.T) {
+		server, _ := runsServer(t, RunStatusActive)
+
+		_, err := WaitForRun(context.Background(), New(server.URL), 3, 6, "alice", opts)
+		assert.ErrorIs(t, err, NotFoundError)
+	})
+}
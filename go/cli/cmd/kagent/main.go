@@ -2,10 +2,13 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
+	"os/signal"
 	"strings"
+	"syscall"
 	"time"
 
 	"github.com/abiosoft/ishell/v2"
@@ -20,29 +23,49 @@ func main() {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
+	cfg := &config.Config{}
+
 	rootCmd := &cobra.Command{
 		Use:   "kagent",
 		Short: "kagent is a CLI for kagent",
-		Long:  `kagent is a CLI for kagent`,
+		Long:  "kagent is a CLI for kagent\n\n" + cli.ExitCodesHelp,
+		// Arguments are checked before this runs, so their errors still show the usage
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+			resolved, err := config.Get()
+			if err != nil {
+				return err
+			}
+			if err := resolved.Validate(); err != nil {
+				return err
+			}
+			if requiresUserID(cmd) {
+				if err := resolved.RequireUserID(); err != nil {
+					return err
+				}
+			}
+			*cfg = *resolved
+			return nil
+		},
 		Run: func(cmd *cobra.Command, args []string) {
 			runInteractive()
 		},
 	}
 
-	cfg := &config.Config{}
-
 	rootCmd.PersistentFlags().StringVar(&cfg.APIURL, "api-url", "http://localhost:8081/api", "API URL")
 	rootCmd.PersistentFlags().StringVar(&cfg.UserID, "user-id", "admin@kagent.dev", "User ID")
 	rootCmd.PersistentFlags().StringVarP(&cfg.Namespace, "namespace", "n", "kagent", "Namespace")
 	rootCmd.PersistentFlags().StringVar(&cfg.A2AURL, "a2a-url", "http://localhost:8083/api/a2a", "A2A URL")
+	rootCmd.PersistentFlags().StringVar(&cfg.ControllerURL, "controller-url", cli.DefaultControllerURL, "Controller API URL")
 	rootCmd.PersistentFlags().StringVarP(&cfg.OutputFormat, "output-format", "o", "table", "Output format")
 	rootCmd.PersistentFlags().BoolVarP(&cfg.Verbose, "verbose", "v", false, "Verbose output")
+	rootCmd.PersistentFlags().String("context", "", "Context to use instead of the current one")
 	installCmd := &cobra.Command{
 		Use:   "install",
 		Short: "Install kagent",
 		Long:  `Install kagent`,
-		Run: func(cmd *cobra.Command, args []string) {
-			cli.InstallCmd(cmd.Context(), cfg)
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cli.InstallCmd(cmd.Context(), cfg)
 		},
 	}
 
@@ -50,8 +73,8 @@ func main() {
 		Use:   "uninstall",
 		Short: "Uninstall kagent",
 		Long:  `Uninstall kagent`,
-		Run: func(cmd *cobra.Command, args []string) {
-			cli.UninstallCmd(cmd.Context(), cfg)
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cli.UninstallCmd(cmd.Context(), cfg)
 		},
 	}
 
@@ -60,31 +83,32 @@ func main() {
 	}
 
 	invokeCmd := &cobra.Command{
-		Use:   "invoke",
-		Short: "Invoke a kagent agent",
-		Long:  `Invoke a kagent agent`,
-		Run: func(cmd *cobra.Command, args []string) {
-			cli.InvokeCmd(cmd.Context(), invokeCfg)
+		Use:         "invoke",
+		Short:       "Invoke a kagent agent",
+		Long:        `Invoke a kagent agent`,
+		Annotations: map[string]string{requiresUserIDAnnotation: "true"},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cli.InvokeCmd(cmd.Context(), invokeCfg)
 		},
 	}
 
 	invokeCmd.Flags().StringVarP(&invokeCfg.Task, "task", "t", "", "Task")
 	invokeCmd.Flags().StringVarP(&invokeCfg.Session, "session", "s", "", "Session")
 	invokeCmd.Flags().StringVarP(&invokeCfg.Agent, "agent", "a", "", "Agent")
-	invokeCmd.Flags().BoolVarP(&invokeCfg.Stream, "stream", "S", false, "Stream the response")
+	invokeCmd.Flags().BoolVarP(&invokeCfg.Stream, "stream", "S", false, "Stream the response. Defaults to streaming if the agent card advertises it")
 	invokeCmd.MarkFlagRequired("task")
 
 	bugReportCmd := &cobra.Command{
 		Use:   "bug-report",
 		Short: "Generate a bug report",
 		Long:  `Generate a bug report`,
-		Run: func(cmd *cobra.Command, args []string) {
+		RunE: func(cmd *cobra.Command, args []string) error {
 			client := autogen_client.New(cfg.APIURL)
 			if err := cli.CheckServerConnection(client); err != nil {
 				pf := cli.NewPortForward(ctx, cfg)
 				defer pf.Stop()
 			}
-			cli.BugReportCmd(cfg)
+			return cli.BugReportCmd(cfg)
 		},
 	}
 
@@ -92,13 +116,13 @@ func main() {
 		Use:   "version",
 		Short: "Print the kagent version",
 		Long:  `Print the kagent version`,
-		Run: func(cmd *cobra.Command, args []string) {
+		RunE: func(cmd *cobra.Command, args []string) error {
 			client := autogen_client.New(cfg.APIURL)
 			if err := cli.CheckServerConnection(client); err != nil {
 				pf := cli.NewPortForward(ctx, cfg)
 				defer pf.Stop()
 			}
-			cli.VersionCmd(cfg)
+			return cli.VersionCmd(cfg)
 		},
 	}
 
@@ -106,8 +130,8 @@ func main() {
 		Use:   "dashboard",
 		Short: "Open the kagent dashboard",
 		Long:  `Open the kagent dashboard`,
-		Run: func(cmd *cobra.Command, args []string) {
-			cli.DashboardCmd(ctx, cfg)
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cli.DashboardCmd(ctx, cfg)
 		},
 	}
 
@@ -120,6 +144,7 @@ func main() {
 		Short: "Interact with an Agent over the A2A protocol",
 		Long:  `Interact with an Agent over the A2A protocol`,
 		Run: func(cmd *cobra.Command, args []string) {
+			a2aCfg.AutoStream = !cmd.Flags().Changed("stream")
 			cli.A2ARun(ctx, a2aCfg)
 		},
 	}
@@ -128,12 +153,15 @@ func main() {
 	a2aCmd.Flags().StringVarP(&a2aCfg.AgentName, "agent-name", "a", "", "Agent Name")
 	a2aCmd.Flags().StringVarP(&a2aCfg.Task, "task", "t", "", "Task")
 	a2aCmd.Flags().DurationVarP(&a2aCfg.Timeout, "timeout", "T", 300*time.Second, "Timeout")
-	a2aCmd.Flags().BoolVarP(&a2aCfg.Stream, "stream", "S", false, "Stream the response")
+	a2aCmd.Flags().BoolVarP(&a2aCfg.Stream, "stream", "S", false, "Stream the response. Defaults to streaming if the agent card advertises it")
+	a2aCmd.Flags().StringArrayVarP(&a2aCfg.Files, "file", "f", nil, "File to attach to the message (can be repeated)")
+	a2aCmd.Flags().Int64Var(&a2aCfg.MaxFileSize, "max-file-size", cli.DefaultMaxA2AFileSize, "Maximum size in bytes of each attached file")
 
 	getCmd := &cobra.Command{
-		Use:   "get",
-		Short: "Get a kagent resource",
-		Long:  `Get a kagent resource`,
+		Use:         "get",
+		Short:       "Get a kagent resource",
+		Long:        `Get a kagent resource`,
+		Annotations: map[string]string{requiresUserIDAnnotation: "true"},
 		Run: func(cmd *cobra.Command, args []string) {
 			fmt.Fprintf(os.Stderr, "No resource type provided\n\n")
 			cmd.Help()
@@ -141,11 +169,14 @@ func main() {
 		},
 	}
 
+	var getWatch bool
+	var getWatchInterval time.Duration
+
 	getSessionCmd := &cobra.Command{
 		Use:   "session [session_id]",
 		Short: "Get a session or list all sessions",
 		Long:  `Get a session by ID or list all sessions`,
-		Run: func(cmd *cobra.Command, args []string) {
+		RunE: func(cmd *cobra.Command, args []string) error {
 			client := autogen_client.New(cfg.APIURL)
 			if err := cli.CheckServerConnection(client); err != nil {
 				pf := cli.NewPortForward(ctx, cfg)
@@ -155,7 +186,13 @@ func main() {
 			if len(args) > 0 {
 				resourceName = args[0]
 			}
-			cli.GetSessionCmd(cfg, resourceName)
+			if getWatch && resourceName == "" {
+				watchCtx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+				defer stop()
+				cli.WatchSessionsCmd(watchCtx, cfg, getWatchInterval)
+				return nil
+			}
+			return cli.GetSessionCmd(cfg, resourceName)
 		},
 	}
 
@@ -163,7 +200,7 @@ func main() {
 		Use:   "run [run_id]",
 		Short: "Get a run or list all runs",
 		Long:  `Get a run by ID or list all runs`,
-		Run: func(cmd *cobra.Command, args []string) {
+		RunE: func(cmd *cobra.Command, args []string) error {
 			client := autogen_client.New(cfg.APIURL)
 			if err := cli.CheckServerConnection(client); err != nil {
 				pf := cli.NewPortForward(ctx, cfg)
@@ -173,7 +210,7 @@ func main() {
 			if len(args) > 0 {
 				resourceName = args[0]
 			}
-			cli.GetRunCmd(cfg, resourceName)
+			return cli.GetRunCmd(cfg, resourceName)
 		},
 	}
 
@@ -181,7 +218,7 @@ func main() {
 		Use:   "agent [agent_name]",
 		Short: "Get an agent or list all agents",
 		Long:  `Get an agent by name or list all agents`,
-		Run: func(cmd *cobra.Command, args []string) {
+		RunE: func(cmd *cobra.Command, args []string) error {
 			client := autogen_client.New(cfg.APIURL)
 			if err := cli.CheckServerConnection(client); err != nil {
 				pf := cli.NewPortForward(ctx, cfg)
@@ -191,7 +228,13 @@ func main() {
 			if len(args) > 0 {
 				resourceName = args[0]
 			}
-			cli.GetAgentCmd(cfg, resourceName)
+			if getWatch && resourceName == "" {
+				watchCtx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+				defer stop()
+				cli.WatchAgentsCmd(watchCtx, cfg, getWatchInterval)
+				return nil
+			}
+			return cli.GetAgentCmd(cfg, resourceName)
 		},
 	}
 
@@ -199,40 +242,310 @@ func main() {
 		Use:   "tool",
 		Short: "Get tools",
 		Long:  `List all available tools`,
-		Run: func(cmd *cobra.Command, args []string) {
+		RunE: func(cmd *cobra.Command, args []string) error {
 			client := autogen_client.New(cfg.APIURL)
 			if err := cli.CheckServerConnection(client); err != nil {
 				pf := cli.NewPortForward(ctx, cfg)
 				defer pf.Stop()
 			}
-			cli.GetToolCmd(cfg)
+			return cli.GetToolCmd(cfg)
 		},
 	}
 
+	for _, cmd := range []*cobra.Command{getSessionCmd, getAgentCmd} {
+		cmd.Flags().BoolVarP(&getWatch, "watch", "w", false, "Watch the list and redraw it when it changes, until interrupted")
+		cmd.Flags().DurationVar(&getWatchInterval, "watch-interval", cli.DefaultWatchInterval, "How often to poll for changes with --watch")
+	}
+
 	getCmd.AddCommand(getSessionCmd, getRunCmd, getAgentCmd, getToolCmd)
 
-	rootCmd.AddCommand(installCmd, uninstallCmd, invokeCmd, bugReportCmd, versionCmd, dashboardCmd, getCmd, a2aCmd)
+	modelConfigCmd := &cobra.Command{
+		Use:   "modelconfig",
+		Short: "Manage model configs",
+		Long:  `Create, list, get and delete the model configs agents use to talk to their model`,
+		Run: func(cmd *cobra.Command, args []string) {
+			fmt.Fprintf(os.Stderr, "No subcommand provided\n\n")
+			cmd.Help()
+			os.Exit(1)
+		},
+	}
+
+	modelConfigCreateCfg := &cli.ModelConfigCreateCfg{
+		Config: cfg,
+	}
+
+	modelConfigCreateCmd := &cobra.Command{
+		Use:   "create [name]",
+		Short: "Create a model config",
+		Long:  `Create a model config. The provider, model and API key are prompted for if not set with flags, without echoing the API key.`,
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			modelConfigCreateCfg.Name = args[0]
+			return cli.ModelConfigCreateCmd(cmd.Context(), modelConfigCreateCfg)
+		},
+	}
+
+	modelConfigCreateCmd.Flags().StringVar(&modelConfigCreateCfg.Provider, "provider", "", "Provider: OpenAI, Anthropic, AzureOpenAI or Ollama")
+	modelConfigCreateCmd.Flags().StringVar(&modelConfigCreateCfg.Model, "model", "", "Model")
+	modelConfigCreateCmd.Flags().StringVar(&modelConfigCreateCfg.APIKey, "api-key", "", "API key, stored in a secret. Prompted for if not set.")
+	modelConfigCreateCmd.Flags().StringVar(&modelConfigCreateCfg.AzureEndpoint, "azure-endpoint", "", "Azure OpenAI endpoint")
+	modelConfigCreateCmd.Flags().StringVar(&modelConfigCreateCfg.AzureAPIVersion, "azure-api-version", "", "Azure OpenAI API version")
+	modelConfigCreateCmd.Flags().StringVar(&modelConfigCreateCfg.OllamaHost, "ollama-host", "", "Ollama host")
+
+	modelConfigListCmd := &cobra.Command{
+		Use:   "list",
+		Short: "List model configs",
+		Long:  `List all model configs`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cli.ModelConfigListCmd(cmd.Context(), cfg)
+		},
+	}
+
+	modelConfigGetCmd := &cobra.Command{
+		Use:   "get [name]",
+		Short: "Get a model config",
+		Long:  `Get a model config by name, optionally prefixed with its namespace`,
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cli.ModelConfigGetCmd(cmd.Context(), cfg, args[0])
+		},
+	}
+
+	modelConfigDeleteCmd := &cobra.Command{
+		Use:   "delete [name]",
+		Short: "Delete a model config",
+		Long:  `Delete a model config by name, optionally prefixed with its namespace`,
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cli.ModelConfigDeleteCmd(cmd.Context(), cfg, args[0])
+		},
+	}
+
+	modelConfigCmd.AddCommand(modelConfigCreateCmd, modelConfigListCmd, modelConfigGetCmd, modelConfigDeleteCmd)
+
+	patchCmd := &cobra.Command{
+		Use:   "patch",
+		Short: "Patch a resource",
+		Long:  `Patch a resource with a JSON merge patch`,
+		Run: func(cmd *cobra.Command, args []string) {
+			fmt.Fprintf(os.Stderr, "No subcommand provided\n\n")
+			cmd.Help()
+			os.Exit(1)
+		},
+	}
+
+	patchAgentCfg := &cli.PatchAgentCfg{
+		Config: cfg,
+	}
+
+	patchAgentCmd := &cobra.Command{
+		Use:   "agent [name]",
+		Short: "Patch an agent",
+		Long:  `Patch an agent by name, optionally prefixed with its namespace. Fields absent from the patch are kept and fields set to null are cleared.`,
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			patchAgentCfg.Name = args[0]
+			return cli.PatchAgentCmd(cmd.Context(), patchAgentCfg)
+		},
+	}
+
+	patchAgentCmd.Flags().StringVarP(&patchAgentCfg.Patch, "patch", "p", "", `JSON merge patch of the Agent resource, e.g. {"spec":{"description":"..."}}`)
+	patchAgentCmd.MarkFlagRequired("patch")
+
+	patchCmd.AddCommand(patchAgentCmd)
+
+	toolServerCmd := &cobra.Command{
+		Use:         "toolserver",
+		Short:       "Manage tool servers",
+		Long:        `Create, list, delete and refresh the MCP tool servers agents get their tools from`,
+		Annotations: map[string]string{requiresUserIDAnnotation: "true"},
+		Run: func(cmd *cobra.Command, args []string) {
+			fmt.Fprintf(os.Stderr, "No subcommand provided\n\n")
+			cmd.Help()
+			os.Exit(1)
+		},
+	}
+
+	toolServerCreateCfg := &cli.ToolServerCreateCfg{
+		Config: cfg,
+	}
+
+	toolServerCreateCmd := &cobra.Command{
+		Use:   "create [name]",
+		Short: "Create a tool server",
+		Long: `Create a tool server from a ToolServer manifest with --file, or from flags: --url for an sse or streamable-http server, --command and --args for a stdio one.
+
+Waits for the tools of the server to be discovered and reports them, unless --wait is 0.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) > 0 {
+				toolServerCreateCfg.Name = args[0]
+			}
+			return cli.ToolServerCreateCmd(cmd.Context(), toolServerCreateCfg)
+		},
+	}
+
+	toolServerCreateCmd.Flags().StringVarP(&toolServerCreateCfg.File, "file", "f", "", "ToolServer manifest, in YAML or JSON")
+	toolServerCreateCmd.Flags().StringVar(&toolServerCreateCfg.Description, "description", "", "Description of the tool server")
+	toolServerCreateCmd.Flags().StringVar(&toolServerCreateCfg.URL, "url", "", "URL of a remote MCP server")
+	toolServerCreateCmd.Flags().StringVar(&toolServerCreateCfg.Transport, "transport", "sse", "Transport of the remote MCP server: sse or streamable-http")
+	toolServerCreateCmd.Flags().StringVar(&toolServerCreateCfg.Command, "command", "", "Command that starts a stdio MCP server")
+	toolServerCreateCmd.Flags().StringSliceVar(&toolServerCreateCfg.Args, "args", nil, "Arguments of the command")
+	toolServerCreateCmd.Flags().DurationVar(&toolServerCreateCfg.Wait, "wait", cli.DefaultToolServerWait, "How long to wait for the tools to be discovered")
+
+	toolServerListCmd := &cobra.Command{
+		Use:   "list",
+		Short: "List tool servers",
+		Long:  `List all tool servers and the number of tools discovered on them`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cli.ToolServerListCmd(cmd.Context(), cfg)
+		},
+	}
+
+	toolServerDeleteCmd := &cobra.Command{
+		Use:   "delete [name]",
+		Short: "Delete a tool server",
+		Long:  `Delete a tool server by name, optionally prefixed with its namespace`,
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cli.ToolServerDeleteCmd(cmd.Context(), cfg, args[0])
+		},
+	}
+
+	toolServerRefreshCmd := &cobra.Command{
+		Use:   "refresh [name]",
+		Short: "Refresh the tools of a tool server",
+		Long:  `Discover the tools of a tool server again, by name optionally prefixed with its namespace`,
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client := autogen_client.New(cfg.APIURL)
+			if err := cli.CheckServerConnection(client); err != nil {
+				pf := cli.NewPortForward(ctx, cfg)
+				defer pf.Stop()
+			}
+			return cli.ToolServerRefreshCmd(cfg, args[0])
+		},
+	}
+
+	toolServerCmd.AddCommand(toolServerCreateCmd, toolServerListCmd, toolServerDeleteCmd, toolServerRefreshCmd)
+
+	configCmd := &cobra.Command{
+		Use:   "config",
+		Short: "Manage the contexts of the CLI",
+		Long: `Manage the contexts of the CLI. A context is a named set of server settings: the API URL, user ID, namespace, A2A URL and controller URL.
+
+The settings of the current context, or of the one set with --context, are used when the flags aren't set, like kubeconfig contexts.`,
+		// The contexts are managed without resolving the current one, which might have been deleted
+		PersistentPreRun: func(cmd *cobra.Command, args []string) {
+			cmd.SilenceUsage = true
+		},
+		Run: func(cmd *cobra.Command, args []string) {
+			fmt.Fprintf(os.Stderr, "No subcommand provided\n\n")
+			cmd.Help()
+			os.Exit(1)
+		},
+	}
+
+	configSetContextCmd := &cobra.Command{
+		Use:   "set-context [name]",
+		Short: "Create or update a context",
+		Long: `Create a context, or update an existing one, with the server settings set with flags. Example:
+
+  kagent config set-context staging --api-url https://kagent.staging.example.com/api --user-id alice@example.com`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cli.ConfigSetContextCmd(cmd.Flags(), args[0])
+		},
+	}
+
+	configUseContextCmd := &cobra.Command{
+		Use:   "use-context [name]",
+		Short: "Set the current context",
+		Long:  `Set the context used by the commands when --context isn't set`,
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cli.ConfigUseContextCmd(args[0])
+		},
+	}
+
+	configGetContextsCmd := &cobra.Command{
+		Use:   "get-contexts",
+		Short: "List contexts",
+		Long:  `List all contexts, marking the one in use`,
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cli.ConfigGetContextsCmd()
+		},
+	}
+
+	configCurrentContextCmd := &cobra.Command{
+		Use:   "current-context",
+		Short: "Show the current context",
+		Long:  `Show the name of the context in use`,
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cli.ConfigCurrentContextCmd()
+		},
+	}
+
+	configDeleteContextCmd := &cobra.Command{
+		Use:   "delete-context [name]",
+		Short: "Delete a context",
+		Long:  `Delete a context. The current context is unset if it's the one deleted.`,
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cli.ConfigDeleteContextCmd(args[0])
+		},
+	}
+
+	configCmd.AddCommand(configSetContextCmd, configUseContextCmd, configGetContextsCmd, configCurrentContextCmd, configDeleteContextCmd)
+
+	rootCmd.AddCommand(installCmd, uninstallCmd, invokeCmd, bugReportCmd, versionCmd, dashboardCmd, getCmd, a2aCmd, modelConfigCmd, patchCmd, toolServerCmd, configCmd)
 
 	// Initialize config
 	if err := config.Init(); err != nil {
 		fmt.Fprintf(os.Stderr, "Error initializing config: %v\n", err)
 		os.Exit(1)
 	}
+	if err := config.BindFlags(rootCmd.PersistentFlags()); err != nil {
+		fmt.Fprintf(os.Stderr, "Error initializing config: %v\n", err)
+		os.Exit(1)
+	}
 
 	if err := rootCmd.ExecuteContext(ctx); err != nil {
-		os.Exit(1)
+		os.Exit(cli.ExitCode(err))
 	}
 
 }
 
+// requiresUserIDAnnotation marks the commands that act on the resources of a user, and so
+// fail without a user ID. The annotation applies to the subcommands too.
+const requiresUserIDAnnotation = "kagent.dev/requires-user-id"
+
+func requiresUserID(cmd *cobra.Command) bool {
+	for ; cmd != nil; cmd = cmd.Parent() {
+		if cmd.Annotations[requiresUserIDAnnotation] == "true" {
+			return true
+		}
+	}
+	return false
+}
+
 func runInteractive() {
 	cfg, err := config.Get()
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error getting config: %v\n", err)
 		os.Exit(1)
 	}
+	// Most of the shell's commands, like chat, act on the resources of the user
+	if err := errors.Join(cfg.Validate(), cfg.RequireUserID()); err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid config: %v\n", err)
+		os.Exit(1)
+	}
 
-	client := autogen_client.New(cfg.APIURL)
+	// The interactive shell keeps its client for the whole session, so cache the model list
+	// it fetches repeatedly
+	client := autogen_client.New(cfg.APIURL, autogen_client.WithResponseCache(5*time.Minute))
 	ctx, cancel := context.WithCancel(context.Background())
 	cmd := exec.CommandContext(ctx, "kubectl", "-n", "kagent", "port-forward", "service/kagent", "8081:8081")
 	// Error connecting to server, port-forward the server
@@ -325,15 +638,17 @@ Examples:
 The task is sent to the agent, and the result is printed to the console.
 
 Example:
-a2a run [--namespace <agent-namespace>] <agent-name> <task>
+a2a run [--namespace <agent-namespace>] [--file <path>]... <agent-name> <task>
 `,
 		Func: func(c *ishell.Context) {
 			if len(c.RawArgs) < 4 {
-				c.Println("Usage: a2a run [--namespace <agent-namespace>] <agent-name> <task>")
+				c.Println("Usage: a2a run [--namespace <agent-namespace>] [--file <path>]... <agent-name> <task>")
 				return
 			}
 			flagSet := pflag.NewFlagSet(c.RawArgs[0], pflag.ContinueOnError)
 			timeout := flagSet.Duration("timeout", 300*time.Second, "Timeout for the task")
+			files := flagSet.StringArray("file", nil, "File to attach to the message (can be repeated)")
+			maxFileSize := flagSet.Int64("max-file-size", cli.DefaultMaxA2AFileSize, "Maximum size in bytes of each attached file")
 			if err := flagSet.Parse(c.Args); err != nil {
 				c.Printf("Failed to parse flags: %v\n", err)
 				return
@@ -341,10 +656,13 @@ a2a run [--namespace <agent-namespace>] <agent-name> <task>
 			agentName := flagSet.Arg(0)
 			prompt := flagSet.Arg(1)
 			cli.A2ARun(ctx, &cli.A2ACfg{
-				Config:    cfg,
-				AgentName: agentName,
-				Task:      prompt,
-				Timeout:   *timeout,
+				Config:      cfg,
+				AgentName:   agentName,
+				Task:        prompt,
+				Timeout:     *timeout,
+				Files:       *files,
+				MaxFileSize: *maxFileSize,
+				AutoStream:  true,
 			})
 		},
 	})
@@ -383,9 +701,13 @@ Examples:
 			}
 			cfg := config.GetCfg(c)
 			if len(c.Args) > 0 {
-				cli.GetSessionCmd(cfg, c.Args[0])
+				if err := cli.GetSessionCmd(cfg, c.Args[0]); err != nil {
+					c.Println(err)
+				}
 			} else {
-				cli.GetSessionCmd(cfg, "")
+				if err := cli.GetSessionCmd(cfg, ""); err != nil {
+					c.Println(err)
+				}
 			}
 		},
 	})
@@ -408,9 +730,13 @@ Examples:
 			}
 			cfg := config.GetCfg(c)
 			if len(c.Args) > 0 {
-				cli.GetRunCmd(cfg, c.Args[0])
+				if err := cli.GetRunCmd(cfg, c.Args[0]); err != nil {
+					c.Println(err)
+				}
 			} else {
-				cli.GetRunCmd(cfg, "")
+				if err := cli.GetRunCmd(cfg, ""); err != nil {
+					c.Println(err)
+				}
 			}
 		},
 	})
@@ -433,9 +759,13 @@ Examples:
 			}
 			cfg := config.GetCfg(c)
 			if len(c.Args) > 0 {
-				cli.GetAgentCmd(cfg, c.Args[0])
+				if err := cli.GetAgentCmd(cfg, c.Args[0]); err != nil {
+					c.Println(err)
+				}
 			} else {
-				cli.GetAgentCmd(cfg, "")
+				if err := cli.GetAgentCmd(cfg, ""); err != nil {
+					c.Println(err)
+				}
 			}
 		},
 	})
@@ -457,7 +787,9 @@ Examples:
 				return
 			}
 			cfg := config.GetCfg(c)
-			cli.GetToolCmd(cfg)
+			if err := cli.GetToolCmd(cfg); err != nil {
+				c.Println(err)
+			}
 		},
 	})
 
@@ -484,7 +816,9 @@ Example:
 				return
 			}
 			cfg := config.GetCfg(c)
-			cli.BugReportCmd(cfg)
+			if err := cli.BugReportCmd(cfg); err != nil {
+				c.Println(err)
+			}
 		},
 	}
 
@@ -518,7 +852,9 @@ Example:
 		Aliases: []string{"v"},
 		Help:    "Print the kagent version.",
 		Func: func(c *ishell.Context) {
-			cli.VersionCmd(cfg)
+			if err := cli.VersionCmd(cfg); err != nil {
+				c.Println(err)
+			}
 			c.SetPrompt(config.BoldBlue("kagent >> "))
 		},
 	})
@@ -529,7 +865,9 @@ Example:
 		Help:    "Install kagent.",
 		Func: func(c *ishell.Context) {
 			cfg := config.GetCfg(c)
-			cli.InstallCmd(ctx, cfg)
+			if err := cli.InstallCmd(ctx, cfg); err != nil {
+				c.Println(err)
+			}
 		},
 	})
 
@@ -543,7 +881,9 @@ Example:
 				return
 			}
 			cfg := config.GetCfg(c)
-			cli.UninstallCmd(ctx, cfg)
+			if err := cli.UninstallCmd(ctx, cfg); err != nil {
+				c.Println(err)
+			}
 		},
 	})
 
@@ -557,7 +897,9 @@ Example:
 				return
 			}
 			cfg := config.GetCfg(c)
-			cli.DashboardCmd(ctx, cfg)
+			if err := cli.DashboardCmd(ctx, cfg); err != nil {
+				c.Println(err)
+			}
 		},
 	})
 
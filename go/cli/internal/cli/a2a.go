@@ -2,17 +2,30 @@ package cli
 
 import (
 	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"mime"
+	"net"
+	"net/http"
 	"os"
 	"os/exec"
+	"path/filepath"
+	"regexp"
 	"strings"
 	"time"
 
 	"github.com/kagent-dev/kagent/go/cli/internal/config"
+	"github.com/kagent-dev/kagent/go/controller/utils/a2autils"
 	"trpc.group/trpc-go/trpc-a2a-go/client"
 	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+	"trpc.group/trpc-go/trpc-a2a-go/server"
 )
 
+// DefaultMaxA2AFileSize is the default size limit for files attached to an A2A message
+const DefaultMaxA2AFileSize int64 = 10 * 1024 * 1024
+
 type A2ACfg struct {
 	SessionID string
 	AgentName string
@@ -20,11 +33,22 @@ type A2ACfg struct {
 	Timeout   time.Duration
 	Config    *config.Config
 	Stream    bool
+	// AutoStream streams if the agent card advertises streaming and sends the message
+	// otherwise, ignoring Stream. Stream is used if the card can't be fetched.
+	AutoStream bool
+	// Files are attached to the message as file parts
+	Files []string
+	// MaxFileSize is the maximum size in bytes of each attached file
+	MaxFileSize int64
 }
 
 func A2ARun(ctx context.Context, cfg *A2ACfg) {
 
-	cancel := startPortForward(ctx)
+	cancel, err := startPortForward(ctx)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		return
+	}
 	defer cancel()
 
 	var sessionID *string
@@ -32,14 +56,30 @@ func A2ARun(ctx context.Context, cfg *A2ACfg) {
 		sessionID = &cfg.SessionID
 	}
 
-	if !cfg.Stream {
-		err := runTask(ctx, cfg.Config.Namespace, cfg.AgentName, cfg.Task, sessionID, cfg.Timeout, cfg.Config)
+	parts, err := buildMessageParts(cfg.Task, cfg.Files, cfg.MaxFileSize)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading files: %v\n", err)
+		return
+	}
+
+	stream := cfg.Stream
+	if cfg.AutoStream {
+		streaming, err := agentStreams(ctx, a2autils.AgentURL(cfg.Config.A2AURL, cfg.Config.Namespace, cfg.AgentName))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error fetching the agent card, streaming is %v: %v\n", stream, err)
+		} else {
+			stream = streaming
+		}
+	}
+
+	if !stream {
+		err := runTask(ctx, cfg.Config.Namespace, cfg.AgentName, parts, sessionID, cfg.Timeout, cfg.Config)
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Error running task: %v\n", err)
 			return
 		}
 	} else {
-		if err := runTaskStream(ctx, cfg.Config.Namespace, cfg.AgentName, cfg.Task, sessionID, cfg.Timeout, cfg.Config); err != nil {
+		if err := runTaskStream(ctx, cfg.Config.Namespace, cfg.AgentName, parts, sessionID, cfg.Timeout, cfg.Config); err != nil {
 			fmt.Fprintf(os.Stderr, "Error running task: %v\n", err)
 			return
 		}
@@ -47,40 +87,116 @@ func A2ARun(ctx context.Context, cfg *A2ACfg) {
 
 }
 
-func startPortForward(ctx context.Context) func() {
+// startPortForward port-forwards the controller service with kubectl. The returned function
+// stops the port-forward.
+func startPortForward(ctx context.Context) (func(), error) {
 	ctx, cancel := context.WithCancel(ctx)
 	a2aPortFwdCmd := exec.CommandContext(ctx, "kubectl", "-n", "kagent", "port-forward", "service/kagent", "8083:8083")
-	// Error connecting to server, port-forward the server
-	go func() {
-		if err := a2aPortFwdCmd.Start(); err != nil {
-			fmt.Fprintf(os.Stderr, "Error starting port-forward: %v\n", err)
-			os.Exit(1)
-		}
-	}()
+	if err := a2aPortFwdCmd.Start(); err != nil {
+		cancel()
+		return nil, &ExitError{Code: ExitCodeConnectionError, Err: fmt.Errorf("error starting port-forward: %w", err)}
+	}
 
 	// Ensure the context is cancelled when the shell is closed
 	return func() {
 		cancel()
 		if err := a2aPortFwdCmd.Wait(); err != nil {
-			// These 2 errors are expected
-			if !strings.Contains(err.Error(), "signal: killed") && !strings.Contains(err.Error(), "exec: not started") {
+			// This error is expected
+			if !strings.Contains(err.Error(), "signal: killed") {
 				fmt.Fprintf(os.Stderr, "Error waiting for port-forward to exit: %v\n", err)
 			}
 		}
+	}, nil
+}
+
+// buildMessageParts returns the text part for the prompt followed by a file part for each file.
+// Files larger than maxFileSize are rejected; a maxFileSize of 0 or less uses DefaultMaxA2AFileSize.
+func buildMessageParts(prompt string, files []string, maxFileSize int64) ([]protocol.Part, error) {
+	if maxFileSize <= 0 {
+		maxFileSize = DefaultMaxA2AFileSize
+	}
+
+	parts := []protocol.Part{protocol.NewTextPart(prompt)}
+	for _, path := range files {
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, err
+		}
+		if info.IsDir() {
+			return nil, fmt.Errorf("%s is a directory", path)
+		}
+		if info.Size() > maxFileSize {
+			return nil, fmt.Errorf("%s is %d bytes, which exceeds the maximum file size of %d bytes", path, info.Size(), maxFileSize)
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+
+		parts = append(parts, protocol.NewFilePartWithBytes(
+			filepath.Base(path),
+			detectMimeType(path, data),
+			base64.StdEncoding.EncodeToString(data),
+		))
+	}
+	return parts, nil
+}
+
+// detectMimeType guesses the MIME type from the file extension, falling back to sniffing the content
+func detectMimeType(path string, data []byte) string {
+	if mimeType := mime.TypeByExtension(filepath.Ext(path)); mimeType != "" {
+		return mimeType
+	}
+	return http.DetectContentType(data)
+}
+
+// a2aSendAttempts and a2aSendBackoff bound the retries of runTask when the message couldn't
+// be delivered
+const (
+	a2aSendAttempts = 3
+	a2aSendBackoff  = time.Second
+)
+
+// a2aTaskPollInterval is how often runTask checks on a task that was still running when the
+// message was answered
+const a2aTaskPollInterval = time.Second
+
+// agentStreams fetches the agent card and reports whether the agent advertises streaming
+func agentStreams(ctx context.Context, agentURL string) (bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimSuffix(agentURL, "/")+protocol.AgentCardPath, nil)
+	if err != nil {
+		return false, err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return false, fmt.Errorf("agent card request failed with status %s", resp.Status)
 	}
+
+	var card server.AgentCard
+	if err := json.NewDecoder(resp.Body).Decode(&card); err != nil {
+		return false, fmt.Errorf("invalid agent card: %w", err)
+	}
+	return card.Capabilities.Streaming != nil && *card.Capabilities.Streaming, nil
 }
 
 func runTaskStream(
 	ctx context.Context,
 	agentNamespace, agentName string,
-	userPrompt string,
+	parts []protocol.Part,
 	sessionID *string,
 	timeout time.Duration,
 	cfg *config.Config,
 ) error {
 
-	a2aURL := fmt.Sprintf("%s/%s/%s", cfg.A2AURL, agentNamespace, agentName)
-	a2a, err := client.NewA2AClient(a2aURL)
+	a2a, err := client.NewA2AClient(a2autils.AgentURL(cfg.A2AURL, agentNamespace, agentName))
 	if err != nil {
 		return err
 	}
@@ -92,7 +208,7 @@ func runTaskStream(
 		Message: protocol.Message{
 			Role:      protocol.MessageRoleUser,
 			ContextID: sessionID,
-			Parts:     []protocol.Part{protocol.NewTextPart(userPrompt)},
+			Parts:     parts,
 		},
 	})
 	if err != nil {
@@ -113,13 +229,12 @@ func runTaskStream(
 func runTask(
 	ctx context.Context,
 	agentNamespace, agentName string,
-	userPrompt string,
+	parts []protocol.Part,
 	sessionID *string,
 	timeout time.Duration,
 	cfg *config.Config,
 ) error {
-	a2aURL := fmt.Sprintf("%s/%s/%s", cfg.A2AURL, agentNamespace, agentName)
-	a2a, err := client.NewA2AClient(a2aURL)
+	a2a, err := client.NewA2AClient(a2autils.AgentURL(cfg.A2AURL, agentNamespace, agentName))
 	if err != nil {
 		return err
 	}
@@ -127,17 +242,29 @@ func runTask(
 	ctx, cancel := context.WithTimeout(ctx, timeout)
 	defer cancel()
 
-	result, err := a2a.SendMessage(ctx, protocol.SendMessageParams{
+	result, err := sendMessageWithRetry(ctx, a2a, protocol.SendMessageParams{
 		Message: protocol.Message{
 			Role:      protocol.MessageRoleUser,
 			ContextID: sessionID,
-			Parts:     []protocol.Part{protocol.NewTextPart(userPrompt)},
+			Parts:     parts,
 		},
-	})
+	}, a2aSendAttempts, a2aSendBackoff)
 	if err != nil {
 		return err
 	}
 
+	if task, ok := result.Result.(*protocol.Task); ok {
+		// Agents without streaming may answer with a task that is still running
+		task, err := pollTask(ctx, a2a, task, a2aTaskPollInterval)
+		if err != nil {
+			return err
+		}
+		result.Result = task
+		if len(task.Artifacts) > 0 {
+			return printArtifacts(a2autils.ExtractArtifacts(task))
+		}
+	}
+
 	jsn, err := result.MarshalJSON()
 	if err != nil {
 		return err
@@ -147,3 +274,95 @@ func runTask(
 
 	return nil
 }
+
+// sendMessageWithRetry sends the message, retrying up to attempts times in total when the
+// request provably didn't reach the agent, waiting backoff times the attempt number between
+// tries. Sending a message starts a task, so responses that may come after the agent got the
+// message, like timeouts and 504s, aren't retried. The message gets an ID before the first
+// try, so that servers can recognize retries. ctx bounds all the attempts.
+func sendMessageWithRetry(ctx context.Context, a2a *client.A2AClient, params protocol.SendMessageParams, attempts int, backoff time.Duration) (*protocol.MessageResult, error) {
+	if params.Message.MessageID == "" {
+		params.Message.MessageID = protocol.GenerateMessageID()
+	}
+	for attempt := 1; ; attempt++ {
+		result, err := a2a.SendMessage(ctx, params)
+		if err == nil || attempt >= attempts || ctx.Err() != nil || !isRetryableA2AError(err) {
+			return result, err
+		}
+		fmt.Fprintf(os.Stderr, "Error sending the message, retrying: %v\n", err)
+
+		select {
+		case <-time.After(time.Duration(attempt) * backoff):
+		case <-ctx.Done():
+			return nil, err
+		}
+	}
+}
+
+// retryableA2AStatus matches the errors the A2A client returns for responses of gateways
+// that didn't forward the request, which it only reports in the error message
+var retryableA2AStatus = regexp.MustCompile(`unexpected http status (502|503)\b`)
+
+// isRetryableA2AError reports whether the request failed before reaching the agent: the
+// connection couldn't be opened, or a gateway had no agent to forward it to
+func isRetryableA2AError(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	var opErr *net.OpError
+	if errors.As(err, &opErr) && opErr.Op == "dial" {
+		return true
+	}
+	return retryableA2AStatus.MatchString(err.Error())
+}
+
+// pollTask gets the task every interval until it reaches a state in which it won't progress
+// without the user
+func pollTask(ctx context.Context, a2a *client.A2AClient, task *protocol.Task, interval time.Duration) (*protocol.Task, error) {
+	for !taskSettled(task.Status.State) {
+		select {
+		case <-time.After(interval):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+
+		latest, err := a2a.GetTasks(ctx, protocol.TaskQueryParams{ID: task.ID})
+		if err != nil {
+			return nil, fmt.Errorf("error getting task %s: %w", task.ID, err)
+		}
+		task = latest
+	}
+	return task, nil
+}
+
+func taskSettled(state protocol.TaskState) bool {
+	switch state {
+	case protocol.TaskStateSubmitted, protocol.TaskStateWorking:
+		return false
+	}
+	return true
+}
+
+// printArtifacts prints the text of each artifact, followed by its files and data parts
+func printArtifacts(artifacts []a2autils.Artifact) error {
+	for _, artifact := range artifacts {
+		if artifact.Text != "" {
+			fmt.Fprintln(os.Stdout, artifact.Text)
+		}
+		for _, file := range artifact.Files {
+			location := file.URI
+			if location == "" {
+				location = fmt.Sprintf("%d bytes", base64.StdEncoding.DecodedLen(len(file.Bytes)))
+			}
+			fmt.Fprintf(os.Stdout, "%s: %s (%s, %s)\n", config.BoldYellow("File"), file.Name, file.MimeType, location)
+		}
+		for _, data := range artifact.Data {
+			jsn, err := json.Marshal(data.Data)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(os.Stdout, "%s: %s\n", config.BoldYellow("Data"), string(jsn))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,296 @@
+package cli
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"trpc.group/trpc-go/trpc-a2a-go/client"
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+	"trpc.group/trpc-go/trpc-a2a-go/server"
+)
+
+func TestBuildMessageParts(t *testing.T) {
+	dir := t.TempDir()
+	textFile := filepath.Join(dir, "notes.txt")
+	if err := os.WriteFile(textFile, []byte("hello"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	unknownFile := filepath.Join(dir, "data.unknownext")
+	if err := os.WriteFile(unknownFile, []byte("%PDF-1.4 fake"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("text only", func(t *testing.T) {
+		parts, err := buildMessageParts("prompt", nil, 0)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(parts) != 1 {
+			t.Fatalf("expected 1 part, got %d", len(parts))
+		}
+		if _, ok := parts[0].(protocol.TextPart); !ok {
+			t.Fatalf("expected a TextPart, got %T", parts[0])
+		}
+	})
+
+	t.Run("attaches files with mime types", func(t *testing.T) {
+		parts, err := buildMessageParts("prompt", []string{textFile, unknownFile}, 0)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(parts) != 3 {
+			t.Fatalf("expected 3 parts, got %d", len(parts))
+		}
+
+		filePart, ok := parts[1].(protocol.FilePart)
+		if !ok {
+			t.Fatalf("expected a FilePart, got %T", parts[1])
+		}
+		file := filePart.File.(*protocol.FileWithBytes)
+		if *file.Name != "notes.txt" {
+			t.Errorf("expected name notes.txt, got %s", *file.Name)
+		}
+		if !strings.HasPrefix(*file.MimeType, "text/plain") {
+			t.Errorf("expected text/plain mime type, got %s", *file.MimeType)
+		}
+		if file.Bytes != base64.StdEncoding.EncodeToString([]byte("hello")) {
+			t.Errorf("unexpected file bytes %s", file.Bytes)
+		}
+
+		sniffed := parts[2].(protocol.FilePart).File.(*protocol.FileWithBytes)
+		if *sniffed.MimeType != "application/pdf" {
+			t.Errorf("expected sniffed application/pdf mime type, got %s", *sniffed.MimeType)
+		}
+	})
+
+	t.Run("rejects files over the size limit", func(t *testing.T) {
+		_, err := buildMessageParts("prompt", []string{textFile}, 2)
+		if err == nil || !strings.Contains(err.Error(), "exceeds the maximum file size") {
+			t.Fatalf("expected size limit error, got %v", err)
+		}
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		_, err := buildMessageParts("prompt", []string{filepath.Join(dir, "missing.txt")}, 0)
+		if err == nil {
+			t.Fatal("expected an error for a missing file")
+		}
+	})
+}
+
+func TestAgentStreams(t *testing.T) {
+	streaming := true
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/a2a/kagent/k8s-agent/.well-known/agent.json" {
+			http.NotFound(w, r)
+			return
+		}
+		json.NewEncoder(w).Encode(server.AgentCard{
+			Name:         "kagent/k8s-agent",
+			Capabilities: server.AgentCapabilities{Streaming: &streaming},
+		})
+	}))
+	defer srv.Close()
+
+	for _, expected := range []bool{true, false} {
+		streaming = expected
+		got, err := agentStreams(context.Background(), srv.URL+"/api/a2a/kagent/k8s-agent")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got != expected {
+			t.Errorf("expected streaming %v, got %v", expected, got)
+		}
+	}
+
+	if _, err := agentStreams(context.Background(), srv.URL+"/api/a2a/kagent/missing"); err == nil {
+		t.Error("expected an error for a missing agent card")
+	}
+}
+
+func TestPollTask(t *testing.T) {
+	polls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request struct {
+			ID     interface{}              `json:"id"`
+			Method string                   `json:"method"`
+			Params protocol.TaskQueryParams `json:"params"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil || request.Method != "tasks/get" {
+			t.Errorf("unexpected request %+v: %v", request, err)
+		}
+
+		polls++
+		state := protocol.TaskStateWorking
+		if polls == 2 {
+			state = protocol.TaskStateCompleted
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"jsonrpc": "2.0",
+			"id":      request.ID,
+			"result":  protocol.Task{ID: request.Params.ID, Kind: protocol.KindTask, Status: protocol.TaskStatus{State: state}},
+		})
+	}))
+	defer srv.Close()
+
+	a2a, err := client.NewA2AClient(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	task := &protocol.Task{ID: "task-1", Status: protocol.TaskStatus{State: protocol.TaskStateSubmitted}}
+	task, err = pollTask(context.Background(), a2a, task, time.Millisecond)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if task.Status.State != protocol.TaskStateCompleted || polls != 2 {
+		t.Errorf("expected the task to complete after 2 polls, got %s after %d", task.Status.State, polls)
+	}
+
+	t.Run("settled tasks aren't polled", func(t *testing.T) {
+		polls = 0
+		done := &protocol.Task{ID: "task-2", Status: protocol.TaskStatus{State: protocol.TaskStateInputRequired}}
+		if _, err := pollTask(context.Background(), a2a, done, time.Millisecond); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if polls != 0 {
+			t.Errorf("expected no polls, got %d", polls)
+		}
+	})
+}
+
+func TestSendMessageWithRetry(t *testing.T) {
+	var messageIDs []string
+	newServer := func(respond func(attempt int, w http.ResponseWriter, id interface{}), opts ...client.Option) (*client.A2AClient, *int) {
+		attempts := 0
+		messageIDs = nil
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var request struct {
+				ID     interface{}                `json:"id"`
+				Params protocol.SendMessageParams `json:"params"`
+			}
+			json.NewDecoder(r.Body).Decode(&request)
+			attempts++
+			messageIDs = append(messageIDs, request.Params.Message.MessageID)
+			respond(attempts, w, request.ID)
+		}))
+		t.Cleanup(srv.Close)
+
+		a2a, err := client.NewA2AClient(srv.URL, opts...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return a2a, &attempts
+	}
+	reply := func(w http.ResponseWriter, id interface{}) {
+		message := protocol.NewMessage(protocol.MessageRoleAgent, []protocol.Part{protocol.NewTextPart("done")})
+		json.NewEncoder(w).Encode(map[string]interface{}{"jsonrpc": "2.0", "id": id, "result": message})
+	}
+	params := protocol.SendMessageParams{
+		Message: protocol.Message{Role: protocol.MessageRoleUser, Parts: []protocol.Part{protocol.NewTextPart("task")}},
+	}
+
+	t.Run("retries transient failures", func(t *testing.T) {
+		a2a, attempts := newServer(func(attempt int, w http.ResponseWriter, id interface{}) {
+			if attempt == 1 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			reply(w, id)
+		})
+
+		result, err := sendMessageWithRetry(context.Background(), a2a, params, 3, time.Millisecond)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if _, ok := result.Result.(*protocol.Message); !ok {
+			t.Errorf("expected a message result, got %T", result.Result)
+		}
+		if *attempts != 2 {
+			t.Errorf("expected 2 attempts, got %d", *attempts)
+		}
+		if messageIDs[0] == "" || messageIDs[0] != messageIDs[1] {
+			t.Errorf("expected the attempts to send the same message ID, got %v", messageIDs)
+		}
+	})
+
+	t.Run("doesn't retry responses that may come after the agent got the message", func(t *testing.T) {
+		a2a, attempts := newServer(func(attempt int, w http.ResponseWriter, id interface{}) {
+			w.WriteHeader(http.StatusGatewayTimeout)
+		})
+
+		if _, err := sendMessageWithRetry(context.Background(), a2a, params, 3, time.Millisecond); err == nil {
+			t.Fatal("expected an error")
+		}
+		if *attempts != 1 {
+			t.Errorf("expected 1 attempt, got %d", *attempts)
+		}
+	})
+
+	t.Run("doesn't retry timeouts", func(t *testing.T) {
+		a2a, attempts := newServer(func(attempt int, w http.ResponseWriter, id interface{}) {
+			time.Sleep(50 * time.Millisecond)
+			reply(w, id)
+		}, client.WithHTTPClient(&http.Client{Timeout: 10 * time.Millisecond}))
+
+		if _, err := sendMessageWithRetry(context.Background(), a2a, params, 3, time.Millisecond); err == nil {
+			t.Fatal("expected an error")
+		}
+		if *attempts != 1 {
+			t.Errorf("expected 1 attempt, got %d", *attempts)
+		}
+	})
+
+	t.Run("doesn't retry protocol errors", func(t *testing.T) {
+		a2a, attempts := newServer(func(attempt int, w http.ResponseWriter, id interface{}) {
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"jsonrpc": "2.0",
+				"id":      id,
+				"error":   map[string]interface{}{"code": -32602, "message": "invalid params"},
+			})
+		})
+
+		if _, err := sendMessageWithRetry(context.Background(), a2a, params, 3, time.Millisecond); err == nil {
+			t.Fatal("expected an error")
+		}
+		if *attempts != 1 {
+			t.Errorf("expected 1 attempt, got %d", *attempts)
+		}
+	})
+
+	t.Run("gives up after the attempts", func(t *testing.T) {
+		a2a, attempts := newServer(func(attempt int, w http.ResponseWriter, id interface{}) {
+			w.WriteHeader(http.StatusBadGateway)
+		})
+
+		if _, err := sendMessageWithRetry(context.Background(), a2a, params, 3, time.Millisecond); err == nil {
+			t.Fatal("expected an error")
+		}
+		if *attempts != 3 {
+			t.Errorf("expected 3 attempts, got %d", *attempts)
+		}
+	})
+
+	t.Run("retries connection errors", func(t *testing.T) {
+		srv := httptest.NewServer(http.NotFoundHandler())
+		url := srv.URL
+		srv.Close()
+
+		a2a, err := client.NewA2AClient(url)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = sendMessageWithRetry(context.Background(), a2a, params, 2, time.Millisecond)
+		if err == nil || !isRetryableA2AError(err) {
+			t.Fatalf("expected a retryable connection error, got %v", err)
+		}
+	})
+}
@@ -11,13 +11,12 @@ import (
 	"github.com/kagent-dev/kagent/go/cli/internal/config"
 )
 
-func BugReportCmd(cfg *config.Config) {
+func BugReportCmd(cfg *config.Config) error {
 	// Create a temporary directory for bug report
 	timestamp := time.Now().Format("20060102-150405")
 	reportDir := fmt.Sprintf("kagent-bug-report-%s", timestamp)
 	if err := os.MkdirAll(reportDir, 0755); err != nil {
-		fmt.Fprintf(os.Stderr, "Error creating report directory: %v\n", err)
-		return
+		return fmt.Errorf("failed to create report directory: %w", err)
 	}
 
 	fmt.Println("Gathering bug report information...")
@@ -122,4 +121,5 @@ func BugReportCmd(cfg *config.Config) {
 
 	fmt.Printf("Bug report generated in directory: %s\n", reportDir)
 	fmt.Println("WARNING: Please review and scrub any sensitive information from agent.yaml before sharing the bug report.")
+	return nil
 }
@@ -1,13 +1,18 @@
 package cli
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"math/rand"
+	"os"
+	"os/signal"
 	"slices"
+	"time"
 
 	"github.com/abiosoft/ishell/v2"
 	"github.com/abiosoft/readline"
+	"github.com/briandowns/spinner"
 	autogen_client "github.com/kagent-dev/kagent/go/autogen/client"
 	"github.com/kagent-dev/kagent/go/cli/internal/config"
 	"github.com/spf13/pflag"
@@ -35,7 +40,7 @@ func ChatCmd(c *ishell.Context) {
 	if len(flagSet.Args()) > 0 {
 		teamName := flagSet.Args()[0]
 		var err error
-		team, err = client.GetTeam(teamName, cfg.UserID)
+		team, err = client.GetAgent(context.Background(), teamName, cfg.UserID)
 		if err != nil {
 			c.Println(err)
 			return
@@ -68,7 +73,8 @@ func ChatCmd(c *ishell.Context) {
 		team = teams[selectedTeamIdx]
 	}
 
-	sessions, err := client.ListSessions(cfg.UserID)
+	// The most recently active sessions are the likeliest to be picked, so they come first
+	sessions, err := client.ListSessionsByLastActivity(cfg.UserID)
 	if err != nil {
 		c.Println(err)
 		return
@@ -137,19 +143,74 @@ func ChatCmd(c *ishell.Context) {
 			continue
 		}
 
-		usage := &autogen_client.ModelsUsage{}
-
-		ch, err := client.InvokeSessionStream(session.ID, cfg.UserID, &autogen_client.InvokeRequest{
-			Task:       task,
-			TeamConfig: team.Component,
-		})
-		if err != nil {
+		if err := streamChatTask(client, session.ID, cfg.UserID, team, task, verbose); err != nil {
+			if errors.Is(err, context.Canceled) {
+				c.Println("request cancelled")
+				continue
+			}
 			c.Printf("Failed to invoke session: %v\n", err)
 			return
 		}
+	}
+}
+
+// streamChatTask invokes the session and streams its events to stdout. Ctrl-C while the
+// request is in flight cancels it and returns context.Canceled instead of exiting the shell.
+func streamChatTask(client autogen_client.Client, sessionID int, userID string, team *autogen_client.Team, task string, verbose bool) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	interrupts := make(chan os.Signal, 1)
+	signal.Notify(interrupts, os.Interrupt)
+	defer signal.Stop(interrupts)
+	go func() {
+		select {
+		case <-interrupts:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	s := spinner.New(spinner.CharSets[35], 100*time.Millisecond)
+	s.Suffix = fmt.Sprintf(" %s...", getThinkingVerb())
+	s.Start()
+	defer s.Stop()
 
-		StreamEvents(ch, usage, verbose)
+	ch, err := client.InvokeSessionStream(ctx, sessionID, userID, &autogen_client.InvokeRequest{
+		Task:       task,
+		TeamConfig: team.Component,
+	})
+	if err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		return err
 	}
+
+	usage := &autogen_client.ModelsUsage{}
+	StreamEvents(ctx, stopOnFirstEvent(ctx, ch, s.Stop), usage, verbose)
+	return ctx.Err()
+}
+
+// stopOnFirstEvent forwards events from ch, calling stop before the first one is delivered
+func stopOnFirstEvent(ctx context.Context, ch <-chan *autogen_client.SseEvent, stop func()) <-chan *autogen_client.SseEvent {
+	out := make(chan *autogen_client.SseEvent)
+	go func() {
+		defer close(out)
+		first := true
+		for event := range ch {
+			if first {
+				stop()
+				first = false
+			}
+			select {
+			case out <- event:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return out
 }
 
 // Yes, this is AI generated, and so is this comment.
@@ -0,0 +1,94 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/kagent-dev/kagent/go/cli/internal/config"
+	"github.com/spf13/pflag"
+)
+
+// ConfigSetContextCmd creates the context, or updates an existing one, with the server
+// settings set with flags
+func ConfigSetContextCmd(flags *pflag.FlagSet, name string) error {
+	changed := func(name string) string {
+		if flag := flags.Lookup(name); flag != nil && flag.Changed {
+			return flag.Value.String()
+		}
+		return ""
+	}
+	context := &config.Context{
+		APIURL:        changed("api-url"),
+		UserID:        changed("user-id"),
+		Namespace:     changed("namespace"),
+		A2AURL:        changed("a2a-url"),
+		ControllerURL: changed("controller-url"),
+	}
+	if err := config.SetContext(name, context); err != nil {
+		return fmt.Errorf("failed to set context %s: %w", name, err)
+	}
+	fmt.Fprintf(os.Stdout, "Context %s set\n", name)
+	return nil
+}
+
+func ConfigUseContextCmd(name string) error {
+	if err := config.UseContext(name); err != nil {
+		return fmt.Errorf("failed to use context %s: %w", name, err)
+	}
+	fmt.Fprintf(os.Stdout, "Switched to context %s\n", name)
+	return nil
+}
+
+func ConfigDeleteContextCmd(name string) error {
+	if err := config.DeleteContext(name); err != nil {
+		return fmt.Errorf("failed to delete context %s: %w", name, err)
+	}
+	fmt.Fprintf(os.Stdout, "Deleted context %s\n", name)
+	return nil
+}
+
+func ConfigCurrentContextCmd() error {
+	cfg, err := config.Load()
+	if err != nil {
+		return err
+	}
+	name := config.ActiveContext(cfg)
+	if name == "" {
+		return fmt.Errorf("no current context set, set one with 'kagent config use-context'")
+	}
+	fmt.Fprintln(os.Stdout, name)
+	return nil
+}
+
+func ConfigGetContextsCmd() error {
+	cfg, err := config.Load()
+	if err != nil {
+		return err
+	}
+	if len(cfg.Contexts) == 0 {
+		fmt.Println("No contexts found")
+		return nil
+	}
+	if err := printContexts(os.Stdout, cfg); err != nil {
+		return fmt.Errorf("failed to print contexts: %w", err)
+	}
+	return nil
+}
+
+func printContexts(w io.Writer, cfg *config.Config) error {
+	active := config.ActiveContext(cfg)
+	headers := []string{"CURRENT", "NAME", "API URL", "USER ID", "NAMESPACE"}
+	names := cfg.ContextNames()
+	rows := make([][]string, len(names))
+	for i, name := range names {
+		current := ""
+		if name == active {
+			current = "*"
+		}
+		context := cfg.Contexts[name]
+		rows[i] = []string{current, name, context.APIURL, context.UserID, context.Namespace}
+	}
+
+	return fprintOutput(w, cfg.Contexts, headers, rows)
+}
@@ -0,0 +1,126 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/kagent-dev/kagent/go/controller/api/v1alpha1"
+)
+
+// DefaultControllerURL is the base URL of the controller API, as port-forwarded by the CLI
+const DefaultControllerURL = "http://localhost:8083/api"
+
+// controllerClient calls the controller API, which serves the Kubernetes resources that
+// Autogen doesn't know about, like model configs
+type controllerClient struct {
+	baseURL    string
+	httpClient *http.Client
+}
+
+func newControllerClient(baseURL string) *controllerClient {
+	return &controllerClient{
+		baseURL:    strings.TrimSuffix(baseURL, "/"),
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// reachable reports whether the controller API answers. The health checks are served at the
+// root of the controller rather than under the API, so the version endpoint is probed.
+func (c *controllerClient) reachable(ctx context.Context) bool {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	return c.do(ctx, http.MethodGet, "/version", nil, nil) == nil
+}
+
+// PatchTeam applies a JSON merge patch to the team (Agent resource) and returns the patched
+// team. Fields absent from the patch are kept and fields set to null are cleared.
+func (c *controllerClient) PatchTeam(ctx context.Context, namespace, name string, patch map[string]interface{}) (*v1alpha1.Agent, error) {
+	team := &v1alpha1.Agent{}
+	if err := c.do(ctx, http.MethodPatch, "/teams/"+namespace+"/"+name, patch, team); err != nil {
+		return nil, fmt.Errorf("failed to patch team %s/%s: %w", namespace, name, err)
+	}
+	return team, nil
+}
+
+// do sends the request and decodes the response into result if set. Error responses are
+// returned as errors with the message sent by the controller, and the exit code matching
+// their status.
+func (c *controllerClient) do(ctx context.Context, method, path string, body, result interface{}) error {
+	var reqBody io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to marshal request: %w", err)
+		}
+		reqBody = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, reqBody)
+	if err != nil {
+		return err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return &ExitError{Code: ExitCodeConnectionError, Err: err}
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+	if resp.StatusCode >= http.StatusBadRequest {
+		return statusError(resp.StatusCode, data)
+	}
+
+	if result == nil || len(data) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(data, result); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
+
+// APIError is an error response of the controller API. Code is the machine-readable code of
+// the error, like quota_exceeded, when the controller sends one.
+type APIError struct {
+	Status  int
+	Code    string
+	Message string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("%s (%d)", e.Message, e.Status)
+}
+
+// statusError returns the error of an error response, with the exit code matching its status.
+// Responses with an error message are returned as an *APIError.
+func statusError(status int, body []byte) error {
+	code := ExitCodeError
+	switch {
+	case status == http.StatusNotFound:
+		code = ExitCodeNotFound
+	case status >= http.StatusInternalServerError:
+		code = ExitCodeServerError
+	}
+
+	var apiErr struct {
+		Error string `json:"error"`
+		Code  string `json:"code"`
+	}
+	if json.Unmarshal(body, &apiErr) == nil && apiErr.Error != "" {
+		return &ExitError{Code: code, Err: &APIError{Status: status, Code: apiErr.Code, Message: apiErr.Error}}
+	}
+	return &ExitError{Code: code, Err: fmt.Errorf("request failed with status %d: %s", status, strings.TrimSpace(string(body)))}
+}
@@ -0,0 +1,75 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestControllerClientPatchTeam(t *testing.T) {
+	var received map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPatch || r.URL.Path != "/api/teams/kagent/k8s-agent" {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"error":"Team not found","code":"agent_not_found"}`))
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		if err := json.Unmarshal(body, &received); err != nil {
+			t.Errorf("invalid patch: %v", err)
+		}
+		_, _ = w.Write([]byte(`{"metadata":{"name":"k8s-agent","namespace":"kagent"},"spec":{"description":"patched"}}`))
+	}))
+	defer server.Close()
+	client := newControllerClient(server.URL + "/api")
+
+	team, err := client.PatchTeam(context.Background(), "kagent", "k8s-agent", map[string]interface{}{
+		"spec": map[string]interface{}{"description": "patched", "systemMessage": nil},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if team.Spec.Description != "patched" {
+		t.Errorf("unexpected team: %+v", team)
+	}
+	spec, _ := received["spec"].(map[string]interface{})
+	if value, ok := spec["systemMessage"]; !ok || value != nil {
+		t.Errorf("expected the patch to clear systemMessage with null, got %v", received)
+	}
+
+	_, err = client.PatchTeam(context.Background(), "kagent", "missing", map[string]interface{}{})
+	var exitErr *ExitError
+	if !errors.As(err, &exitErr) || exitErr.Code != ExitCodeNotFound {
+		t.Errorf("expected a not found exit error, got %v", err)
+	}
+}
+
+func TestStatusError(t *testing.T) {
+	err := fmt.Errorf("failed to import tools: %w", statusError(http.StatusTooManyRequests, []byte(`{"error":"Tool server quota exceeded","code":"quota_exceeded"}`)))
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("expected an API error, got %v", err)
+	}
+	if apiErr.Code != "quota_exceeded" || apiErr.Status != http.StatusTooManyRequests || apiErr.Message != "Tool server quota exceeded" {
+		t.Errorf("unexpected API error: %+v", apiErr)
+	}
+	if err.Error() != "failed to import tools: Tool server quota exceeded (429)" {
+		t.Errorf("unexpected message: %s", err)
+	}
+	if code := ExitCode(err); code != ExitCodeError {
+		t.Errorf("expected exit code %d, got %d", ExitCodeError, code)
+	}
+
+	err = statusError(http.StatusBadGateway, []byte("bad gateway"))
+	if errors.As(err, &apiErr) {
+		t.Errorf("expected no API error without an error message, got %+v", apiErr)
+	}
+	if code := ExitCode(err); code != ExitCodeServerError {
+		t.Errorf("expected exit code %d, got %d", ExitCodeServerError, code)
+	}
+}
@@ -13,7 +13,7 @@ import (
 	"github.com/kagent-dev/kagent/go/cli/internal/config"
 )
 
-func DashboardCmd(ctx context.Context, cfg *config.Config) {
+func DashboardCmd(ctx context.Context, cfg *config.Config) error {
 	ctx, cancel := context.WithCancel(ctx)
 	cmd := exec.CommandContext(ctx, "kubectl", "-n", cfg.Namespace, "port-forward", "service/kagent", "8082:80")
 
@@ -27,8 +27,7 @@ func DashboardCmd(ctx context.Context, cfg *config.Config) {
 	}()
 
 	if err := cmd.Start(); err != nil {
-		fmt.Fprintf(os.Stderr, "Error port-forwarding kagent: %v\n", err)
-		return
+		return fmt.Errorf("failed to port-forward kagent: %w", err)
 	}
 
 	// Wait for the port-forward to start
@@ -37,11 +36,12 @@ func DashboardCmd(ctx context.Context, cfg *config.Config) {
 	// Open the dashboard in the browser
 	openCmd := exec.CommandContext(ctx, "open", "http://localhost:8082")
 	if err := openCmd.Run(); err != nil {
-		fmt.Fprintf(os.Stderr, "Error opening kagent dashboard: %v\n", err)
+		return fmt.Errorf("failed to open kagent dashboard: %w", err)
 	}
 
 	fmt.Fprintln(os.Stdout, "kagent dashboard is available at http://localhost:8082")
 
 	fmt.Println("Press the Enter Key to stop the port-forward...")
 	fmt.Scanln() // wait for Enter Key
+	return nil
 }
@@ -0,0 +1,71 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+
+	autogen_client "github.com/kagent-dev/kagent/go/autogen/client"
+)
+
+// Exit codes of the kagent commands, so that scripts can tell failures apart:
+//
+//	0  success
+//	1  any other failure, like an invalid argument
+//	2  the resource wasn't found
+//	3  the server failed to handle the request
+//	4  the server couldn't be reached
+const (
+	ExitCodeError           = 1
+	ExitCodeNotFound        = 2
+	ExitCodeServerError     = 3
+	ExitCodeConnectionError = 4
+)
+
+// ExitCodesHelp describes the exit codes in the help of the commands
+const ExitCodesHelp = `Exit codes:
+  0  success
+  1  any other failure, like an invalid argument
+  2  the resource wasn't found
+  3  the server failed to handle the request
+  4  the server couldn't be reached`
+
+// ExitError is an error of a command that makes kagent exit with Code
+type ExitError struct {
+	Code int
+	Err  error
+}
+
+func (e *ExitError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *ExitError) Unwrap() error {
+	return e.Err
+}
+
+// ExitCode returns the code kagent exits with when a command fails with err
+func ExitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+	var exitErr *ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.Code
+	}
+	return ExitCodeError
+}
+
+// clientError wraps an error returned by the Autogen client with msg and the exit code
+// of its cause
+func clientError(msg string, err error) error {
+	code := ExitCodeServerError
+	var urlErr *url.Error
+	switch {
+	case errors.Is(err, autogen_client.NotFoundError):
+		code = ExitCodeNotFound
+	case errors.As(err, &urlErr):
+		code = ExitCodeConnectionError
+	}
+	return &ExitError{Code: code, Err: fmt.Errorf("%s: %w", msg, err)}
+}
@@ -0,0 +1,120 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	autogen_client "github.com/kagent-dev/kagent/go/autogen/client"
+	"github.com/kagent-dev/kagent/go/cli/internal/config"
+)
+
+func TestExitCode(t *testing.T) {
+	tests := map[string]struct {
+		err  error
+		want int
+	}{
+		"no error":      {err: nil, want: 0},
+		"plain error":   {err: errors.New("invalid run ID"), want: ExitCodeError},
+		"wrapped":       {err: fmt.Errorf("failed: %w", &ExitError{Code: ExitCodeNotFound, Err: errors.New("gone")}), want: ExitCodeNotFound},
+		"server error":  {err: clientError("failed to get agents", errors.New("request failed with status: 500")), want: ExitCodeServerError},
+		"not found":     {err: clientError("failed to get run 1", fmt.Errorf("request failed: %w", autogen_client.NotFoundError)), want: ExitCodeNotFound},
+		"no connection": {err: &ExitError{Code: ExitCodeConnectionError, Err: errors.New("connection refused")}, want: ExitCodeConnectionError},
+	}
+	for name, tt := range tests {
+		if got := ExitCode(tt.err); got != tt.want {
+			t.Errorf("%s: expected exit code %d, got %d", name, tt.want, got)
+		}
+	}
+}
+
+func TestGetCmdExitCodes(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/runs/404":
+			w.WriteHeader(http.StatusNotFound)
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer server.Close()
+	cfg := &config.Config{APIURL: server.URL, UserID: "alice"}
+
+	if code := ExitCode(GetRunCmd(cfg, "404")); code != ExitCodeNotFound {
+		t.Errorf("expected exit code %d for a missing run, got %d", ExitCodeNotFound, code)
+	}
+	if code := ExitCode(GetRunCmd(cfg, "abc")); code != ExitCodeError {
+		t.Errorf("expected exit code %d for an invalid run ID, got %d", ExitCodeError, code)
+	}
+	if code := ExitCode(GetSessionCmd(cfg, "")); code != ExitCodeServerError {
+		t.Errorf("expected exit code %d for a server error, got %d", ExitCodeServerError, code)
+	}
+
+	server.Close()
+	if code := ExitCode(GetToolCmd(cfg)); code != ExitCodeConnectionError {
+		t.Errorf("expected exit code %d for an unreachable server, got %d", ExitCodeConnectionError, code)
+	}
+}
+
+func TestInvokeCmdExitCodes(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/version":
+			_, _ = w.Write([]byte(`{"version":"1.0.0"}`))
+		case "/teams/":
+			_, _ = w.Write([]byte(`{"status":true,"data":[]}`))
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer server.Close()
+	cfg := &config.Config{APIURL: server.URL, UserID: "alice"}
+
+	task := filepath.Join(t.TempDir(), "task.txt")
+	if err := os.WriteFile(task, []byte("hello"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if code := ExitCode(InvokeCmd(context.Background(), &InvokeCfg{Config: cfg, Agent: "missing"})); code != ExitCodeError {
+		t.Errorf("expected exit code %d without a task, got %d", ExitCodeError, code)
+	}
+	if code := ExitCode(InvokeCmd(context.Background(), &InvokeCfg{Config: cfg, Task: task, Agent: "missing"})); code != ExitCodeNotFound {
+		t.Errorf("expected exit code %d for a missing agent, got %d", ExitCodeNotFound, code)
+	}
+}
+
+func TestControllerClientExitCodes(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/modelconfigs/kagent/missing":
+			w.WriteHeader(http.StatusNotFound)
+		case "/api/modelconfigs":
+			w.WriteHeader(http.StatusBadRequest)
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer server.Close()
+	client := newControllerClient(server.URL + "/api")
+	ctx := context.Background()
+
+	for path, want := range map[string]int{
+		"/modelconfigs/kagent/missing": ExitCodeNotFound,
+		"/modelconfigs":                ExitCodeError,
+		"/toolservers":                 ExitCodeServerError,
+	} {
+		if code := ExitCode(client.do(ctx, "GET", path, nil, nil)); code != want {
+			t.Errorf("GET %s: expected exit code %d, got %d", path, want, code)
+		}
+	}
+
+	server.Close()
+	if code := ExitCode(client.do(ctx, "GET", "/toolservers", nil, nil)); code != ExitCodeConnectionError {
+		t.Errorf("expected exit code %d for an unreachable server, got %d", ExitCodeConnectionError, code)
+	}
+}
@@ -3,7 +3,9 @@ package cli
 import (
 	"encoding/json"
 	"fmt"
+	"io"
 	"iter"
+	"os"
 	"slices"
 
 	"github.com/jedib0t/go-pretty/v6/table"
@@ -39,6 +41,10 @@ func Filter[E any](s iter.Seq[E], f func(E) bool) iter.Seq[E] {
 }
 
 func printOutput(data interface{}, tableHeaders []string, tableRows [][]string) error {
+	return fprintOutput(os.Stdout, data, tableHeaders, tableRows)
+}
+
+func fprintOutput(w io.Writer, data interface{}, tableHeaders []string, tableRows [][]string) error {
 	format := OutputFormat(viper.GetString("output_format"))
 
 	tw := table.NewWriter()
@@ -55,20 +61,20 @@ func printOutput(data interface{}, tableHeaders []string, tableRows [][]string)
 
 	switch format {
 	case OutputFormatJSON:
-		return printJSON(data)
+		return printJSON(w, data)
 	case OutputFormatTable:
-		fmt.Println(tw.Render())
+		fmt.Fprintln(w, tw.Render())
 		return nil
 	default:
 		return fmt.Errorf("unknown output format: %s", format)
 	}
 }
 
-func printJSON(data interface{}) error {
+func printJSON(w io.Writer, data interface{}) error {
 	output, err := json.MarshalIndent(data, "", "  ")
 	if err != nil {
 		return fmt.Errorf("error formatting JSON: %w", err)
 	}
-	fmt.Println(string(output))
+	fmt.Fprintln(w, string(output))
 	return nil
 }
@@ -2,7 +2,9 @@ package cli
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"strconv"
 
@@ -10,117 +12,120 @@ import (
 	"github.com/kagent-dev/kagent/go/cli/internal/config"
 )
 
-func GetAgentCmd(cfg *config.Config, resourceName string) {
+func GetAgentCmd(cfg *config.Config, resourceName string) error {
 	client := autogen_client.New(cfg.APIURL)
 
 	if resourceName == "" {
 		agentList, err := client.ListTeams(cfg.UserID)
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "Failed to get agents: %v\n", err)
-			return
+			return clientError("failed to get agents", err)
 		}
 
 		if len(agentList) == 0 {
 			fmt.Println("No agents found")
-			return
+			return nil
 		}
 
-		if err := printTeams(agentList); err != nil {
-			fmt.Fprintf(os.Stderr, "Failed to print agents: %v\n", err)
-			return
+		if err := printTeams(os.Stdout, agentList); err != nil {
+			return fmt.Errorf("failed to print agents: %w", err)
 		}
-	} else {
-		agent, err := client.GetTeam(resourceName, cfg.UserID)
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "Failed to get agent %s: %v\n", resourceName, err)
-			return
-		}
-		byt, _ := json.MarshalIndent(agent, "", "  ")
-		fmt.Fprintln(os.Stdout, string(byt))
+		return nil
+	}
+
+	if _, _, err := autogen_client.ParseAgentRef(resourceName); err != nil {
+		return err
+	}
+	agentList, err := client.ListTeams(cfg.UserID)
+	if err != nil {
+		return clientError(fmt.Sprintf("failed to get agent %s", resourceName), err)
+	}
+	agent, err := autogen_client.FindAgentByRef(agentList, resourceName)
+	if errors.Is(err, autogen_client.NotFoundError) {
+		return clientError(fmt.Sprintf("failed to get agent %s", resourceName), err)
+	} else if err != nil {
+		return err
 	}
+	byt, _ := json.MarshalIndent(agent, "", "  ")
+	fmt.Fprintln(os.Stdout, string(byt))
+	return nil
 }
 
-func GetRunCmd(cfg *config.Config, resourceName string) {
+func GetRunCmd(cfg *config.Config, resourceName string) error {
 	client := autogen_client.New(cfg.APIURL)
 	if resourceName == "" {
 		runList, err := client.ListRuns(cfg.UserID)
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "Failed to get runs: %v\n", err)
-			return
+			return clientError("failed to get runs", err)
 		}
 
 		if len(runList) == 0 {
 			fmt.Println("No runs found")
-			return
+			return nil
 		}
 
 		if err := printRuns(runList); err != nil {
-			fmt.Fprintf(os.Stderr, "Failed to print runs: %v\n", err)
-			return
-		}
-	} else {
-		// Convert run ID from string to integer
-		runID, err := strconv.Atoi(resourceName)
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "Invalid run ID: %s, must be a number: %v\n", resourceName, err)
-			return
+			return fmt.Errorf("failed to print runs: %w", err)
 		}
+		return nil
+	}
 
-		run, err := client.GetRun(runID)
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "Failed to get run %d: %v\n", runID, err)
-			return
-		}
-		byt, _ := json.MarshalIndent(run, "", "  ")
-		fmt.Fprintln(os.Stdout, string(byt))
+	// Convert run ID from string to integer
+	runID, err := strconv.Atoi(resourceName)
+	if err != nil {
+		return fmt.Errorf("invalid run ID: %s, must be a number: %w", resourceName, err)
 	}
+
+	run, err := client.GetRun(runID)
+	if err != nil {
+		return clientError(fmt.Sprintf("failed to get run %d", runID), err)
+	}
+	byt, _ := json.MarshalIndent(run, "", "  ")
+	fmt.Fprintln(os.Stdout, string(byt))
+	return nil
 }
 
-func GetSessionCmd(cfg *config.Config, resourceName string) {
+func GetSessionCmd(cfg *config.Config, resourceName string) error {
 	client := autogen_client.New(cfg.APIURL)
 	if resourceName == "" {
 		sessionList, err := client.ListSessions(cfg.UserID)
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "Failed to get sessions: %v\n", err)
-			return
+			return clientError("failed to get sessions", err)
 		}
 
 		if len(sessionList) == 0 {
 			fmt.Println("No sessions found")
-			return
+			return nil
 		}
 
-		if err := printSessions(sessionList); err != nil {
-			fmt.Fprintf(os.Stderr, "Failed to print sessions: %v\n", err)
-			return
-		}
-	} else {
-		sessionID, err := strconv.Atoi(resourceName)
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "Failed to convert session name to ID: %v\n", err)
-			return
-		}
-		session, err := client.GetSessionById(sessionID, cfg.UserID)
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "Failed to get session %s: %v\n", resourceName, err)
-			return
+		if err := printSessions(os.Stdout, sessionList); err != nil {
+			return fmt.Errorf("failed to print sessions: %w", err)
 		}
-		byt, _ := json.MarshalIndent(session, "", "  ")
-		fmt.Fprintln(os.Stdout, string(byt))
+		return nil
+	}
+
+	sessionID, err := strconv.Atoi(resourceName)
+	if err != nil {
+		return fmt.Errorf("failed to convert session name to ID: %w", err)
+	}
+	session, err := client.GetSessionById(sessionID, cfg.UserID)
+	if err != nil {
+		return clientError(fmt.Sprintf("failed to get session %s", resourceName), err)
 	}
+	byt, _ := json.MarshalIndent(session, "", "  ")
+	fmt.Fprintln(os.Stdout, string(byt))
+	return nil
 }
 
-func GetToolCmd(cfg *config.Config) {
+func GetToolCmd(cfg *config.Config) error {
 	client := autogen_client.New(cfg.APIURL)
 	toolList, err := client.ListTools(cfg.UserID)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Failed to get tools: %v\n", err)
-		return
+		return clientError("failed to get tools", err)
 	}
 	if err := printTools(toolList); err != nil {
-		fmt.Fprintf(os.Stderr, "Failed to print tools: %v\n", err)
-		return
+		return fmt.Errorf("failed to print tools: %w", err)
 	}
+	return nil
 }
 
 func printTools(tools []*autogen_client.Tool) error {
@@ -168,7 +173,7 @@ func printRuns(runs []*autogen_client.Run) error {
 	return printOutput(runs, headers, rows)
 }
 
-func printTeams(teams []*autogen_client.Team) error {
+func printTeams(w io.Writer, teams []*autogen_client.Team) error {
 	// Prepare table data
 	headers := []string{"#", "NAME", "ID", "CREATED"}
 	rows := make([][]string, len(teams))
@@ -181,10 +186,10 @@ func printTeams(teams []*autogen_client.Team) error {
 		}
 	}
 
-	return printOutput(teams, headers, rows)
+	return fprintOutput(w, teams, headers, rows)
 }
 
-func printSessions(sessions []*autogen_client.Session) error {
+func printSessions(w io.Writer, sessions []*autogen_client.Session) error {
 	headers := []string{"#", "ID", "NAME"}
 	rows := make([][]string, len(sessions))
 	for i, session := range sessions {
@@ -195,5 +200,5 @@ func printSessions(sessions []*autogen_client.Session) error {
 		}
 	}
 
-	return printOutput(sessions, headers, rows)
+	return fprintOutput(w, sessions, headers, rows)
 }
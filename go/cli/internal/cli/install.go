@@ -44,11 +44,9 @@ func installChart(ctx context.Context, chartName string, namespace string, regis
 	return "", nil
 }
 
-func InstallCmd(ctx context.Context, cfg *config.Config) {
-
+func InstallCmd(ctx context.Context, cfg *config.Config) error {
 	if version.Version == "dev" {
-		fmt.Fprintln(os.Stderr, "Installation requires released version of kagent")
-		return
+		return fmt.Errorf("installation requires released version of kagent")
 	}
 
 	// get model provider from KAGENT_DEFAULT_MODEL_PROVIDER environment variable or use DefaultModelProvider
@@ -59,9 +57,7 @@ func InstallCmd(ctx context.Context, cfg *config.Config) {
 	apiKeyValue := os.Getenv(apiKeyName)
 
 	if apiKeyName != "" && apiKeyValue == "" {
-		fmt.Fprintf(os.Stderr, "%s is not set\n", apiKeyName)
-		fmt.Fprintf(os.Stderr, "Please set the %s environment variable\n", apiKeyName)
-		return
+		return fmt.Errorf("%s is not set, please set the %s environment variable", apiKeyName, apiKeyName)
 	}
 
 	// Build Helm values
@@ -102,8 +98,7 @@ func InstallCmd(ctx context.Context, cfg *config.Config) {
 			// Restart the spinner
 			s.Start()
 		} else {
-			fmt.Fprintln(os.Stderr, "Error installing kagent-crds:", output)
-			return
+			return fmt.Errorf("failed to install kagent-crds: %s", output)
 		}
 	}
 
@@ -112,8 +107,7 @@ func InstallCmd(ctx context.Context, cfg *config.Config) {
 	if output, err := installChart(ctx, "kagent", cfg.Namespace, helmRegistry, helmVersion, values, s); err != nil {
 		// Always stop the spinner before printing error messages
 		s.Stop()
-		fmt.Fprintln(os.Stderr, "Error installing kagent:", output)
-		return
+		return fmt.Errorf("failed to install kagent: %s", output)
 	}
 
 	// Create a new context for port-forward
@@ -122,8 +116,7 @@ func InstallCmd(ctx context.Context, cfg *config.Config) {
 	portForwardCmd := exec.CommandContext(pfCtx, "kubectl", "-n", cfg.Namespace, "port-forward", "service/kagent", "8081:8081")
 	if err := portForwardCmd.Start(); err != nil {
 		s.Stop()
-		fmt.Fprintln(os.Stderr, "Error starting port-forward:", err)
-		return
+		return &ExitError{Code: ExitCodeConnectionError, Err: fmt.Errorf("failed to start port-forward: %w", err)}
 	}
 
 	// Wait for port-forward to be ready
@@ -132,13 +125,13 @@ func InstallCmd(ctx context.Context, cfg *config.Config) {
 	// Check if port-forward is running
 	if portForwardCmd.Process == nil {
 		s.Stop()
-		fmt.Fprintln(os.Stderr, "Port-forward failed to start")
-		return
+		return &ExitError{Code: ExitCodeConnectionError, Err: fmt.Errorf("port-forward failed to start")}
 	}
 
 	// Stop the spinner completely before printing the success message
 	s.Stop()
 	fmt.Fprintln(os.Stdout, "kagent installed successfully")
+	return nil
 }
 
 // deleteCRDs manually deletes Kubernetes CRDs for kagent
@@ -172,7 +165,7 @@ func deleteCRDs(ctx context.Context) error {
 	return nil
 }
 
-func UninstallCmd(ctx context.Context, cfg *config.Config) {
+func UninstallCmd(ctx context.Context, cfg *config.Config) error {
 	s := spinner.New(spinner.CharSets[35], 100*time.Millisecond)
 
 	// First uninstall kagent
@@ -194,8 +187,7 @@ func UninstallCmd(ctx context.Context, cfg *config.Config) {
 		if strings.Contains(output, "not found") {
 			fmt.Fprintln(os.Stderr, "Warning: kagent release not found, skipping uninstallation")
 		} else {
-			fmt.Fprintln(os.Stderr, "Error uninstalling kagent:", output)
-			return
+			return fmt.Errorf("failed to uninstall kagent: %s", output)
 		}
 	}
 
@@ -218,15 +210,14 @@ func UninstallCmd(ctx context.Context, cfg *config.Config) {
 			fmt.Fprintln(os.Stderr, "Warning: kagent-crds release not found, try to delete crds directly")
 			// delete the CRDs directly, this is a workaround for the fact that helm doesn't delete CRDs
 			if err := deleteCRDs(ctx); err != nil {
-				fmt.Fprintln(os.Stderr, "Error deleting CRDs:", err)
-				return
+				return fmt.Errorf("failed to delete CRDs: %w", err)
 			}
 		} else {
-			fmt.Fprintln(os.Stderr, "Error uninstalling kagent-crds:", output)
-			return
+			return fmt.Errorf("failed to uninstall kagent-crds: %s", output)
 		}
 	}
 
 	s.Stop()
 	fmt.Fprintln(os.Stdout, "\nkagent uninstalled successfully")
+	return nil
 }
@@ -20,8 +20,7 @@ type InvokeCfg struct {
 	Stream  bool
 }
 
-func InvokeCmd(ctx context.Context, cfg *InvokeCfg) {
-
+func InvokeCmd(ctx context.Context, cfg *InvokeCfg) error {
 	client := autogen_client.New(cfg.Config.APIURL)
 
 	var pf *portForward
@@ -33,22 +32,19 @@ func InvokeCmd(ctx context.Context, cfg *InvokeCfg) {
 	var task string
 	switch cfg.Task {
 	case "":
-		fmt.Fprintln(os.Stderr, "Task is required")
-		return
+		return fmt.Errorf("task is required")
 	case "-":
 		// Read from stdin
 		content, err := io.ReadAll(os.Stdin)
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error reading from stdin: %v\n", err)
-			return
+			return fmt.Errorf("failed to read the task from stdin: %w", err)
 		}
 		task = string(content)
 	default:
 		// Read from file
 		content, err := os.ReadFile(cfg.Task)
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error reading from file: %v\n", err)
-			return
+			return fmt.Errorf("failed to read the task from %s: %w", cfg.Task, err)
 		}
 		task = string(content)
 	}
@@ -60,8 +56,7 @@ func InvokeCmd(ctx context.Context, cfg *InvokeCfg) {
 		if err != nil {
 			if errors.Is(err, autogen_client.NotFoundError) {
 				if cfg.Agent == "" {
-					fmt.Fprintln(os.Stderr, "Agent is required when creating a new session")
-					return
+					return fmt.Errorf("agent is required when creating a new session")
 				}
 				// If the session is not found, create it
 
@@ -70,56 +65,49 @@ func InvokeCmd(ctx context.Context, cfg *InvokeCfg) {
 					UserID: cfg.Config.UserID,
 				})
 				if err != nil {
-					fmt.Fprintf(os.Stderr, "Error creating session: %v\n", err)
-					return
+					return clientError("failed to create session", err)
 				}
 			} else {
-				fmt.Fprintf(os.Stderr, "Error getting session: %v\n", err)
-				return
+				return clientError("failed to get session", err)
 			}
 		}
 
-		team, err = client.GetTeam(cfg.Agent, cfg.Config.UserID)
+		team, err = client.GetAgent(ctx, cfg.Agent, cfg.Config.UserID)
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error getting team: %v\n", err)
-			return
+			return clientError("failed to get agent", err)
 		}
 
 		if cfg.Stream {
 			usage := &autogen_client.ModelsUsage{}
-			ch, err := client.InvokeSessionStream(session.ID, cfg.Config.UserID, &autogen_client.InvokeRequest{
+			ch, err := client.InvokeSessionStream(ctx, session.ID, cfg.Config.UserID, &autogen_client.InvokeRequest{
 				Task:       task,
 				TeamConfig: team.Component,
 			})
 			if err != nil {
-				fmt.Fprintf(os.Stderr, "Error invoking session: %v\n", err)
-				return
+				return clientError("failed to invoke session", err)
 			}
-			StreamEvents(ch, usage, cfg.Config.Verbose)
+			StreamEvents(ctx, ch, usage, cfg.Config.Verbose)
 		} else {
-			result, err := client.InvokeSession(session.ID, cfg.Config.UserID, &autogen_client.InvokeRequest{
+			result, err := client.InvokeSession(ctx, session.ID, cfg.Config.UserID, &autogen_client.InvokeRequest{
 				Task:       task,
 				TeamConfig: team.Component,
 			})
 			if err != nil {
-				fmt.Fprintf(os.Stderr, "Error invoking session: %v\n", err)
-				return
+				return clientError("failed to invoke session", err)
 			}
 
 			enc := json.NewEncoder(os.Stdout)
 			enc.SetIndent("", "  ")
 			if err := enc.Encode(result.TaskResult); err != nil {
-				fmt.Fprintf(os.Stderr, "Error encoding task result: %v\n", err)
-				return
+				return fmt.Errorf("failed to encode task result: %w", err)
 			}
 		}
 
 	} else {
 
-		team, err := client.GetTeam(cfg.Agent, cfg.Config.UserID)
+		team, err := client.GetAgent(ctx, cfg.Agent, cfg.Config.UserID)
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error getting team: %v\n", err)
-			return
+			return clientError("failed to get agent", err)
 		}
 
 		req := &autogen_client.InvokeTaskRequest{
@@ -129,25 +117,23 @@ func InvokeCmd(ctx context.Context, cfg *InvokeCfg) {
 
 		if cfg.Stream {
 			usage := &autogen_client.ModelsUsage{}
-			ch, err := client.InvokeTaskStream(req)
+			ch, err := client.InvokeTaskStream(ctx, req)
 			if err != nil {
-				fmt.Fprintf(os.Stderr, "Error invoking task: %v\n", err)
-				return
+				return clientError("failed to invoke task", err)
 			}
-			StreamEvents(ch, usage, cfg.Config.Verbose)
+			StreamEvents(ctx, ch, usage, cfg.Config.Verbose)
 		} else {
-			result, err := client.InvokeTask(req)
+			result, err := client.InvokeTask(ctx, req)
 			if err != nil {
-				fmt.Fprintf(os.Stderr, "Error invoking task: %v\n", err)
-				return
+				return clientError("failed to invoke task", err)
 			}
 
 			enc := json.NewEncoder(os.Stdout)
 			enc.SetIndent("", "  ")
 			if err := enc.Encode(result.TaskResult); err != nil {
-				fmt.Fprintf(os.Stderr, "Error encoding task result: %v\n", err)
-				return
+				return fmt.Errorf("failed to encode task result: %w", err)
 			}
 		}
 	}
+	return nil
 }
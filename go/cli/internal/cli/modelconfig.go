@@ -0,0 +1,299 @@
+package cli
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kagent-dev/kagent/go/cli/internal/config"
+	"github.com/kagent-dev/kagent/go/controller/api/v1alpha1"
+	"golang.org/x/term"
+)
+
+// modelConfigProviders are the providers model configs can be created with from the CLI
+var modelConfigProviders = []v1alpha1.ModelProvider{
+	v1alpha1.OpenAI,
+	v1alpha1.Anthropic,
+	v1alpha1.AzureOpenAI,
+	v1alpha1.Ollama,
+}
+
+type ModelConfigCreateCfg struct {
+	Config *config.Config
+	// Name is the name of the model config, optionally prefixed with its namespace
+	Name     string
+	Provider string
+	Model    string
+	// APIKey is prompted for without echo if empty, except for Ollama
+	APIKey          string
+	AzureEndpoint   string
+	AzureAPIVersion string
+	OllamaHost      string
+}
+
+// modelConfig is a model config as returned by the controller API
+type modelConfig struct {
+	Ref             string                 `json:"ref"`
+	ProviderName    string                 `json:"providerName"`
+	Model           string                 `json:"model"`
+	APIKeySecretRef string                 `json:"apiKeySecretRef"`
+	APIKeySecretKey string                 `json:"apiKeySecretKey"`
+	ModelParams     map[string]interface{} `json:"modelParams"`
+}
+
+// createModelConfigRequest is the body of model config creations sent to the controller API
+type createModelConfigRequest struct {
+	Ref      string `json:"ref"`
+	Provider struct {
+		Name string `json:"name"`
+		Type string `json:"type"`
+	} `json:"provider"`
+	Model        string                      `json:"model"`
+	APIKey       string                      `json:"apiKey,omitempty"`
+	AzureParams  *v1alpha1.AzureOpenAIConfig `json:"azureOpenAI,omitempty"`
+	OllamaParams *v1alpha1.OllamaConfig      `json:"ollama,omitempty"`
+}
+
+func ModelConfigCreateCmd(ctx context.Context, cfg *ModelConfigCreateCfg) error {
+	if cfg.Name == "" {
+		return fmt.Errorf("model config name is required")
+	}
+
+	p := &prompter{
+		in:  bufio.NewReader(os.Stdin),
+		out: os.Stdout,
+		readSecret: func() (string, error) {
+			if !term.IsTerminal(int(os.Stdin.Fd())) {
+				return "", fmt.Errorf("stdin is not a terminal: pass the API key with --api-key")
+			}
+			key, err := term.ReadPassword(int(os.Stdin.Fd()))
+			fmt.Fprintln(os.Stdout)
+			return string(key), err
+		},
+	}
+	if err := promptModelConfig(p, cfg); err != nil {
+		return fmt.Errorf("failed to read model config: %w", err)
+	}
+
+	client, stop, err := controllerClientFor(ctx, cfg.Config)
+	if err != nil {
+		return err
+	}
+	defer stop()
+
+	var created struct {
+		Metadata struct {
+			Name      string `json:"name"`
+			Namespace string `json:"namespace"`
+		} `json:"metadata"`
+	}
+	if err := client.do(ctx, "POST", "/modelconfigs", newCreateModelConfigRequest(cfg), &created); err != nil {
+		return fmt.Errorf("failed to create model config: %w", err)
+	}
+	fmt.Fprintf(os.Stdout, "Created model config %s/%s\n", created.Metadata.Namespace, created.Metadata.Name)
+	return nil
+}
+
+func ModelConfigListCmd(ctx context.Context, cfg *config.Config) error {
+	client, stop, err := controllerClientFor(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	defer stop()
+
+	var response struct {
+		Data []*modelConfig `json:"data"`
+	}
+	if err := client.do(ctx, "GET", "/modelconfigs", nil, &response); err != nil {
+		return fmt.Errorf("failed to list model configs: %w", err)
+	}
+	if len(response.Data) == 0 {
+		fmt.Println("No model configs found")
+		return nil
+	}
+	if err := printModelConfigs(os.Stdout, response.Data); err != nil {
+		return fmt.Errorf("failed to print model configs: %w", err)
+	}
+	return nil
+}
+
+func ModelConfigGetCmd(ctx context.Context, cfg *config.Config, name string) error {
+	client, stop, err := controllerClientFor(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	defer stop()
+
+	var modelConfig modelConfig
+	if err := client.do(ctx, "GET", "/modelconfigs/"+modelConfigPath(name, cfg.Namespace), nil, &modelConfig); err != nil {
+		return fmt.Errorf("failed to get model config %s: %w", name, err)
+	}
+	if err := printJSON(os.Stdout, modelConfig); err != nil {
+		return fmt.Errorf("failed to print model config: %w", err)
+	}
+	return nil
+}
+
+func ModelConfigDeleteCmd(ctx context.Context, cfg *config.Config, name string) error {
+	client, stop, err := controllerClientFor(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	defer stop()
+
+	if err := client.do(ctx, "DELETE", "/modelconfigs/"+modelConfigPath(name, cfg.Namespace), nil, nil); err != nil {
+		return fmt.Errorf("failed to delete model config %s: %w", name, err)
+	}
+	fmt.Fprintf(os.Stdout, "Deleted model config %s\n", name)
+	return nil
+}
+
+// controllerClientFor returns a client for the controller API, port-forwarding it if it
+// can't be reached. The returned function stops the port-forward.
+func controllerClientFor(ctx context.Context, cfg *config.Config) (*controllerClient, func(), error) {
+	baseURL := cfg.ControllerURL
+	if baseURL == "" {
+		baseURL = DefaultControllerURL
+	}
+	client := newControllerClient(baseURL)
+	if client.reachable(ctx) {
+		return client, func() {}, nil
+	}
+
+	stop, err := startPortForward(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	// Try to connect 5 times
+	for i := 0; i < 5 && !client.reachable(ctx); i++ {
+		time.Sleep(50 * time.Millisecond)
+	}
+	return client, stop, nil
+}
+
+// modelConfigPath returns the {namespace}/{name} path of the model config, in namespace
+// unless name is prefixed with one
+func modelConfigPath(name, namespace string) string {
+	if strings.Contains(name, "/") {
+		return name
+	}
+	return namespace + "/" + name
+}
+
+func newCreateModelConfigRequest(cfg *ModelConfigCreateCfg) *createModelConfigRequest {
+	req := &createModelConfigRequest{
+		Ref:    modelConfigPath(cfg.Name, cfg.Config.Namespace),
+		Model:  cfg.Model,
+		APIKey: cfg.APIKey,
+	}
+	req.Provider.Name = cfg.Provider
+	req.Provider.Type = cfg.Provider
+	switch v1alpha1.ModelProvider(cfg.Provider) {
+	case v1alpha1.AzureOpenAI:
+		req.AzureParams = &v1alpha1.AzureOpenAIConfig{Endpoint: cfg.AzureEndpoint, APIVersion: cfg.AzureAPIVersion}
+	case v1alpha1.Ollama:
+		if cfg.OllamaHost != "" {
+			req.OllamaParams = &v1alpha1.OllamaConfig{Host: cfg.OllamaHost}
+		}
+	}
+	return req
+}
+
+// prompter reads the answers to prompts, and secrets without echoing them
+type prompter struct {
+	in         *bufio.Reader
+	out        io.Writer
+	readSecret func() (string, error)
+}
+
+func (p *prompter) ask(question string) (string, error) {
+	fmt.Fprintf(p.out, "%s: ", question)
+	line, err := p.in.ReadString('\n')
+	if err != nil && (err != io.EOF || line == "") {
+		return "", err
+	}
+	return strings.TrimSpace(line), nil
+}
+
+func (p *prompter) askSecret(question string) (string, error) {
+	fmt.Fprintf(p.out, "%s: ", question)
+	secret, err := p.readSecret()
+	return strings.TrimSpace(secret), err
+}
+
+// promptModelConfig prompts for the settings of the model config that weren't set with flags
+func promptModelConfig(p *prompter, cfg *ModelConfigCreateCfg) error {
+	providers := make([]string, len(modelConfigProviders))
+	for i, provider := range modelConfigProviders {
+		providers[i] = string(provider)
+	}
+	for !slices.Contains(providers, cfg.Provider) {
+		if cfg.Provider != "" {
+			fmt.Fprintf(p.out, "Unsupported provider %s\n", cfg.Provider)
+		}
+		answer, err := p.ask(fmt.Sprintf("Provider (%s)", strings.Join(providers, ", ")))
+		if err != nil {
+			return err
+		}
+		cfg.Provider = answer
+	}
+
+	for cfg.Model == "" {
+		answer, err := p.ask("Model")
+		if err != nil {
+			return err
+		}
+		cfg.Model = answer
+	}
+
+	provider := v1alpha1.ModelProvider(cfg.Provider)
+	if provider == v1alpha1.AzureOpenAI {
+		for cfg.AzureEndpoint == "" {
+			answer, err := p.ask("Azure endpoint")
+			if err != nil {
+				return err
+			}
+			cfg.AzureEndpoint = answer
+		}
+		for cfg.AzureAPIVersion == "" {
+			answer, err := p.ask("Azure API version")
+			if err != nil {
+				return err
+			}
+			cfg.AzureAPIVersion = answer
+		}
+	}
+
+	if provider != v1alpha1.Ollama {
+		for cfg.APIKey == "" {
+			answer, err := p.askSecret("API key")
+			if err != nil {
+				return err
+			}
+			cfg.APIKey = answer
+		}
+	}
+	return nil
+}
+
+func printModelConfigs(w io.Writer, modelConfigs []*modelConfig) error {
+	headers := []string{"#", "NAME", "PROVIDER", "MODEL", "API KEY SECRET"}
+	rows := make([][]string, len(modelConfigs))
+	for i, modelConfig := range modelConfigs {
+		rows[i] = []string{
+			strconv.Itoa(i + 1),
+			modelConfig.Ref,
+			modelConfig.ProviderName,
+			modelConfig.Model,
+			modelConfig.APIKeySecretRef,
+		}
+	}
+
+	return fprintOutput(w, modelConfigs, headers, rows)
+}
@@ -0,0 +1,120 @@
+package cli
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/kagent-dev/kagent/go/cli/internal/config"
+)
+
+func TestPromptModelConfig(t *testing.T) {
+	newPrompter := func(input string, secrets ...string) (*prompter, *strings.Builder) {
+		out := &strings.Builder{}
+		return &prompter{
+			in:  bufio.NewReader(strings.NewReader(input)),
+			out: out,
+			readSecret: func() (string, error) {
+				secret := secrets[0]
+				secrets = secrets[1:]
+				return secret, nil
+			},
+		}, out
+	}
+
+	t.Run("prompts for the missing settings", func(t *testing.T) {
+		p, out := newPrompter("Gemini\nOpenAI\ngpt-4o\n", "sk-secret")
+		cfg := &ModelConfigCreateCfg{Name: "my-model"}
+		if err := promptModelConfig(p, cfg); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if cfg.Provider != "OpenAI" || cfg.Model != "gpt-4o" || cfg.APIKey != "sk-secret" {
+			t.Fatalf("unexpected config: %+v", cfg)
+		}
+		if !strings.Contains(out.String(), "Unsupported provider Gemini") {
+			t.Errorf("expected the unsupported provider to be reported, got %q", out.String())
+		}
+		if strings.Contains(out.String(), "sk-secret") {
+			t.Errorf("the API key was echoed: %q", out.String())
+		}
+	})
+
+	t.Run("doesn't ask for an API key for Ollama", func(t *testing.T) {
+		p, _ := newPrompter("llama3\n")
+		cfg := &ModelConfigCreateCfg{Name: "local", Provider: "Ollama"}
+		if err := promptModelConfig(p, cfg); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if cfg.Model != "llama3" || cfg.APIKey != "" {
+			t.Fatalf("unexpected config: %+v", cfg)
+		}
+	})
+
+	t.Run("asks for the Azure endpoint and API version", func(t *testing.T) {
+		p, _ := newPrompter("https://example.openai.azure.com\n2024-06-01\n", "azure-key")
+		cfg := &ModelConfigCreateCfg{Name: "azure", Provider: "AzureOpenAI", Model: "gpt-4o"}
+		if err := promptModelConfig(p, cfg); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if cfg.AzureEndpoint != "https://example.openai.azure.com" || cfg.AzureAPIVersion != "2024-06-01" {
+			t.Fatalf("unexpected config: %+v", cfg)
+		}
+	})
+
+	t.Run("fails when the input ends", func(t *testing.T) {
+		p, _ := newPrompter("")
+		if err := promptModelConfig(p, &ModelConfigCreateCfg{Name: "my-model"}); err == nil {
+			t.Fatal("expected an error")
+		}
+	})
+}
+
+func TestControllerClient(t *testing.T) {
+	var received createModelConfigRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/api/modelconfigs":
+			if err := json.NewDecoder(r.Body).Decode(&received); err != nil {
+				t.Errorf("failed to decode request: %v", err)
+			}
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"metadata":{"name":"my-model","namespace":"kagent"}}`))
+		case r.Method == http.MethodGet && r.URL.Path == "/api/version":
+			_, _ = w.Write([]byte(`{"version":"dev"}`))
+		case r.URL.Path == "/api/modelconfigs/kagent/missing":
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"error":"ModelConfig not found","code":"not_found"}`))
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer server.Close()
+	client := newControllerClient(server.URL + "/api/")
+
+	if !client.reachable(context.Background()) {
+		t.Error("expected the controller to be reachable")
+	}
+
+	cfg := &ModelConfigCreateCfg{
+		Config:   &config.Config{Namespace: "kagent"},
+		Name:     "my-model",
+		Provider: "OpenAI",
+		Model:    "gpt-4o",
+		APIKey:   "sk-secret",
+	}
+	if err := client.do(context.Background(), "POST", "/modelconfigs", newCreateModelConfigRequest(cfg), nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if received.Ref != "kagent/my-model" || received.Provider.Type != "OpenAI" || received.APIKey != "sk-secret" {
+		t.Errorf("unexpected request: %+v", received)
+	}
+
+	err := client.do(context.Background(), "GET", "/modelconfigs/"+modelConfigPath("missing", "kagent"), nil, &modelConfig{})
+	if err == nil || !strings.Contains(err.Error(), "ModelConfig not found (404)") {
+		t.Errorf("expected the controller's error message, got %v", err)
+	}
+}
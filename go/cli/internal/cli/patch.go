@@ -0,0 +1,52 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/kagent-dev/kagent/go/cli/internal/config"
+)
+
+type PatchAgentCfg struct {
+	Config *config.Config
+	// Name is the name of the agent, optionally prefixed with its namespace
+	Name string
+	// Patch is a JSON merge patch of the Agent resource
+	Patch string
+}
+
+func PatchAgentCmd(ctx context.Context, cfg *PatchAgentCfg) error {
+	patch, err := parseMergePatch(cfg.Patch)
+	if err != nil {
+		return err
+	}
+
+	client, stop, err := controllerClientFor(ctx, cfg.Config)
+	if err != nil {
+		return err
+	}
+	defer stop()
+
+	namespace, name := cfg.Config.Namespace, cfg.Name
+	if ns, n, ok := strings.Cut(cfg.Name, "/"); ok {
+		namespace, name = ns, n
+	}
+	team, err := client.PatchTeam(ctx, namespace, name, patch)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stdout, "Patched agent %s/%s\n", team.Namespace, team.Name)
+	return nil
+}
+
+// parseMergePatch parses a JSON merge patch, which must be an object
+func parseMergePatch(data string) (map[string]interface{}, error) {
+	var patch map[string]interface{}
+	if err := json.Unmarshal([]byte(data), &patch); err != nil || patch == nil {
+		return nil, fmt.Errorf("the patch must be a JSON object, like {\"spec\":{\"description\":\"...\"}}")
+	}
+	return patch, nil
+}
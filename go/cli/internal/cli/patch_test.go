@@ -0,0 +1,68 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kagent-dev/kagent/go/cli/internal/config"
+)
+
+func TestPatchAgentCmd(t *testing.T) {
+	var patchedPath string
+	var received map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/api/version":
+			_, _ = w.Write([]byte(`{}`))
+		case r.Method == http.MethodPatch:
+			patchedPath = r.URL.Path
+			if err := json.NewDecoder(r.Body).Decode(&received); err != nil {
+				t.Errorf("invalid patch: %v", err)
+			}
+			_, _ = w.Write([]byte(`{"metadata":{"name":"k8s-agent","namespace":"other"}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+	cfg := &config.Config{ControllerURL: server.URL + "/api", Namespace: "kagent"}
+
+	t.Run("patches the agent in the namespace of the config", func(t *testing.T) {
+		err := PatchAgentCmd(context.Background(), &PatchAgentCfg{Config: cfg, Name: "k8s-agent", Patch: `{"spec":{"description":"patched"}}`})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if patchedPath != "/api/teams/kagent/k8s-agent" {
+			t.Errorf("unexpected path %s", patchedPath)
+		}
+		spec, _ := received["spec"].(map[string]interface{})
+		if spec["description"] != "patched" {
+			t.Errorf("unexpected patch %v", received)
+		}
+	})
+
+	t.Run("uses the namespace of the name", func(t *testing.T) {
+		err := PatchAgentCmd(context.Background(), &PatchAgentCfg{Config: cfg, Name: "other/k8s-agent", Patch: `{}`})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if patchedPath != "/api/teams/other/k8s-agent" {
+			t.Errorf("unexpected path %s", patchedPath)
+		}
+	})
+
+	t.Run("rejects patches that aren't objects", func(t *testing.T) {
+		for _, patch := range []string{``, `null`, `"spec"`, `[]`, `{`} {
+			patchedPath = ""
+			if err := PatchAgentCmd(context.Background(), &PatchAgentCfg{Config: cfg, Name: "k8s-agent", Patch: patch}); err == nil {
+				t.Errorf("expected an error for %q", patch)
+			}
+			if patchedPath != "" {
+				t.Errorf("the agent was patched with %q", patch)
+			}
+		}
+	})
+}
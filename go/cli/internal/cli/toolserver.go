@@ -0,0 +1,263 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	autogen_client "github.com/kagent-dev/kagent/go/autogen/client"
+	"github.com/kagent-dev/kagent/go/cli/internal/config"
+	"github.com/kagent-dev/kagent/go/controller/api/v1alpha1"
+	"sigs.k8s.io/yaml"
+)
+
+// DefaultToolServerWait is how long toolserver create waits for the tools to be discovered
+const DefaultToolServerWait = 30 * time.Second
+
+const (
+	toolServerTransportSse            = "sse"
+	toolServerTransportStreamableHttp = "streamable-http"
+)
+
+type ToolServerCreateCfg struct {
+	Config *config.Config
+	// Name is the name of the tool server, optionally prefixed with its namespace. It
+	// overrides the name in File.
+	Name string
+	// File is a ToolServer manifest, in YAML or JSON. The flags below are ignored if set.
+	File        string
+	Description string
+	// URL and Transport configure a remote MCP server, Command and Args a stdio one
+	URL       string
+	Transport string
+	Command   string
+	Args      []string
+	// Wait is how long to wait for the tools to be discovered. 0 doesn't wait.
+	Wait time.Duration
+}
+
+// toolServer is a tool server as returned by the controller API
+type toolServer struct {
+	Ref             string                    `json:"ref"`
+	Config          v1alpha1.ToolServerConfig `json:"config"`
+	DiscoveredTools []*v1alpha1.MCPTool       `json:"discoveredTools"`
+}
+
+func ToolServerCreateCmd(ctx context.Context, cfg *ToolServerCreateCfg) error {
+	server, err := buildToolServer(cfg)
+	if err != nil {
+		return fmt.Errorf("invalid tool server: %w", err)
+	}
+	ref := server.Namespace + "/" + server.Name
+
+	client, stop, err := controllerClientFor(ctx, cfg.Config)
+	if err != nil {
+		return err
+	}
+	defer stop()
+
+	path := "/toolservers?user_id=" + url.QueryEscape(cfg.Config.UserID)
+	if err := client.do(ctx, "POST", path, server, nil); err != nil {
+		return fmt.Errorf("failed to create tool server: %w", err)
+	}
+	fmt.Fprintf(os.Stdout, "Created tool server %s\n", ref)
+
+	if cfg.Wait <= 0 {
+		return nil
+	}
+	fmt.Fprintln(os.Stdout, "Waiting for tools to be discovered...")
+	tools, err := waitForDiscoveredTools(ctx, client, ref, cfg.Wait, time.Second)
+	if err != nil {
+		return fmt.Errorf("failed to get discovered tools: %w", err)
+	}
+	if len(tools) == 0 {
+		fmt.Fprintf(os.Stdout, "No tools discovered after %s. Check again with 'kagent toolserver list'.\n", cfg.Wait)
+		return nil
+	}
+	printDiscoveredTools(os.Stdout, tools)
+	return nil
+}
+
+func ToolServerListCmd(ctx context.Context, cfg *config.Config) error {
+	client, stop, err := controllerClientFor(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	defer stop()
+
+	servers, err := listToolServers(ctx, client)
+	if err != nil {
+		return fmt.Errorf("failed to list tool servers: %w", err)
+	}
+	if len(servers) == 0 {
+		fmt.Println("No tool servers found")
+		return nil
+	}
+	if err := printToolServers(os.Stdout, servers); err != nil {
+		return fmt.Errorf("failed to print tool servers: %w", err)
+	}
+	return nil
+}
+
+func ToolServerDeleteCmd(ctx context.Context, cfg *config.Config, name string) error {
+	client, stop, err := controllerClientFor(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	defer stop()
+
+	if err := client.do(ctx, "DELETE", "/toolservers/"+modelConfigPath(name, cfg.Namespace), nil, nil); err != nil {
+		return fmt.Errorf("failed to delete tool server %s: %w", name, err)
+	}
+	fmt.Fprintf(os.Stdout, "Deleted tool server %s\n", name)
+	return nil
+}
+
+// ToolServerRefreshCmd makes Autogen discover the tools of the tool server again
+func ToolServerRefreshCmd(cfg *config.Config, name string) error {
+	client := autogen_client.New(cfg.APIURL)
+	ref := modelConfigPath(name, cfg.Namespace)
+
+	server, err := client.GetToolServerByLabel(ref, cfg.UserID)
+	if err != nil {
+		return clientError(fmt.Sprintf("failed to get tool server %s", ref), err)
+	}
+	if err := client.RefreshToolServer(server.Id, cfg.UserID); err != nil {
+		return clientError(fmt.Sprintf("failed to refresh tool server %s", ref), err)
+	}
+	tools, err := client.ListToolsForServer(&server.Id, cfg.UserID)
+	if err != nil {
+		return clientError(fmt.Sprintf("failed to list tools of %s", ref), err)
+	}
+	fmt.Fprintf(os.Stdout, "Refreshed tool server %s: %d tool(s) discovered\n", ref, len(tools))
+	return nil
+}
+
+// buildToolServer returns the ToolServer to create, read from the file or built from the flags
+func buildToolServer(cfg *ToolServerCreateCfg) (*v1alpha1.ToolServer, error) {
+	server := &v1alpha1.ToolServer{}
+	if cfg.File != "" {
+		data, err := os.ReadFile(cfg.File)
+		if err != nil {
+			return nil, err
+		}
+		if err := yaml.Unmarshal(data, server); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", cfg.File, err)
+		}
+	} else {
+		server.Spec.Description = cfg.Description
+		switch {
+		case cfg.URL != "" && cfg.Command != "":
+			return nil, fmt.Errorf("set either --url or --command, not both")
+		case cfg.URL != "":
+			httpConfig := v1alpha1.HttpToolServerConfig{URL: cfg.URL}
+			switch cfg.Transport {
+			case toolServerTransportStreamableHttp:
+				server.Spec.Config.StreamableHttp = &v1alpha1.StreamableHttpServerConfig{HttpToolServerConfig: httpConfig}
+			case toolServerTransportSse, "":
+				server.Spec.Config.Sse = &v1alpha1.SseMcpServerConfig{HttpToolServerConfig: httpConfig}
+			default:
+				return nil, fmt.Errorf("unknown transport %q: must be %s or %s", cfg.Transport, toolServerTransportSse, toolServerTransportStreamableHttp)
+			}
+		case cfg.Command != "":
+			server.Spec.Config.Stdio = &v1alpha1.StdioMcpServerConfig{Command: cfg.Command, Args: cfg.Args}
+		default:
+			return nil, fmt.Errorf("set --file, --url or --command")
+		}
+	}
+
+	if cfg.Name != "" {
+		server.Namespace, server.Name = "", cfg.Name
+		if namespace, name, ok := strings.Cut(cfg.Name, "/"); ok {
+			server.Namespace, server.Name = namespace, name
+		}
+	}
+	if server.Name == "" {
+		return nil, fmt.Errorf("the tool server needs a name")
+	}
+	if server.Namespace == "" {
+		server.Namespace = cfg.Config.Namespace
+	}
+	if toolServerType(server.Spec.Config) == "" {
+		return nil, fmt.Errorf("the tool server has no stdio, sse or streamableHttp config")
+	}
+	return server, nil
+}
+
+func listToolServers(ctx context.Context, client *controllerClient) ([]*toolServer, error) {
+	var response struct {
+		Data []*toolServer `json:"data"`
+	}
+	if err := client.do(ctx, "GET", "/toolservers", nil, &response); err != nil {
+		return nil, err
+	}
+	return response.Data, nil
+}
+
+// waitForDiscoveredTools polls the tool servers until the one with the ref has discovered
+// tools or the timeout expires, and returns its tools
+func waitForDiscoveredTools(ctx context.Context, client *controllerClient, ref string, timeout, interval time.Duration) ([]*v1alpha1.MCPTool, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	for {
+		servers, err := listToolServers(ctx, client)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, nil
+			}
+			return nil, err
+		}
+		for _, server := range servers {
+			if server.Ref == ref && len(server.DiscoveredTools) > 0 {
+				return server.DiscoveredTools, nil
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, nil
+		case <-time.After(interval):
+		}
+	}
+}
+
+func toolServerType(config v1alpha1.ToolServerConfig) string {
+	switch {
+	case config.Stdio != nil:
+		return "stdio"
+	case config.Sse != nil:
+		return toolServerTransportSse
+	case config.StreamableHttp != nil:
+		return toolServerTransportStreamableHttp
+	}
+	return ""
+}
+
+func printDiscoveredTools(w io.Writer, tools []*v1alpha1.MCPTool) {
+	names := make([]string, len(tools))
+	for i, tool := range tools {
+		names[i] = tool.Name
+	}
+	fmt.Fprintf(w, "Discovered %d tool(s): %s\n", len(tools), strings.Join(names, ", "))
+}
+
+func printToolServers(w io.Writer, servers []*toolServer) error {
+	headers := []string{"#", "NAME", "TYPE", "TOOLS"}
+	rows := make([][]string, len(servers))
+	for i, server := range servers {
+		rows[i] = []string{
+			strconv.Itoa(i + 1),
+			server.Ref,
+			toolServerType(server.Config),
+			strconv.Itoa(len(server.DiscoveredTools)),
+		}
+	}
+
+	return fprintOutput(w, servers, headers, rows)
+}
@@ -0,0 +1,114 @@
+package cli
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/kagent-dev/kagent/go/cli/internal/config"
+)
+
+func TestBuildToolServer(t *testing.T) {
+	cfg := &config.Config{Namespace: "kagent"}
+
+	t.Run("builds a remote server from flags", func(t *testing.T) {
+		server, err := buildToolServer(&ToolServerCreateCfg{
+			Config:    cfg,
+			Name:      "fetch",
+			URL:       "http://fetch:8080/mcp",
+			Transport: toolServerTransportStreamableHttp,
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if server.Namespace != "kagent" || server.Name != "fetch" {
+			t.Errorf("unexpected name %s/%s", server.Namespace, server.Name)
+		}
+		if server.Spec.Config.StreamableHttp == nil || server.Spec.Config.StreamableHttp.URL != "http://fetch:8080/mcp" {
+			t.Errorf("unexpected config: %+v", server.Spec.Config)
+		}
+	})
+
+	t.Run("builds a stdio server from flags", func(t *testing.T) {
+		server, err := buildToolServer(&ToolServerCreateCfg{
+			Config:  cfg,
+			Name:    "tools/everything",
+			Command: "npx",
+			Args:    []string{"-y", "@modelcontextprotocol/server-everything"},
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if server.Namespace != "tools" || server.Spec.Config.Stdio == nil || len(server.Spec.Config.Stdio.Args) != 2 {
+			t.Errorf("unexpected tool server: %+v", server)
+		}
+	})
+
+	t.Run("reads a manifest", func(t *testing.T) {
+		file := filepath.Join(t.TempDir(), "toolserver.yaml")
+		manifest := `apiVersion: kagent.dev/v1alpha1
+kind: ToolServer
+metadata:
+  name: fetch
+spec:
+  description: Fetches URLs
+  config:
+    sse:
+      url: http://fetch:8080/sse
+`
+		if err := os.WriteFile(file, []byte(manifest), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		server, err := buildToolServer(&ToolServerCreateCfg{Config: cfg, File: file})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if server.Name != "fetch" || server.Namespace != "kagent" || server.Spec.Config.Sse == nil {
+			t.Errorf("unexpected tool server: %+v", server)
+		}
+	})
+
+	t.Run("rejects invalid flags", func(t *testing.T) {
+		for name, createCfg := range map[string]*ToolServerCreateCfg{
+			"no config":         {Config: cfg, Name: "fetch"},
+			"url and command":   {Config: cfg, Name: "fetch", URL: "http://fetch", Command: "fetch"},
+			"unknown transport": {Config: cfg, Name: "fetch", URL: "http://fetch", Transport: "websocket"},
+			"no name":           {Config: cfg, URL: "http://fetch"},
+		} {
+			if _, err := buildToolServer(createCfg); err == nil {
+				t.Errorf("%s: expected an error", name)
+			}
+		}
+	})
+}
+
+func TestWaitForDiscoveredTools(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) < 3 {
+			_, _ = w.Write([]byte(`{"data":[{"ref":"kagent/fetch","config":{"sse":{"url":"http://fetch"}}}]}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"data":[{"ref":"kagent/fetch","config":{"sse":{"url":"http://fetch"}},"discoveredTools":[{"name":"fetch"}]}]}`))
+	}))
+	defer server.Close()
+	client := newControllerClient(server.URL)
+
+	tools, err := waitForDiscoveredTools(context.Background(), client, "kagent/fetch", 5*time.Second, time.Millisecond)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(tools) != 1 || tools[0].Name != "fetch" {
+		t.Errorf("unexpected tools: %+v", tools)
+	}
+
+	tools, err = waitForDiscoveredTools(context.Background(), client, "kagent/missing", 20*time.Millisecond, time.Millisecond)
+	if err != nil || tools != nil {
+		t.Errorf("expected no tools and no error on timeout, got %+v, %v", tools, err)
+	}
+}
@@ -69,7 +69,8 @@ func (p *portForward) Stop() {
 	}
 }
 
-func StreamEvents(ch <-chan *autogen_client.SseEvent, usage *autogen_client.ModelsUsage, verbose bool) {
+// StreamEvents prints events from ch until the channel is closed or ctx is done
+func StreamEvents(ctx context.Context, ch <-chan *autogen_client.SseEvent, usage *autogen_client.ModelsUsage, verbose bool) {
 	// Tool call requests and executions are sent as separate messages, but we should print them together
 	// so if we receive a tool call request, we buffer it until we receive the corresponding tool call execution
 	// We only need to buffer one request and one execution at a time
@@ -77,7 +78,17 @@ func StreamEvents(ch <-chan *autogen_client.SseEvent, usage *autogen_client.Mode
 	// This is a map of agent source to whether we are currently streaming from that agent
 	// If we are then we don't want to print the whole TextMessage, but only the content of the ModelStreamingEvent
 	streaming := map[string]bool{}
-	for event := range ch {
+	for {
+		var event *autogen_client.SseEvent
+		select {
+		case <-ctx.Done():
+			return
+		case e, ok := <-ch:
+			if !ok {
+				return
+			}
+			event = e
+		}
 		ev, err := autogen_client.ParseEvent(event.Data)
 		if err != nil {
 			// TODO: verbose logging
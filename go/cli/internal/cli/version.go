@@ -3,6 +3,7 @@ package cli
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"github.com/kagent-dev/kagent/go/internal/version"
 	"os"
 	"time"
@@ -11,8 +12,8 @@ import (
 	"github.com/kagent-dev/kagent/go/cli/internal/config"
 )
 
-func VersionCmd(cfg *config.Config) {
-	versionInfo := map[string]string{
+func VersionCmd(cfg *config.Config) error {
+	versionInfo := map[string]interface{}{
 		"kagent_version": version.Version,
 		"git_commit":     version.GitCommit,
 		"build_date":     version.BuildDate,
@@ -20,12 +21,18 @@ func VersionCmd(cfg *config.Config) {
 	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
 	defer cancel()
 	client := autogen_client.New(cfg.APIURL)
-	version, err := client.GetVersion(ctx)
+	version, err := client.GetVersionInfo(ctx)
 	if err != nil {
 		versionInfo["backend_version"] = "unknown"
 	} else {
-		versionInfo["backend_version"] = version
+		versionInfo["backend_version"] = version.Version
+		if len(version.Features) > 0 {
+			versionInfo["backend_features"] = version.Features
+		}
 	}
 
-	json.NewEncoder(os.Stdout).Encode(versionInfo)
+	if err := json.NewEncoder(os.Stdout).Encode(versionInfo); err != nil {
+		return fmt.Errorf("failed to print version: %w", err)
+	}
+	return nil
 }
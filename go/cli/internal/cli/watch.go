@@ -0,0 +1,83 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	autogen_client "github.com/kagent-dev/kagent/go/autogen/client"
+	"github.com/kagent-dev/kagent/go/cli/internal/config"
+)
+
+// DefaultWatchInterval is how often get --watch polls for changes
+const DefaultWatchInterval = 2 * time.Second
+
+// clearScreen moves the cursor to the top left corner and clears the terminal
+const clearScreen = "\033[H\033[2J"
+
+// WatchSessionsCmd lists sessions every interval and redraws the table when it changes,
+// until ctx is cancelled
+func WatchSessionsCmd(ctx context.Context, cfg *config.Config, interval time.Duration) {
+	client := autogen_client.New(cfg.APIURL)
+	watchList(ctx, os.Stdout, interval, func(w io.Writer) error {
+		sessionList, err := client.ListSessions(cfg.UserID)
+		if err != nil {
+			return fmt.Errorf("failed to get sessions: %w", err)
+		}
+		if len(sessionList) == 0 {
+			fmt.Fprintln(w, "No sessions found")
+			return nil
+		}
+		return printSessions(w, sessionList)
+	})
+}
+
+// WatchAgentsCmd lists agents every interval and redraws the table when it changes,
+// until ctx is cancelled
+func WatchAgentsCmd(ctx context.Context, cfg *config.Config, interval time.Duration) {
+	client := autogen_client.New(cfg.APIURL)
+	watchList(ctx, os.Stdout, interval, func(w io.Writer) error {
+		agentList, err := client.ListTeams(cfg.UserID)
+		if err != nil {
+			return fmt.Errorf("failed to get agents: %w", err)
+		}
+		if len(agentList) == 0 {
+			fmt.Fprintln(w, "No agents found")
+			return nil
+		}
+		return printTeams(w, agentList)
+	})
+}
+
+// watchList calls render every interval and writes its output to out, clearing the screen
+// first, whenever the output differs from the previous one. Errors are shown in place of the
+// output and watching carries on, so a temporarily unavailable server doesn't end the watch.
+func watchList(ctx context.Context, out io.Writer, interval time.Duration, render func(w io.Writer) error) {
+	if interval <= 0 {
+		interval = DefaultWatchInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	previous := ""
+	for {
+		var buf bytes.Buffer
+		if err := render(&buf); err != nil {
+			buf.Reset()
+			fmt.Fprintf(&buf, "Error: %v\n", err)
+		}
+		if current := buf.String(); current != previous {
+			fmt.Fprint(out, clearScreen+current)
+			previous = current
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,43 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWatchList(t *testing.T) {
+	outputs := []string{"a\n", "a\n", "b\n", "error", "b\n"}
+	calls := 0
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var out bytes.Buffer
+	watchList(ctx, &out, time.Millisecond, func(w io.Writer) error {
+		output := outputs[calls]
+		calls++
+		if calls == len(outputs) {
+			cancel()
+		}
+		if output == "error" {
+			return fmt.Errorf("server unavailable")
+		}
+		fmt.Fprint(w, output)
+		return nil
+	})
+
+	if calls != len(outputs) {
+		t.Fatalf("expected %d renders, got %d", len(outputs), calls)
+	}
+	// Unchanged output is not redrawn
+	renders := strings.Split(out.String(), clearScreen)[1:]
+	expected := []string{"a\n", "b\n", "Error: server unavailable\n", "b\n"}
+	if strings.Join(renders, "|") != strings.Join(expected, "|") {
+		t.Fatalf("expected renders %q, got %q", expected, renders)
+	}
+}
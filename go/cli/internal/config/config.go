@@ -10,12 +10,17 @@ import (
 )
 
 type Config struct {
-	APIURL       string `mapstructure:"api_url"`
-	UserID       string `mapstructure:"user_id"`
-	Namespace    string `mapstructure:"namespace"`
-	A2AURL       string `mapstructure:"a2a_url"`
-	OutputFormat string `mapstructure:"output_format"`
-	Verbose      bool   `mapstructure:"verbose"`
+	APIURL    string `mapstructure:"api_url"`
+	UserID    string `mapstructure:"user_id"`
+	Namespace string `mapstructure:"namespace"`
+	A2AURL    string `mapstructure:"a2a_url"`
+	// ControllerURL is the base URL of the controller API, which serves model configs
+	ControllerURL string `mapstructure:"controller_url"`
+	OutputFormat  string `mapstructure:"output_format"`
+	Verbose       bool   `mapstructure:"verbose"`
+	// CurrentContext is the context used when --context isn't set
+	CurrentContext string              `mapstructure:"current_context"`
+	Contexts       map[string]*Context `mapstructure:"contexts"`
 }
 
 func Init() error {
@@ -42,8 +47,9 @@ func Init() error {
 	viper.SetDefault("output_format", "table")
 	viper.SetDefault("namespace", "kagent")
 	viper.SetDefault("a2a_url", "http://localhost:8083/api/a2a")
+	viper.SetDefault("controller_url", "http://localhost:8083/api")
 
-	viper.MustBindEnv("USER_ID")
+	viper.MustBindEnv(userIDEnv)
 
 	if err := viper.ReadInConfig(); err != nil {
 		// If config file doesn't exist, create it with defaults
@@ -58,7 +64,28 @@ func Init() error {
 	return nil
 }
 
+// Get returns the config, with the settings of the active context applied unless they are
+// overridden by flags
 func Get() (*Config, error) {
+	config, err := Load()
+	if err != nil {
+		return nil, err
+	}
+
+	name := ActiveContext(config)
+	if name == "" {
+		return config, nil
+	}
+	context, ok := config.Contexts[name]
+	if !ok {
+		return nil, fmt.Errorf("context %s not found in %s", name, viper.ConfigFileUsed())
+	}
+	config.applyContext(context)
+	return config, nil
+}
+
+// Load returns the config without applying the active context
+func Load() (*Config, error) {
 	var config Config
 	if err := viper.Unmarshal(&config); err != nil {
 		return nil, fmt.Errorf("error unmarshaling config: %w", err)
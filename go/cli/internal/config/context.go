@@ -0,0 +1,201 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+	"sigs.k8s.io/yaml"
+)
+
+const userIDEnv = "USER_ID"
+
+// contextNameRegex matches valid context names. Viper lowercases keys and splits them on
+// dots, so names are restricted to lowercase letters, digits, '-' and '_'.
+var contextNameRegex = regexp.MustCompile(`^[a-z0-9]([a-z0-9_-]*[a-z0-9])?$`)
+
+// Context is a named set of server settings, like a kubeconfig context. The settings of the
+// active context override the top-level ones of the config file.
+type Context struct {
+	APIURL        string `mapstructure:"api_url" json:"api_url,omitempty"`
+	UserID        string `mapstructure:"user_id" json:"user_id,omitempty"`
+	Namespace     string `mapstructure:"namespace" json:"namespace,omitempty"`
+	A2AURL        string `mapstructure:"a2a_url" json:"a2a_url,omitempty"`
+	ControllerURL string `mapstructure:"controller_url" json:"controller_url,omitempty"`
+}
+
+// flagKeys are the config keys set by the flags bound with BindFlags
+var flagKeys = map[string]string{
+	"api-url":        "api_url",
+	"user-id":        "user_id",
+	"namespace":      "namespace",
+	"a2a-url":        "a2a_url",
+	"controller-url": "controller_url",
+	"output-format":  "output_format",
+	"verbose":        "verbose",
+	"context":        "context",
+}
+
+// boundFlags are the flags bound with BindFlags, by config key
+var boundFlags = map[string]*pflag.Flag{}
+
+// BindFlags makes the flags that are set override the config file and the active context
+func BindFlags(flags *pflag.FlagSet) error {
+	for name, key := range flagKeys {
+		flag := flags.Lookup(name)
+		if flag == nil {
+			continue
+		}
+		if err := viper.BindPFlag(key, flag); err != nil {
+			return fmt.Errorf("error binding flag %s: %w", name, err)
+		}
+		boundFlags[key] = flag
+	}
+	return nil
+}
+
+// ActiveContext returns the name of the context set with --context, or else the current one
+func ActiveContext(config *Config) string {
+	if name := viper.GetString("context"); name != "" {
+		return name
+	}
+	return config.CurrentContext
+}
+
+// overridden reports whether the setting was set with a flag or the environment
+func overridden(key string) bool {
+	if flag, ok := boundFlags[key]; ok && flag.Changed {
+		return true
+	}
+	if key == "user_id" {
+		_, ok := os.LookupEnv(userIDEnv)
+		return ok
+	}
+	return false
+}
+
+func (c *Config) applyContext(context *Context) {
+	for _, setting := range []struct {
+		key   string
+		value string
+		field *string
+	}{
+		{"api_url", context.APIURL, &c.APIURL},
+		{"user_id", context.UserID, &c.UserID},
+		{"namespace", context.Namespace, &c.Namespace},
+		{"a2a_url", context.A2AURL, &c.A2AURL},
+		{"controller_url", context.ControllerURL, &c.ControllerURL},
+	} {
+		if setting.value != "" && !overridden(setting.key) {
+			*setting.field = setting.value
+		}
+	}
+}
+
+// ContextNames returns the names of the contexts, sorted
+func (c *Config) ContextNames() []string {
+	names := make([]string, 0, len(c.Contexts))
+	for name := range c.Contexts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// SetContext creates the context in the config file, or updates the settings of an existing
+// one with the non-empty settings of context
+func SetContext(name string, context *Context) error {
+	if !contextNameRegex.MatchString(name) {
+		return fmt.Errorf("invalid context name %q: must consist of lowercase letters, digits, '-' and '_'", name)
+	}
+	return updateConfigFile(func(file map[string]interface{}) error {
+		contexts := fileContexts(file)
+		settings, _ := contexts[name].(map[string]interface{})
+		if settings == nil {
+			settings = map[string]interface{}{}
+		}
+		for key, value := range map[string]string{
+			"api_url":        context.APIURL,
+			"user_id":        context.UserID,
+			"namespace":      context.Namespace,
+			"a2a_url":        context.A2AURL,
+			"controller_url": context.ControllerURL,
+		} {
+			if value != "" {
+				settings[key] = value
+			}
+		}
+		contexts[name] = settings
+		file["contexts"] = contexts
+		return nil
+	})
+}
+
+// UseContext makes the context the current one in the config file
+func UseContext(name string) error {
+	return updateConfigFile(func(file map[string]interface{}) error {
+		if _, ok := fileContexts(file)[name]; !ok {
+			return fmt.Errorf("context %s not found", name)
+		}
+		file["current_context"] = name
+		return nil
+	})
+}
+
+// DeleteContext removes the context from the config file. The current context is unset if
+// it's the one removed.
+func DeleteContext(name string) error {
+	return updateConfigFile(func(file map[string]interface{}) error {
+		contexts := fileContexts(file)
+		if _, ok := contexts[name]; !ok {
+			return fmt.Errorf("context %s not found", name)
+		}
+		delete(contexts, name)
+		file["contexts"] = contexts
+		if file["current_context"] == name {
+			delete(file, "current_context")
+		}
+		return nil
+	})
+}
+
+func fileContexts(file map[string]interface{}) map[string]interface{} {
+	contexts, _ := file["contexts"].(map[string]interface{})
+	if contexts == nil {
+		contexts = map[string]interface{}{}
+	}
+	return contexts
+}
+
+// updateConfigFile applies update to the settings of the config file and writes them back.
+// Viper isn't used to write them as it would also write the values of the flags.
+func updateConfigFile(update func(file map[string]interface{}) error) error {
+	path := viper.ConfigFileUsed()
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("error reading config file: %w", err)
+	}
+
+	var file map[string]interface{}
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return fmt.Errorf("error parsing config file: %w", err)
+	}
+	if file == nil {
+		file = map[string]interface{}{}
+	}
+	if err := update(file); err != nil {
+		return err
+	}
+
+	data, err = yaml.Marshal(file)
+	if err != nil {
+		return fmt.Errorf("error marshaling config: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("error writing config file: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,154 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+)
+
+const testConfigFile = `api_url: http://localhost:8081/api
+user_id: admin@kagent.dev
+namespace: kagent
+current_context: staging
+contexts:
+  staging:
+    api_url: https://staging.example.com/api
+    user_id: alice@example.com
+  prod:
+    api_url: https://prod.example.com/api
+    namespace: kagent-prod
+`
+
+// setupTestConfig loads content as the config file and returns the flags bound to it
+func setupTestConfig(t *testing.T, content string) *pflag.FlagSet {
+	t.Helper()
+	viper.Reset()
+	boundFlags = map[string]*pflag.Flag{}
+	t.Cleanup(viper.Reset)
+
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	viper.SetConfigFile(path)
+	if err := viper.ReadInConfig(); err != nil {
+		t.Fatal(err)
+	}
+
+	flags := pflag.NewFlagSet("kagent", pflag.ContinueOnError)
+	flags.String("api-url", "http://localhost:8081/api", "")
+	flags.String("user-id", "admin@kagent.dev", "")
+	flags.String("namespace", "kagent", "")
+	flags.String("context", "", "")
+	if err := BindFlags(flags); err != nil {
+		t.Fatal(err)
+	}
+	return flags
+}
+
+func TestGetResolvesContext(t *testing.T) {
+	t.Run("applies the current context", func(t *testing.T) {
+		setupTestConfig(t, testConfigFile)
+		cfg, err := Get()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if cfg.APIURL != "https://staging.example.com/api" || cfg.UserID != "alice@example.com" || cfg.Namespace != "kagent" {
+			t.Errorf("unexpected config: %+v", cfg)
+		}
+	})
+
+	t.Run("applies the context set with --context", func(t *testing.T) {
+		flags := setupTestConfig(t, testConfigFile)
+		if err := flags.Parse([]string{"--context", "prod"}); err != nil {
+			t.Fatal(err)
+		}
+		cfg, err := Get()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if cfg.APIURL != "https://prod.example.com/api" || cfg.UserID != "admin@kagent.dev" || cfg.Namespace != "kagent-prod" {
+			t.Errorf("unexpected config: %+v", cfg)
+		}
+	})
+
+	t.Run("flags override the context", func(t *testing.T) {
+		flags := setupTestConfig(t, testConfigFile)
+		if err := flags.Parse([]string{"--api-url", "http://localhost:9000/api"}); err != nil {
+			t.Fatal(err)
+		}
+		cfg, err := Get()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if cfg.APIURL != "http://localhost:9000/api" || cfg.UserID != "alice@example.com" {
+			t.Errorf("unexpected config: %+v", cfg)
+		}
+	})
+
+	t.Run("fails on an unknown context", func(t *testing.T) {
+		flags := setupTestConfig(t, testConfigFile)
+		if err := flags.Parse([]string{"--context", "dev"}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := Get(); err == nil {
+			t.Fatal("expected an error")
+		}
+	})
+}
+
+func TestUpdateContexts(t *testing.T) {
+	setupTestConfig(t, testConfigFile)
+	reload := func() *Config {
+		t.Helper()
+		if err := viper.ReadInConfig(); err != nil {
+			t.Fatal(err)
+		}
+		cfg, err := Load()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return cfg
+	}
+
+	if err := SetContext("dev", &Context{APIURL: "http://dev.example.com/api"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := SetContext("staging", &Context{Namespace: "kagent-staging"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := UseContext("dev"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	cfg := reload()
+	if cfg.CurrentContext != "dev" || cfg.Contexts["dev"].APIURL != "http://dev.example.com/api" {
+		t.Errorf("unexpected config: %+v", cfg)
+	}
+	if staging := cfg.Contexts["staging"]; staging.APIURL != "https://staging.example.com/api" || staging.Namespace != "kagent-staging" {
+		t.Errorf("expected the staging context to be updated, got %+v", staging)
+	}
+	if cfg.APIURL != "http://localhost:8081/api" {
+		t.Errorf("expected the top-level settings to be kept, got %s", cfg.APIURL)
+	}
+
+	if err := DeleteContext("dev"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	cfg = reload()
+	if _, ok := cfg.Contexts["dev"]; ok || cfg.CurrentContext != "" {
+		t.Errorf("expected the dev context to be deleted and unset, got %+v", cfg)
+	}
+	if got := cfg.ContextNames(); len(got) != 2 || got[0] != "prod" || got[1] != "staging" {
+		t.Errorf("unexpected contexts: %v", got)
+	}
+
+	if err := UseContext("missing"); err == nil {
+		t.Error("expected an error for a missing context")
+	}
+	if err := SetContext("Bad.Name", &Context{}); err == nil {
+		t.Error("expected an error for an invalid name")
+	}
+}
@@ -0,0 +1,43 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+)
+
+// Validate checks that the server URLs of the config are absolute http or https URLs, so
+// that a typo fails before any request is sent rather than as a connection error
+func (c *Config) Validate() error {
+	return errors.Join(
+		validateURL("api_url", "--api-url", c.APIURL),
+		validateURL("a2a_url", "--a2a-url", c.A2AURL),
+		validateURL("controller_url", "--controller-url", c.ControllerURL),
+	)
+}
+
+// RequireUserID checks that a user ID is set, for the commands that act on the resources of
+// a user
+func (c *Config) RequireUserID() error {
+	if c.UserID == "" {
+		return fmt.Errorf("no user ID is set: set it with --user-id, the user_id config key or the %s environment variable", userIDEnv)
+	}
+	return nil
+}
+
+func validateURL(key, flag, value string) error {
+	if value == "" {
+		return fmt.Errorf("%s is not set: set it with %s or in the config file", key, flag)
+	}
+	u, err := url.Parse(value)
+	if err != nil {
+		return fmt.Errorf("%s %q is not a valid URL: %w", key, value, err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("%s %q is not a valid URL: the scheme must be http or https", key, value)
+	}
+	if u.Host == "" {
+		return fmt.Errorf("%s %q is not a valid URL: it has no host", key, value)
+	}
+	return nil
+}
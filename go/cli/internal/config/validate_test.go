@@ -0,0 +1,96 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func validConfig() *Config {
+	return &Config{
+		APIURL:        "http://localhost:8081/api",
+		UserID:        "admin@kagent.dev",
+		A2AURL:        "http://localhost:8083/api/a2a",
+		ControllerURL: "https://kagent.example.com/api",
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		modify  func(*Config)
+		wantErr []string
+	}{
+		{
+			name:   "valid",
+			modify: func(*Config) {},
+		},
+		{
+			name:    "missing API URL",
+			modify:  func(c *Config) { c.APIURL = "" },
+			wantErr: []string{"api_url is not set", "--api-url"},
+		},
+		{
+			name:    "API URL without a scheme",
+			modify:  func(c *Config) { c.APIURL = "localhost:8081/api" },
+			wantErr: []string{`api_url "localhost:8081/api" is not a valid URL`},
+		},
+		{
+			name:    "unparseable A2A URL",
+			modify:  func(c *Config) { c.A2AURL = "http://local host:8083" },
+			wantErr: []string{`a2a_url "http://local host:8083" is not a valid URL`},
+		},
+		{
+			name:    "controller URL without a host",
+			modify:  func(c *Config) { c.ControllerURL = "http:///api" },
+			wantErr: []string{"controller_url", "it has no host"},
+		},
+		{
+			name: "reports every invalid URL",
+			modify: func(c *Config) {
+				c.APIURL = "ftp://localhost/api"
+				c.A2AURL = ""
+			},
+			wantErr: []string{"api_url", "the scheme must be http or https", "a2a_url is not set"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := validConfig()
+			tt.modify(cfg)
+			err := cfg.Validate()
+			if len(tt.wantErr) == 0 {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatal("expected an error")
+			}
+			for _, want := range tt.wantErr {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("error %q doesn't contain %q", err, want)
+				}
+			}
+		})
+	}
+}
+
+func TestRequireUserID(t *testing.T) {
+	cfg := validConfig()
+	if err := cfg.RequireUserID(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	cfg.UserID = ""
+	err := cfg.RequireUserID()
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	for _, want := range []string{"--user-id", "user_id", userIDEnv} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q doesn't contain %q", err, want)
+		}
+	}
+}
@@ -100,10 +100,49 @@ type AnyType struct {
 type A2AConfig struct {
 	// +kubebuilder:validation:MinItems=1
 	Skills []AgentSkill `json:"skills,omitempty"`
+	// DefaultInputModes are the media types the agent accepts, e.g. text or application/pdf.
+	// Defaults to text.
+	// +optional
+	DefaultInputModes []string `json:"defaultInputModes,omitempty"`
+	// DefaultOutputModes are the media types the agent responds with. Defaults to text.
+	// +optional
+	DefaultOutputModes []string `json:"defaultOutputModes,omitempty"`
+	// +optional
+	Provider *AgentProvider `json:"provider,omitempty"`
+	// Capabilities are the optional A2A features the agent supports. Streaming defaults to true.
+	// +optional
+	Capabilities *AgentCapabilities `json:"capabilities,omitempty"`
+	// Authentication declares how A2A clients authenticate, for agents served behind a
+	// proxy that authenticates them. Clients aren't asked to authenticate if unset.
+	// +optional
+	Authentication *AgentAuthentication `json:"authentication,omitempty"`
 }
 
 type AgentSkill server.AgentSkill
 
+type AgentProvider server.AgentProvider
+
+// AgentCapabilities declares the optional A2A features an agent supports
+type AgentCapabilities struct {
+	// Streaming is whether the agent supports streaming responses
+	// +optional
+	Streaming *bool `json:"streaming,omitempty"`
+	// PushNotifications is whether the agent can push notifications
+	// +optional
+	PushNotifications *bool `json:"pushNotifications,omitempty"`
+	// StateTransitionHistory is whether the agent provides the history of task states
+	// +optional
+	StateTransitionHistory *bool `json:"stateTransitionHistory,omitempty"`
+}
+
+// AgentAuthentication declares the authentication schemes A2A clients may use
+type AgentAuthentication struct {
+	// Schemes are the HTTP authentication schemes accepted, e.g. Bearer or Basic. Clients
+	// may use any of them.
+	// +kubebuilder:validation:MinItems=1
+	Schemes []string `json:"schemes"`
+}
+
 // AgentStatus defines the observed state of Agent.
 type AgentStatus struct {
 	ObservedGeneration int64              `json:"observedGeneration,omitempty"`
@@ -36,6 +36,31 @@ func (in *A2AConfig) DeepCopyInto(out *A2AConfig) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.DefaultInputModes != nil {
+		in, out := &in.DefaultInputModes, &out.DefaultInputModes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.DefaultOutputModes != nil {
+		in, out := &in.DefaultOutputModes, &out.DefaultOutputModes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Provider != nil {
+		in, out := &in.Provider, &out.Provider
+		*out = new(AgentProvider)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Capabilities != nil {
+		in, out := &in.Capabilities, &out.Capabilities
+		*out = new(AgentCapabilities)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Authentication != nil {
+		in, out := &in.Authentication, &out.Authentication
+		*out = new(AgentAuthentication)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new A2AConfig.
@@ -75,6 +100,56 @@ func (in *Agent) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AgentAuthentication) DeepCopyInto(out *AgentAuthentication) {
+	*out = *in
+	if in.Schemes != nil {
+		in, out := &in.Schemes, &out.Schemes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AgentAuthentication.
+func (in *AgentAuthentication) DeepCopy() *AgentAuthentication {
+	if in == nil {
+		return nil
+	}
+	out := new(AgentAuthentication)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AgentCapabilities) DeepCopyInto(out *AgentCapabilities) {
+	*out = *in
+	if in.Streaming != nil {
+		in, out := &in.Streaming, &out.Streaming
+		*out = new(bool)
+		**out = **in
+	}
+	if in.PushNotifications != nil {
+		in, out := &in.PushNotifications, &out.PushNotifications
+		*out = new(bool)
+		**out = **in
+	}
+	if in.StateTransitionHistory != nil {
+		in, out := &in.StateTransitionHistory, &out.StateTransitionHistory
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AgentCapabilities.
+func (in *AgentCapabilities) DeepCopy() *AgentCapabilities {
+	if in == nil {
+		return nil
+	}
+	out := new(AgentCapabilities)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AgentList) DeepCopyInto(out *AgentList) {
 	*out = *in
@@ -107,6 +182,26 @@ func (in *AgentList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AgentProvider) DeepCopyInto(out *AgentProvider) {
+	*out = *in
+	if in.URL != nil {
+		in, out := &in.URL, &out.URL
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AgentProvider.
+func (in *AgentProvider) DeepCopy() *AgentProvider {
+	if in == nil {
+		return nil
+	}
+	out := new(AgentProvider)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AgentSkill) DeepCopyInto(out *AgentSkill) {
 	*out = *in
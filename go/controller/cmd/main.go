@@ -38,6 +38,7 @@ import (
 	"github.com/kagent-dev/kagent/go/controller/internal/utils/syncutils"
 
 	"github.com/kagent-dev/kagent/go/controller/internal/httpserver"
+	"github.com/kagent-dev/kagent/go/controller/internal/httpserver/handlers"
 	utils_internal "github.com/kagent-dev/kagent/go/controller/internal/utils"
 
 	// Import all Kubernetes client auth plugins (e.g. Azure, GCP, OIDC, etc.)
@@ -84,6 +85,9 @@ func init() {
 
 // nolint:gocyclo
 func main() {
+	// Runs created before this are left over from a previous controller, see StaleRunReconciler
+	startedAt := time.Now()
+
 	var metricsAddr string
 	var metricsCertPath, metricsCertName, metricsCertKey string
 	var webhookCertPath, webhookCertName, webhookCertKey string
@@ -95,8 +99,22 @@ func main() {
 	var defaultModelConfig types.NamespacedName
 	var tlsOpts []func(*tls.Config)
 	var httpServerAddr string
+	var httpReadHeaderTimeout, httpReadTimeout, httpWriteTimeout, httpIdleTimeout time.Duration
 	var watchNamespaces string
+	var allowedAutogenURLs string
 	var a2aBaseUrl string
+	var quotas handlers.QuotaConfig
+	var quotaOverridesFile string
+	var maxBodyBytes, maxInvokeBodyBytes int64
+	var strictJSON bool
+	var maxConcurrentInvocations, maxConcurrentInvocationsPerUser int
+	var userIDSource handlers.UserIDSource
+	var autogenReadyTimeout, autogenReadyInterval, autogenReadyMaxInterval time.Duration
+	var staleRunAction string
+	var staleRunThreshold time.Duration
+	var staleRunInterval time.Duration
+	var redactPatterns []string
+	var redactReplacement string
 
 	flag.StringVar(&metricsAddr, "metrics-bind-address", "0", "The address the metrics endpoint binds to. "+
 		"Use :8443 for HTTPS or :8080 for HTTP, or leave as 0 to disable the metrics service.")
@@ -117,13 +135,43 @@ func main() {
 		"If set, HTTP/2 will be enabled for the metrics and webhook servers")
 
 	flag.StringVar(&autogenStudioBaseURL, "autogen-base-url", "http://127.0.0.1:8081/api", "The base url of the Autogen Studio server.")
+	flag.DurationVar(&autogenReadyTimeout, "autogen-ready-timeout", 5*time.Minute, "How long to wait for the Autogen Studio server to become ready on startup.")
+	flag.DurationVar(&autogenReadyInterval, "autogen-ready-interval", time.Second, "The initial delay between Autogen Studio readiness checks. It doubles after each failed check.")
+	flag.DurationVar(&autogenReadyMaxInterval, "autogen-ready-max-interval", 15*time.Second, "The maximum delay between Autogen Studio readiness checks.")
+	flag.StringVar(&staleRunAction, "stale-run-action", string(autogen.StaleRunActionFail), "What to do with runs left active by a restart: none, fail (mark them as failed) or retry (fail them and invoke their task again).")
+	flag.DurationVar(&staleRunThreshold, "stale-run-threshold", time.Hour, "How long a run created since the controller started must have been active to be considered interrupted by a restart. Runs created before are interrupted whatever their age.")
+	flag.DurationVar(&staleRunInterval, "stale-run-interval", 10*time.Minute, "How often runs interrupted by a restart of Autogen are looked for after startup. 0 only looks on startup.")
+	flag.Func("redact-pattern", "A regular expression whose matches are redacted from tasks and feedback before they are sent to Autogen and stored. Can be repeated.", func(pattern string) error {
+		redactPatterns = append(redactPatterns, pattern)
+		return nil
+	})
+	flag.StringVar(&redactReplacement, "redact-replacement", autogen_client.DefaultRedactionReplacement, "The text that replaces the matches of --redact-pattern.")
 
 	flag.StringVar(&defaultModelConfig.Name, "default-model-config-name", "default-model-config", "The name of the default model config.")
 	flag.StringVar(&defaultModelConfig.Namespace, "default-model-config-namespace", kagentNamespace, "The namespace of the default model config.")
 	flag.StringVar(&httpServerAddr, "http-server-address", ":8083", "The address the HTTP server binds to.")
+	flag.DurationVar(&httpReadHeaderTimeout, "http-read-header-timeout", httpserver.DefaultReadHeaderTimeout, "How long clients may take to send the headers of an HTTP request.")
+	flag.DurationVar(&httpReadTimeout, "http-read-timeout", httpserver.DefaultReadTimeout, "How long clients may take to send an HTTP request. Invocations and A2A requests are exempt.")
+	flag.DurationVar(&httpWriteTimeout, "http-write-timeout", httpserver.DefaultWriteTimeout, "How long the HTTP response of a request may take. Invocations and A2A requests are exempt.")
+	flag.DurationVar(&httpIdleTimeout, "http-idle-timeout", httpserver.DefaultIdleTimeout, "How long idle keep-alive HTTP connections are kept open.")
 	flag.StringVar(&a2aBaseUrl, "a2a-base-url", "http://127.0.0.1:8083", "The base URL of the A2A Server endpoint, as advertised to clients.")
 
 	flag.StringVar(&watchNamespaces, "watch-namespaces", "", "The namespaces to watch for .")
+	flag.StringVar(&allowedAutogenURLs, "allowed-autogen-urls", "", "Comma separated list of alternate Autogen base urls that invocations may select with the X-Autogen-URL header.")
+
+	flag.Int64Var(&maxBodyBytes, "max-request-body-bytes", handlers.DefaultMaxBodyBytes, "The maximum size of API request bodies.")
+	flag.Int64Var(&maxInvokeBodyBytes, "max-invoke-request-body-bytes", handlers.DefaultMaxInvokeBodyBytes, "The maximum size of agent and session invoke request bodies.")
+	flag.BoolVar(&strictJSON, "strict-json", false, "If set, create and update API requests with unknown fields are rejected.")
+	flag.IntVar(&maxConcurrentInvocations, "max-concurrent-invocations", 0, "The maximum number of agent and session invocations served at the same time. 0 means unlimited.")
+	flag.IntVar(&maxConcurrentInvocationsPerUser, "max-concurrent-invocations-per-user", 0, "The maximum number of invocations a user can have in flight at the same time. 0 means unlimited.")
+	flag.StringVar((*string)(&userIDSource.Mode), "user-id-source", string(handlers.UserIDFromQuery), "Where the user ID of API requests is read from: query (the user_id parameter), header or jwt (a claim of the bearer token, verified with --user-id-jwt-key).")
+	flag.StringVar(&userIDSource.Header, "user-id-header", handlers.DefaultUserIDHeader, "The header the user ID is read from with --user-id-source=header.")
+	flag.StringVar(&userIDSource.Claim, "user-id-claim", handlers.DefaultUserIDClaim, "The bearer token claim the user ID is read from with --user-id-source=jwt.")
+	flag.StringVar(&userIDSource.JWTKeyFile, "user-id-jwt-key", "", "The PEM file of the RSA, ECDSA or Ed25519 public key bearer tokens are verified with. Required with --user-id-source=jwt.")
+
+	flag.IntVar(&quotas.Default.MaxSessions, "max-sessions-per-user", 0, "The maximum number of sessions a user can create. 0 means unlimited.")
+	flag.IntVar(&quotas.Default.MaxToolServers, "max-toolservers-per-user", 0, "The maximum number of tool servers a user can create through the API. 0 means unlimited.")
+	flag.StringVar(&quotaOverridesFile, "quota-overrides-file", "", "A JSON file of per-user limits that replace the defaults, e.g. {\"alice\": {\"max_sessions\": 10, \"max_tool_servers\": 5}}.")
 
 	opts := zap.Options{
 		Development: true,
@@ -135,6 +183,41 @@ func main() {
 
 	setupLog.Info("Starting KAgent Controller", "version", Version, "git_commit", GitCommit, "build_date", BuildDate)
 
+	if err := userIDSource.Validate(); err != nil {
+		setupLog.Error(err, "invalid --user-id-source")
+		os.Exit(1)
+	}
+	if quotaOverridesFile != "" {
+		overrides, err := handlers.LoadQuotaOverrides(quotaOverridesFile)
+		if err != nil {
+			setupLog.Error(err, "invalid --quota-overrides-file")
+			os.Exit(1)
+		}
+		quotas.Overrides = overrides
+	}
+	autogenReadyBackoff := readyBackoff{
+		Timeout:     autogenReadyTimeout,
+		Interval:    autogenReadyInterval,
+		MaxInterval: autogenReadyMaxInterval,
+	}
+	if err := autogenReadyBackoff.validate(); err != nil {
+		setupLog.Error(err, "invalid --autogen-ready-timeout, --autogen-ready-interval or --autogen-ready-max-interval")
+		os.Exit(1)
+	}
+	if err := autogen.ValidateStaleRunAction(autogen.StaleRunAction(staleRunAction)); err != nil {
+		setupLog.Error(err, "invalid --stale-run-action")
+		os.Exit(1)
+	}
+	var messageRedactor autogen_client.MessageRedactor
+	if len(redactPatterns) > 0 {
+		redactor, err := autogen_client.NewRegexRedactor(redactReplacement, redactPatterns...)
+		if err != nil {
+			setupLog.Error(err, "invalid --redact-pattern")
+			os.Exit(1)
+		}
+		messageRedactor = redactor
+	}
+
 	// if the enable-http2 flag is false (the default), http/2 should be disabled
 	// due to its vulnerabilities. More specifically, disabling http/2 will
 	// prevent from being vulnerable to the HTTP/2 Stream Cancellation and
@@ -258,12 +341,15 @@ func main() {
 	builtinTools := syncutils.NewAtomicMap[string, string]()
 	builtinTools.Set("k8s-get-pod", "k8s.get_pod")
 
+	// The clients of alternate backends are created with the same options
+	var autogenClientOptions []autogen_client.Option
 	autogenClient := autogen_client.New(
 		autogenStudioBaseURL,
+		autogenClientOptions...,
 	)
 
 	// wait for autogen to become ready on port 8081 before starting the manager
-	if err := waitForAutogenReady(context.Background(), setupLog, autogenClient, time.Minute*5, time.Second*15); err != nil {
+	if err := waitForAutogenReady(context.Background(), setupLog, autogenClient, autogenReadyBackoff); err != nil {
 		setupLog.Error(err, "failed to wait for autogen to become ready")
 		os.Exit(1)
 	}
@@ -277,8 +363,13 @@ func main() {
 
 	a2aHandler := a2a.NewA2AHttpMux(httpserver.APIPathA2A)
 
+	// A2A tasks are sent to Autogen outside of the HTTP server, so they are redacted here
+	a2aAutogenClient := autogenClient
+	if messageRedactor != nil {
+		a2aAutogenClient = autogen_client.NewRedactingClient(autogenClient, messageRedactor)
+	}
 	a2aReconciler := a2a.NewAutogenReconciler(
-		autogenClient,
+		a2aAutogenClient,
 		a2aHandler,
 		a2aBaseUrl+httpserver.APIPathA2A,
 	)
@@ -366,17 +457,42 @@ func main() {
 	}
 
 	httpServer := httpserver.NewHTTPServer(httpserver.ServerConfig{
-		BindAddr:          httpServerAddr,
-		AutogenClient:     autogenClient,
-		KubeClient:        kubeClient,
-		A2AHandler:        a2aHandler,
-		WatchedNamespaces: watchNamespacesList,
+		BindAddr:                        httpServerAddr,
+		AutogenClient:                   autogenClient,
+		KubeClient:                      kubeClient,
+		A2AHandler:                      a2aHandler,
+		WatchedNamespaces:               watchNamespacesList,
+		Quotas:                          quotas,
+		AllowedAutogenURLs:              splitNonEmpty(allowedAutogenURLs),
+		AutogenClientOptions:            autogenClientOptions,
+		MaxBodyBytes:                    maxBodyBytes,
+		MaxInvokeBodyBytes:              maxInvokeBodyBytes,
+		StrictJSON:                      strictJSON,
+		MaxConcurrentInvocations:        maxConcurrentInvocations,
+		MaxConcurrentInvocationsPerUser: maxConcurrentInvocationsPerUser,
+		UserIDSource:                    userIDSource,
+		MessageRedactor:                 messageRedactor,
+		ReadHeaderTimeout:               httpReadHeaderTimeout,
+		ReadTimeout:                     httpReadTimeout,
+		WriteTimeout:                    httpWriteTimeout,
+		IdleTimeout:                     httpIdleTimeout,
 	})
 	if err := mgr.Add(httpServer); err != nil {
 		setupLog.Error(err, "unable to set up HTTP server")
 		os.Exit(1)
 	}
 
+	if err := mgr.Add(&autogen.StaleRunReconciler{
+		AutogenClient: autogenClient,
+		Action:        autogen.StaleRunAction(staleRunAction),
+		Threshold:     staleRunThreshold,
+		StartedAt:     startedAt,
+		Interval:      staleRunInterval,
+	}); err != nil {
+		setupLog.Error(err, "unable to set up stale run reconciler")
+		os.Exit(1)
+	}
+
 	setupLog.Info("starting manager")
 	if err := mgr.Start(ctrl.SetupSignalHandler()); err != nil {
 		setupLog.Error(err, "problem running manager")
@@ -384,35 +500,70 @@ func main() {
 	}
 }
 
+// readyBackoff controls how long and how often a dependency is polled for readiness
+type readyBackoff struct {
+	// Timeout is the total time to wait
+	Timeout time.Duration
+	// Interval is the delay after the first failed attempt; it doubles after each failure
+	Interval time.Duration
+	// MaxInterval caps the delay between attempts
+	MaxInterval time.Duration
+}
+
+// validate rejects backoffs that would retry without waiting or never retry at all
+func (b readyBackoff) validate() error {
+	if b.Timeout <= 0 {
+		return fmt.Errorf("timeout must be positive, got %v", b.Timeout)
+	}
+	if b.Interval <= 0 {
+		return fmt.Errorf("interval must be positive, got %v", b.Interval)
+	}
+	if b.MaxInterval < 0 {
+		return fmt.Errorf("max interval must not be negative, got %v", b.MaxInterval)
+	}
+	return nil
+}
+
 func waitForAutogenReady(
 	ctx context.Context,
 	log logr.Logger,
 	client autogen_client.Client,
-	timeout, interval time.Duration,
+	backoff readyBackoff,
 ) error {
-	log.Info("waiting for autogen to become ready")
-	return waitForReady(func() error {
+	log.Info("waiting for autogen to become ready", "timeout", backoff.Timeout)
+	return waitForReady(func(attempt int) error {
 		version, err := client.GetVersion(ctx)
 		if err != nil {
-			log.Error(err, "autogen is not ready")
+			log.Error(err, "autogen is not ready", "attempt", attempt)
 			return err
 		}
-		log.Info("autogen is ready", "version", version)
+		log.Info("autogen is ready", "version", version, "attempt", attempt)
 		return nil
-	}, timeout, interval)
+	}, backoff)
 }
 
-func waitForReady(f func() error, timeout, interval time.Duration) error {
-	deadline := time.Now().Add(timeout)
-	for {
-		if time.Now().After(deadline) {
-			return fmt.Errorf("timed out after %v", timeout)
-		}
-		if err := f(); err == nil {
+func waitForReady(f func(attempt int) error, backoff readyBackoff) error {
+	if err := backoff.validate(); err != nil {
+		return fmt.Errorf("invalid readiness backoff: %w", err)
+	}
+	deadline := time.Now().Add(backoff.Timeout)
+	interval := backoff.Interval
+	for attempt := 1; ; attempt++ {
+		err := f(attempt)
+		if err == nil {
 			return nil
 		}
 
-		time.Sleep(interval)
+		remaining := time.Until(deadline)
+		if remaining <= 0 {
+			return fmt.Errorf("timed out after %v and %d attempts: %w", backoff.Timeout, attempt, err)
+		}
+		time.Sleep(min(interval, remaining))
+
+		interval *= 2
+		if backoff.MaxInterval > 0 && interval > backoff.MaxInterval {
+			interval = backoff.MaxInterval
+		}
 	}
 }
 
@@ -456,3 +607,14 @@ func filterValidNamespaces(namespaces []string) []string {
 
 	return validNamespaces
 }
+
+// splitNonEmpty splits a comma separated list, dropping blank entries
+func splitNonEmpty(list string) []string {
+	var values []string
+	for _, value := range strings.Split(list, ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
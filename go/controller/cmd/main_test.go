@@ -1,8 +1,10 @@
 package main
 
 import (
+	"errors"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -112,3 +114,50 @@ func TestConfigureNamespaceWatching(t *testing.T) {
 		})
 	}
 }
+
+func TestWaitForReady(t *testing.T) {
+	t.Run("succeeds after transient failures", func(t *testing.T) {
+		calls := 0
+		err := waitForReady(func(attempt int) error {
+			calls++
+			assert.Equal(t, calls, attempt)
+			if attempt < 3 {
+				return errors.New("not ready")
+			}
+			return nil
+		}, readyBackoff{Timeout: time.Second, Interval: time.Millisecond, MaxInterval: 2 * time.Millisecond})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 3, calls)
+	})
+
+	t.Run("times out", func(t *testing.T) {
+		calls := 0
+		err := waitForReady(func(attempt int) error {
+			calls++
+			return errors.New("not ready")
+		}, readyBackoff{Timeout: 20 * time.Millisecond, Interval: time.Millisecond, MaxInterval: 5 * time.Millisecond})
+
+		assert.ErrorContains(t, err, "timed out")
+		assert.ErrorContains(t, err, "not ready")
+		assert.Greater(t, calls, 1)
+	})
+
+	t.Run("rejects non-positive intervals", func(t *testing.T) {
+		for _, backoff := range []readyBackoff{
+			{Timeout: time.Second, Interval: 0},
+			{Timeout: time.Second, Interval: -time.Millisecond},
+			{Timeout: 0, Interval: time.Millisecond},
+			{Timeout: time.Second, Interval: time.Millisecond, MaxInterval: -time.Millisecond},
+		} {
+			calls := 0
+			err := waitForReady(func(attempt int) error {
+				calls++
+				return errors.New("not ready")
+			}, backoff)
+
+			assert.ErrorContains(t, err, "invalid readiness backoff")
+			assert.Equal(t, 0, calls)
+		}
+	})
+}
@@ -0,0 +1,44 @@
+package a2a_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/utils/ptr"
+	"trpc.group/trpc-go/trpc-a2a-go/server"
+
+	"github.com/kagent-dev/kagent/go/controller/internal/a2a"
+)
+
+func TestHandlerMuxServesAgentCard(t *testing.T) {
+	mux := a2a.NewA2AHttpMux("/api/a2a")
+	card := server.AgentCard{
+		Name:               "test-namespace/test-agent",
+		Description:        "Test agent",
+		URL:                "http://localhost:8083/api/a2a/test-namespace/test-agent",
+		Capabilities:       server.AgentCapabilities{Streaming: ptr.To(false)},
+		DefaultInputModes:  []string{"text"},
+		DefaultOutputModes: []string{"text"},
+		Skills:             []server.AgentSkill{{ID: "skill1", Name: "Test Skill"}},
+	}
+	require.NoError(t, mux.SetAgentHandler("test-namespace/test-agent", &a2a.A2AHandlerParams{AgentCard: card}))
+
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/a2a/test-namespace/test-agent/.well-known/agent.json", nil))
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var served server.AgentCard
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &served))
+	assert.Equal(t, card.Name, served.Name)
+	assert.Equal(t, ptr.To(false), served.Capabilities.Streaming)
+
+	t.Run("unknown agent", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/a2a/test-namespace/other/.well-known/agent.json", nil))
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
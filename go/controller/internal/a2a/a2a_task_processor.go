@@ -21,6 +21,21 @@ type MessageHandler interface {
 	HandleMessageStream(ctx context.Context, task string, contextID string) (<-chan client.Event, error)
 }
 
+// streamingSupport is implemented by message handlers whose agent may not support the
+// streaming invoke path
+type streamingSupport interface {
+	SupportsStreaming() bool
+}
+
+// SupportsStreaming reports whether the handler serves streaming requests. Handlers that
+// don't implement streamingSupport do.
+func SupportsStreaming(handler MessageHandler) bool {
+	if s, ok := handler.(streamingSupport); ok {
+		return s.SupportsStreaming()
+	}
+	return true
+}
+
 type a2aMessageProcessor struct {
 	// msgHandler is a function that processes the input text.
 	// in production this is done by handing off the input text by a call to
@@ -86,6 +101,10 @@ func (a *a2aMessageProcessor) ProcessMessage(
 		}, nil
 	}
 
+	if !SupportsStreaming(a.msgHandler) {
+		return nil, fmt.Errorf("agent does not support streaming, send the message with message/send instead")
+	}
+
 	events, err := a.msgHandler.HandleMessageStream(ctx, text, handle.GetContextID())
 	if err != nil {
 		return nil, err
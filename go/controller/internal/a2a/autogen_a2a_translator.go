@@ -5,10 +5,12 @@ import (
 	"errors"
 	"fmt"
 	"log"
+	"strings"
 
 	autogen_client "github.com/kagent-dev/kagent/go/autogen/client"
 	"github.com/kagent-dev/kagent/go/controller/api/v1alpha1"
 	common "github.com/kagent-dev/kagent/go/controller/internal/utils"
+	"github.com/kagent-dev/kagent/go/controller/utils/a2autils"
 	"k8s.io/utils/ptr"
 	"trpc.group/trpc-go/trpc-a2a-go/server"
 )
@@ -52,10 +54,12 @@ func (a *autogenA2ATranslator) TranslateHandlerForAgent(
 		return nil, nil
 	}
 
-	handler, err := a.makeHandlerForTeam(autogenTeam)
+	handler, err := a.makeHandlerForTeam(autogenTeam, agentStreams(agent))
 	if err != nil {
 		return nil, err
 	}
+	// Advertise streaming only if the handler serves it, so clients pick message/send otherwise
+	card.Capabilities.Streaming = ptr.To(SupportsStreaming(handler))
 
 	return &A2AHandlerParams{
 		AgentCard:   *card,
@@ -78,40 +82,109 @@ func (a *autogenA2ATranslator) translateCardForAgent(
 		return nil, fmt.Errorf("no skills found for agent %s", agentRef)
 	}
 
-	var convertedSkills []server.AgentSkill
-	for _, skill := range skills {
-		convertedSkills = append(convertedSkills, server.AgentSkill(skill))
-	}
+	convertedSkills := ConvertAgentSkills(skills)
 
-	return &server.AgentCard{
+	card := &server.AgentCard{
 		Name:        agentRef,
 		Description: agent.Spec.Description,
-		URL:         fmt.Sprintf("%s/%s", a.a2aBaseUrl, agentRef),
-		//Provider:           nil,
-		Version: fmt.Sprintf("%v", agent.Generation),
+		URL:         a2autils.AgentURL(a.a2aBaseUrl, agent.Namespace, agent.Name),
+		Version:     fmt.Sprintf("%v", agent.Generation),
 		//DocumentationURL:   nil,
-		//Authentication:     nil,
-		Capabilities: server.AgentCapabilities{
-			Streaming: ptr.To(true),
-		},
-		DefaultInputModes:  []string{"text"},
-		DefaultOutputModes: []string{"text"},
+		Capabilities:       convertAgentCapabilities(a2AConfig.Capabilities),
+		DefaultInputModes:  modesOrText(a2AConfig.DefaultInputModes),
+		DefaultOutputModes: modesOrText(a2AConfig.DefaultOutputModes),
 		Skills:             convertedSkills,
-	}, nil
+	}
+	if a2AConfig.Provider != nil {
+		card.Provider = (*server.AgentProvider)(a2AConfig.Provider.DeepCopy())
+	}
+	if a2AConfig.Authentication != nil {
+		card.SecuritySchemes, card.Security = convertAgentAuthentication(a2AConfig.Authentication)
+	}
+	return card, nil
+}
+
+func modesOrText(modes []string) []string {
+	if len(modes) == 0 {
+		return []string{"text"}
+	}
+	return append([]string{}, modes...)
+}
+
+// agentStreams reports whether the agent supports the streaming invoke path. Autogen teams
+// stream unless the agent's A2A config says otherwise.
+func agentStreams(agent *v1alpha1.Agent) bool {
+	a2AConfig := agent.Spec.A2AConfig
+	if a2AConfig == nil || a2AConfig.Capabilities == nil || a2AConfig.Capabilities.Streaming == nil {
+		return true
+	}
+	return *a2AConfig.Capabilities.Streaming
+}
+
+// convertAgentCapabilities returns the capabilities of an agent card, except for streaming,
+// which depends on the handler of the agent
+func convertAgentCapabilities(capabilities *v1alpha1.AgentCapabilities) server.AgentCapabilities {
+	converted := server.AgentCapabilities{}
+	if capabilities == nil {
+		return converted
+	}
+	if capabilities.PushNotifications != nil {
+		converted.PushNotifications = ptr.To(*capabilities.PushNotifications)
+	}
+	if capabilities.StateTransitionHistory != nil {
+		converted.StateTransitionHistory = ptr.To(*capabilities.StateTransitionHistory)
+	}
+	return converted
+}
+
+// convertAgentAuthentication returns an HTTP security scheme for each authentication scheme,
+// named after the scheme in lower case, and security requirements allowing any of them
+func convertAgentAuthentication(authentication *v1alpha1.AgentAuthentication) (map[string]server.SecurityScheme, []map[string][]string) {
+	schemes := make(map[string]server.SecurityScheme, len(authentication.Schemes))
+	security := make([]map[string][]string, 0, len(authentication.Schemes))
+	for _, scheme := range authentication.Schemes {
+		name := strings.ToLower(scheme)
+		if _, ok := schemes[name]; ok {
+			continue
+		}
+		schemes[name] = server.SecurityScheme{
+			Type:   server.SecuritySchemeTypeHTTP,
+			Scheme: ptr.To(scheme),
+		}
+		security = append(security, map[string][]string{name: {}})
+	}
+	return schemes, security
+}
+
+// ConvertAgentSkills converts the skills of an agent's A2A config to the skills of its agent card
+func ConvertAgentSkills(skills []v1alpha1.AgentSkill) []server.AgentSkill {
+	convertedSkills := make([]server.AgentSkill, 0, len(skills))
+	for _, skill := range skills {
+		convertedSkills = append(convertedSkills, server.AgentSkill(skill))
+	}
+	return convertedSkills
 }
 
 func (a *autogenA2ATranslator) makeHandlerForTeam(
 	autogenTeam *autogen_client.Team,
+	streaming bool,
 ) (MessageHandler, error) {
 	return &taskHandler{
-		team:   autogenTeam,
-		client: a.autogenClient,
+		team:      autogenTeam,
+		client:    a.autogenClient,
+		streaming: streaming,
 	}, nil
 }
 
 type taskHandler struct {
 	team   *autogen_client.Team
 	client autogen_client.Client
+	// streaming is false for agents that opted out of the streaming invoke path
+	streaming bool
+}
+
+func (t *taskHandler) SupportsStreaming() bool {
+	return t.streaming
 }
 
 func (t *taskHandler) HandleMessage(ctx context.Context, task string, contextID string) ([]autogen_client.Event, error) {
@@ -131,7 +204,7 @@ func (t *taskHandler) HandleMessage(ctx context.Context, task string, contextID
 				return nil, fmt.Errorf("failed to get session: %w", err)
 			}
 		}
-		resp, err := t.client.InvokeSession(session.ID, common.GetGlobalUserID(), &autogen_client.InvokeRequest{
+		resp, err := t.client.InvokeSession(ctx, session.ID, common.GetGlobalUserID(), &autogen_client.InvokeRequest{
 			Task:       task,
 			TeamConfig: t.team.Component,
 		})
@@ -141,7 +214,7 @@ func (t *taskHandler) HandleMessage(ctx context.Context, task string, contextID
 		taskResult = &resp.TaskResult
 	} else {
 
-		resp, err := t.client.InvokeTask(&autogen_client.InvokeTaskRequest{
+		resp, err := t.client.InvokeTask(ctx, &autogen_client.InvokeTaskRequest{
 			Task:       task,
 			TeamConfig: t.team.Component,
 		})
@@ -181,7 +254,7 @@ func (t *taskHandler) HandleMessageStream(ctx context.Context, task string, cont
 			}
 		}
 
-		stream, err := t.client.InvokeSessionStream(session.ID, common.GetGlobalUserID(), &autogen_client.InvokeRequest{
+		stream, err := t.client.InvokeSessionStream(ctx, session.ID, common.GetGlobalUserID(), &autogen_client.InvokeRequest{
 			Task:       task,
 			TeamConfig: t.team.Component,
 		})
@@ -205,7 +278,7 @@ func (t *taskHandler) HandleMessageStream(ctx context.Context, task string, cont
 		return events, nil
 	} else {
 
-		stream, err := t.client.InvokeTaskStream(&autogen_client.InvokeTaskRequest{
+		stream, err := t.client.InvokeTaskStream(ctx, &autogen_client.InvokeTaskRequest{
 			Task:       task,
 			TeamConfig: t.team.Component,
 		})
@@ -15,6 +15,7 @@ import (
 	common "github.com/kagent-dev/kagent/go/controller/internal/utils"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/utils/ptr"
+	"trpc.group/trpc-go/trpc-a2a-go/server"
 )
 
 // Helper function to create a mock autogen team with proper Component
@@ -86,9 +87,65 @@ func TestTranslateHandlerForAgent(t *testing.T) {
 		assert.Equal(t, []string{"text"}, result.AgentCard.DefaultOutputModes)
 		assert.Len(t, result.AgentCard.Skills, 1)
 		assert.Equal(t, "skill1", result.AgentCard.Skills[0].ID)
+		assert.Equal(t, ptr.To(true), result.AgentCard.Capabilities.Streaming)
+		assert.Nil(t, result.AgentCard.Provider)
+		assert.Empty(t, result.AgentCard.SecuritySchemes)
 		assert.NotNil(t, result.TaskHandler)
 	})
 
+	t.Run("should use the card fields of the A2A config", func(t *testing.T) {
+		mockClient := fake.NewMockAutogenClient()
+		translator := a2a.NewAutogenA2ATranslator(baseURL, mockClient)
+
+		agent := &v1alpha1.Agent{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:       "test-agent",
+				Namespace:  "test-namespace",
+				Generation: 1,
+			},
+			Spec: v1alpha1.AgentSpec{
+				Description: "Test agent",
+				A2AConfig: &v1alpha1.A2AConfig{
+					Skills: []v1alpha1.AgentSkill{
+						{ID: "skill1", Name: "Test Skill"},
+					},
+					DefaultInputModes:  []string{"text", "application/pdf"},
+					DefaultOutputModes: []string{"application/json"},
+					Provider: &v1alpha1.AgentProvider{
+						Organization: "Example",
+						URL:          ptr.To("https://example.com"),
+					},
+					Capabilities: &v1alpha1.AgentCapabilities{
+						Streaming:         ptr.To(false),
+						PushNotifications: ptr.To(true),
+					},
+					Authentication: &v1alpha1.AgentAuthentication{
+						Schemes: []string{"Bearer", "Basic"},
+					},
+				},
+			},
+		}
+
+		autogenTeam := createMockAutogenTeam(123, common.GetObjectRef(agent))
+
+		result, err := translator.TranslateHandlerForAgent(ctx, agent, autogenTeam)
+
+		require.NoError(t, err)
+		require.NotNil(t, result)
+		card := result.AgentCard
+		assert.Equal(t, []string{"text", "application/pdf"}, card.DefaultInputModes)
+		assert.Equal(t, []string{"application/json"}, card.DefaultOutputModes)
+		assert.Equal(t, &server.AgentProvider{Organization: "Example", URL: ptr.To("https://example.com")}, card.Provider)
+		assert.Equal(t, ptr.To(false), card.Capabilities.Streaming)
+		assert.Equal(t, ptr.To(true), card.Capabilities.PushNotifications)
+		assert.Nil(t, card.Capabilities.StateTransitionHistory)
+		assert.Equal(t, map[string]server.SecurityScheme{
+			"bearer": {Type: server.SecuritySchemeTypeHTTP, Scheme: ptr.To("Bearer")},
+			"basic":  {Type: server.SecuritySchemeTypeHTTP, Scheme: ptr.To("Basic")},
+		}, card.SecuritySchemes)
+		assert.Equal(t, []map[string][]string{{"bearer": {}}, {"basic": {}}}, card.Security)
+	})
+
 	t.Run("should return nil for agent without A2A config", func(t *testing.T) {
 		mockClient := fake.NewMockAutogenClient()
 		translator := a2a.NewAutogenA2ATranslator(baseURL, mockClient)
@@ -139,6 +196,43 @@ func TestTranslateHandlerForAgent(t *testing.T) {
 	})
 }
 
+func TestAgentCardStreaming(t *testing.T) {
+	ctx := context.Background()
+	translator := a2a.NewAutogenA2ATranslator("http://localhost:8083", fake.NewMockAutogenClient())
+
+	newAgent := func(capabilities *v1alpha1.AgentCapabilities) *v1alpha1.Agent {
+		return &v1alpha1.Agent{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-agent", Namespace: "test-namespace"},
+			Spec: v1alpha1.AgentSpec{
+				A2AConfig: &v1alpha1.A2AConfig{
+					Skills:       []v1alpha1.AgentSkill{{ID: "skill1", Name: "Test Skill"}},
+					Capabilities: capabilities,
+				},
+			},
+		}
+	}
+
+	tests := []struct {
+		name         string
+		capabilities *v1alpha1.AgentCapabilities
+		streaming    bool
+	}{
+		{"streams by default", nil, true},
+		{"streams unless opted out", &v1alpha1.AgentCapabilities{PushNotifications: ptr.To(true)}, true},
+		{"opted out", &v1alpha1.AgentCapabilities{Streaming: ptr.To(false)}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			agent := newAgent(tt.capabilities)
+			result, err := translator.TranslateHandlerForAgent(ctx, agent, createMockAutogenTeam(1, common.GetObjectRef(agent)))
+			require.NoError(t, err)
+
+			assert.Equal(t, ptr.To(tt.streaming), result.AgentCard.Capabilities.Streaming)
+			assert.Equal(t, tt.streaming, a2a.SupportsStreaming(result.TaskHandler))
+		})
+	}
+}
+
 func TestTaskHandlerWithSession(t *testing.T) {
 	ctx := context.Background()
 	baseURL := "http://localhost:8083"
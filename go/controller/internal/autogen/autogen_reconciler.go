@@ -100,7 +100,7 @@ func (a *autogenReconciler) handleAgentDeletion(req ctrl.Request) error {
 
 	// TODO(sbx0r): temporary mock on GlobalUserID.
 	//              This block will be removed after resolving previous TODO
-	team, err := a.autogenClient.GetTeam(req.NamespacedName.String(), common.GetGlobalUserID())
+	team, err := a.autogenClient.GetAgent(context.Background(), req.NamespacedName.String(), common.GetGlobalUserID())
 	if err != nil {
 		return fmt.Errorf("failed to get agent on agent deletion %s/%s: %w",
 			req.Namespace, req.Name, err)
@@ -307,9 +307,8 @@ func (a *autogenReconciler) ReconcileAutogenToolServer(ctx context.Context, req
 	// reconcile the agent team itself
 	toolServer := &v1alpha1.ToolServer{}
 	if err := a.kube.Get(ctx, req.NamespacedName, toolServer); err != nil {
-		// if the tool server is not found, we can ignore it
 		if k8s_errors.IsNotFound(err) {
-			return nil
+			return a.handleToolServerDeletion(req)
 		}
 		return fmt.Errorf("failed to get tool server %s: %v", req.Name, err)
 	}
@@ -339,6 +338,27 @@ func (a *autogenReconciler) ReconcileAutogenToolServer(ctx context.Context, req
 	return nil
 }
 
+// handleToolServerDeletion removes the autogen tool server of a deleted ToolServer. Autogen
+// deletes the tools discovered from a server along with it, so they no longer show up in ListTools.
+func (a *autogenReconciler) handleToolServerDeletion(req ctrl.Request) error {
+	toolServers, err := a.autogenClient.ListToolServers(common.GetGlobalUserID())
+	if err != nil {
+		return fmt.Errorf("failed to list tool servers on tool server deletion %s: %w", req.NamespacedName.String(), err)
+	}
+
+	for _, toolServer := range toolServers {
+		if toolServer.Component.Label != req.NamespacedName.String() {
+			continue
+		}
+		if err := a.autogenClient.DeleteToolServer(&toolServer.Id, common.GetGlobalUserID()); err != nil {
+			return fmt.Errorf("failed to delete tool server %s: %w", req.NamespacedName.String(), err)
+		}
+		reconcileLog.Info("ToolServer was deleted", "namespace", req.Namespace, "name", req.Name)
+	}
+
+	return nil
+}
+
 func (a *autogenReconciler) reconcileToolServerStatus(
 	ctx context.Context,
 	toolServer *v1alpha1.ToolServer,
@@ -533,7 +553,7 @@ func (a *autogenReconciler) upsertTeam(team *autogen_client.Team) error {
 	}
 
 	// delete if team exists
-	existingTeam, err := a.autogenClient.GetTeam(team.Component.Label, common.GetGlobalUserID())
+	existingTeam, err := a.autogenClient.GetAgent(context.Background(), team.Component.Label, common.GetGlobalUserID())
 	if err != nil && err != autogen_client.NotFoundError {
 		return fmt.Errorf("failed to get existing team %s: %v", team.Component.Label, err)
 	}
@@ -0,0 +1,75 @@
+package autogen_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/kagent-dev/kagent/go/autogen/api"
+	autogen_client "github.com/kagent-dev/kagent/go/autogen/client"
+	autogen_fake "github.com/kagent-dev/kagent/go/autogen/client/fake"
+	"github.com/kagent-dev/kagent/go/controller/api/v1alpha1"
+	"github.com/kagent-dev/kagent/go/controller/internal/autogen"
+	common "github.com/kagent-dev/kagent/go/controller/internal/utils"
+)
+
+func TestReconcileAutogenToolServerDeletion(t *testing.T) {
+	ctx := context.Background()
+	require.NoError(t, v1alpha1.AddToScheme(scheme.Scheme))
+
+	kubeClient := fake.NewClientBuilder().WithScheme(scheme.Scheme).Build()
+	defaultModelConfig := types.NamespacedName{Namespace: "default", Name: "default-model"}
+	autogenClient := autogen_fake.NewInMemoryAutogenClient()
+	reconciler := autogen.NewAutogenReconciler(
+		autogen.NewAutogenApiTranslator(kubeClient, defaultModelConfig),
+		kubeClient,
+		autogenClient,
+		defaultModelConfig,
+		nil,
+	)
+
+	deleted, err := autogenClient.CreateToolServer(&autogen_client.ToolServer{
+		Component: api.Component{Label: "default/deleted-server"},
+	}, common.GetGlobalUserID())
+	require.NoError(t, err)
+	kept, err := autogenClient.CreateToolServer(&autogen_client.ToolServer{
+		Component: api.Component{Label: "default/kept-server"},
+	}, common.GetGlobalUserID())
+	require.NoError(t, err)
+	autogenClient.AddToolsForServer(deleted.Id,
+		&autogen_client.Tool{Component: &api.Component{Provider: "deleted.tool.a"}},
+		&autogen_client.Tool{Component: &api.Component{Provider: "deleted.tool.b"}},
+	)
+	autogenClient.AddToolsForServer(kept.Id,
+		&autogen_client.Tool{Component: &api.Component{Provider: "kept.tool"}},
+	)
+
+	// The ToolServer was never created in the kube client, as if it had been deleted
+	err = reconciler.ReconcileAutogenToolServer(ctx, ctrl.Request{
+		NamespacedName: types.NamespacedName{Namespace: "default", Name: "deleted-server"},
+	})
+	require.NoError(t, err)
+
+	toolServers, err := autogenClient.ListToolServers(common.GetGlobalUserID())
+	require.NoError(t, err)
+	require.Len(t, toolServers, 1)
+	assert.Equal(t, "default/kept-server", toolServers[0].Component.Label)
+
+	tools, err := autogenClient.ListTools(common.GetGlobalUserID())
+	require.NoError(t, err)
+	require.Len(t, tools, 1)
+	assert.Equal(t, "kept.tool", tools[0].Component.Provider)
+
+	t.Run("is a no-op once the tool server is gone", func(t *testing.T) {
+		err := reconciler.ReconcileAutogenToolServer(ctx, ctrl.Request{
+			NamespacedName: types.NamespacedName{Namespace: "default", Name: "deleted-server"},
+		})
+		require.NoError(t, err)
+	})
+}
@@ -0,0 +1,239 @@
+package autogen
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	autogen_client "github.com/kagent-dev/kagent/go/autogen/client"
+)
+
+// StaleRunAction is what the StaleRunReconciler does with runs left active by a restart
+type StaleRunAction string
+
+const (
+	// StaleRunActionNone leaves stale runs alone
+	StaleRunActionNone StaleRunAction = "none"
+	// StaleRunActionFail marks stale runs as failed
+	StaleRunActionFail StaleRunAction = "fail"
+	// StaleRunActionRetry marks stale runs as failed and invokes their task again in the same session
+	StaleRunActionRetry StaleRunAction = "retry"
+
+	// InterruptedRunMessage is the error message of the stale runs marked as failed
+	InterruptedRunMessage = "interrupted by restart"
+)
+
+var staleRunsReconciled = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Name: "kagent_stale_runs_reconciled_total",
+	Help: "Number of runs left active by a restart that were failed or retried",
+}, []string{"action"})
+
+func init() {
+	metrics.Registry.MustRegister(staleRunsReconciled)
+}
+
+const (
+	defaultMaxConcurrentRetries = 4
+	defaultRetryQueueSize       = 100
+	defaultRetryTimeout         = 30 * time.Minute
+)
+
+// StaleRunReconciler finds the runs of all users left active by a restart of the controller
+// or of Autogen, so they don't show as in progress forever. The runs created before StartedAt
+// are stale whatever their age, as the controller that followed them is gone. Later runs are
+// only stale once active for longer than Threshold, as they may still be running.
+type StaleRunReconciler struct {
+	AutogenClient autogen_client.Client
+	Action        StaleRunAction
+	Threshold     time.Duration
+	// StartedAt is when the controller process started. It's ignored if zero.
+	StartedAt time.Time
+	// Interval is how often stale runs are looked for after startup, so the runs interrupted
+	// by a restart of Autogen are found too. With 0 they are only looked for on startup.
+	Interval time.Duration
+	// MaxConcurrentRetries bounds the stale runs retried at once. It defaults to 4. Runs
+	// that would have to wait behind more than 100 others are failed but not retried.
+	MaxConcurrentRetries int
+	// RetryTimeout bounds each retried invocation. It defaults to 30m.
+	RetryTimeout time.Duration
+
+	// now is replaced in tests
+	now func() time.Time
+	// retries queues the runs to retry, see startRetries
+	retries chan *autogen_client.Run
+}
+
+// ValidateStaleRunAction checks that action is one of the supported actions
+func ValidateStaleRunAction(action StaleRunAction) error {
+	switch action {
+	case StaleRunActionNone, StaleRunActionFail, StaleRunActionRetry:
+		return nil
+	}
+	return fmt.Errorf("invalid stale run action %q: must be one of %s, %s or %s", action, StaleRunActionNone, StaleRunActionFail, StaleRunActionRetry)
+}
+
+// NeedLeaderElection makes only the leader reconcile stale runs
+func (r *StaleRunReconciler) NeedLeaderElection() bool {
+	return true
+}
+
+// Start reconciles the stale runs on startup, then every Interval until ctx is done. Failing
+// to do so is logged rather than stopping the manager.
+func (r *StaleRunReconciler) Start(ctx context.Context) error {
+	if r.Action == StaleRunActionNone || r.Action == "" {
+		return nil
+	}
+
+	var wg sync.WaitGroup
+	if r.Action == StaleRunActionRetry {
+		r.startRetries(ctx, &wg)
+	}
+	defer wg.Wait()
+
+	r.reconcileAndLog(ctx)
+	if r.Interval <= 0 {
+		<-ctx.Done()
+		return nil
+	}
+
+	ticker := time.NewTicker(r.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			r.reconcileAndLog(ctx)
+		}
+	}
+}
+
+func (r *StaleRunReconciler) reconcileAndLog(ctx context.Context) {
+	log := ctrl.Log.WithName("stale-runs")
+	reconciled, err := r.Reconcile(ctx)
+	if err != nil {
+		log.Error(err, "Failed to reconcile stale runs", "reconciled", reconciled)
+		return
+	}
+	log.Info("Reconciled stale runs", "action", r.Action, "reconciled", reconciled)
+}
+
+// Reconcile fails the stale runs of all users, queues them for retry with
+// StaleRunActionRetry, and returns how many it reconciled
+func (r *StaleRunReconciler) Reconcile(ctx context.Context) (int, error) {
+	log := ctrl.Log.WithName("stale-runs")
+	now := time.Now
+	if r.now != nil {
+		now = r.now
+	}
+
+	runs, err := r.AutogenClient.ListRunsByStatus(autogen_client.RunStatusActive)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list active runs: %w", err)
+	}
+
+	reconciled := 0
+	for _, run := range runs {
+		createdAt, err := parseAutogenTime(run.CreatedAt)
+		if err != nil {
+			log.Info("Skipping run with unknown creation time", "runID", run.ID, "createdAt", run.CreatedAt)
+			continue
+		}
+		beforeStart := !r.StartedAt.IsZero() && createdAt.Before(r.StartedAt)
+		if !beforeStart && now().Sub(createdAt) < r.Threshold {
+			continue
+		}
+
+		if err := r.AutogenClient.UpdateRunStatus(run.ID, autogen_client.RunStatusError, InterruptedRunMessage); err != nil {
+			return reconciled, fmt.Errorf("failed to fail stale run %d: %w", run.ID, err)
+		}
+		reconciled++
+		staleRunsReconciled.WithLabelValues(string(r.Action)).Inc()
+		log.Info("Marked stale run as failed", "runID", run.ID, "sessionID", run.SessionID, "createdAt", run.CreatedAt)
+
+		if r.retries != nil {
+			select {
+			case r.retries <- run:
+			default:
+				log.Info("Too many stale runs waiting to be retried, not retrying", "runID", run.ID, "sessionID", run.SessionID)
+			}
+		}
+	}
+	return reconciled, nil
+}
+
+// startRetries starts the workers retrying the runs queued by Reconcile. They stop once ctx
+// is done.
+func (r *StaleRunReconciler) startRetries(ctx context.Context, wg *sync.WaitGroup) {
+	workers := r.MaxConcurrentRetries
+	if workers <= 0 {
+		workers = defaultMaxConcurrentRetries
+	}
+	timeout := r.RetryTimeout
+	if timeout <= 0 {
+		timeout = defaultRetryTimeout
+	}
+
+	r.retries = make(chan *autogen_client.Run, defaultRetryQueueSize)
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case run := <-r.retries:
+					log := ctrl.Log.WithName("stale-runs").WithValues("runID", run.ID, "sessionID", run.SessionID)
+					retryCtx, cancel := context.WithTimeout(ctx, timeout)
+					if err := r.retry(retryCtx, run); err != nil {
+						log.Error(err, "Failed to retry stale run")
+					} else {
+						log.Info("Retried stale run")
+					}
+					cancel()
+				}
+			}
+		}()
+	}
+}
+
+// retry invokes the task of the stale run again in its session
+func (r *StaleRunReconciler) retry(ctx context.Context, run *autogen_client.Run) error {
+	task, ok := run.Task.Content.(string)
+	if !ok || task == "" {
+		return fmt.Errorf("run has no text task to retry")
+	}
+	session, err := r.AutogenClient.GetSessionById(run.SessionID, run.UserID)
+	if err != nil {
+		return fmt.Errorf("failed to get session: %w", err)
+	}
+	if session.TeamID == nil {
+		return fmt.Errorf("session has no agent bound")
+	}
+	team, err := r.AutogenClient.GetTeamByID(*session.TeamID, run.UserID)
+	if err != nil {
+		return fmt.Errorf("failed to get the session's agent: %w", err)
+	}
+
+	if _, err := r.AutogenClient.InvokeSession(ctx, run.SessionID, run.UserID, &autogen_client.InvokeRequest{
+		Task:       task,
+		TeamConfig: team.Component,
+	}); err != nil {
+		return fmt.Errorf("retried run failed: %w", err)
+	}
+	return nil
+}
+
+// parseAutogenTime parses the timestamps of Autogen objects. Timestamps without a zone are UTC.
+func parseAutogenTime(value string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339Nano, value); err == nil {
+		return t, nil
+	}
+	return time.Parse("2006-01-02T15:04:05.999999999", value)
+}
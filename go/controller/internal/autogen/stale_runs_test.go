@@ -0,0 +1,181 @@
+package autogen
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kagent-dev/kagent/go/autogen/api"
+	autogen_client "github.com/kagent-dev/kagent/go/autogen/client"
+	autogen_fake "github.com/kagent-dev/kagent/go/autogen/client/fake"
+)
+
+func TestStaleRunReconciler(t *testing.T) {
+	const userID = "admin@kagent.dev"
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	setup := func(t *testing.T) (*autogen_fake.InMemoryAutogenClient, map[string]*autogen_client.Run) {
+		client := autogen_fake.NewInMemoryAutogenClient()
+		require.NoError(t, client.CreateTeam(&autogen_client.Team{
+			BaseObject: autogen_client.BaseObject{Id: 1, UserID: userID},
+			Component:  &api.Component{Label: "default/test-agent"},
+		}))
+		teamID := 1
+		session, err := client.CreateSession(&autogen_client.CreateSession{Name: "session", UserID: userID, TeamID: &teamID})
+		require.NoError(t, err)
+
+		runs := map[string]*autogen_client.Run{}
+		for i := 0; i < 4; i++ {
+			_, err := client.CreateRun(&autogen_client.CreateRunRequest{SessionID: session.ID, UserID: userID})
+			require.NoError(t, err)
+		}
+		sessionRuns, err := client.ListSessionRuns(session.ID, userID)
+		require.NoError(t, err)
+		byID := map[int]*autogen_client.Run{}
+		for _, run := range sessionRuns {
+			byID[run.ID] = run
+		}
+
+		runs["stale"] = byID[1]
+		runs["stale"].Status = autogen_client.RunStatusActive
+		runs["stale"].CreatedAt = "2025-06-01T09:00:00.123456"
+		runs["stale"].Task = autogen_client.Task{Source: "user", Content: "list the pods"}
+		runs["recent"] = byID[2]
+		runs["recent"].Status = autogen_client.RunStatusActive
+		runs["recent"].CreatedAt = "2025-06-01T11:50:00+00:00"
+		runs["complete"] = byID[3]
+		runs["complete"].Status = autogen_client.RunStatusComplete
+		runs["complete"].CreatedAt = "2025-06-01T09:00:00"
+		runs["no-time"] = byID[4]
+		runs["no-time"].Status = autogen_client.RunStatusActive
+		return client, runs
+	}
+
+	t.Run("fails active runs older than the threshold", func(t *testing.T) {
+		client, runs := setup(t)
+		reconciler := &StaleRunReconciler{
+			AutogenClient: client,
+			Action:        StaleRunActionFail,
+			Threshold:     time.Hour,
+			now:           func() time.Time { return now },
+		}
+
+		reconciled, err := reconciler.Reconcile(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, 1, reconciled)
+		assert.Equal(t, autogen_client.RunStatusError, runs["stale"].Status)
+		assert.Equal(t, InterruptedRunMessage, runs["stale"].ErrorMessage)
+		assert.Equal(t, autogen_client.RunStatusActive, runs["recent"].Status)
+		assert.Equal(t, autogen_client.RunStatusComplete, runs["complete"].Status)
+		assert.Equal(t, autogen_client.RunStatusActive, runs["no-time"].Status)
+	})
+
+	t.Run("fails active runs created before the controller started", func(t *testing.T) {
+		client, runs := setup(t)
+		reconciler := &StaleRunReconciler{
+			AutogenClient: client,
+			Action:        StaleRunActionFail,
+			Threshold:     time.Hour,
+			StartedAt:     now.Add(-5 * time.Minute),
+			now:           func() time.Time { return now },
+		}
+
+		reconciled, err := reconciler.Reconcile(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, 2, reconciled)
+		assert.Equal(t, autogen_client.RunStatusError, runs["stale"].Status)
+		assert.Equal(t, autogen_client.RunStatusError, runs["recent"].Status)
+	})
+
+	t.Run("covers the runs of all users", func(t *testing.T) {
+		client, runs := setup(t)
+		other, err := client.CreateSession(&autogen_client.CreateSession{Name: "other", UserID: "other-user"})
+		require.NoError(t, err)
+		created, err := client.CreateRun(&autogen_client.CreateRunRequest{SessionID: other.ID, UserID: "other-user"})
+		require.NoError(t, err)
+		otherRun, err := client.GetRun(created.ID)
+		require.NoError(t, err)
+		otherRun.Status = autogen_client.RunStatusActive
+		otherRun.CreatedAt = "2025-06-01T09:00:00"
+
+		reconciler := &StaleRunReconciler{
+			AutogenClient: client,
+			Action:        StaleRunActionFail,
+			Threshold:     time.Hour,
+			now:           func() time.Time { return now },
+		}
+
+		reconciled, err := reconciler.Reconcile(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, 2, reconciled)
+		assert.Equal(t, autogen_client.RunStatusError, runs["stale"].Status)
+		assert.Equal(t, autogen_client.RunStatusError, otherRun.Status)
+	})
+
+	t.Run("retries the task of stale runs", func(t *testing.T) {
+		client, runs := setup(t)
+		retried := make(chan string, 1)
+		client.SetInvokeResponse("list the pods", &autogen_fake.InvokeResponse{
+			TaskResult: autogen_client.TaskResult{StopReason: "done"},
+		})
+		reconciler := &StaleRunReconciler{
+			AutogenClient: &retryRecorder{Client: client, retried: retried},
+			Action:        StaleRunActionRetry,
+			Threshold:     time.Hour,
+			now:           func() time.Time { return now },
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		stopped := make(chan error)
+		go func() { stopped <- reconciler.Start(ctx) }()
+
+		select {
+		case task := <-retried:
+			assert.Equal(t, "list the pods", task)
+		case <-time.After(time.Second):
+			t.Fatal("stale run was not retried")
+		}
+		assert.Equal(t, autogen_client.RunStatusError, runs["stale"].Status)
+
+		cancel()
+		select {
+		case err := <-stopped:
+			assert.NoError(t, err)
+		case <-time.After(time.Second):
+			t.Fatal("reconciler didn't stop")
+		}
+	})
+
+	t.Run("does nothing with action none", func(t *testing.T) {
+		client, runs := setup(t)
+		reconciler := &StaleRunReconciler{
+			AutogenClient: client,
+			Action:        StaleRunActionNone,
+			Threshold:     time.Hour,
+			now:           func() time.Time { return now },
+		}
+
+		require.NoError(t, reconciler.Start(context.Background()))
+		assert.Equal(t, autogen_client.RunStatusActive, runs["stale"].Status)
+	})
+
+	t.Run("rejects unknown actions", func(t *testing.T) {
+		assert.NoError(t, ValidateStaleRunAction(StaleRunActionRetry))
+		assert.Error(t, ValidateStaleRunAction("requeue"))
+	})
+}
+
+// retryRecorder reports the tasks of the sessions invoked through it
+type retryRecorder struct {
+	autogen_client.Client
+	retried chan<- string
+}
+
+func (r *retryRecorder) InvokeSession(ctx context.Context, sessionID int, userID string, request *autogen_client.InvokeRequest) (*autogen_client.TeamResult, error) {
+	result, err := r.Client.InvokeSession(ctx, sessionID, userID, request)
+	r.retried <- request.Task
+	return result, err
+}
@@ -0,0 +1,95 @@
+package errors
+
+import (
+	"errors"
+	"net/http"
+
+	autogen_client "github.com/kagent-dev/kagent/go/autogen/client"
+)
+
+// ErrorCode is a machine-readable error reason returned in the "code" field of API errors,
+// so clients can branch on it instead of parsing the message
+type ErrorCode string
+
+const (
+	CodeBadRequest         ErrorCode = "bad_request"
+	CodeValidationFailed   ErrorCode = "validation_failed"
+	CodeNotFound           ErrorCode = "not_found"
+	CodeSessionNotFound    ErrorCode = "session_not_found"
+	CodeAgentNotFound      ErrorCode = "agent_not_found"
+	CodeConflict           ErrorCode = "conflict"
+	CodeRunInProgress      ErrorCode = "run_in_progress"
+	CodeRunStopped         ErrorCode = "run_stopped"
+	CodeQuotaExceeded      ErrorCode = "quota_exceeded"
+	CodeTooManyRequests    ErrorCode = "too_many_requests"
+	CodeRequestTooLarge    ErrorCode = "request_too_large"
+	CodeUnauthorized       ErrorCode = "unauthorized"
+	CodeBackendUnavailable ErrorCode = "backend_unavailable"
+	CodeInternal           ErrorCode = "internal_error"
+)
+
+// sentinelCodes maps the sentinel errors of the Autogen client to the code reported when an
+// APIError wraps them without setting its own
+var sentinelCodes = []struct {
+	err  error
+	code ErrorCode
+}{
+	{autogen_client.NotFoundError, CodeNotFound},
+	{autogen_client.ErrUnauthorized, CodeUnauthorized},
+	{autogen_client.ErrCircuitOpen, CodeBackendUnavailable},
+}
+
+var statusCodes = map[int]ErrorCode{
+	http.StatusBadRequest:            CodeBadRequest,
+	http.StatusUnprocessableEntity:   CodeValidationFailed,
+	http.StatusNotFound:              CodeNotFound,
+	http.StatusConflict:              CodeConflict,
+	http.StatusTooManyRequests:       CodeTooManyRequests,
+	http.StatusRequestEntityTooLarge: CodeRequestTooLarge,
+	http.StatusUnauthorized:          CodeUnauthorized,
+	http.StatusServiceUnavailable:    CodeBackendUnavailable,
+}
+
+// CodeOf returns the machine-readable reason of err: the ErrorCode of an APIError if set,
+// else the code of a wrapped sentinel error, else a code derived from the HTTP status
+func CodeOf(err error) ErrorCode {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && apiErr.ErrorCode != "" {
+		return apiErr.ErrorCode
+	}
+	for _, sentinel := range sentinelCodes {
+		if errors.Is(err, sentinel.err) {
+			return sentinel.code
+		}
+	}
+	if apiErr != nil {
+		if code, ok := statusCodes[apiErr.Code]; ok {
+			return code
+		}
+	}
+	return CodeInternal
+}
+
+// CodeForStatus returns the code of errors with the HTTP status that don't set their own
+func CodeForStatus(status int) ErrorCode {
+	if code, ok := statusCodes[status]; ok {
+		return code
+	}
+	return CodeInternal
+}
+
+// StatusOf returns the HTTP status of err: the status of an APIError, else the status of the
+// code of a wrapped sentinel error, else 500
+func StatusOf(err error) int {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.Code
+	}
+	code := CodeOf(err)
+	for status, statusCode := range statusCodes {
+		if statusCode == code {
+			return status
+		}
+	}
+	return http.StatusInternalServerError
+}
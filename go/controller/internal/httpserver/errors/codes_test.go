@@ -0,0 +1,50 @@
+package errors
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	autogen_client "github.com/kagent-dev/kagent/go/autogen/client"
+)
+
+func TestCodeOf(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want ErrorCode
+	}{
+		{"explicit code", NewTooManyRequestsError("quota", nil).WithCode(CodeQuotaExceeded), CodeQuotaExceeded},
+		{"derived from status", NewValidationError("invalid", nil), CodeValidationFailed},
+		{"wrapped sentinel", NewInternalServerError("failed", fmt.Errorf("error making request: %w", autogen_client.ErrCircuitOpen)), CodeBackendUnavailable},
+		{"explicit code wins over sentinel", NewNotFoundError("missing", autogen_client.NotFoundError).WithCode(CodeSessionNotFound), CodeSessionNotFound},
+		{"unknown status", NewInternalServerError("failed", nil), CodeInternal},
+		{"plain error", fmt.Errorf("boom"), CodeInternal},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, CodeOf(tt.err))
+		})
+	}
+}
+
+func TestStatusOf(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"api error", NewConflictError("busy", nil), http.StatusConflict},
+		{"wrapped api error", fmt.Errorf("failed: %w", NewNotFoundError("missing", nil)), http.StatusNotFound},
+		{"api error status wins over sentinel", NewInternalServerError("failed", autogen_client.ErrCircuitOpen), http.StatusInternalServerError},
+		{"plain sentinel", fmt.Errorf("run 1: %w", autogen_client.NotFoundError), http.StatusNotFound},
+		{"plain error", fmt.Errorf("boom"), http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, StatusOf(tt.err))
+		})
+	}
+}
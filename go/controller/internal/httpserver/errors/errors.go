@@ -10,6 +10,15 @@ type APIError struct {
 	Code    int
 	Message string
 	Err     error
+	// ErrorCode is the machine-readable reason of the error. If empty, CodeOf derives it from
+	// Err or Code.
+	ErrorCode ErrorCode
+}
+
+// WithCode sets the machine-readable reason of the error
+func (e *APIError) WithCode(code ErrorCode) *APIError {
+	e.ErrorCode = code
+	return e
 }
 
 // Error implements the error interface
@@ -73,3 +82,21 @@ func NewConflictError(message string, err error) *APIError {
 		Err:     err,
 	}
 }
+
+// NewTooManyRequestsError creates a new too many requests error
+func NewTooManyRequestsError(message string, err error) *APIError {
+	return &APIError{
+		Code:    http.StatusTooManyRequests,
+		Message: message,
+		Err:     err,
+	}
+}
+
+// NewRequestEntityTooLargeError creates a new request entity too large error
+func NewRequestEntityTooLargeError(message string, err error) *APIError {
+	return &APIError{
+		Code:    http.StatusRequestEntityTooLarge,
+		Message: message,
+		Err:     err,
+	}
+}
@@ -0,0 +1,111 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	ctrllog "sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/kagent-dev/kagent/go/autogen/api"
+	"github.com/kagent-dev/kagent/go/controller/api/v1alpha1"
+	"github.com/kagent-dev/kagent/go/controller/internal/httpserver/errors"
+	common "github.com/kagent-dev/kagent/go/controller/internal/utils"
+)
+
+// HandleListAgentTools handles GET /api/agents/{namespace}/{teamName}/tools requests. The
+// Agent is translated like it is for Autogen, so the returned tools are the resolved
+// components the agent runs with, including the discovered MCP tools of its tool servers.
+func (h *TeamsHandler) HandleListAgentTools(w ErrorResponseWriter, r *http.Request) {
+	log := ctrllog.FromContext(r.Context()).WithName("teams-handler").WithValues("operation", "list-tools")
+
+	namespace, err := GetPathParam(r, "namespace")
+	if err != nil {
+		w.RespondWithError(errors.NewBadRequestError("Failed to get namespace from path", err))
+		return
+	}
+
+	teamName, err := GetPathParam(r, "teamName")
+	if err != nil {
+		w.RespondWithError(errors.NewBadRequestError("Failed to get teamName from path", err))
+		return
+	}
+
+	log = log.WithValues(
+		"teamNamespace", namespace,
+		"teamName", teamName,
+	)
+
+	log.V(1).Info("Getting Team from Kubernetes")
+	team := &v1alpha1.Agent{}
+	if err := common.GetObject(
+		r.Context(),
+		h.KubeClient,
+		team,
+		teamName,
+		namespace,
+	); err != nil {
+		if k8serrors.IsNotFound(err) {
+			w.RespondWithError(errors.NewNotFoundError("Team not found", nil).WithCode(errors.CodeAgentNotFound))
+			return
+		}
+		w.RespondWithError(errors.NewInternalServerError("Failed to get Team", err))
+		return
+	}
+
+	log.V(1).Info("Translating Team to Autogen format")
+	autogenTeam, err := h.translateTeam(r.Context(), team)
+	if err != nil {
+		// Missing tool servers or undiscovered tools are configuration problems of the Agent
+		w.RespondWithError(errors.NewValidationError("Failed to resolve the tools of the Team", err))
+		return
+	}
+
+	tools, err := teamComponentTools(autogenTeam.Component)
+	if err != nil {
+		w.RespondWithError(errors.NewInternalServerError("Failed to extract the tools of the Team", err))
+		return
+	}
+
+	log.Info("Successfully listed Team tools", "count", len(tools))
+	RespondWithJSON(w, http.StatusOK, NewResponse(tools, "Successfully listed Team tools"))
+}
+
+// teamComponentTools returns the tools of the agents participating in the team component.
+// Agent tools are returned as their TeamTool component, without the tools of the nested team.
+func teamComponentTools(team *api.Component) ([]*api.Component, error) {
+	if team == nil {
+		return nil, fmt.Errorf("team has no component")
+	}
+
+	var teamConfig struct {
+		Participants []*api.Component `json:"participants"`
+	}
+	if err := decodeComponentConfig(team, &teamConfig); err != nil {
+		return nil, fmt.Errorf("failed to decode team config: %w", err)
+	}
+
+	tools := []*api.Component{}
+	for _, participant := range teamConfig.Participants {
+		if participant == nil || participant.ComponentType != "agent" {
+			continue
+		}
+		var agentConfig struct {
+			Tools []*api.Component `json:"tools"`
+		}
+		if err := decodeComponentConfig(participant, &agentConfig); err != nil {
+			return nil, fmt.Errorf("failed to decode config of agent %s: %w", participant.Label, err)
+		}
+		tools = append(tools, agentConfig.Tools...)
+	}
+	return tools, nil
+}
+
+func decodeComponentConfig(component *api.Component, out interface{}) error {
+	b, err := json.Marshal(component.Config)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, out)
+}
@@ -0,0 +1,126 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kagent-dev/kagent/go/autogen/api"
+	"github.com/kagent-dev/kagent/go/controller/api/v1alpha1"
+)
+
+func TestHandleListAgentTools(t *testing.T) {
+	modelConfig := &v1alpha1.ModelConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-model-config", Namespace: "default"},
+		Spec: v1alpha1.ModelConfigSpec{
+			Model:    "test",
+			Provider: "Ollama",
+			Ollama:   &v1alpha1.OllamaConfig{Host: "http://test-host"},
+		},
+	}
+	toolServer := &v1alpha1.ToolServer{
+		ObjectMeta: metav1.ObjectMeta{Name: "search-server", Namespace: "default"},
+		Status: v1alpha1.ToolServerStatus{
+			DiscoveredTools: []*v1alpha1.MCPTool{
+				{Name: "search", Component: v1alpha1.Component{Provider: "mcp.search", ComponentType: "tool", Label: "search"}},
+				{Name: "fetch", Component: v1alpha1.Component{Provider: "mcp.fetch", ComponentType: "tool", Label: "fetch"}},
+			},
+		},
+	}
+	agentWithTools := func(name string, toolNames ...string) *v1alpha1.Agent {
+		agent := createTestAgent(name, modelConfig)
+		agent.Spec.Tools = []*v1alpha1.Tool{{
+			Type:      v1alpha1.ToolProviderType_McpServer,
+			McpServer: &v1alpha1.McpServerTool{ToolServer: "search-server", ToolNames: toolNames},
+		}}
+		return agent
+	}
+
+	listTools := func(objects []client.Object, name string) *httptest.ResponseRecorder {
+		handler, _ := setupTestHandler(objects...)
+		req := httptest.NewRequest("GET", "/api/agents/default/"+name+"/tools", nil)
+		req = mux.SetURLVars(req, map[string]string{
+			"namespace": "default",
+			"teamName":  name,
+		})
+		w := httptest.NewRecorder()
+		handler.HandleListAgentTools(&testErrorResponseWriter{w}, req)
+		return w
+	}
+
+	t.Run("returns the resolved tools of the agent", func(t *testing.T) {
+		agent := agentWithTools("search-agent", "search")
+		w := listTools([]client.Object{modelConfig, toolServer, agent}, "search-agent")
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+		var tools []*api.Component
+		require.NoError(t, DecodeResponseData(w.Body.Bytes(), &tools))
+		require.Len(t, tools, 1)
+		assert.Equal(t, "mcp.search", tools[0].Provider)
+		assert.Equal(t, "tool", tools[0].ComponentType)
+	})
+
+	t.Run("returns an empty list for an agent without tools", func(t *testing.T) {
+		agent := createTestAgent("plain-agent", modelConfig)
+		w := listTools([]client.Object{modelConfig, agent}, "plain-agent")
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+		var tools []*api.Component
+		require.NoError(t, DecodeResponseData(w.Body.Bytes(), &tools))
+		assert.Empty(t, tools)
+	})
+
+	t.Run("returns 422 for a tool that was not discovered", func(t *testing.T) {
+		agent := agentWithTools("broken-agent", "missing")
+		w := listTools([]client.Object{modelConfig, toolServer, agent}, "broken-agent")
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	})
+
+	t.Run("returns 404 for non-existent agent", func(t *testing.T) {
+		w := listTools([]client.Object{modelConfig}, "non-existent")
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
+
+func TestTeamComponentTools(t *testing.T) {
+	team := &api.Component{
+		ComponentType: "team",
+		Config: map[string]interface{}{
+			"participants": []interface{}{
+				map[string]interface{}{
+					"component_type": "agent",
+					"label":          "a",
+					"config": map[string]interface{}{
+						"tools": []interface{}{
+							map[string]interface{}{"provider": "p1", "component_type": "tool"},
+						},
+					},
+				},
+				map[string]interface{}{
+					"component_type": "agent",
+					"label":          "b",
+					"config": map[string]interface{}{
+						"tools": []interface{}{
+							map[string]interface{}{"provider": "p2", "component_type": "tool"},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	tools, err := teamComponentTools(team)
+	require.NoError(t, err)
+	require.Len(t, tools, 2)
+	assert.Equal(t, "p1", tools[0].Provider)
+	assert.Equal(t, "p2", tools[1].Provider)
+
+	_, err = teamComponentTools(nil)
+	assert.Error(t, err)
+}
@@ -0,0 +1,67 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	autogen_client "github.com/kagent-dev/kagent/go/autogen/client"
+)
+
+// AutogenURLHeader selects an alternate Autogen backend for an invocation. Only URLs in
+// the server's allow-list are accepted.
+const AutogenURLHeader = "X-Autogen-URL"
+
+// normalizeAutogenURL validates an Autogen base URL and strips trailing slashes so
+// allow-list entries and header values compare equal
+func normalizeAutogenURL(rawURL string) (string, error) {
+	parsed, err := url.Parse(rawURL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return "", fmt.Errorf("%q is not an absolute http or https URL", rawURL)
+	}
+	return strings.TrimRight(parsed.String(), "/"), nil
+}
+
+// autogenClientFor returns the Autogen client selected by the request's X-Autogen-URL
+// header, or the default client if the header is not set. The client of an alternate backend
+// is created with the default client's options. The returned backend is the normalized URL, or
+// empty for the default client.
+func (b *Base) autogenClientFor(r *http.Request) (autogen_client.Client, string, error) {
+	rawURL := r.Header.Get(AutogenURLHeader)
+	if rawURL == "" {
+		return b.AutogenClient, "", nil
+	}
+
+	backend, err := normalizeAutogenURL(rawURL)
+	if err != nil {
+		return nil, "", err
+	}
+
+	allowed := false
+	for _, allowedURL := range b.AllowedAutogenURLs {
+		if normalized, err := normalizeAutogenURL(allowedURL); err == nil && normalized == backend {
+			allowed = true
+			break
+		}
+	}
+	if !allowed {
+		return nil, "", fmt.Errorf("autogen URL %q is not allowed", rawURL)
+	}
+
+	if cached, ok := b.autogenClients.Load(backend); ok {
+		return cached.(autogen_client.Client), backend, nil
+	}
+	newClient := b.newAutogenClient
+	if newClient == nil {
+		newClient = func(baseURL string) autogen_client.Client {
+			client := autogen_client.New(baseURL, b.AutogenClientOptions...)
+			if b.MessageRedactor != nil {
+				return autogen_client.NewRedactingClient(client, b.MessageRedactor)
+			}
+			return client
+		}
+	}
+	client, _ := b.autogenClients.LoadOrStore(backend, newClient(backend))
+	return client.(autogen_client.Client), backend, nil
+}
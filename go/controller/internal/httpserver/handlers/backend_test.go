@@ -0,0 +1,167 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kagent-dev/kagent/go/autogen/api"
+	autogen_client "github.com/kagent-dev/kagent/go/autogen/client"
+	autogen_fake "github.com/kagent-dev/kagent/go/autogen/client/fake"
+)
+
+func TestAutogenClientFor(t *testing.T) {
+	alternate := autogen_fake.NewInMemoryAutogenClient()
+	var created []string
+	base := &Base{
+		AutogenClient:      autogen_fake.NewInMemoryAutogenClient(),
+		AllowedAutogenURLs: []string{"http://autogen.tenant-a:8081/api/"},
+		newAutogenClient: func(baseURL string) autogen_client.Client {
+			created = append(created, baseURL)
+			return alternate
+		},
+	}
+
+	request := func(autogenURL string) *http.Request {
+		req := httptest.NewRequest("POST", "/api/sessions/1/invoke", nil)
+		if autogenURL != "" {
+			req.Header.Set(AutogenURLHeader, autogenURL)
+		}
+		return req
+	}
+
+	t.Run("defaults to the configured client", func(t *testing.T) {
+		client, backend, err := base.autogenClientFor(request(""))
+		require.NoError(t, err)
+		assert.Same(t, base.AutogenClient, client)
+		assert.Empty(t, backend)
+	})
+
+	t.Run("selects an allowed backend and reuses its client", func(t *testing.T) {
+		for i := 0; i < 2; i++ {
+			client, backend, err := base.autogenClientFor(request("http://autogen.tenant-a:8081/api"))
+			require.NoError(t, err)
+			assert.Same(t, alternate, client)
+			assert.Equal(t, "http://autogen.tenant-a:8081/api", backend)
+		}
+		assert.Equal(t, []string{"http://autogen.tenant-a:8081/api"}, created)
+	})
+
+	t.Run("rejects backends outside the allow-list", func(t *testing.T) {
+		_, _, err := base.autogenClientFor(request("http://169.254.169.254/latest"))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "not allowed")
+
+		_, _, err = base.autogenClientFor(request("autogen.tenant-a:8081"))
+		require.Error(t, err)
+	})
+}
+
+func TestAutogenClientForUsesDefaultOptions(t *testing.T) {
+	authorization := make(chan string, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization <- r.Header.Get("Authorization")
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"status":true,"data":{"version":"1.0"}}`))
+	}))
+	defer server.Close()
+
+	base := &Base{
+		AutogenClient:        autogen_fake.NewInMemoryAutogenClient(),
+		AllowedAutogenURLs:   []string{server.URL},
+		AutogenClientOptions: []autogen_client.Option{autogen_client.WithToken("secret")},
+	}
+	req := httptest.NewRequest("POST", "/api/sessions/1/invoke", nil)
+	req.Header.Set(AutogenURLHeader, server.URL)
+
+	client, _, err := base.autogenClientFor(req)
+	require.NoError(t, err)
+	_, err = client.GetVersion(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "Bearer secret", <-authorization)
+}
+
+func TestSessionInvokeAutogenURL(t *testing.T) {
+	handler, userID := setupTestHandler()
+	alternate := autogen_fake.NewInMemoryAutogenClient()
+	handler.AllowedAutogenURLs = []string{"http://autogen.tenant-a:8081/api"}
+	handler.newAutogenClient = func(string) autogen_client.Client { return alternate }
+	sessions := NewSessionsHandler(handler.Base)
+
+	// The session only exists in the alternate backend
+	session, err := alternate.CreateSession(&autogen_client.CreateSession{Name: "session", UserID: userID})
+	require.NoError(t, err)
+
+	invoke := func(autogenURL string) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(&autogen_client.InvokeRequest{Task: "hello", TeamConfig: &api.Component{}})
+		req := httptest.NewRequest("POST", fmt.Sprintf("/api/sessions/%d/invoke?user_id=%s", session.ID, userID), bytes.NewBuffer(body))
+		req = mux.SetURLVars(req, map[string]string{"sessionID": fmt.Sprintf("%d", session.ID)})
+		req.Header.Set(AutogenURLHeader, autogenURL)
+		w := httptest.NewRecorder()
+		sessions.HandleSessionInvoke(&testErrorResponseWriter{w}, req)
+		return w
+	}
+
+	assert.Equal(t, http.StatusOK, invoke("http://autogen.tenant-a:8081/api").Code)
+	assert.Equal(t, http.StatusBadRequest, invoke("http://autogen.tenant-b:8081/api").Code)
+}
+
+func TestDeleteSessionAutogenURL(t *testing.T) {
+	handler, userID := setupTestHandler()
+	alternate := autogen_fake.NewInMemoryAutogenClient()
+	alternate.InvokeDelay = time.Minute
+	handler.AllowedAutogenURLs = []string{"http://autogen.tenant-a:8081/api"}
+	handler.newAutogenClient = func(string) autogen_client.Client { return alternate }
+	sessions := NewSessionsHandler(handler.Base)
+
+	// Both backends have a session with the same ID, and only the alternate one has a run
+	session, err := alternate.CreateSession(&autogen_client.CreateSession{Name: "session", UserID: userID})
+	require.NoError(t, err)
+	_, err = handler.AutogenClient.CreateSession(&autogen_client.CreateSession{Name: "session", UserID: userID})
+	require.NoError(t, err)
+
+	newRequest := func(method, path, autogenURL string) *http.Request {
+		body, _ := json.Marshal(&autogen_client.InvokeRequest{Task: "long task", TeamConfig: &api.Component{}})
+		req := httptest.NewRequest(method, fmt.Sprintf("/api/sessions/%d%s?user_id=%s", session.ID, path, userID), bytes.NewBuffer(body))
+		req = mux.SetURLVars(req, map[string]string{"sessionID": fmt.Sprintf("%d", session.ID)})
+		if autogenURL != "" {
+			req.Header.Set(AutogenURLHeader, autogenURL)
+		}
+		return req
+	}
+
+	go func() {
+		w := httptest.NewRecorder()
+		sessions.HandleSessionInvoke(&testErrorResponseWriter{w}, newRequest(http.MethodPost, "/invoke", "http://autogen.tenant-a:8081/api"))
+	}()
+	key := streamKey{backend: "http://autogen.tenant-a:8081/api", sessionID: session.ID}
+	require.Eventually(t, func() bool { return sessions.runs.active(key) == 1 }, time.Second, 10*time.Millisecond)
+
+	t.Run("refuses to delete the session with the run without force", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		sessions.HandleDeleteSession(&testErrorResponseWriter{w}, newRequest(http.MethodDelete, "", "http://autogen.tenant-a:8081/api"))
+		assert.Equal(t, http.StatusConflict, w.Code)
+		assert.Equal(t, 1, sessions.runs.active(key))
+	})
+
+	t.Run("deletes the default backend's session without stopping the run", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		sessions.HandleDeleteSession(&testErrorResponseWriter{w}, newRequest(http.MethodDelete, "", ""))
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, 1, sessions.runs.active(key))
+
+		_, err := alternate.GetSessionById(session.ID, userID)
+		assert.NoError(t, err)
+	})
+
+	sessions.runs.stop(key, errSessionDeleted)
+}
@@ -0,0 +1,82 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	stderrors "errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/kagent-dev/kagent/go/controller/internal/httpserver/errors"
+)
+
+const (
+	// DefaultMaxBodyBytes is the largest request body DecodeJSONBody reads unless the route sets its own limit
+	DefaultMaxBodyBytes int64 = 1 << 20
+	// DefaultMaxInvokeBodyBytes is the default limit for invoke routes, whose tasks can carry large inputs
+	DefaultMaxInvokeBodyBytes int64 = 10 << 20
+)
+
+type maxBodyBytesKey struct{}
+
+type strictJSONKey struct{}
+
+// WithMaxBodyBytes makes DecodeJSONBody read at most limit bytes of the request body for the
+// wrapped handler. A limit of 0 or less keeps the limit already set for the request.
+func WithMaxBodyBytes(limit int64, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if limit > 0 {
+			r = r.WithContext(context.WithValue(r.Context(), maxBodyBytesKey{}, limit))
+		}
+		next(w, r)
+	}
+}
+
+// maxBodyBytes returns the body size limit set for the request, or DefaultMaxBodyBytes
+func maxBodyBytes(r *http.Request) int64 {
+	if limit, ok := r.Context().Value(maxBodyBytesKey{}).(int64); ok {
+		return limit
+	}
+	return DefaultMaxBodyBytes
+}
+
+// WithStrictJSON makes DecodeJSONBody reject request bodies with fields the target doesn't have
+func WithStrictJSON(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		next(w, r.WithContext(context.WithValue(r.Context(), strictJSONKey{}, true)))
+	}
+}
+
+func isStrictJSON(r *http.Request) bool {
+	strict, _ := r.Context().Value(strictJSONKey{}).(bool)
+	return strict
+}
+
+// decodeMergePatch reads a JSON merge patch (RFC 7396) from the request body. Patches must be
+// objects: other values would replace the whole resource. The patched resource is always decoded
+// with unknown fields rejected, so StrictJSON makes no difference to patches.
+func decodeMergePatch(r *http.Request) ([]byte, error) {
+	var patch map[string]json.RawMessage
+	if err := DecodeJSONBody(r, &patch); err != nil {
+		return nil, err
+	}
+	if patch == nil {
+		return nil, fmt.Errorf("merge patch must be a JSON object")
+	}
+	return json.Marshal(patch)
+}
+
+// invalidBodyError returns the error to respond with when DecodeJSONBody fails
+func invalidBodyError(err error) *errors.APIError {
+	var maxBytesErr *http.MaxBytesError
+	if stderrors.As(err, &maxBytesErr) {
+		return errors.NewRequestEntityTooLargeError(
+			fmt.Sprintf("Request body exceeds the limit of %d bytes", maxBytesErr.Limit), err)
+	}
+	// The decoder doesn't have a typed error for unknown fields
+	if field, ok := strings.CutPrefix(err.Error(), "json: unknown field "); ok {
+		return errors.NewBadRequestError(fmt.Sprintf("Unknown field %s in request body", field), err)
+	}
+	return errors.NewBadRequestError("Invalid request body", err)
+}
@@ -0,0 +1,72 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestBodyLimit(t *testing.T) {
+	handler, userID := setupTestHandler()
+	sessions := NewSessionsHandler(handler.Base)
+
+	// A session name just over the default limit
+	body := `{"user_id":"` + userID + `","name":"` + strings.Repeat("a", int(DefaultMaxBodyBytes)) + `"}`
+	createSession := func(h http.HandlerFunc) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("POST", "/api/sessions", strings.NewReader(body))
+		w := httptest.NewRecorder()
+		h(w, req)
+		return w
+	}
+	handle := func(w http.ResponseWriter, r *http.Request) {
+		sessions.HandleCreateSession(&testErrorResponseWriter{w}, r)
+	}
+
+	t.Run("rejects bodies over the default limit", func(t *testing.T) {
+		w := createSession(handle)
+		assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+		assert.Contains(t, w.Body.String(), "exceeds the limit")
+	})
+
+	t.Run("applies the route limit", func(t *testing.T) {
+		w := createSession(WithMaxBodyBytes(2*DefaultMaxBodyBytes, handle))
+		assert.Equal(t, http.StatusCreated, w.Code)
+
+		w = createSession(WithMaxBodyBytes(1024, handle))
+		assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+	})
+
+	t.Run("malformed bodies are still bad requests", func(t *testing.T) {
+		req := httptest.NewRequest("POST", "/api/sessions", strings.NewReader(`{"user_id":`))
+		w := httptest.NewRecorder()
+		handle(w, req)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestStrictJSON(t *testing.T) {
+	handler, userID := setupTestHandler()
+	sessions := NewSessionsHandler(handler.Base)
+	handle := func(w http.ResponseWriter, r *http.Request) {
+		sessions.HandleCreateSession(&testErrorResponseWriter{w}, r)
+	}
+	createSession := func(h http.HandlerFunc) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("POST", "/api/sessions", strings.NewReader(`{"user_id":"`+userID+`","nam":"typo"}`))
+		w := httptest.NewRecorder()
+		h(w, req)
+		return w
+	}
+
+	t.Run("ignores unknown fields by default", func(t *testing.T) {
+		assert.Equal(t, http.StatusCreated, createSession(handle).Code)
+	})
+
+	t.Run("names the unknown field", func(t *testing.T) {
+		w := createSession(WithStrictJSON(handle))
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), `Unknown field "nam"`)
+	})
+}
@@ -0,0 +1,143 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	"github.com/kagent-dev/kagent/go/controller/internal/httpserver/errors"
+)
+
+// invokeRetryAfterSeconds is sent in the Retry-After header of invocations rejected by an InvokeLimiter
+const invokeRetryAfterSeconds = 1
+
+// invocationsInFlight is the number of invocations currently being served
+var invocationsInFlight = prometheus.NewGauge(prometheus.GaugeOpts{
+	Name: "kagent_invocations_in_flight",
+	Help: "Number of agent and session invocations currently being served by the HTTP API",
+})
+
+func init() {
+	metrics.Registry.MustRegister(invocationsInFlight)
+}
+
+// InvokeLimiter bounds the number of invocations served at the same time, in total and per
+// user. A limit of 0 means unlimited.
+type InvokeLimiter struct {
+	maxInFlight        int
+	maxInFlightPerUser int
+
+	mu       sync.Mutex
+	inFlight int
+	perUser  map[string]int
+}
+
+// NewInvokeLimiter creates a new InvokeLimiter
+func NewInvokeLimiter(maxInFlight, maxInFlightPerUser int) *InvokeLimiter {
+	return &InvokeLimiter{
+		maxInFlight:        maxInFlight,
+		maxInFlightPerUser: maxInFlightPerUser,
+		perUser:            make(map[string]int),
+	}
+}
+
+type invokeSlotKey struct{}
+
+// invokeSlot is the slot an invocation holds in an InvokeLimiter
+type invokeSlot struct {
+	release func()
+
+	mu       sync.Mutex
+	detached bool
+	once     sync.Once
+}
+
+// Limit rejects requests with 429 and a Retry-After header while the limiter is saturated.
+// The slot taken by a request is released when next returns, including when it panics,
+// unless next keeps the invocation running with HoldInvokeSlot. Requests without a user ID
+// only count towards the total limit.
+func (l *InvokeLimiter) Limit(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID, _ := GetUserID(r)
+		if err := l.acquire(userID); err != nil {
+			w.Header().Set("Retry-After", strconv.Itoa(invokeRetryAfterSeconds))
+			if ew, ok := w.(ErrorResponseWriter); ok {
+				ew.RespondWithError(err)
+			} else {
+				http.Error(w, err.Message, err.Code)
+			}
+			return
+		}
+		slot := &invokeSlot{release: func() { l.release(userID) }}
+		defer func() {
+			slot.mu.Lock()
+			detached := slot.detached
+			slot.mu.Unlock()
+			if !detached {
+				slot.once.Do(slot.release)
+			}
+		}()
+
+		next(w, r.WithContext(context.WithValue(r.Context(), invokeSlotKey{}, slot)))
+	}
+}
+
+// HoldInvokeSlot keeps the InvokeLimiter slot of the request taken after the handler returns,
+// for invocations that outlive their request. The returned function releases the slot, and
+// must be called once the invocation is done. It does nothing for requests without a slot.
+func HoldInvokeSlot(r *http.Request) func() {
+	slot, ok := r.Context().Value(invokeSlotKey{}).(*invokeSlot)
+	if !ok {
+		return func() {}
+	}
+	slot.mu.Lock()
+	slot.detached = true
+	slot.mu.Unlock()
+	return func() { slot.once.Do(slot.release) }
+}
+
+// InFlight returns the number of invocations currently holding a slot
+func (l *InvokeLimiter) InFlight() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.inFlight
+}
+
+func (l *InvokeLimiter) acquire(userID string) *errors.APIError {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.maxInFlight > 0 && l.inFlight >= l.maxInFlight {
+		return errors.NewTooManyRequestsError(
+			fmt.Sprintf("Too many concurrent invocations: limit of %d reached", l.maxInFlight), nil)
+	}
+	if userID != "" && l.maxInFlightPerUser > 0 && l.perUser[userID] >= l.maxInFlightPerUser {
+		return errors.NewTooManyRequestsError(
+			fmt.Sprintf("Too many concurrent invocations for user %s: limit of %d reached", userID, l.maxInFlightPerUser), nil)
+	}
+
+	l.inFlight++
+	if userID != "" {
+		l.perUser[userID]++
+	}
+	invocationsInFlight.Inc()
+	return nil
+}
+
+func (l *InvokeLimiter) release(userID string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.inFlight--
+	if userID != "" {
+		if l.perUser[userID]--; l.perUser[userID] <= 0 {
+			delete(l.perUser, userID)
+		}
+	}
+	invocationsInFlight.Dec()
+}
@@ -0,0 +1,104 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInvokeLimiter(t *testing.T) {
+	limiter := NewInvokeLimiter(3, 2)
+
+	started := make(chan struct{})
+	unblock := make(chan struct{})
+	handler := limiter.Limit(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("block") == "true" {
+			started <- struct{}{}
+			<-unblock
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+
+	serve := func(query string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		handler(&testErrorResponseWriter{w}, httptest.NewRequest("POST", "/api/sessions/1/invoke?"+query, nil))
+		return w
+	}
+
+	var wg sync.WaitGroup
+	block := func(userID string) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			serve("block=true&user_id=" + userID)
+		}()
+		<-started
+	}
+
+	block("alice")
+	block("alice")
+	assert.Equal(t, 2, limiter.InFlight())
+
+	t.Run("rejects users over their limit", func(t *testing.T) {
+		w := serve("user_id=alice")
+		assert.Equal(t, http.StatusTooManyRequests, w.Code)
+		assert.Equal(t, "1", w.Header().Get("Retry-After"))
+		assert.Contains(t, w.Body.String(), "for user alice")
+
+		assert.Equal(t, http.StatusOK, serve("user_id=bob").Code)
+	})
+
+	t.Run("rejects everyone over the total limit", func(t *testing.T) {
+		block("bob")
+		w := serve("user_id=carol")
+		assert.Equal(t, http.StatusTooManyRequests, w.Code)
+		assert.Equal(t, "1", w.Header().Get("Retry-After"))
+		assert.Contains(t, w.Body.String(), "limit of 3 reached")
+	})
+
+	close(unblock)
+	wg.Wait()
+	assert.Equal(t, 0, limiter.InFlight())
+
+	t.Run("releases the slot when the handler panics", func(t *testing.T) {
+		limiter := NewInvokeLimiter(1, 0)
+		handler := limiter.Limit(func(w http.ResponseWriter, r *http.Request) {
+			panic("handler failed")
+		})
+		require.Panics(t, func() {
+			handler(httptest.NewRecorder(), httptest.NewRequest("POST", "/api/sessions/1/invoke?user_id=alice", nil))
+		})
+		assert.Equal(t, 0, limiter.InFlight())
+	})
+}
+
+func TestInvokeLimiterHeldSlot(t *testing.T) {
+	limiter := NewInvokeLimiter(1, 0)
+
+	var release func()
+	held := limiter.Limit(func(w http.ResponseWriter, r *http.Request) {
+		release = HoldInvokeSlot(r)
+		w.WriteHeader(http.StatusOK)
+	})
+	other := limiter.Limit(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	serve := func(handler http.HandlerFunc) int {
+		w := httptest.NewRecorder()
+		handler(&testErrorResponseWriter{w}, httptest.NewRequest("POST", "/api/sessions/1/invoke/stream?user_id=alice", nil))
+		return w.Code
+	}
+
+	require.Equal(t, http.StatusOK, serve(held))
+	assert.Equal(t, 1, limiter.InFlight(), "the slot is held after the handler returns")
+	assert.Equal(t, http.StatusTooManyRequests, serve(other))
+
+	release()
+	release()
+	assert.Equal(t, 0, limiter.InFlight(), "releasing twice frees the slot once")
+	assert.Equal(t, http.StatusOK, serve(other))
+}
@@ -0,0 +1,88 @@
+package handlers
+
+import (
+	"net/http"
+
+	autogen_client "github.com/kagent-dev/kagent/go/autogen/client"
+	"github.com/kagent-dev/kagent/go/controller/internal/httpserver/errors"
+	common "github.com/kagent-dev/kagent/go/controller/internal/utils"
+	ctrllog "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// ConversationsHandler handles requests for A2A conversation history
+type ConversationsHandler struct {
+	*Base
+}
+
+// NewConversationsHandler creates a new ConversationsHandler
+func NewConversationsHandler(base *Base) *ConversationsHandler {
+	return &ConversationsHandler{Base: base}
+}
+
+func messageCursor(message *autogen_client.RunMessage) pageCursor {
+	cursor := pageCursor{ID: message.ID}
+	if message.CreatedAt != nil {
+		cursor.CreatedAt = *message.CreatedAt
+	}
+	return cursor
+}
+
+// HandleListConversationMessages handles GET /api/a2a/conversations/{contextID}/messages requests.
+// A2A tasks with a context id run in an Autogen session of the global user named after the
+// context, so only those sessions can be read here. Messages are returned newest first and
+// are paginated with limit and cursor like sessions.
+func (h *ConversationsHandler) HandleListConversationMessages(w ErrorResponseWriter, r *http.Request) {
+	log := ctrllog.FromContext(r.Context()).WithName("conversations-handler").WithValues("operation", "list-messages")
+
+	contextID, err := GetPathParam(r, "contextID")
+	if err != nil {
+		w.RespondWithError(errors.NewBadRequestError("Failed to get context ID from path", err))
+		return
+	}
+	log = log.WithValues("contextID", contextID)
+
+	pageParams, err := getPageParams(r)
+	if err != nil {
+		w.RespondWithError(errors.NewBadRequestError("Invalid pagination parameters", err))
+		return
+	}
+
+	userID := common.GetGlobalUserID()
+	session, err := h.AutogenClient.GetSession(contextID, userID)
+	if err == autogen_client.NotFoundError || (err == nil && session.UserID != userID) {
+		w.RespondWithError(errors.NewNotFoundError("Conversation not found", nil).WithCode(errors.CodeSessionNotFound))
+		return
+	}
+	if err != nil {
+		w.RespondWithError(errors.NewInternalServerError("Failed to get conversation", err))
+		return
+	}
+
+	log.V(1).Info("Listing conversation runs from Autogen", "sessionID", session.ID)
+	runs, err := h.AutogenClient.ListSessionRuns(session.ID, userID)
+	if err != nil {
+		w.RespondWithError(errors.NewInternalServerError("Failed to list conversation runs", err))
+		return
+	}
+
+	var messages []*autogen_client.RunMessage
+	for _, run := range runs {
+		messages = append(messages, run.Messages...)
+	}
+
+	params := pageParams
+	if !params.Enabled {
+		params.Limit = len(messages)
+	}
+	page, nextCursor := paginate(messages, messageCursor, params)
+
+	configs := make([]autogen_client.TaskMessageMap, 0, len(page))
+	for _, message := range page {
+		configs = append(configs, messageConfig(message))
+	}
+
+	log.Info("Successfully listed conversation messages", "count", len(configs))
+	response := NewResponse(configs, "Successfully listed conversation messages")
+	response.NextCursor = nextCursor
+	RespondWithJSON(w, http.StatusOK, response)
+}
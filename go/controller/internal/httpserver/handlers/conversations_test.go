@@ -0,0 +1,87 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	autogen_client "github.com/kagent-dev/kagent/go/autogen/client"
+	common "github.com/kagent-dev/kagent/go/controller/internal/utils"
+)
+
+func TestHandleListConversationMessages(t *testing.T) {
+	handler, _ := setupTestHandler()
+	conversations := NewConversationsHandler(handler.Base)
+
+	session, err := handler.AutogenClient.CreateSession(&autogen_client.CreateSession{Name: "ctx-1", UserID: common.GetGlobalUserID()})
+	require.NoError(t, err)
+	_, err = handler.AutogenClient.CreateSession(&autogen_client.CreateSession{Name: "user-session", UserID: "alice"})
+	require.NoError(t, err)
+	_, err = handler.AutogenClient.CreateRun(&autogen_client.CreateRunRequest{SessionID: session.ID, UserID: common.GetGlobalUserID()})
+	require.NoError(t, err)
+	runs, err := handler.AutogenClient.ListSessionRuns(session.ID, common.GetGlobalUserID())
+	require.NoError(t, err)
+	require.Len(t, runs, 1)
+
+	createdAt := func(s string) *string { return &s }
+	runs[0].Messages = []*autogen_client.RunMessage{
+		{ID: 1, CreatedAt: createdAt("2025-01-01T00:00:01"), Config: map[string]interface{}{"content": "first"}},
+		{ID: 2, CreatedAt: createdAt("2025-01-01T00:00:02"), Config: map[string]interface{}{"content": "second"}},
+		{ID: 3, CreatedAt: createdAt("2025-01-01T00:00:03"), Config: map[string]interface{}{"content": "third"}},
+	}
+
+	list := func(contextID, query string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", "/api/a2a/conversations/"+contextID+"/messages?"+query, nil)
+		req = mux.SetURLVars(req, map[string]string{"contextID": contextID})
+		w := httptest.NewRecorder()
+		conversations.HandleListConversationMessages(&testErrorResponseWriter{w}, req)
+		return w
+	}
+	contents := func(w *httptest.ResponseRecorder) ([]string, string) {
+		var response struct {
+			Data       []map[string]interface{} `json:"data"`
+			NextCursor string                   `json:"next_cursor"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		var got []string
+		for _, message := range response.Data {
+			got = append(got, message["content"].(string))
+		}
+		return got, response.NextCursor
+	}
+
+	t.Run("lists messages newest first", func(t *testing.T) {
+		w := list("ctx-1", "")
+		require.Equal(t, http.StatusOK, w.Code)
+		got, next := contents(w)
+		assert.Equal(t, []string{"third", "second", "first"}, got)
+		assert.Empty(t, next)
+	})
+
+	t.Run("paginates", func(t *testing.T) {
+		w := list("ctx-1", "limit=2")
+		require.Equal(t, http.StatusOK, w.Code)
+		got, next := contents(w)
+		assert.Equal(t, []string{"third", "second"}, got)
+		require.NotEmpty(t, next)
+
+		w = list("ctx-1", "limit=2&cursor="+next)
+		require.Equal(t, http.StatusOK, w.Code)
+		got, next = contents(w)
+		assert.Equal(t, []string{"first"}, got)
+		assert.Empty(t, next)
+	})
+
+	t.Run("unknown context", func(t *testing.T) {
+		assert.Equal(t, http.StatusNotFound, list("missing", "").Code)
+	})
+
+	t.Run("sessions of other users are not conversations", func(t *testing.T) {
+		assert.Equal(t, http.StatusNotFound, list("user-session", "").Code)
+	})
+}
@@ -0,0 +1,109 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	autogen_client "github.com/kagent-dev/kagent/go/autogen/client"
+)
+
+// DeadlineExceededStopReason is the stop reason of the partial result returned when an
+// invocation runs past the duration requested with X-Max-Duration or the timeout parameter.
+// The result has the usual shape, with the messages produced before the deadline.
+const DeadlineExceededStopReason = "deadline_exceeded"
+
+// getMaxDuration reads the invocation time budget from the X-Max-Duration header or the
+// timeout query parameter. Both accept a Go duration such as "30s" or a number of seconds.
+// It returns 0 if neither is set.
+func getMaxDuration(r *http.Request) (time.Duration, error) {
+	value := r.Header.Get(autogen_client.MaxDurationHeader)
+	if value == "" {
+		value = r.URL.Query().Get("timeout")
+	}
+	if value == "" {
+		return 0, nil
+	}
+
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		seconds, convErr := strconv.ParseFloat(value, 64)
+		if convErr != nil {
+			return 0, fmt.Errorf("invalid max duration %q: must be a duration such as 30s or a number of seconds", value)
+		}
+		duration = time.Duration(seconds * float64(time.Second))
+	}
+	if duration <= 0 {
+		return 0, fmt.Errorf("invalid max duration %q: must be positive", value)
+	}
+	return duration, nil
+}
+
+// withMaxDuration derives the invocation context from the request, bounded by its max duration
+func withMaxDuration(r *http.Request) (context.Context, context.CancelFunc, time.Duration, error) {
+	maxDuration, err := getMaxDuration(r)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+	if maxDuration == 0 {
+		ctx, cancel := context.WithCancel(r.Context())
+		return ctx, cancel, 0, nil
+	}
+	ctx, cancel := context.WithTimeout(r.Context(), maxDuration)
+	return ctx, cancel, maxDuration, nil
+}
+
+// deadlineExceeded reports whether err was caused by the invocation context's deadline,
+// rather than the client going away
+func deadlineExceeded(ctx context.Context, err error) bool {
+	return err != nil && ctx.Err() == context.DeadlineExceeded
+}
+
+// latestRunID returns the highest run ID of the session, or 0 if it has none
+func latestRunID(autogenClient autogen_client.Client, sessionID int, userID string) int {
+	runs, err := autogenClient.ListSessionRuns(sessionID, userID)
+	if err != nil {
+		return 0
+	}
+	latest := 0
+	for _, run := range runs {
+		if run.ID > latest {
+			latest = run.ID
+		}
+	}
+	return latest
+}
+
+// partialSessionResult builds the partial result of a session invocation from the messages
+// stored so far for the newest run after previousRunID
+func partialSessionResult(autogenClient autogen_client.Client, sessionID int, userID string, previousRunID int, elapsed time.Duration) *autogen_client.TeamResult {
+	messages := []json.RawMessage{}
+
+	runs, err := autogenClient.ListSessionRuns(sessionID, userID)
+	if err == nil {
+		var latest *autogen_client.Run
+		for _, run := range runs {
+			if run.ID > previousRunID && (latest == nil || run.ID > latest.ID) {
+				latest = run
+			}
+		}
+		if latest != nil {
+			for _, message := range latest.Messages {
+				if raw, err := json.Marshal(message.Config); err == nil {
+					messages = append(messages, raw)
+				}
+			}
+		}
+	}
+
+	return &autogen_client.TeamResult{
+		TaskResult: autogen_client.TaskResult{
+			Messages:   messages,
+			StopReason: DeadlineExceededStopReason,
+		},
+		Duration: elapsed.Seconds(),
+	}
+}
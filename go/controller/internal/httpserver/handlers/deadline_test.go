@@ -0,0 +1,119 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kagent-dev/kagent/go/autogen/api"
+	autogen_client "github.com/kagent-dev/kagent/go/autogen/client"
+	autogen_fake "github.com/kagent-dev/kagent/go/autogen/client/fake"
+)
+
+func TestGetMaxDuration(t *testing.T) {
+	tests := []struct {
+		name     string
+		header   string
+		query    string
+		expected time.Duration
+		wantErr  bool
+	}{
+		{name: "unset"},
+		{name: "duration header", header: "1m30s", expected: 90 * time.Second},
+		{name: "seconds header", header: "2.5", expected: 2500 * time.Millisecond},
+		{name: "timeout param", query: "10s", expected: 10 * time.Second},
+		{name: "header takes precedence", header: "1s", query: "10s", expected: time.Second},
+		{name: "invalid", header: "soon", wantErr: true},
+		{name: "not positive", query: "0", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", "/invoke?timeout="+tt.query, nil)
+			if tt.header != "" {
+				req.Header.Set(autogen_client.MaxDurationHeader, tt.header)
+			}
+			duration, err := getMaxDuration(req)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, duration)
+		})
+	}
+}
+
+func TestSessionInvokeMaxDuration(t *testing.T) {
+	handler, userID := setupTestHandler()
+	sessions := NewSessionsHandler(handler.Base)
+	autogenClient := handler.AutogenClient.(*autogen_fake.InMemoryAutogenClient)
+	autogenClient.InvokeDelay = time.Minute
+
+	session, err := autogenClient.CreateSession(&autogen_client.CreateSession{Name: "session", UserID: userID})
+	require.NoError(t, err)
+
+	invoke := func(maxDuration string) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(&autogen_client.InvokeRequest{Task: "hello", TeamConfig: &api.Component{}})
+		req := httptest.NewRequest("POST", fmt.Sprintf("/api/sessions/%d/invoke?user_id=%s", session.ID, userID), bytes.NewBuffer(body))
+		req = mux.SetURLVars(req, map[string]string{"sessionID": fmt.Sprintf("%d", session.ID)})
+		req.Header.Set(autogen_client.MaxDurationHeader, maxDuration)
+		w := httptest.NewRecorder()
+		sessions.HandleSessionInvoke(&testErrorResponseWriter{w}, req)
+		return w
+	}
+
+	t.Run("returns a partial result when the deadline passes", func(t *testing.T) {
+		w := invoke("20ms")
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var result autogen_client.TeamResult
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+		assert.Equal(t, DeadlineExceededStopReason, result.TaskResult.StopReason)
+		assert.NotNil(t, result.TaskResult.Messages)
+	})
+
+	t.Run("invalid max duration", func(t *testing.T) {
+		w := invoke("-1s")
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestPartialSessionResult(t *testing.T) {
+	handler, userID := setupTestHandler()
+
+	session, err := handler.AutogenClient.CreateSession(&autogen_client.CreateSession{Name: "session", UserID: userID})
+	require.NoError(t, err)
+	for i := 0; i < 2; i++ {
+		_, err = handler.AutogenClient.CreateRun(&autogen_client.CreateRunRequest{SessionID: session.ID, UserID: userID})
+		require.NoError(t, err)
+	}
+
+	runs, err := handler.AutogenClient.ListSessionRuns(session.ID, userID)
+	require.NoError(t, err)
+	require.Len(t, runs, 2)
+	previous, current := runs[0], runs[1]
+	if previous.ID > current.ID {
+		previous, current = current, previous
+	}
+	previous.Messages = []*autogen_client.RunMessage{{Config: map[string]interface{}{"content": "old"}}}
+	current.Messages = []*autogen_client.RunMessage{{Config: map[string]interface{}{"content": "new"}}}
+
+	result := partialSessionResult(handler.AutogenClient, session.ID, userID, previous.ID, 2*time.Second)
+	assert.Equal(t, DeadlineExceededStopReason, result.TaskResult.StopReason)
+	assert.Equal(t, 2.0, result.Duration)
+	require.Len(t, result.TaskResult.Messages, 1)
+	assert.JSONEq(t, `{"content":"new"}`, string(result.TaskResult.Messages[0]))
+
+	// Without a run created by the invocation there is nothing to return
+	result = partialSessionResult(handler.AutogenClient, session.ID, userID, current.ID, time.Second)
+	assert.Empty(t, result.TaskResult.Messages)
+}
@@ -1,8 +1,8 @@
 package handlers
 
 import (
-	"encoding/json"
-	"io"
+	stderrors "errors"
+	"fmt"
 	"net/http"
 
 	"github.com/kagent-dev/kagent/go/autogen/client"
@@ -10,6 +10,27 @@ import (
 	ctrllog "sigs.k8s.io/controller-runtime/pkg/log"
 )
 
+// maxFeedbackBatchSize bounds the number of feedback submitted in one batch
+const maxFeedbackBatchSize = 100
+
+var feedbackIssueTypes = map[client.FeedbackIssueType]bool{
+	client.FeedbackIssueTypeInstructions: true,
+	client.FeedbackIssueTypeFactual:      true,
+	client.FeedbackIssueTypeIncomplete:   true,
+	client.FeedbackIssueTypeTool:         true,
+}
+
+// validateFeedback checks the fields of a feedback submission
+func validateFeedback(feedback *client.FeedbackSubmission) error {
+	if feedback.FeedbackText == "" {
+		return fmt.Errorf("Missing required field: feedbackText")
+	}
+	if feedback.IssueType != nil && !feedbackIssueTypes[*feedback.IssueType] {
+		return fmt.Errorf("Unknown issue type %q", *feedback.IssueType)
+	}
+	return nil
+}
+
 // FeedbackHandler handles user feedback submissions
 type FeedbackHandler struct {
 	*Base
@@ -26,26 +47,26 @@ func (h *FeedbackHandler) HandleCreateFeedback(w ErrorResponseWriter, r *http.Re
 
 	log.Info("Received feedback submission")
 
-	// Read request body
-	body, err := io.ReadAll(r.Body)
-	if err != nil {
-		log.Error(err, "Failed to read request body")
-		w.RespondWithError(errors.NewBadRequestError("Failed to read request body", err))
+	var feedbackReq client.FeedbackSubmission
+	if err := DecodeJSONBody(r, &feedbackReq); err != nil {
+		log.Error(err, "Failed to parse feedback data")
+		w.RespondWithError(invalidBodyError(err))
 		return
 	}
 
-	// Parse the feedback submission request
-	var feedbackReq client.FeedbackSubmission
-	if err := json.Unmarshal(body, &feedbackReq); err != nil {
-		log.Error(err, "Failed to parse feedback data")
-		w.RespondWithError(errors.NewBadRequestError("Invalid feedback data format", err))
+	// The feedback belongs to the user of the request, whatever user the body claims
+	userID, err := GetUserID(r)
+	if err != nil {
+		log.Error(err, "Failed to get user ID")
+		w.RespondWithError(errors.NewBadRequestError("Failed to get user ID", err))
 		return
 	}
+	feedbackReq.UserID = userID
 
 	// Validate the request
-	if feedbackReq.FeedbackText == "" {
-		log.Error(nil, "Missing required field: feedbackText")
-		w.RespondWithError(errors.NewBadRequestError("Missing required field: feedbackText", nil))
+	if err := validateFeedback(&feedbackReq); err != nil {
+		log.Error(err, "Invalid feedback")
+		w.RespondWithError(errors.NewBadRequestError(err.Error(), nil))
 		return
 	}
 
@@ -60,6 +81,76 @@ func (h *FeedbackHandler) HandleCreateFeedback(w ErrorResponseWriter, r *http.Re
 	RespondWithJSON(w, http.StatusOK, "Feedback submitted successfully")
 }
 
+// HandleCreateFeedbackBatch handles POST /api/feedback/batch requests. Each feedback of the
+// array is validated and submitted on its own, so invalid or failed feedback is reported in
+// its item of the result instead of failing the whole batch.
+func (h *FeedbackHandler) HandleCreateFeedbackBatch(w ErrorResponseWriter, r *http.Request) {
+	log := ctrllog.FromContext(r.Context()).WithName("feedback-handler").WithValues("operation", "create-feedback-batch")
+
+	var batch []*client.FeedbackSubmission
+	if err := DecodeJSONBody(r, &batch); err != nil {
+		log.Error(err, "Failed to parse feedback batch")
+		w.RespondWithError(invalidBodyError(err))
+		return
+	}
+	if len(batch) == 0 {
+		w.RespondWithError(errors.NewBadRequestError("The feedback batch is empty", nil))
+		return
+	}
+	if len(batch) > maxFeedbackBatchSize {
+		w.RespondWithError(errors.NewBadRequestError(fmt.Sprintf("A feedback batch can hold at most %d feedback", maxFeedbackBatchSize), nil))
+		return
+	}
+
+	userID, err := GetUserID(r)
+	if err != nil {
+		log.Error(err, "Failed to get user ID")
+		w.RespondWithError(errors.NewBadRequestError("Failed to get user ID", err))
+		return
+	}
+	log = log.WithValues("userID", userID, "count", len(batch))
+
+	// Only valid feedback is submitted, and indices maps it back to its position in the batch
+	itemErrors := make([]error, len(batch))
+	var valid []*client.FeedbackSubmission
+	var indices []int
+	for i, feedback := range batch {
+		if feedback == nil {
+			itemErrors[i] = fmt.Errorf("Feedback is null")
+			continue
+		}
+		feedback.UserID = userID
+		if err := validateFeedback(feedback); err != nil {
+			itemErrors[i] = err
+			continue
+		}
+		valid = append(valid, feedback)
+		indices = append(indices, i)
+	}
+
+	if len(valid) > 0 {
+		submitted, err := h.AutogenClient.CreateFeedbackBatch(r.Context(), valid)
+		if submitted == nil {
+			log.Error(err, "Failed to create feedback batch")
+			w.RespondWithError(errors.NewInternalServerError("Failed to create feedback batch", err))
+			return
+		}
+		for _, item := range submitted.Items {
+			if !item.Success {
+				itemErrors[indices[item.Index]] = stderrors.New(item.Error)
+			}
+		}
+	}
+
+	result := &client.FeedbackBatchResult{Items: make([]client.FeedbackBatchItem, 0, len(batch))}
+	for i, err := range itemErrors {
+		result.Add(i, err)
+	}
+
+	log.Info("Feedback batch submitted", "succeeded", result.Succeeded, "failed", result.Failed)
+	RespondWithJSON(w, http.StatusOK, NewResponse(result, fmt.Sprintf("Submitted %d of %d feedback", result.Succeeded, len(batch))))
+}
+
 func (h *FeedbackHandler) HandleListFeedback(w ErrorResponseWriter, r *http.Request) {
 	log := ctrllog.FromContext(r.Context()).WithName("feedback-handler").WithValues("operation", "list-feedback")
 
@@ -80,5 +171,5 @@ func (h *FeedbackHandler) HandleListFeedback(w ErrorResponseWriter, r *http.Requ
 	}
 
 	log.Info("Feedback listed successfully")
-	RespondWithJSON(w, http.StatusOK, feedback)
+	RespondWithJSON(w, http.StatusOK, NewResponse(feedback, "Successfully listed feedback"))
 }
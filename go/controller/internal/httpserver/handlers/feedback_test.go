@@ -0,0 +1,125 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	autogen_client "github.com/kagent-dev/kagent/go/autogen/client"
+	autogen_fake "github.com/kagent-dev/kagent/go/autogen/client/fake"
+)
+
+func TestHandleCreateFeedback(t *testing.T) {
+	handler, userID := setupTestHandler()
+	autogenClient := handler.AutogenClient.(*autogen_fake.InMemoryAutogenClient)
+	feedback := NewFeedbackHandler(handler.Base)
+	handle := func(w http.ResponseWriter, r *http.Request) {
+		feedback.HandleCreateFeedback(&testErrorResponseWriter{w}, r)
+	}
+	submit := func(h http.HandlerFunc, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("POST", "/api/feedback?user_id="+userID, strings.NewReader(body))
+		w := httptest.NewRecorder()
+		h(w, req)
+		return w
+	}
+
+	t.Run("stores the feedback for the user of the request", func(t *testing.T) {
+		w := submit(handle, `{"is_positive": true, "feedback_text": "great", "user_id": "someone-else"}`)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+		stored, err := autogenClient.ListFeedback(userID)
+		require.NoError(t, err)
+		require.Len(t, stored, 1)
+		assert.Equal(t, userID, stored[0].UserID)
+	})
+
+	t.Run("rejects unknown fields with strict JSON", func(t *testing.T) {
+		body := `{"is_positive": true, "feedback_txt": "typo"}`
+		w := submit(WithStrictJSON(handle), body)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), `Unknown field "feedback_txt"`)
+
+		// Without strict JSON the field is ignored, and the feedback fails validation instead
+		w = submit(handle, body)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "feedbackText")
+	})
+
+	t.Run("rejects invalid bodies", func(t *testing.T) {
+		w := submit(handle, `not json`)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestHandleCreateFeedbackBatch(t *testing.T) {
+	handler, userID := setupTestHandler()
+	autogenClient := handler.AutogenClient.(*autogen_fake.InMemoryAutogenClient)
+	feedback := NewFeedbackHandler(handler.Base)
+
+	submit := func(body string) (*httptest.ResponseRecorder, *autogen_client.FeedbackBatchResult) {
+		req := httptest.NewRequest("POST", "/api/feedback/batch?user_id="+userID, strings.NewReader(body))
+		w := httptest.NewRecorder()
+		feedback.HandleCreateFeedbackBatch(&testErrorResponseWriter{w}, req)
+
+		var response struct {
+			Data *autogen_client.FeedbackBatchResult `json:"data"`
+		}
+		_ = json.Unmarshal(w.Body.Bytes(), &response)
+		return w, response.Data
+	}
+
+	t.Run("reports each feedback", func(t *testing.T) {
+		w, result := submit(`[
+			{"is_positive": true, "feedback_text": "great", "message_id": 1, "user_id": "someone-else"},
+			{"is_positive": false, "feedback_text": ""},
+			{"is_positive": false, "feedback_text": "wrong", "issue_type": "rude"},
+			null,
+			{"is_positive": false, "feedback_text": "wrong", "issue_type": "factual", "message_id": 2}
+		]`)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		require.NotNil(t, result)
+		assert.Equal(t, 2, result.Succeeded)
+		assert.Equal(t, 3, result.Failed)
+		require.Len(t, result.Items, 5)
+		for i, item := range result.Items {
+			assert.Equal(t, i, item.Index)
+		}
+		assert.True(t, result.Items[0].Success)
+		assert.Contains(t, result.Items[1].Error, "feedbackText")
+		assert.Contains(t, result.Items[2].Error, "rude")
+		assert.False(t, result.Items[3].Success)
+		assert.True(t, result.Items[4].Success)
+
+		stored, err := autogenClient.ListFeedback(userID)
+		require.NoError(t, err)
+		require.Len(t, stored, 2, "only the valid feedback is stored")
+		for _, f := range stored {
+			assert.Equal(t, userID, f.UserID, "the feedback belongs to the user of the request")
+		}
+	})
+
+	t.Run("rejects empty batches", func(t *testing.T) {
+		w, _ := submit(`[]`)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("rejects batches that are too large", func(t *testing.T) {
+		items := make([]string, maxFeedbackBatchSize+1)
+		for i := range items {
+			items[i] = fmt.Sprintf(`{"feedback_text": "feedback %d"}`, i)
+		}
+		w, _ := submit("[" + strings.Join(items, ",") + "]")
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("rejects bodies that aren't arrays", func(t *testing.T) {
+		w, _ := submit(`{"feedback_text": "great"}`)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
@@ -1,6 +1,9 @@
 package handlers
 
 import (
+	"slices"
+	"sync"
+
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
@@ -9,18 +12,22 @@ import (
 
 // Handlers holds all the HTTP handler components
 type Handlers struct {
-	Health      *HealthHandler
-	ModelConfig *ModelConfigHandler
-	Model       *ModelHandler
-	Provider    *ProviderHandler
-	Sessions    *SessionsHandler
-	Teams       *TeamsHandler
-	Tools       *ToolsHandler
-	ToolServers *ToolServersHandler
-	Invoke      *InvokeHandler
-	Memory      *MemoryHandler
-	Feedback    *FeedbackHandler
-	Namespaces  *NamespacesHandler
+	Health        *HealthHandler
+	ModelConfig   *ModelConfigHandler
+	Model         *ModelHandler
+	Provider      *ProviderHandler
+	Sessions      *SessionsHandler
+	Runs          *RunsHandler
+	Teams         *TeamsHandler
+	Tools         *ToolsHandler
+	ToolServers   *ToolServersHandler
+	Invoke        *InvokeHandler
+	Memory        *MemoryHandler
+	Feedback      *FeedbackHandler
+	Namespaces    *NamespacesHandler
+	Quota         *QuotaHandler
+	Conversations *ConversationsHandler
+	Version       *VersionHandler
 }
 
 // Base holds common dependencies for all handlers
@@ -28,28 +35,60 @@ type Base struct {
 	KubeClient         client.Client
 	AutogenClient      autogen_client.Client
 	DefaultModelConfig types.NamespacedName
+	Quotas             QuotaConfig
+	// WatchedNamespaces are the namespaces the controller watches, empty if it watches all
+	WatchedNamespaces []string
+	// AllowedAutogenURLs are the alternate Autogen backends that invocations may select
+	// with the X-Autogen-URL header
+	AllowedAutogenURLs []string
+	// MessageRedactor redacts the messages sent to alternate backends if set. The messages sent
+	// to AutogenClient are redacted by NewHandlers.
+	MessageRedactor autogen_client.MessageRedactor
+	// AutogenClientOptions are the options AutogenClient was created with. The clients of
+	// alternate backends are created with them too.
+	AutogenClientOptions []autogen_client.Option
+
+	// newAutogenClient creates clients for alternate backends; nil means autogen_client.New
+	newAutogenClient func(baseURL string) autogen_client.Client
+	autogenClients   sync.Map
+}
+
+// isWatchedNamespace reports whether the controller watches namespace
+func (b *Base) isWatchedNamespace(namespace string) bool {
+	return len(b.WatchedNamespaces) == 0 || slices.Contains(b.WatchedNamespaces, namespace)
 }
 
 // NewHandlers creates a new Handlers instance with all handler components
-func NewHandlers(kubeClient client.Client, autogenClient autogen_client.Client, defaultModelConfig types.NamespacedName, watchedNamespaces []string) *Handlers {
+func NewHandlers(kubeClient client.Client, autogenClient autogen_client.Client, defaultModelConfig types.NamespacedName, watchedNamespaces []string, quotas QuotaConfig, allowedAutogenURLs []string, autogenClientOptions []autogen_client.Option, redactor autogen_client.MessageRedactor, features map[string]bool) *Handlers {
+	if redactor != nil {
+		autogenClient = autogen_client.NewRedactingClient(autogenClient, redactor)
+	}
 	base := &Base{
-		KubeClient:         kubeClient,
-		AutogenClient:      autogenClient,
-		DefaultModelConfig: defaultModelConfig,
+		KubeClient:           kubeClient,
+		AutogenClient:        autogenClient,
+		DefaultModelConfig:   defaultModelConfig,
+		Quotas:               quotas,
+		AllowedAutogenURLs:   allowedAutogenURLs,
+		AutogenClientOptions: autogenClientOptions,
+		MessageRedactor:      redactor,
 	}
 
 	return &Handlers{
-		Health:      NewHealthHandler(),
-		ModelConfig: NewModelConfigHandler(base),
-		Model:       NewModelHandler(base),
-		Provider:    NewProviderHandler(base),
-		Sessions:    NewSessionsHandler(base),
-		Teams:       NewTeamsHandler(base),
-		Tools:       NewToolsHandler(base),
-		ToolServers: NewToolServersHandler(base),
-		Invoke:      NewInvokeHandler(base),
-		Memory:      NewMemoryHandler(base),
-		Feedback:    NewFeedbackHandler(base),
-		Namespaces:  NewNamespacesHandler(base, watchedNamespaces),
+		Health:        NewHealthHandler(autogenClient),
+		ModelConfig:   NewModelConfigHandler(base),
+		Model:         NewModelHandler(base),
+		Provider:      NewProviderHandler(base),
+		Sessions:      NewSessionsHandler(base),
+		Runs:          NewRunsHandler(base),
+		Teams:         NewTeamsHandler(base),
+		Tools:         NewToolsHandler(base),
+		ToolServers:   NewToolServersHandler(base),
+		Invoke:        NewInvokeHandler(base),
+		Memory:        NewMemoryHandler(base),
+		Feedback:      NewFeedbackHandler(base),
+		Namespaces:    NewNamespacesHandler(base, watchedNamespaces),
+		Quota:         NewQuotaHandler(base),
+		Conversations: NewConversationsHandler(base),
+		Version:       NewVersionHandler(features),
 	}
 }
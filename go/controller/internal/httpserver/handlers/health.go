@@ -1,20 +1,40 @@
 package handlers
 
 import (
+	"context"
 	"net/http"
+	"time"
 
 	ctrllog "sigs.k8s.io/controller-runtime/pkg/log"
+
+	autogen_client "github.com/kagent-dev/kagent/go/autogen/client"
 )
 
+// defaultReadyCheckTimeout bounds each readiness check so a slow dependency can't hang the probe
+const defaultReadyCheckTimeout = 3 * time.Second
+
+// ReadyResponse reports the state of each dependency checked by HandleReady
+type ReadyResponse struct {
+	Ready  bool              `json:"ready"`
+	Checks map[string]string `json:"checks"`
+}
+
 // HealthHandler handles health check requests
-type HealthHandler struct{}
+type HealthHandler struct {
+	autogenClient     autogen_client.Client
+	readyCheckTimeout time.Duration
+}
 
 // NewHealthHandler creates a new HealthHandler
-func NewHealthHandler() *HealthHandler {
-	return &HealthHandler{}
+func NewHealthHandler(autogenClient autogen_client.Client) *HealthHandler {
+	return &HealthHandler{
+		autogenClient:     autogenClient,
+		readyCheckTimeout: defaultReadyCheckTimeout,
+	}
 }
 
-// HandleHealth handles GET /health requests
+// HandleHealth handles GET /health requests. It is a liveness check and doesn't look
+// at dependencies.
 func (h *HealthHandler) HandleHealth(w http.ResponseWriter, r *http.Request) {
 	log := ctrllog.FromContext(r.Context()).WithName("health-handler")
 	log.V(1).Info("Handling health check request")
@@ -22,3 +42,30 @@ func (h *HealthHandler) HandleHealth(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusOK)
 	w.Write([]byte("OK"))
 }
+
+// HandleReady handles GET /ready requests. It responds 503 naming the dependencies that
+// are down if any check fails.
+func (h *HealthHandler) HandleReady(w http.ResponseWriter, r *http.Request) {
+	log := ctrllog.FromContext(r.Context()).WithName("health-handler").WithValues("operation", "ready")
+
+	response := ReadyResponse{
+		Ready:  true,
+		Checks: map[string]string{},
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), h.readyCheckTimeout)
+	defer cancel()
+	if _, err := h.autogenClient.GetVersion(ctx); err != nil {
+		log.Info("Autogen is not reachable", "error", err.Error())
+		response.Ready = false
+		response.Checks["autogen"] = err.Error()
+	} else {
+		response.Checks["autogen"] = "ok"
+	}
+
+	status := http.StatusOK
+	if !response.Ready {
+		status = http.StatusServiceUnavailable
+	}
+	RespondWithJSON(w, status, response)
+}
@@ -0,0 +1,42 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	autogen_fake "github.com/kagent-dev/kagent/go/autogen/client/fake"
+)
+
+func TestHandleReady(t *testing.T) {
+	ready := func(handler *HealthHandler) (int, ReadyResponse) {
+		w := httptest.NewRecorder()
+		handler.HandleReady(w, httptest.NewRequest("GET", "/ready", nil))
+
+		var response ReadyResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		return w.Code, response
+	}
+
+	t.Run("ready when autogen is reachable", func(t *testing.T) {
+		code, response := ready(NewHealthHandler(autogen_fake.NewInMemoryAutogenClient()))
+		assert.Equal(t, http.StatusOK, code)
+		assert.True(t, response.Ready)
+		assert.Equal(t, "ok", response.Checks["autogen"])
+	})
+
+	t.Run("503 naming autogen when it is down", func(t *testing.T) {
+		autogenClient := autogen_fake.NewInMemoryAutogenClient()
+		autogenClient.VersionError = fmt.Errorf("connection refused")
+
+		code, response := ready(NewHealthHandler(autogenClient))
+		assert.Equal(t, http.StatusServiceUnavailable, code)
+		assert.False(t, response.Ready)
+		assert.Contains(t, response.Checks["autogen"], "connection refused")
+	})
+}
@@ -14,6 +14,8 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	ctrllog "sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/kagent-dev/kagent/go/controller/internal/httpserver/errors"
 )
 
 type ErrorResponseWriter interface {
@@ -32,23 +34,52 @@ func RespondWithJSON(w http.ResponseWriter, code int, payload interface{}) {
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(code)
-	w.Write(response)
-
+	RespondWithContent(w, code, "application/json", response)
 	log.V(2).Info("Sent JSON response", "statusCode", code, "responseSize", len(response))
 }
 
+// RespondWithContent writes body with the status and content type, which must be set
+// together since headers can't change once the status is written
+func RespondWithContent(w http.ResponseWriter, code int, contentType string, body []byte) {
+	w.Header().Set("Content-Type", contentType)
+	w.WriteHeader(code)
+	if _, err := w.Write(body); err != nil {
+		ctrllog.Log.WithName("http-helpers").V(1).Info("Failed to write response", "error", err.Error())
+	}
+}
+
+// RespondWithError writes an error response with the code of the status, like the errors
+// returned through ErrorResponseWriter
 func RespondWithError(w http.ResponseWriter, code int, message string) {
 	log := ctrllog.Log.WithName("http-helpers")
 	log.Info("Responding with error", "statusCode", code, "message", message)
 
-	RespondWithJSON(w, code, map[string]string{"error": message})
+	RespondWithJSON(w, code, map[string]string{"error": message, "code": string(errors.CodeForStatus(code))})
 }
 
+// startStreamResponse writes the headers of a streamed response of the content type and
+// flushes them, so clients see the stream start before the first event
+func startStreamResponse(w ErrorResponseWriter, contentType string) {
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	w.Flush()
+}
+
+// GetUserID returns the user ID of the request. It is read from the user_id query parameter
+// unless the server resolves it with a UserIDSource.
 func GetUserID(r *http.Request) (string, error) {
 	log := ctrllog.Log.WithName("http-helpers")
 
+	if resolved, ok := r.Context().Value(userIDKey{}).(resolvedUserID); ok {
+		if resolved.err != nil {
+			log.Info("Failed to resolve user ID", "error", resolved.err.Error())
+			return "", resolved.err
+		}
+		log.V(2).Info("Retrieved user ID from request", "userID", resolved.userID)
+		return resolved.userID, nil
+	}
+
 	userID := r.URL.Query().Get("user_id")
 	if userID == "" {
 		log.Info("Missing user_id parameter in request")
@@ -59,7 +90,8 @@ func GetUserID(r *http.Request) (string, error) {
 	return userID, nil
 }
 
-// GetPathParam gets a path parameter from the request
+// GetPathParam gets a path parameter from the request. Values are validated by name with
+// pathParamValidators, e.g. namespaces must be valid DNS labels.
 func GetPathParam(r *http.Request, name string) (string, error) {
 	log := ctrllog.Log.WithName("http-helpers")
 
@@ -69,6 +101,10 @@ func GetPathParam(r *http.Request, name string) (string, error) {
 		log.Info("Missing required path parameter", "paramName", name)
 		return "", fmt.Errorf("%s is required", name)
 	}
+	if err := validatePathParam(name, value); err != nil {
+		log.Info("Invalid path parameter", "paramName", name)
+		return "", err
+	}
 
 	log.V(2).Info("Retrieved path parameter", "paramName", name, "value", value)
 	return value, nil
@@ -88,16 +124,27 @@ func GetIntPathParam(r *http.Request, name string) (int, error) {
 		log.Info("Invalid integer path parameter", "paramName", name, "value", strValue)
 		return 0, fmt.Errorf("invalid %s: must be an integer", name)
 	}
+	if intValue <= 0 {
+		log.Info("Invalid integer path parameter", "paramName", name, "value", intValue)
+		return 0, fmt.Errorf("invalid %s: must be a positive integer", name)
+	}
 
 	log.V(2).Info("Retrieved integer path parameter", "paramName", name, "value", intValue)
 	return intValue, nil
 }
 
-// DecodeJSONBody decodes a JSON request body into the provided struct
+// DecodeJSONBody decodes a JSON request body into the provided struct. Bodies larger than the
+// limit set for the route with WithMaxBodyBytes, or DefaultMaxBodyBytes, fail with an *http.MaxBytesError.
+// Routes wrapped with WithStrictJSON also reject unknown fields.
 func DecodeJSONBody(r *http.Request, target interface{}) error {
 	log := ctrllog.Log.WithName("http-helpers")
 
-	if err := json.NewDecoder(r.Body).Decode(target); err != nil {
+	r.Body = http.MaxBytesReader(nil, r.Body, maxBodyBytes(r))
+	decoder := json.NewDecoder(r.Body)
+	if isStrictJSON(r) {
+		decoder.DisallowUnknownFields()
+	}
+	if err := decoder.Decode(target); err != nil {
 		log.Info("Failed to decode JSON request body", "error", err.Error())
 		return err
 	}
@@ -1,9 +1,11 @@
 package handlers
 
 import (
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/go-logr/logr"
 	autogen_client "github.com/kagent-dev/kagent/go/autogen/client"
@@ -55,17 +57,45 @@ func (h *InvokeHandler) HandleInvokeAgent(w ErrorResponseWriter, r *http.Request
 		return
 	}
 
+	// Teams are reconciled into the default backend, so only the invocation itself goes to
+	// an alternate backend
+	invokeClient, _, err := h.autogenClientFor(r)
+	if err != nil {
+		w.RespondWithError(errors.NewBadRequestError("Invalid Autogen URL", err))
+		return
+	}
+
 	team, err := h.AutogenClient.GetTeamByID(agentID, req.UserID)
 	if err != nil {
 		w.RespondWithError(errors.NewInternalServerError("Failed to get team", err))
 		return
 	}
 
-	result, err := h.AutogenClient.InvokeTask(&autogen_client.InvokeTaskRequest{
+	ctx, cancel, maxDuration, err := withMaxDuration(r)
+	if err != nil {
+		w.RespondWithError(errors.NewBadRequestError("Invalid max duration", err))
+		return
+	}
+	defer cancel()
+
+	start := time.Now()
+	result, err := invokeClient.InvokeTask(ctx, &autogen_client.InvokeTaskRequest{
 		Task:       req.Message,
 		TeamConfig: team.Component,
 	})
 	if err != nil {
+		if deadlineExceeded(ctx, err) {
+			// Task invocations aren't stored, so there are no messages to return
+			log.Info("Agent invocation exceeded its max duration, returning partial result", "maxDuration", maxDuration)
+			RespondWithJSON(w, http.StatusOK, &autogen_client.InvokeTaskResult{
+				Duration: time.Since(start).Seconds(),
+				TaskResult: autogen_client.TaskResult{
+					Messages:   []json.RawMessage{},
+					StopReason: DeadlineExceededStopReason,
+				},
+			})
+			return
+		}
 		w.RespondWithError(errors.NewInternalServerError("Failed to invoke task", err))
 		return
 	}
@@ -86,13 +116,19 @@ func (h *InvokeHandler) HandleInvokeAgentStream(w ErrorResponseWriter, r *http.R
 		return
 	}
 
+	invokeClient, _, err := h.autogenClientFor(r)
+	if err != nil {
+		w.RespondWithError(errors.NewBadRequestError("Invalid Autogen URL", err))
+		return
+	}
+
 	team, err := h.AutogenClient.GetTeamByID(agentID, req.UserID)
 	if err != nil {
 		w.RespondWithError(errors.NewInternalServerError("Failed to get team", err))
 		return
 	}
 
-	ch, err := h.AutogenClient.InvokeTaskStream(&autogen_client.InvokeTaskRequest{
+	ch, err := invokeClient.InvokeTaskStream(r.Context(), &autogen_client.InvokeTaskRequest{
 		Task:       req.Message,
 		TeamConfig: team.Component,
 	})
@@ -104,9 +140,7 @@ func (h *InvokeHandler) HandleInvokeAgentStream(w ErrorResponseWriter, r *http.R
 	log.Info("Asynchronous request - streaming response")
 
 	log.Info("Successfully invoked agent")
-	w.Header().Set("Content-Type", "text/event-stream")
-	w.WriteHeader(http.StatusOK)
-	w.Flush()
+	startStreamResponse(w, "text/event-stream")
 
 	for event := range ch {
 		w.Write([]byte(fmt.Sprintf("event: %s\ndata: %s\n\n", event.Event, event.Data)))
@@ -131,18 +165,21 @@ func (h *InvokeHandler) extractAgentParams(w ErrorResponseWriter, r *http.Reques
 
 	var invokeRequest InvokeRequest
 	if err = DecodeJSONBody(r, &invokeRequest); err != nil {
-		w.RespondWithError(errors.NewBadRequestError("Invalid request body", err))
+		w.RespondWithError(invalidBodyError(err))
 		return 0, nil, err
 	}
 
-	userID := invokeRequest.UserID
-	if userID == "" {
-		userID, err = GetUserID(r)
-		if err != nil {
-			w.RespondWithError(errors.NewBadRequestError("Failed to get user ID", err))
-			return 0, nil, err
-		}
+	// The invocation runs as the user the invoke limits are counted for, so the user must be
+	// set on the request rather than only in the body
+	userID, err := GetUserID(r)
+	if err == nil {
+		userID, err = RequestUserID(r, invokeRequest.UserID)
+	}
+	if err != nil {
+		w.RespondWithError(errors.NewBadRequestError("Failed to get user ID", err))
+		return 0, nil, err
 	}
+	invokeRequest.UserID = userID
 	log.WithValues("userID", userID)
 
 	return agentID, &invokeRequest, nil
@@ -3,6 +3,7 @@ package handlers_test
 import (
 	"bytes"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -54,7 +55,7 @@ func TestInvokeHandler(t *testing.T) {
 			UserID:  "test-user",
 		}
 		jsonBody, _ := json.Marshal(reqBody)
-		req := httptest.NewRequest("POST", "/api/agents/"+agentID+"/invoke", bytes.NewBuffer(jsonBody))
+		req := httptest.NewRequest("POST", "/api/agents/"+agentID+"/invoke?user_id=test-user", bytes.NewBuffer(jsonBody))
 		req.Header.Set("Content-Type", "application/json")
 
 		router := mux.NewRouter()
@@ -85,7 +86,7 @@ func TestInvokeHandler(t *testing.T) {
 			UserID:  "test-user",
 		}
 		jsonBody, _ := json.Marshal(reqBody)
-		req := httptest.NewRequest("POST", "/api/agents/"+agentID+"/invoke", bytes.NewBuffer(jsonBody))
+		req := httptest.NewRequest("POST", "/api/agents/"+agentID+"/invoke?user_id=test-user", bytes.NewBuffer(jsonBody))
 		req.Header.Set("Content-Type", "application/json")
 
 		router := mux.NewRouter()
@@ -99,6 +100,37 @@ func TestInvokeHandler(t *testing.T) {
 		assert.NotNil(t, responseRecorder.errorReceived)
 	})
 
+	t.Run("InvokeError", func(t *testing.T) {
+		handler, mockClient, responseRecorder := setupHandler()
+
+		err := mockClient.CreateTeam(&autogen_client.Team{
+			BaseObject: autogen_client.BaseObject{Id: 1},
+			Component:  &api.Component{Label: "test-team"},
+		})
+		require.NoError(t, err)
+		invokeErr := fmt.Errorf("model provider unavailable")
+		mockClient.SetInvokeResponse("Test message", &fake.InvokeResponse{Err: invokeErr})
+
+		reqBody := handlers.InvokeRequest{
+			Message: "Test message",
+			UserID:  "test-user",
+		}
+		jsonBody, _ := json.Marshal(reqBody)
+		req := httptest.NewRequest("POST", "/api/agents/1/invoke?user_id=test-user", bytes.NewBuffer(jsonBody))
+		req.Header.Set("Content-Type", "application/json")
+
+		router := mux.NewRouter()
+		router.HandleFunc("/api/agents/{agentId}/invoke", func(w http.ResponseWriter, r *http.Request) {
+			handler.HandleInvokeAgent(responseRecorder, r)
+		}).Methods("POST")
+
+		router.ServeHTTP(responseRecorder, req)
+
+		assert.Equal(t, http.StatusInternalServerError, responseRecorder.Code)
+		require.NotNil(t, responseRecorder.errorReceived)
+		assert.ErrorIs(t, responseRecorder.errorReceived, invokeErr)
+	})
+
 	t.Run("InvalidAgentIdParameter", func(t *testing.T) {
 		handler, _, responseRecorder := setupHandler()
 
@@ -121,3 +153,31 @@ func TestInvokeHandler(t *testing.T) {
 		assert.NotNil(t, responseRecorder.errorReceived)
 	})
 }
+
+func TestInvokeHandlerRejectsImpersonation(t *testing.T) {
+	mockClient := fake.NewMockAutogenClient()
+	require.NoError(t, mockClient.CreateTeam(&autogen_client.Team{
+		BaseObject: autogen_client.BaseObject{Id: 1, UserID: "alice"},
+		Component:  &api.Component{Label: "test-team"},
+	}))
+	handler := handlers.NewInvokeHandler(&handlers.Base{})
+	handler.WithClient(mockClient)
+
+	source := handlers.UserIDSource{Mode: handlers.UserIDFromHeader}
+	require.NoError(t, source.Validate())
+	router := mux.NewRouter()
+	router.Use(source.Middleware)
+
+	responseRecorder := newMockErrorResponseWriter()
+	router.HandleFunc("/api/agents/{agentId}/invoke", func(w http.ResponseWriter, r *http.Request) {
+		handler.HandleInvokeAgent(responseRecorder, r)
+	}).Methods("POST")
+
+	jsonBody, _ := json.Marshal(handlers.InvokeRequest{Message: "Test message", UserID: "mallory"})
+	req := httptest.NewRequest("POST", "/api/agents/1/invoke", bytes.NewBuffer(jsonBody))
+	req.Header.Set(handlers.DefaultUserIDHeader, "alice")
+	router.ServeHTTP(responseRecorder, req)
+
+	assert.Equal(t, http.StatusBadRequest, responseRecorder.Code)
+	assert.Contains(t, responseRecorder.Body.String(), "doesn't match")
+}
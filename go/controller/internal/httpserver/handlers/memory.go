@@ -1,7 +1,6 @@
 package handlers
 
 import (
-	"encoding/json"
 	"fmt"
 	"net/http"
 	"strings"
@@ -64,7 +63,7 @@ func (h *MemoryHandler) HandleListMemories(w ErrorResponseWriter, r *http.Reques
 	}
 
 	log.Info("Successfully listed Memories", "count", len(memoryResponses))
-	RespondWithJSON(w, http.StatusOK, memoryResponses)
+	RespondWithJSON(w, http.StatusOK, NewResponse(memoryResponses, "Successfully listed memories"))
 }
 
 type CreateMemoryRequest struct {
@@ -80,9 +79,9 @@ func (h *MemoryHandler) HandleCreateMemory(w ErrorResponseWriter, r *http.Reques
 	log.Info("Received request to create Memory")
 
 	var req CreateMemoryRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+	if err := DecodeJSONBody(r, &req); err != nil {
 		log.Error(err, "Failed to decode request body")
-		w.RespondWithError(errors.NewBadRequestError("Invalid request body", err))
+		w.RespondWithError(invalidBodyError(err))
 		return
 	}
 
@@ -317,9 +316,9 @@ func (h *MemoryHandler) HandleUpdateMemory(w ErrorResponseWriter, r *http.Reques
 	)
 
 	var req UpdateMemoryRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+	if err := DecodeJSONBody(r, &req); err != nil {
 		log.Error(err, "Failed to decode request body")
-		w.RespondWithError(errors.NewBadRequestError("Invalid request body", err))
+		w.RespondWithError(invalidBodyError(err))
 		return
 	}
 
@@ -74,7 +74,7 @@ func TestMemoryHandler(t *testing.T) {
 			assert.Equal(t, http.StatusOK, responseRecorder.Code)
 
 			var memories []handlers.MemoryResponse
-			err = json.Unmarshal(responseRecorder.Body.Bytes(), &memories)
+			err = handlers.DecodeResponseData(responseRecorder.Body.Bytes(), &memories)
 			require.NoError(t, err)
 			assert.Len(t, memories, 1)
 
@@ -95,7 +95,7 @@ func TestMemoryHandler(t *testing.T) {
 			assert.Equal(t, http.StatusOK, responseRecorder.Code)
 
 			var memories []handlers.MemoryResponse
-			err := json.Unmarshal(responseRecorder.Body.Bytes(), &memories)
+			err := handlers.DecodeResponseData(responseRecorder.Body.Bytes(), &memories)
 			require.NoError(t, err)
 			assert.Len(t, memories, 0)
 		})
@@ -0,0 +1,103 @@
+package handlers
+
+import (
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/kagent-dev/kagent/go/autogen/api"
+	autogen_client "github.com/kagent-dev/kagent/go/autogen/client"
+)
+
+// modelProviderTTL is how long the result of validating a model client provider is cached
+const modelProviderTTL = 5 * time.Minute
+
+// modelProviderResult is the cached result of validating a model client provider with Autogen
+type modelProviderResult struct {
+	// unsupported is the error Autogen reported for the provider, empty if it is supported
+	unsupported string
+	checkedAt   time.Time
+}
+
+// modelProviderCache caches whether Autogen supports the providers of model client components,
+// so that creating an agent doesn't validate the same provider each time
+type modelProviderCache struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	results map[string]modelProviderResult
+}
+
+func newModelProviderCache() *modelProviderCache {
+	return &modelProviderCache{ttl: modelProviderTTL, results: map[string]modelProviderResult{}}
+}
+
+// check validates the model client component with Autogen unless its provider has a cached
+// result. It returns the error Autogen reported for the provider, or "" if it is supported.
+// Errors about other fields are left to the validation of the whole team.
+func (c *modelProviderCache) check(autogenClient autogen_client.Client, component *api.Component) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if result, ok := c.results[component.Provider]; ok && time.Since(result.checkedAt) < c.ttl {
+		return result.unsupported, nil
+	}
+
+	resp, err := autogenClient.Validate(&autogen_client.ValidationRequest{Component: component})
+	if err != nil {
+		return "", err
+	}
+	result := modelProviderResult{checkedAt: time.Now()}
+	for _, validationErr := range resp.Errors {
+		if validationErr != nil && validationErr.Field == "provider" {
+			result.unsupported = validationErr.Error
+			break
+		}
+	}
+	c.results[component.Provider] = result
+	return result.unsupported, nil
+}
+
+// modelClientComponents returns the distinct model client components nested in the config of
+// component, one per provider
+func modelClientComponents(component *api.Component) []*api.Component {
+	if component == nil {
+		return nil
+	}
+	var components []*api.Component
+	seen := map[string]bool{}
+	var walk func(value interface{})
+	walk = func(value interface{}) {
+		switch v := value.(type) {
+		case map[string]interface{}:
+			if v["component_type"] == "model" {
+				if provider, ok := v["provider"].(string); ok && !seen[provider] {
+					if modelClient, err := componentFromMap(v); err == nil {
+						seen[provider] = true
+						components = append(components, modelClient)
+					}
+				}
+			}
+			for _, nested := range v {
+				walk(nested)
+			}
+		case []interface{}:
+			for _, nested := range v {
+				walk(nested)
+			}
+		}
+	}
+	walk(component.Config)
+	return components
+}
+
+func componentFromMap(m map[string]interface{}) (*api.Component, error) {
+	byt, err := json.Marshal(m)
+	if err != nil {
+		return nil, err
+	}
+	component := &api.Component{}
+	if err := json.Unmarshal(byt, component); err != nil {
+		return nil, err
+	}
+	return component, nil
+}
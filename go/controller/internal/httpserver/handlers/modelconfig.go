@@ -1,10 +1,8 @@
 package handlers
 
 import (
-	"encoding/json"
 	"fmt"
 	"net/http"
-	"reflect"
 	"strings"
 
 	"github.com/kagent-dev/kagent/go/controller/api/v1alpha1"
@@ -76,7 +74,7 @@ func (h *ModelConfigHandler) HandleListModelConfigs(w ErrorResponseWriter, r *ht
 	}
 
 	log.Info("Successfully listed ModelConfigs", "count", len(configs))
-	RespondWithJSON(w, http.StatusOK, configs)
+	RespondWithJSON(w, http.StatusOK, NewResponse(configs, "Successfully listed model configs"))
 }
 
 // HandleGetModelConfig handles GET /api/modelconfigs/{namespace}/{configName} requests
@@ -151,23 +149,6 @@ func (h *ModelConfigHandler) HandleGetModelConfig(w ErrorResponseWriter, r *http
 	RespondWithJSON(w, http.StatusOK, responseItem)
 }
 
-// Helper function to get all JSON keys from a struct type
-func getStructJSONKeys(structType reflect.Type) []string {
-	keys := []string{}
-	if structType.Kind() != reflect.Struct {
-		return keys
-	}
-	for i := 0; i < structType.NumField(); i++ {
-		field := structType.Field(i)
-		jsonTag := field.Tag.Get("json")
-		if jsonTag != "" && jsonTag != "-" {
-			tagParts := strings.Split(jsonTag, ",")
-			keys = append(keys, tagParts[0])
-		}
-	}
-	return keys
-}
-
 type CreateModelConfigRequest struct {
 	Ref             string                      `json:"ref"`
 	Provider        Provider                    `json:"provider"`
@@ -190,9 +171,9 @@ func (h *ModelConfigHandler) HandleCreateModelConfig(w ErrorResponseWriter, r *h
 	log.Info("Received request to create ModelConfig")
 
 	var req CreateModelConfigRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+	if err := DecodeJSONBody(r, &req); err != nil {
 		log.Error(err, "Failed to decode request body")
-		w.RespondWithError(errors.NewBadRequestError("Invalid request body", err))
+		w.RespondWithError(invalidBodyError(err))
 		return
 	}
 
@@ -372,9 +353,9 @@ func (h *ModelConfigHandler) HandleUpdateModelConfig(w ErrorResponseWriter, r *h
 
 	var req UpdateModelConfigRequest
 
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+	if err := DecodeJSONBody(r, &req); err != nil {
 		log.Error(err, "Failed to decode request body")
-		w.RespondWithError(errors.NewBadRequestError("Invalid request body", err))
+		w.RespondWithError(invalidBodyError(err))
 		return
 	}
 
@@ -73,7 +73,7 @@ func TestModelConfigHandler(t *testing.T) {
 			assert.Equal(t, http.StatusOK, responseRecorder.Code)
 
 			var configs []handlers.ModelConfigResponse
-			err = json.Unmarshal(responseRecorder.Body.Bytes(), &configs)
+			err = handlers.DecodeResponseData(responseRecorder.Body.Bytes(), &configs)
 			require.NoError(t, err)
 			assert.Len(t, configs, 1)
 
@@ -96,7 +96,7 @@ func TestModelConfigHandler(t *testing.T) {
 			assert.Equal(t, http.StatusOK, responseRecorder.Code)
 
 			var configs []handlers.ModelConfigResponse
-			err := json.Unmarshal(responseRecorder.Body.Bytes(), &configs)
+			err := handlers.DecodeResponseData(responseRecorder.Body.Bytes(), &configs)
 			require.NoError(t, err)
 			assert.Len(t, configs, 0)
 		})
@@ -28,5 +28,5 @@ func (h *ModelHandler) HandleListSupportedModels(w ErrorResponseWriter, r *http.
 		return
 	}
 
-	RespondWithJSON(w, http.StatusOK, models)
+	RespondWithJSON(w, http.StatusOK, NewResponse(models, "Successfully listed supported models"))
 }
@@ -52,7 +52,7 @@ func (h *NamespacesHandler) HandleListNamespaces(w ErrorResponseWriter, r *http.
 			})
 		}
 
-		RespondWithJSON(w, http.StatusOK, namespaces)
+		RespondWithJSON(w, http.StatusOK, NewResponse(namespaces, "Successfully listed namespaces"))
 		return
 	}
 
@@ -77,5 +77,5 @@ func (h *NamespacesHandler) HandleListNamespaces(w ErrorResponseWriter, r *http.
 		})
 	}
 
-	RespondWithJSON(w, http.StatusOK, namespaces)
+	RespondWithJSON(w, http.StatusOK, NewResponse(namespaces, "Successfully listed namespaces"))
 }
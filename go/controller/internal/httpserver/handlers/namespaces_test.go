@@ -2,7 +2,6 @@ package handlers_test
 
 import (
 	"context"
-	"encoding/json"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -73,7 +72,7 @@ func TestNamespacesHandler(t *testing.T) {
 			assert.Equal(t, http.StatusOK, responseRecorder.Code)
 
 			var responseNamespaces []handlers.NamespaceResponse
-			err = json.Unmarshal(responseRecorder.Body.Bytes(), &responseNamespaces)
+			err = handlers.DecodeResponseData(responseRecorder.Body.Bytes(), &responseNamespaces)
 			require.NoError(t, err)
 
 			// Check that all namespaces are returned
@@ -117,7 +116,7 @@ func TestNamespacesHandler(t *testing.T) {
 			assert.Equal(t, http.StatusOK, responseRecorder.Code)
 
 			var responseNamespaces []handlers.NamespaceResponse
-			err = json.Unmarshal(responseRecorder.Body.Bytes(), &responseNamespaces)
+			err = handlers.DecodeResponseData(responseRecorder.Body.Bytes(), &responseNamespaces)
 			require.NoError(t, err)
 			assert.Len(t, responseNamespaces, 2)
 
@@ -154,7 +153,7 @@ func TestNamespacesHandler(t *testing.T) {
 			assert.Equal(t, http.StatusOK, responseRecorder.Code)
 
 			var responseNamespaces []handlers.NamespaceResponse
-			err = json.Unmarshal(responseRecorder.Body.Bytes(), &responseNamespaces)
+			err = handlers.DecodeResponseData(responseRecorder.Body.Bytes(), &responseNamespaces)
 			require.NoError(t, err)
 
 			// Check that only watched namespaces are returned
@@ -188,7 +187,7 @@ func TestNamespacesHandler(t *testing.T) {
 			assert.Equal(t, http.StatusOK, responseRecorder.Code)
 
 			var responseNamespaces []handlers.NamespaceResponse
-			err = json.Unmarshal(responseRecorder.Body.Bytes(), &responseNamespaces)
+			err = handlers.DecodeResponseData(responseRecorder.Body.Bytes(), &responseNamespaces)
 			require.NoError(t, err)
 
 			// Check that only existing watched namespaces were returned
@@ -222,7 +221,7 @@ func TestNamespacesHandler(t *testing.T) {
 			assert.Equal(t, http.StatusOK, responseRecorder.Code)
 
 			var responseNamespaces []handlers.NamespaceResponse
-			err = json.Unmarshal(responseRecorder.Body.Bytes(), &responseNamespaces)
+			err = handlers.DecodeResponseData(responseRecorder.Body.Bytes(), &responseNamespaces)
 			require.NoError(t, err)
 
 			// We should get an empty list because we are only watching non-existent namespaces
@@ -239,7 +238,7 @@ func TestNamespacesHandler(t *testing.T) {
 			assert.Equal(t, http.StatusOK, responseRecorder.Code)
 
 			var responseNamespaces []handlers.NamespaceResponse
-			err := json.Unmarshal(responseRecorder.Body.Bytes(), &responseNamespaces)
+			err := handlers.DecodeResponseData(responseRecorder.Body.Bytes(), &responseNamespaces)
 			require.NoError(t, err)
 			assert.Len(t, responseNamespaces, 0)
 		})
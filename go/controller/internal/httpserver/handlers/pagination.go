@@ -0,0 +1,113 @@
+package handlers
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+)
+
+const (
+	defaultPageLimit = 50
+	maxPageLimit     = 500
+)
+
+// pageCursor is the keyset position of the last item on a page.
+// Items are ordered newest first by (created_at, id).
+type pageCursor struct {
+	CreatedAt string `json:"created_at"`
+	ID        int    `json:"id"`
+}
+
+// pageParams holds the pagination parameters of a list request
+type pageParams struct {
+	// Enabled is true if the client asked for pagination with limit or cursor
+	Enabled bool
+	Limit   int
+	Cursor  *pageCursor
+}
+
+func encodeCursor(c pageCursor) string {
+	raw, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+func decodeCursor(s string) (*pageCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cursor")
+	}
+
+	var c pageCursor
+	if err := json.Unmarshal(raw, &c); err != nil {
+		return nil, fmt.Errorf("invalid cursor")
+	}
+	if c.ID <= 0 {
+		return nil, fmt.Errorf("invalid cursor")
+	}
+	return &c, nil
+}
+
+// getPageParams reads the limit and cursor query parameters
+func getPageParams(r *http.Request) (pageParams, error) {
+	query := r.URL.Query()
+	params := pageParams{Limit: defaultPageLimit}
+
+	if limitStr := query.Get("limit"); limitStr != "" {
+		limit, err := strconv.Atoi(limitStr)
+		if err != nil || limit <= 0 {
+			return params, fmt.Errorf("invalid limit: must be a positive integer")
+		}
+		if limit > maxPageLimit {
+			limit = maxPageLimit
+		}
+		params.Limit = limit
+		params.Enabled = true
+	}
+
+	if cursorStr := query.Get("cursor"); cursorStr != "" {
+		cursor, err := decodeCursor(cursorStr)
+		if err != nil {
+			return params, err
+		}
+		params.Cursor = cursor
+		params.Enabled = true
+	}
+
+	return params, nil
+}
+
+// before reports whether a sorts before b in newest-first order
+func (a pageCursor) before(b pageCursor) bool {
+	if a.CreatedAt != b.CreatedAt {
+		return a.CreatedAt > b.CreatedAt
+	}
+	return a.ID > b.ID
+}
+
+// paginate sorts items newest first and returns the page following the cursor,
+// together with the cursor of the next page
+func paginate[T any](items []T, key func(T) pageCursor, params pageParams) ([]T, string) {
+	sorted := make([]T, len(items))
+	copy(sorted, items)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return key(sorted[i]).before(key(sorted[j]))
+	})
+
+	start := 0
+	if params.Cursor != nil {
+		start = sort.Search(len(sorted), func(i int) bool {
+			return params.Cursor.before(key(sorted[i]))
+		})
+	}
+
+	end := start + params.Limit
+	if end >= len(sorted) {
+		return sorted[start:], ""
+	}
+
+	page := sorted[start:end]
+	return page, encodeCursor(key(page[len(page)-1]))
+}
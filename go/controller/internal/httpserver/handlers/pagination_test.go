@@ -0,0 +1,149 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	autogen_client "github.com/kagent-dev/kagent/go/autogen/client"
+	autogen_fake "github.com/kagent-dev/kagent/go/autogen/client/fake"
+)
+
+type testItem struct {
+	CreatedAt string
+	ID        int
+}
+
+func testItemCursor(item testItem) pageCursor {
+	return pageCursor{CreatedAt: item.CreatedAt, ID: item.ID}
+}
+
+func TestPaginate(t *testing.T) {
+	items := []testItem{
+		{CreatedAt: "2025-01-01T00:00:00", ID: 1},
+		{CreatedAt: "2025-01-03T00:00:00", ID: 3},
+		{CreatedAt: "2025-01-02T00:00:00", ID: 2},
+		{CreatedAt: "2025-01-02T00:00:00", ID: 4},
+	}
+
+	page, next := paginate(items, testItemCursor, pageParams{Limit: 2})
+	assert.Equal(t, []testItem{items[1], items[3]}, page)
+	require.NotEmpty(t, next)
+
+	cursor, err := decodeCursor(next)
+	require.NoError(t, err)
+
+	// Rows inserted ahead of the cursor do not shift the next page
+	items = append(items, testItem{CreatedAt: "2025-01-04T00:00:00", ID: 5})
+
+	page, next = paginate(items, testItemCursor, pageParams{Limit: 2, Cursor: cursor})
+	assert.Equal(t, []testItem{items[2], items[0]}, page)
+	assert.Empty(t, next)
+}
+
+func TestDecodeCursor(t *testing.T) {
+	cursor := pageCursor{CreatedAt: "2025-01-01T00:00:00", ID: 7}
+	decoded, err := decodeCursor(encodeCursor(cursor))
+	require.NoError(t, err)
+	assert.Equal(t, cursor, *decoded)
+
+	for _, malformed := range []string{"not base64!", "bm90IGpzb24", encodeCursor(pageCursor{})} {
+		_, err := decodeCursor(malformed)
+		assert.Error(t, err, malformed)
+	}
+}
+
+func TestHandleListSessionsPagination(t *testing.T) {
+	handler, userID := setupTestHandler()
+	sessions := NewSessionsHandler(handler.Base)
+	for i := 0; i < 3; i++ {
+		_, err := handler.AutogenClient.CreateSession(&autogen_client.CreateSession{Name: fmt.Sprintf("session-%d", i), UserID: userID})
+		require.NoError(t, err)
+	}
+
+	list := func(query string) (*httptest.ResponseRecorder, StandardResponse) {
+		req := httptest.NewRequest("GET", fmt.Sprintf("/api/sessions?user_id=%s%s", userID, query), nil)
+		w := httptest.NewRecorder()
+		sessions.HandleListSessions(&testErrorResponseWriter{w}, req)
+
+		var response StandardResponse
+		if w.Code == http.StatusOK {
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		}
+		return w, response
+	}
+
+	w, first := list("&limit=2")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Len(t, first.Data, 2)
+	require.NotEmpty(t, first.NextCursor)
+
+	w, second := list("&limit=2&cursor=" + first.NextCursor)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Len(t, second.Data, 1)
+	assert.Empty(t, second.NextCursor)
+
+	w, _ = list("&cursor=garbage")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w, _ = list("&limit=-1")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestHandleListSessionsByLastActivity(t *testing.T) {
+	handler, userID := setupTestHandler()
+	autogenClient := handler.AutogenClient.(*autogen_fake.InMemoryAutogenClient)
+	sessions := NewSessionsHandler(handler.Base)
+
+	// Session 1 is the oldest but has the latest run, session 3 has no runs
+	var ids []int
+	for i := 1; i <= 3; i++ {
+		session, err := autogenClient.CreateSession(&autogen_client.CreateSession{Name: fmt.Sprintf("session-%d", i), UserID: userID})
+		require.NoError(t, err)
+		session.CreatedAt = fmt.Sprintf("2025-01-0%dT00:00:00", i)
+		ids = append(ids, session.ID)
+	}
+	for i, createdAt := range map[int]string{0: "2025-01-05T00:00:00", 1: "2025-01-02T12:00:00"} {
+		_, err := autogenClient.CreateRun(&autogen_client.CreateRunRequest{SessionID: ids[i], UserID: userID})
+		require.NoError(t, err)
+		runs, err := autogenClient.ListSessionRuns(ids[i], userID)
+		require.NoError(t, err)
+		runs[0].CreatedAt = createdAt
+	}
+
+	list := func(query string) (*httptest.ResponseRecorder, []int, string) {
+		req := httptest.NewRequest("GET", fmt.Sprintf("/api/sessions?user_id=%s%s", userID, query), nil)
+		w := httptest.NewRecorder()
+		sessions.HandleListSessions(&testErrorResponseWriter{w}, req)
+
+		var response struct {
+			Data       []*autogen_client.Session `json:"data"`
+			NextCursor string                    `json:"next_cursor"`
+		}
+		_ = json.Unmarshal(w.Body.Bytes(), &response)
+		var listed []int
+		for _, session := range response.Data {
+			listed = append(listed, session.ID)
+		}
+		return w, listed, response.NextCursor
+	}
+
+	w, listed, _ := list("&sort=last_activity")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, []int{ids[0], ids[2], ids[1]}, listed)
+
+	w, first, next := list("&sort=last_activity&limit=2")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, []int{ids[0], ids[2]}, first)
+	w, second, _ := list("&sort=last_activity&limit=2&cursor=" + next)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, []int{ids[1]}, second)
+
+	w, _, _ = list("&sort=name")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
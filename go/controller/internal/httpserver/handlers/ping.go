@@ -0,0 +1,155 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrllog "sigs.k8s.io/controller-runtime/pkg/log"
+	"trpc.group/trpc-go/trpc-a2a-go/server"
+
+	autogen_client "github.com/kagent-dev/kagent/go/autogen/client"
+	"github.com/kagent-dev/kagent/go/controller/api/v1alpha1"
+	"github.com/kagent-dev/kagent/go/controller/internal/a2a"
+	"github.com/kagent-dev/kagent/go/controller/internal/httpserver/errors"
+	common "github.com/kagent-dev/kagent/go/controller/internal/utils"
+)
+
+const (
+	// pingTask is the task sent to an agent to check that it responds
+	pingTask = "This is a connectivity check. Reply with the single word: pong"
+	// defaultPingTimeout bounds a ping unless the request sets a max duration
+	defaultPingTimeout = time.Minute
+)
+
+// PingResponse is returned by a successful agent ping
+type PingResponse struct {
+	Agent       string              `json:"agent"`
+	Description string              `json:"description,omitempty"`
+	Skills      []server.AgentSkill `json:"skills,omitempty"`
+	LatencyMs   int64               `json:"latencyMs"`
+	Response    string              `json:"response"`
+}
+
+// HandlePingAgent handles POST /api/agents/{namespace}/{teamName}/ping requests. It sends a
+// trivial task through the invoke path and returns the agent's details with the latency and
+// first reply. Agents rejected by the controller, e.g. for a missing model config, fail with
+// the reason before anything is invoked.
+func (h *InvokeHandler) HandlePingAgent(w ErrorResponseWriter, r *http.Request) {
+	log := ctrllog.FromContext(r.Context()).WithName("invoke-handler").WithValues("operation", "ping")
+
+	namespace, err := GetPathParam(r, "namespace")
+	if err != nil {
+		w.RespondWithError(errors.NewBadRequestError("Failed to get namespace from path", err))
+		return
+	}
+
+	agentName, err := GetPathParam(r, "teamName")
+	if err != nil {
+		w.RespondWithError(errors.NewBadRequestError("Failed to get teamName from path", err))
+		return
+	}
+
+	log = log.WithValues(
+		"agentNamespace", namespace,
+		"agentName", agentName,
+	)
+
+	agent := &v1alpha1.Agent{}
+	if err := common.GetObject(r.Context(), h.KubeClient, agent, agentName, namespace); err != nil {
+		if k8serrors.IsNotFound(err) {
+			w.RespondWithError(errors.NewNotFoundError("Agent not found", nil).WithCode(errors.CodeAgentNotFound))
+			return
+		}
+		w.RespondWithError(errors.NewInternalServerError("Failed to get Agent", err))
+		return
+	}
+	agentRef := common.GetObjectRef(agent)
+
+	accepted := meta.FindStatusCondition(agent.Status.Conditions, v1alpha1.AgentConditionTypeAccepted)
+	if accepted != nil && accepted.Status == metav1.ConditionFalse {
+		w.RespondWithError(errors.NewValidationError(
+			fmt.Sprintf("Agent %s is misconfigured: %s", agentRef, accepted.Message), nil))
+		return
+	}
+
+	invokeClient, _, err := h.autogenClientFor(r)
+	if err != nil {
+		w.RespondWithError(errors.NewBadRequestError("Invalid Autogen URL", err))
+		return
+	}
+
+	team, err := h.AutogenClient.GetAgent(r.Context(), agentRef, common.GetGlobalUserID())
+	if err == autogen_client.NotFoundError {
+		w.RespondWithError(errors.NewNotFoundError(
+			fmt.Sprintf("Agent %s has not been reconciled into Autogen yet", agentRef), nil).WithCode(errors.CodeAgentNotFound))
+		return
+	}
+	if err != nil {
+		w.RespondWithError(errors.NewInternalServerError("Failed to get agent from Autogen", err))
+		return
+	}
+
+	ctx, cancel, maxDuration, err := withMaxDuration(r)
+	if err != nil {
+		w.RespondWithError(errors.NewBadRequestError("Invalid max duration", err))
+		return
+	}
+	defer cancel()
+	if maxDuration == 0 {
+		var cancelTimeout context.CancelFunc
+		ctx, cancelTimeout = context.WithTimeout(ctx, defaultPingTimeout)
+		defer cancelTimeout()
+	}
+
+	start := time.Now()
+	result, err := invokeClient.InvokeTask(ctx, &autogen_client.InvokeTaskRequest{
+		Task:       pingTask,
+		TeamConfig: team.Component,
+	})
+	latency := time.Since(start)
+	if err != nil {
+		if ctx.Err() != nil {
+			w.RespondWithError(errors.NewInternalServerError(
+				fmt.Sprintf("Agent %s did not respond within %s", agentRef, latency.Round(time.Millisecond)), err))
+			return
+		}
+		w.RespondWithError(errors.NewInternalServerError(fmt.Sprintf("Agent %s failed to respond", agentRef), err))
+		return
+	}
+
+	response := &PingResponse{
+		Agent:       agentRef,
+		Description: agent.Spec.Description,
+		LatencyMs:   latency.Milliseconds(),
+		Response:    firstReply(result.TaskResult.Messages),
+	}
+	if agent.Spec.A2AConfig != nil {
+		response.Skills = a2a.ConvertAgentSkills(agent.Spec.A2AConfig.Skills)
+	}
+
+	log.Info("Successfully pinged agent", "latency", latency)
+	RespondWithJSON(w, http.StatusOK, response)
+}
+
+// firstReply returns the text content of the first message not sent by the user
+func firstReply(messages []json.RawMessage) string {
+	for _, raw := range messages {
+		var message struct {
+			Source  string      `json:"source"`
+			Content interface{} `json:"content"`
+		}
+		if err := json.Unmarshal(raw, &message); err != nil || message.Source == "user" {
+			continue
+		}
+		if content, ok := message.Content.(string); ok && content != "" {
+			return content
+		}
+	}
+	return ""
+}
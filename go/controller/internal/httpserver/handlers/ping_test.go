@@ -0,0 +1,83 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	autogen_fake "github.com/kagent-dev/kagent/go/autogen/client/fake"
+	"github.com/kagent-dev/kagent/go/controller/api/v1alpha1"
+)
+
+func TestHandlePingAgent(t *testing.T) {
+	modelConfig := createTestModelConfig()
+	agent := createTestAgent("ping-agent", modelConfig)
+	agent.Spec.Description = "Answers pings"
+	agent.Spec.A2AConfig = &v1alpha1.A2AConfig{Skills: []v1alpha1.AgentSkill{{ID: "ping", Name: "Ping"}}}
+	rejected := createTestAgent("rejected-agent", modelConfig)
+	rejected.Status.Conditions = []metav1.Condition{{
+		Type:    v1alpha1.AgentConditionTypeAccepted,
+		Status:  metav1.ConditionFalse,
+		Reason:  "ReconcileFailed",
+		Message: "model config default/missing not found",
+	}}
+	unreconciled := createTestAgent("unreconciled-agent", modelConfig)
+
+	handler, userID := setupTestHandler(agent, rejected, unreconciled, modelConfig)
+	autogenClient := handler.AutogenClient.(*autogen_fake.InMemoryAutogenClient)
+	createAutogenTeam(autogenClient, userID, agent)
+	invoke := NewInvokeHandler(handler.Base)
+
+	ping := func(name string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("POST", "/api/agents/default/"+name+"/ping", nil)
+		req = mux.SetURLVars(req, map[string]string{"namespace": "default", "teamName": name})
+		w := httptest.NewRecorder()
+		invoke.HandlePingAgent(&testErrorResponseWriter{w}, req)
+		return w
+	}
+
+	t.Run("returns the agent and its first reply", func(t *testing.T) {
+		w := ping("ping-agent")
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var response PingResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, "default/ping-agent", response.Agent)
+		assert.Equal(t, "Answers pings", response.Description)
+		require.Len(t, response.Skills, 1)
+		assert.Equal(t, "ping", response.Skills[0].ID)
+		assert.Contains(t, response.Response, "Task completed")
+	})
+
+	t.Run("reports why the agent was rejected", func(t *testing.T) {
+		w := ping("rejected-agent")
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+		assert.Contains(t, w.Body.String(), "model config default/missing not found")
+	})
+
+	t.Run("agent not reconciled into autogen", func(t *testing.T) {
+		w := ping("unreconciled-agent")
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Contains(t, w.Body.String(), "has not been reconciled")
+	})
+
+	t.Run("missing agent", func(t *testing.T) {
+		assert.Equal(t, http.StatusNotFound, ping("missing-agent").Code)
+	})
+
+	t.Run("agent fails to respond", func(t *testing.T) {
+		autogenClient.SetInvokeResponse("", &autogen_fake.InvokeResponse{Err: fmt.Errorf("tool server unreachable")})
+		defer autogenClient.SetInvokeResponse("", nil)
+
+		w := ping("ping-agent")
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		assert.Contains(t, w.Body.String(), "Agent default/ping-agent failed to respond")
+	})
+}
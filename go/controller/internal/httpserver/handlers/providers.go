@@ -3,6 +3,7 @@ package handlers
 import (
 	"net/http"
 	"reflect"
+	"strings"
 
 	"github.com/kagent-dev/kagent/go/controller/api/v1alpha1"
 	ctrllog "sigs.k8s.io/controller-runtime/pkg/log"
@@ -42,6 +43,118 @@ func getRequiredKeysForMemoryProvider(providerType v1alpha1.MemoryProvider) []st
 	}
 }
 
+// ProviderParam describes a provider config parameter so forms can render a matching input
+type ProviderParam struct {
+	Name string `json:"name"`
+	// Type is the JSON type of the value: string, integer, number, boolean, array or object
+	Type     string `json:"type"`
+	Required bool   `json:"required"`
+	// Secret is set for values that should be masked
+	Secret      bool   `json:"secret"`
+	Description string `json:"description,omitempty"`
+}
+
+// secretParams are the provider config parameters that hold credentials
+var secretParams = map[string]bool{
+	"azureAdToken": true,
+}
+
+// paramDescriptions describe the provider config parameters, following the API type docs
+var paramDescriptions = map[string]string{
+	"baseUrl":          "Base URL for the API (overrides default)",
+	"organization":     "Organization ID for the OpenAI API",
+	"temperature":      "Temperature for sampling",
+	"maxTokens":        "Maximum tokens to generate",
+	"topP":             "Top-p sampling parameter",
+	"topK":             "Top-k sampling parameter, or the number of results to return for memories",
+	"frequencyPenalty": "Frequency penalty",
+	"presencePenalty":  "Presence penalty",
+	"seed":             "Seed value",
+	"n":                "N value",
+	"timeout":          "Timeout",
+	"azureEndpoint":    "Endpoint for the Azure OpenAI API",
+	"apiVersion":       "API version for the Azure OpenAI API",
+	"azureDeployment":  "Deployment name for the Azure OpenAI API",
+	"azureAdToken":     "Azure AD token for authentication",
+	"host":             "Host for the Ollama API",
+	"options":          "Options for the Ollama API",
+	"indexHost":        "The index host to connect to",
+	"namespace":        "The namespace to use for the Pinecone index",
+	"recordFields":     "The fields to retrieve from the Pinecone index",
+	"scoreThreshold":   "The score threshold of results to include in the context",
+}
+
+// jsonType returns the JSON type of values of t
+func jsonType(t reflect.Type) string {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.String:
+		return "string"
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "integer"
+	case reflect.Float32, reflect.Float64:
+		return "number"
+	case reflect.Slice, reflect.Array:
+		return "array"
+	default:
+		return "object"
+	}
+}
+
+// getProviderParams describes the JSON fields of a provider config struct
+func getProviderParams(configType reflect.Type, requiredKeys []string) []ProviderParam {
+	requiredSet := make(map[string]bool, len(requiredKeys))
+	for _, k := range requiredKeys {
+		requiredSet[k] = true
+	}
+
+	params := []ProviderParam{}
+	for i := 0; i < configType.NumField(); i++ {
+		field := configType.Field(i)
+		name := strings.Split(field.Tag.Get("json"), ",")[0]
+		if name == "" || name == "-" {
+			continue
+		}
+		params = append(params, ProviderParam{
+			Name:        name,
+			Type:        jsonType(field.Type),
+			Required:    requiredSet[name],
+			Secret:      secretParams[name],
+			Description: paramDescriptions[name],
+		})
+	}
+	return params
+}
+
+// providerInfo returns the listing of a provider. The requiredParams and optionalParams name
+// lists predate params and are kept for older clients.
+func providerInfo(provider string, configType reflect.Type, requiredKeys []string) map[string]interface{} {
+	params := getProviderParams(configType, requiredKeys)
+
+	requiredParams := []string{}
+	optionalParams := []string{}
+	for _, param := range params {
+		if param.Required {
+			requiredParams = append(requiredParams, param.Name)
+		} else {
+			optionalParams = append(optionalParams, param.Name)
+		}
+	}
+
+	return map[string]interface{}{
+		"name":           provider,
+		"type":           provider,
+		"requiredParams": requiredParams,
+		"optionalParams": optionalParams,
+		"params":         params,
+	}
+}
+
 func (h *ProviderHandler) HandleListSupportedMemoryProviders(w ErrorResponseWriter, r *http.Request) {
 	log := ctrllog.FromContext(r.Context()).WithName("provider-handler").WithValues("operation", "list-supported-memory-providers")
 
@@ -57,29 +170,11 @@ func (h *ProviderHandler) HandleListSupportedMemoryProviders(w ErrorResponseWrit
 	providersResponse := []map[string]interface{}{}
 
 	for _, pData := range providersData {
-		allKeys := getStructJSONKeys(pData.configType)
 		requiredKeys := getRequiredKeysForMemoryProvider(pData.providerEnum)
-		requiredSet := make(map[string]struct{})
-		for _, k := range requiredKeys {
-			requiredSet[k] = struct{}{}
-		}
-
-		optionalKeys := []string{}
-		for _, k := range allKeys {
-			if _, isRequired := requiredSet[k]; !isRequired {
-				optionalKeys = append(optionalKeys, k)
-			}
-		}
-
-		providersResponse = append(providersResponse, map[string]interface{}{
-			"name":           string(pData.providerEnum),
-			"type":           string(pData.providerEnum),
-			"requiredParams": requiredKeys,
-			"optionalParams": optionalKeys,
-		})
+		providersResponse = append(providersResponse, providerInfo(string(pData.providerEnum), pData.configType, requiredKeys))
 	}
 
-	RespondWithJSON(w, http.StatusOK, providersResponse)
+	RespondWithJSON(w, http.StatusOK, NewResponse(providersResponse, "Successfully listed supported providers"))
 }
 
 func (h *ProviderHandler) HandleListSupportedModelProviders(w ErrorResponseWriter, r *http.Request) {
@@ -100,27 +195,9 @@ func (h *ProviderHandler) HandleListSupportedModelProviders(w ErrorResponseWrite
 	providersResponse := []map[string]interface{}{}
 
 	for _, pData := range providersData {
-		allKeys := getStructJSONKeys(pData.configType)
 		requiredKeys := getRequiredKeysForModelProvider(pData.providerEnum)
-		requiredSet := make(map[string]struct{})
-		for _, k := range requiredKeys {
-			requiredSet[k] = struct{}{}
-		}
-
-		optionalKeys := []string{}
-		for _, k := range allKeys {
-			if _, isRequired := requiredSet[k]; !isRequired {
-				optionalKeys = append(optionalKeys, k)
-			}
-		}
-
-		providersResponse = append(providersResponse, map[string]interface{}{
-			"name":           string(pData.providerEnum),
-			"type":           string(pData.providerEnum),
-			"requiredParams": requiredKeys,
-			"optionalParams": optionalKeys,
-		})
+		providersResponse = append(providersResponse, providerInfo(string(pData.providerEnum), pData.configType, requiredKeys))
 	}
 
-	RespondWithJSON(w, http.StatusOK, providersResponse)
+	RespondWithJSON(w, http.StatusOK, NewResponse(providersResponse, "Successfully listed supported providers"))
 }
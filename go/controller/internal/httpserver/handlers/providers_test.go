@@ -0,0 +1,52 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandleListSupportedModelProviders(t *testing.T) {
+	handler, _ := setupTestHandler()
+	providers := NewProviderHandler(handler.Base)
+
+	req := httptest.NewRequest("GET", "/api/providers/models", nil)
+	w := httptest.NewRecorder()
+	providers.HandleListSupportedModelProviders(&testErrorResponseWriter{w}, req)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var response []struct {
+		Name           string          `json:"name"`
+		RequiredParams []string        `json:"requiredParams"`
+		OptionalParams []string        `json:"optionalParams"`
+		Params         []ProviderParam `json:"params"`
+	}
+	require.NoError(t, DecodeResponseData(w.Body.Bytes(), &response))
+
+	var azure map[string]ProviderParam
+	for _, provider := range response {
+		if provider.Name != "AzureOpenAI" {
+			continue
+		}
+		assert.Equal(t, []string{"azureEndpoint", "apiVersion"}, provider.RequiredParams)
+		assert.Contains(t, provider.OptionalParams, "azureAdToken")
+		azure = make(map[string]ProviderParam)
+		for _, param := range provider.Params {
+			azure[param.Name] = param
+		}
+	}
+	require.NotNil(t, azure, "AzureOpenAI is listed")
+
+	assert.Equal(t, ProviderParam{
+		Name:        "azureEndpoint",
+		Type:        "string",
+		Required:    true,
+		Description: "Endpoint for the Azure OpenAI API",
+	}, azure["azureEndpoint"])
+	assert.True(t, azure["azureAdToken"].Secret)
+	assert.Equal(t, "integer", azure["maxTokens"].Type, "pointers are described by their element type")
+	assert.False(t, azure["maxTokens"].Required)
+}
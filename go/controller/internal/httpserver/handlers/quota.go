@@ -0,0 +1,196 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+
+	ctrllog "sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/kagent-dev/kagent/go/controller/api/v1alpha1"
+	"github.com/kagent-dev/kagent/go/controller/internal/httpserver/errors"
+)
+
+// ToolServerCreatedByAnnotation records the user that created a ToolServer through the API,
+// so tool servers can be counted against that user's quota
+const ToolServerCreatedByAnnotation = "kagent.dev/created-by"
+
+// QuotaLimits holds resource limits for a single user. A limit of 0 means unlimited.
+type QuotaLimits struct {
+	MaxSessions    int `json:"max_sessions"`
+	MaxToolServers int `json:"max_tool_servers"`
+}
+
+// QuotaConfig holds the default per-user limits and optional per-user overrides
+type QuotaConfig struct {
+	Default   QuotaLimits
+	Overrides map[string]QuotaLimits
+}
+
+// LimitsFor returns the limits that apply to the given user
+func (q QuotaConfig) LimitsFor(userID string) QuotaLimits {
+	if limits, ok := q.Overrides[userID]; ok {
+		return limits
+	}
+	return q.Default
+}
+
+// Enabled reports whether any limit is configured
+func (q QuotaConfig) Enabled() bool {
+	return q.Default != (QuotaLimits{}) || len(q.Overrides) > 0
+}
+
+// LoadQuotaOverrides reads per-user limits from a JSON file mapping user IDs to limits,
+// e.g. {"alice": {"max_sessions": 10, "max_tool_servers": 5}}
+func LoadQuotaOverrides(path string) (map[string]QuotaLimits, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read quota overrides: %w", err)
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	var overrides map[string]QuotaLimits
+	if err := decoder.Decode(&overrides); err != nil {
+		return nil, fmt.Errorf("failed to parse quota overrides: %w", err)
+	}
+	for userID, limits := range overrides {
+		if userID == "" {
+			return nil, fmt.Errorf("quota overrides must not contain an empty user ID")
+		}
+		if limits.MaxSessions < 0 || limits.MaxToolServers < 0 {
+			return nil, fmt.Errorf("quota overrides for %q must not be negative", userID)
+		}
+	}
+	return overrides, nil
+}
+
+// QuotaUsage is the usage of a single resource against its limit
+type QuotaUsage struct {
+	Used  int `json:"used"`
+	Limit int `json:"limit"`
+}
+
+// QuotaResponse is the response for GET /api/quota
+type QuotaResponse struct {
+	UserID      string     `json:"user_id"`
+	Sessions    QuotaUsage `json:"sessions"`
+	ToolServers QuotaUsage `json:"tool_servers"`
+}
+
+// QuotaHandler handles quota-related requests
+type QuotaHandler struct {
+	*Base
+}
+
+// NewQuotaHandler creates a new QuotaHandler
+func NewQuotaHandler(base *Base) *QuotaHandler {
+	return &QuotaHandler{Base: base}
+}
+
+// HandleGetQuota handles GET /api/quota requests
+func (h *QuotaHandler) HandleGetQuota(w ErrorResponseWriter, r *http.Request) {
+	log := ctrllog.FromContext(r.Context()).WithName("quota-handler").WithValues("operation", "get")
+
+	userID, err := GetUserID(r)
+	if err != nil {
+		w.RespondWithError(errors.NewBadRequestError("Failed to get user ID", err))
+		return
+	}
+	log = log.WithValues("userID", userID)
+
+	sessionCount, err := h.countSessions(userID)
+	if err != nil {
+		w.RespondWithError(errors.NewInternalServerError("Failed to count sessions", err))
+		return
+	}
+
+	toolServerCount, err := h.countToolServers(r.Context(), userID)
+	if err != nil {
+		w.RespondWithError(errors.NewInternalServerError("Failed to count ToolServers", err))
+		return
+	}
+
+	limits := h.Quotas.LimitsFor(userID)
+
+	log.V(1).Info("Successfully retrieved quota usage")
+	RespondWithJSON(w, http.StatusOK, QuotaResponse{
+		UserID:      userID,
+		Sessions:    QuotaUsage{Used: sessionCount, Limit: limits.MaxSessions},
+		ToolServers: QuotaUsage{Used: toolServerCount, Limit: limits.MaxToolServers},
+	})
+}
+
+// checkSessionQuota returns an error if the user cannot create another session
+func (b *Base) checkSessionQuota(userID string) error {
+	limit := b.Quotas.LimitsFor(userID).MaxSessions
+	if limit <= 0 {
+		return nil
+	}
+
+	count, err := b.countSessions(userID)
+	if err != nil {
+		return errors.NewInternalServerError("Failed to count sessions", err)
+	}
+	if count >= limit {
+		return errors.NewTooManyRequestsError(fmt.Sprintf("Session quota exceeded: %d of %d sessions used", count, limit), nil).
+			WithCode(errors.CodeQuotaExceeded)
+	}
+	return nil
+}
+
+// checkToolServerQuota returns an error if the user cannot create another ToolServer
+func (b *Base) checkToolServerQuota(ctx context.Context, userID string) error {
+	limit := b.Quotas.LimitsFor(userID).MaxToolServers
+	if limit <= 0 {
+		return nil
+	}
+
+	count, err := b.countToolServers(ctx, userID)
+	if err != nil {
+		return errors.NewInternalServerError("Failed to count ToolServers", err)
+	}
+	if count >= limit {
+		return errors.NewTooManyRequestsError(fmt.Sprintf("ToolServer quota exceeded: %d of %d tool servers used", count, limit), nil).
+			WithCode(errors.CodeQuotaExceeded)
+	}
+	return nil
+}
+
+func (b *Base) countSessions(userID string) (int, error) {
+	sessions, err := b.AutogenClient.ListSessions(userID)
+	if err != nil {
+		return 0, err
+	}
+	return len(sessions), nil
+}
+
+func (b *Base) countToolServers(ctx context.Context, userID string) (int, error) {
+	toolServerList := &v1alpha1.ToolServerList{}
+	if err := b.KubeClient.List(ctx, toolServerList); err != nil {
+		return 0, err
+	}
+
+	count := 0
+	for _, toolServer := range toolServerList.Items {
+		if toolServer.Annotations[ToolServerCreatedByAnnotation] == userID {
+			count++
+		}
+	}
+
+	// Imported tools have a tool server in Autogen only
+	autogenToolServers, err := b.AutogenClient.ListToolServers(userID)
+	if err != nil {
+		return 0, err
+	}
+	for _, toolServer := range autogenToolServers {
+		if strings.HasPrefix(toolServer.Component.Label, importedToolServerNamespace+"/") {
+			count++
+		}
+	}
+	return count, nil
+}
@@ -0,0 +1,128 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	autogen_client "github.com/kagent-dev/kagent/go/autogen/client"
+	"github.com/kagent-dev/kagent/go/controller/api/v1alpha1"
+)
+
+func TestQuotaConfigLimitsFor(t *testing.T) {
+	quotas := QuotaConfig{
+		Default: QuotaLimits{MaxSessions: 2, MaxToolServers: 1},
+		Overrides: map[string]QuotaLimits{
+			"power-user": {MaxSessions: 10},
+		},
+	}
+
+	assert.Equal(t, QuotaLimits{MaxSessions: 2, MaxToolServers: 1}, quotas.LimitsFor("someone"))
+	assert.Equal(t, QuotaLimits{MaxSessions: 10}, quotas.LimitsFor("power-user"))
+	assert.True(t, quotas.Enabled())
+	assert.True(t, QuotaConfig{Overrides: map[string]QuotaLimits{"power-user": {}}}.Enabled())
+	assert.False(t, QuotaConfig{}.Enabled())
+}
+
+func TestLoadQuotaOverrides(t *testing.T) {
+	write := func(content string) string {
+		path := filepath.Join(t.TempDir(), "overrides.json")
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+		return path
+	}
+
+	overrides, err := LoadQuotaOverrides(write(`{"alice": {"max_sessions": 10, "max_tool_servers": 5}}`))
+	require.NoError(t, err)
+	assert.Equal(t, map[string]QuotaLimits{"alice": {MaxSessions: 10, MaxToolServers: 5}}, overrides)
+
+	_, err = LoadQuotaOverrides(write(`{"alice": {"max_session": 10}}`))
+	assert.Error(t, err)
+	_, err = LoadQuotaOverrides(write(`{"alice": {"max_sessions": -1}}`))
+	assert.Error(t, err)
+	_, err = LoadQuotaOverrides(write(`{"": {"max_sessions": 1}}`))
+	assert.Error(t, err)
+	_, err = LoadQuotaOverrides(filepath.Join(t.TempDir(), "missing.json"))
+	assert.Error(t, err)
+}
+
+func TestSessionQuota(t *testing.T) {
+	handler, userID := setupTestHandler()
+	handler.Quotas = QuotaConfig{Default: QuotaLimits{MaxSessions: 1}}
+	sessions := NewSessionsHandler(handler.Base)
+
+	createSession := func() int {
+		body, _ := json.Marshal(&autogen_client.CreateSession{Name: "session", UserID: userID})
+		req := httptest.NewRequest("POST", "/api/sessions", bytes.NewBuffer(body))
+		w := httptest.NewRecorder()
+		sessions.HandleCreateSession(&testErrorResponseWriter{w}, req)
+		return w.Code
+	}
+
+	assert.Equal(t, http.StatusCreated, createSession())
+	assert.Equal(t, http.StatusTooManyRequests, createSession())
+
+	// Other users are not affected
+	_, err := handler.AutogenClient.CreateSession(&autogen_client.CreateSession{Name: "other", UserID: "other-user"})
+	require.NoError(t, err)
+
+	quota := NewQuotaHandler(handler.Base)
+	req := httptest.NewRequest("GET", fmt.Sprintf("/api/quota?user_id=%s", userID), nil)
+	w := httptest.NewRecorder()
+	quota.HandleGetQuota(&testErrorResponseWriter{w}, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response QuotaResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, userID, response.UserID)
+	assert.Equal(t, QuotaUsage{Used: 1, Limit: 1}, response.Sessions)
+	assert.Equal(t, QuotaUsage{Used: 0, Limit: 0}, response.ToolServers)
+}
+
+func TestToolServerQuota(t *testing.T) {
+	handler, userID := setupTestHandler()
+	handler.Quotas = QuotaConfig{Default: QuotaLimits{MaxToolServers: 1}}
+	toolServers := NewToolServersHandler(handler.Base)
+
+	createToolServer := func(name string, userID string) int {
+		body, _ := json.Marshal(&v1alpha1.ToolServer{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+			Spec: v1alpha1.ToolServerSpec{
+				Config: v1alpha1.ToolServerConfig{
+					Stdio: &v1alpha1.StdioMcpServerConfig{Command: "python"},
+				},
+			},
+		})
+		url := "/api/toolservers"
+		if userID != "" {
+			url += "?user_id=" + userID
+		}
+		req := httptest.NewRequest("POST", url, bytes.NewBuffer(body))
+		w := httptest.NewRecorder()
+		toolServers.HandleCreateToolServer(&testErrorResponseWriter{w}, req)
+		return w.Code
+	}
+
+	assert.Equal(t, http.StatusBadRequest, createToolServer("no-user", ""))
+	assert.Equal(t, http.StatusCreated, createToolServer("first", userID))
+	assert.Equal(t, http.StatusTooManyRequests, createToolServer("second", userID))
+	// Re-applying an existing ToolServer is allowed at the limit
+	assert.Equal(t, http.StatusOK, createToolServer("first", userID))
+	assert.Equal(t, http.StatusCreated, createToolServer("other", "other-user"))
+	// Another user's ToolServer can't be overwritten, whether or not the quota is used up
+	assert.Equal(t, http.StatusConflict, createToolServer("other", userID))
+	assert.Equal(t, http.StatusConflict, createToolServer("first", "third-user"))
+
+	created := &v1alpha1.ToolServer{}
+	require.NoError(t, handler.KubeClient.Get(t.Context(), client.ObjectKey{Name: "first", Namespace: "default"}, created))
+	assert.Equal(t, userID, created.Annotations[ToolServerCreatedByAnnotation])
+}
@@ -0,0 +1,27 @@
+package handlers
+
+import (
+	"reflect"
+)
+
+// StandardResponse is the envelope returned by list endpoints
+type StandardResponse struct {
+	Status     bool        `json:"status"`
+	Message    string      `json:"message,omitempty"`
+	Data       interface{} `json:"data"`
+	NextCursor string      `json:"next_cursor,omitempty"`
+}
+
+// NewResponse wraps data in a successful StandardResponse.
+// Nil slices are encoded as empty lists rather than null.
+func NewResponse(data interface{}, message string) StandardResponse {
+	if v := reflect.ValueOf(data); v.Kind() == reflect.Slice && v.IsNil() {
+		data = reflect.MakeSlice(v.Type(), 0, 0).Interface()
+	}
+
+	return StandardResponse{
+		Status:  true,
+		Message: message,
+		Data:    data,
+	}
+}
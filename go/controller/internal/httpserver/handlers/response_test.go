@@ -0,0 +1,68 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// DecodeResponseData unmarshals the data field of a StandardResponse body into target. It is
+// exported for the tests of package handlers_test.
+func DecodeResponseData(body []byte, target interface{}) error {
+	var response struct {
+		Data json.RawMessage `json:"data"`
+	}
+	if err := json.Unmarshal(body, &response); err != nil {
+		return err
+	}
+	return json.Unmarshal(response.Data, target)
+}
+
+func TestNewResponse(t *testing.T) {
+	var empty []string
+	raw, err := json.Marshal(NewResponse(empty, "Nothing here"))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"status": true, "message": "Nothing here", "data": []}`, string(raw))
+
+	raw, err = json.Marshal(NewResponse([]int{1, 2}, ""))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"status": true, "data": [1, 2]}`, string(raw))
+}
+
+func TestResponseHelpers(t *testing.T) {
+	t.Run("JSON", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		RespondWithJSON(w, http.StatusCreated, map[string]string{"name": "agent"})
+		assert.Equal(t, http.StatusCreated, w.Code)
+		assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+		assert.JSONEq(t, `{"name": "agent"}`, w.Body.String())
+	})
+
+	t.Run("content", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		RespondWithContent(w, http.StatusOK, "text/markdown; charset=utf-8", []byte("# Run 1"))
+		assert.Equal(t, "text/markdown; charset=utf-8", w.Header().Get("Content-Type"))
+		assert.Equal(t, "# Run 1", w.Body.String())
+	})
+
+	t.Run("error with the code of the status", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		RespondWithError(w, http.StatusNotFound, "Session not found")
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+		assert.JSONEq(t, `{"error": "Session not found", "code": "not_found"}`, w.Body.String())
+	})
+
+	t.Run("stream", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		startStreamResponse(&testErrorResponseWriter{w}, "text/event-stream")
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "text/event-stream", w.Header().Get("Content-Type"))
+		assert.Equal(t, "no-cache", w.Header().Get("Cache-Control"))
+		assert.True(t, w.Flushed)
+	})
+}
@@ -0,0 +1,65 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// errSessionDeleted is the cancellation cause of runs stopped because their session was deleted
+var errSessionDeleted = errors.New("session was deleted")
+
+// runRegistry tracks the run in progress of each session so it can be stopped when the
+// session is deleted. A session runs one invocation at a time, so the run of an invocation
+// is the session's only unfinished run started by this controller.
+type runRegistry struct {
+	mu   sync.Mutex
+	runs map[streamKey]context.CancelCauseFunc
+}
+
+func newRunRegistry() *runRegistry {
+	return &runRegistry{runs: map[streamKey]context.CancelCauseFunc{}}
+}
+
+// start derives the context of a new run of the session from ctx. It returns false if the
+// session already has a run in progress. Otherwise the returned done func must be called
+// once the run has finished.
+func (r *runRegistry) start(ctx context.Context, key streamKey) (context.Context, func(), bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.runs[key]; ok {
+		return nil, nil, false
+	}
+
+	ctx, cancel := context.WithCancelCause(ctx)
+	r.runs[key] = cancel
+
+	return ctx, func() {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		delete(r.runs, key)
+		cancel(nil)
+	}, true
+}
+
+// active returns the number of runs of the session in progress
+func (r *runRegistry) active(key streamKey) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.runs[key]; ok {
+		return 1
+	}
+	return 0
+}
+
+// stop cancels the run of the session in progress with cause and returns how many there were
+func (r *runRegistry) stop(key streamKey, cause error) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	cancel, ok := r.runs[key]
+	if !ok {
+		return 0
+	}
+	cancel(cause)
+	return 1
+}
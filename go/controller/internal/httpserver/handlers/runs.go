@@ -0,0 +1,87 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+
+	autogen_client "github.com/kagent-dev/kagent/go/autogen/client"
+	"github.com/kagent-dev/kagent/go/controller/internal/httpserver/errors"
+	ctrllog "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// runStatuses are the statuses runs can be filtered by
+var runStatuses = map[string]bool{
+	autogen_client.RunStatusCreated:  true,
+	autogen_client.RunStatusActive:   true,
+	autogen_client.RunStatusComplete: true,
+	autogen_client.RunStatusError:    true,
+	autogen_client.RunStatusStopped:  true,
+}
+
+// RunsHandler handles run-related requests
+type RunsHandler struct {
+	*Base
+}
+
+// NewRunsHandler creates a new RunsHandler
+func NewRunsHandler(base *Base) *RunsHandler {
+	return &RunsHandler{Base: base}
+}
+
+// HandleListRuns handles GET /api/runs requests. It returns the runs of all the sessions of
+// the user, optionally only those with the given status.
+func (h *RunsHandler) HandleListRuns(w ErrorResponseWriter, r *http.Request) {
+	log := ctrllog.FromContext(r.Context()).WithName("runs-handler").WithValues("operation", "list")
+
+	userID, err := GetUserID(r)
+	if err != nil {
+		w.RespondWithError(errors.NewBadRequestError("Failed to get user ID", err))
+		return
+	}
+	log = log.WithValues("userID", userID)
+
+	status := r.URL.Query().Get("status")
+	if status != "" && !runStatuses[status] {
+		w.RespondWithError(errors.NewBadRequestError(fmt.Sprintf("Invalid status %q", status), nil))
+		return
+	}
+
+	pageParams, err := getPageParams(r)
+	if err != nil {
+		w.RespondWithError(errors.NewBadRequestError("Invalid pagination parameters", err))
+		return
+	}
+
+	log.V(1).Info("Listing runs from Autogen")
+	runs, err := h.AutogenClient.ListRuns(userID)
+	if err != nil {
+		w.RespondWithError(errors.NewInternalServerError("Failed to list runs", err))
+		return
+	}
+
+	if status != "" {
+		filtered := make([]*autogen_client.Run, 0, len(runs))
+		for _, run := range runs {
+			if run.Status == status {
+				filtered = append(filtered, run)
+			}
+		}
+		runs = filtered
+	}
+
+	if pageParams.Enabled {
+		page, nextCursor := paginate(runs, runCursor, pageParams)
+		log.Info("Successfully listed runs", "count", len(page))
+		response := NewResponse(page, "Successfully listed runs")
+		response.NextCursor = nextCursor
+		RespondWithJSON(w, http.StatusOK, response)
+		return
+	}
+
+	log.Info("Successfully listed runs", "count", len(runs))
+	RespondWithJSON(w, http.StatusOK, NewResponse(runs, "Successfully listed runs"))
+}
+
+func runCursor(run *autogen_client.Run) pageCursor {
+	return pageCursor{CreatedAt: run.CreatedAt, ID: run.ID}
+}
@@ -0,0 +1,85 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	autogen_client "github.com/kagent-dev/kagent/go/autogen/client"
+	autogen_fake "github.com/kagent-dev/kagent/go/autogen/client/fake"
+)
+
+func TestHandleListRuns(t *testing.T) {
+	handler, userID := setupTestHandler()
+	autogenClient := handler.AutogenClient.(*autogen_fake.InMemoryAutogenClient)
+	runs := NewRunsHandler(handler.Base)
+
+	createRuns := func(user string, statuses ...string) {
+		session, err := autogenClient.CreateSession(&autogen_client.CreateSession{UserID: user})
+		require.NoError(t, err)
+		for range statuses {
+			_, err := autogenClient.CreateRun(&autogen_client.CreateRunRequest{SessionID: session.ID, UserID: user})
+			require.NoError(t, err)
+		}
+		sessionRuns, err := autogenClient.ListSessionRuns(session.ID, user)
+		require.NoError(t, err)
+		for i, run := range sessionRuns {
+			run.Status = statuses[i]
+			run.CreatedAt = fmt.Sprintf("2025-01-01T00:00:%02d", run.ID)
+		}
+	}
+	createRuns(userID, autogen_client.RunStatusComplete, autogen_client.RunStatusError)
+	createRuns(userID, autogen_client.RunStatusComplete)
+	createRuns("other-user", autogen_client.RunStatusComplete)
+
+	list := func(query string) (*httptest.ResponseRecorder, []*autogen_client.Run, string) {
+		req := httptest.NewRequest("GET", fmt.Sprintf("/api/runs?user_id=%s%s", userID, query), nil)
+		w := httptest.NewRecorder()
+		runs.HandleListRuns(&testErrorResponseWriter{w}, req)
+
+		var response struct {
+			Data       []*autogen_client.Run `json:"data"`
+			NextCursor string                `json:"next_cursor"`
+		}
+		_ = json.Unmarshal(w.Body.Bytes(), &response)
+		return w, response.Data, response.NextCursor
+	}
+
+	t.Run("lists the runs of all the user's sessions", func(t *testing.T) {
+		w, listed, _ := list("")
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		assert.Len(t, listed, 3, "the runs of other users are not listed")
+	})
+
+	t.Run("filters by status", func(t *testing.T) {
+		w, listed, _ := list("&status=complete")
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		require.Len(t, listed, 2)
+		for _, run := range listed {
+			assert.Equal(t, autogen_client.RunStatusComplete, run.Status)
+		}
+	})
+
+	t.Run("paginates newest first", func(t *testing.T) {
+		w, first, cursor := list("&limit=2")
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		require.Len(t, first, 2)
+		assert.Greater(t, first[0].ID, first[1].ID)
+		require.NotEmpty(t, cursor)
+
+		_, second, cursor := list("&limit=2&cursor=" + cursor)
+		require.Len(t, second, 1)
+		assert.Less(t, second[0].ID, first[1].ID)
+		assert.Empty(t, cursor)
+	})
+
+	t.Run("rejects unknown statuses", func(t *testing.T) {
+		w, _, _ := list("&status=done")
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
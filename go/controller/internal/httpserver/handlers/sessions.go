@@ -1,9 +1,16 @@
 package handlers
 
 import (
+	"context"
+	"encoding/json"
+	stderrors "errors"
 	"fmt"
+	"io"
 	"net/http"
+	"strconv"
+	"time"
 
+	"github.com/kagent-dev/kagent/go/autogen/api"
 	autogen_client "github.com/kagent-dev/kagent/go/autogen/client"
 	"github.com/kagent-dev/kagent/go/controller/internal/httpserver/errors"
 	ctrllog "sigs.k8s.io/controller-runtime/pkg/log"
@@ -12,14 +19,25 @@ import (
 // SessionsHandler handles session-related requests
 type SessionsHandler struct {
 	*Base
+	streams   *streamRegistry
+	runs      *runRegistry
+	summaries *summaryCache
 }
 
 // NewSessionsHandler creates a new SessionsHandler
 func NewSessionsHandler(base *Base) *SessionsHandler {
-	return &SessionsHandler{Base: base}
+	return &SessionsHandler{Base: base, streams: newStreamRegistry(), runs: newRunRegistry(), summaries: newSummaryCache()}
 }
 
-// HandleListSessions handles GET /api/sessions requests
+// Orders of the sessions listed by HandleListSessions
+const (
+	sessionSortCreatedAt    = "created_at"
+	sessionSortLastActivity = "last_activity"
+)
+
+// HandleListSessions handles GET /api/sessions requests. With sort=last_activity the sessions
+// are listed most recently active first, by the start of their latest run or their creation
+// if they have no runs.
 func (h *SessionsHandler) HandleListSessions(w ErrorResponseWriter, r *http.Request) {
 	log := ctrllog.FromContext(r.Context()).WithName("sessions-handler").WithValues("operation", "list")
 
@@ -30,6 +48,18 @@ func (h *SessionsHandler) HandleListSessions(w ErrorResponseWriter, r *http.Requ
 	}
 	log = log.WithValues("userID", userID)
 
+	pageParams, err := getPageParams(r)
+	if err != nil {
+		w.RespondWithError(errors.NewBadRequestError("Invalid pagination parameters", err))
+		return
+	}
+
+	sortBy := r.URL.Query().Get("sort")
+	if sortBy != "" && sortBy != sessionSortCreatedAt && sortBy != sessionSortLastActivity {
+		w.RespondWithError(errors.NewBadRequestError(fmt.Sprintf("Invalid sort %q: must be %s or %s", sortBy, sessionSortCreatedAt, sessionSortLastActivity), nil))
+		return
+	}
+
 	log.V(1).Info("Listing sessions from Autogen")
 	sessions, err := h.AutogenClient.ListSessions(userID)
 	if err != nil {
@@ -37,8 +67,36 @@ func (h *SessionsHandler) HandleListSessions(w ErrorResponseWriter, r *http.Requ
 		return
 	}
 
+	cursor := sessionCursor
+	if sortBy == sessionSortLastActivity {
+		runs, err := h.AutogenClient.ListRuns(userID)
+		if err != nil {
+			w.RespondWithError(errors.NewInternalServerError("Failed to list runs", err))
+			return
+		}
+		sessions = autogen_client.SortSessionsByLastActivity(sessions, runs)
+		// Pages are keyed by the last activity, which the cursor holds in place of created_at
+		lastActivity := autogen_client.SessionLastActivity(sessions, runs)
+		cursor = func(session *autogen_client.Session) pageCursor {
+			return pageCursor{CreatedAt: lastActivity[session.ID], ID: session.ID}
+		}
+	}
+
+	if pageParams.Enabled {
+		page, nextCursor := paginate(sessions, cursor, pageParams)
+		log.Info("Successfully listed sessions", "count", len(page))
+		response := NewResponse(page, "Successfully listed sessions")
+		response.NextCursor = nextCursor
+		RespondWithJSON(w, http.StatusOK, response)
+		return
+	}
+
 	log.Info("Successfully listed sessions", "count", len(sessions))
-	RespondWithJSON(w, http.StatusOK, sessions)
+	RespondWithJSON(w, http.StatusOK, NewResponse(sessions, "Successfully listed sessions"))
+}
+
+func sessionCursor(session *autogen_client.Session) pageCursor {
+	return pageCursor{CreatedAt: session.CreatedAt, ID: session.ID}
 }
 
 // HandleCreateSession handles POST /api/sessions requests
@@ -47,16 +105,24 @@ func (h *SessionsHandler) HandleCreateSession(w ErrorResponseWriter, r *http.Req
 
 	var sessionRequest *autogen_client.CreateSession
 	if err := DecodeJSONBody(r, &sessionRequest); err != nil {
-		w.RespondWithError(errors.NewBadRequestError("Invalid request body", err))
+		w.RespondWithError(invalidBodyError(err))
 		return
 	}
 
-	if sessionRequest.UserID == "" {
-		w.RespondWithError(errors.NewBadRequestError("user_id is required", nil))
+	userID, err := RequestUserID(r, sessionRequest.UserID)
+	if err != nil {
+		w.RespondWithError(errors.NewBadRequestError("Failed to get user ID", err))
 		return
 	}
+	sessionRequest.UserID = userID
 	log = log.WithValues("userID", sessionRequest.UserID)
 
+	if err := h.checkSessionQuota(sessionRequest.UserID); err != nil {
+		log.Info("Session quota check failed", "error", err.Error())
+		w.RespondWithError(err)
+		return
+	}
+
 	log.V(1).Info("Creating session in Autogen",
 		"name", sessionRequest.Name)
 	session, err := h.AutogenClient.CreateSession(sessionRequest)
@@ -90,12 +156,12 @@ func (h *SessionsHandler) HandleGetSession(w ErrorResponseWriter, r *http.Reques
 	log.V(1).Info("Getting session from Autogen")
 	session, err := h.AutogenClient.GetSessionById(sessionID, userID)
 	if err != nil {
-		w.RespondWithError(errors.NewInternalServerError("Failed to get session", err))
+		w.RespondWithError(sessionError("Failed to get session", err))
 		return
 	}
 
 	if session == nil {
-		w.RespondWithError(errors.NewNotFoundError("Session not found", nil))
+		w.RespondWithError(errors.NewNotFoundError("Session not found", nil).WithCode(errors.CodeSessionNotFound))
 		return
 	}
 
@@ -103,6 +169,9 @@ func (h *SessionsHandler) HandleGetSession(w ErrorResponseWriter, r *http.Reques
 	RespondWithJSON(w, http.StatusOK, session)
 }
 
+// HandleSessionInvoke handles POST /api/sessions/{sessionID}/invoke requests. Without a
+// team_config in the request, the session's agent is invoked. A session runs one invocation
+// at a time: invoking, streaming or re-running a session with a run in progress responds 409.
 func (h *SessionsHandler) HandleSessionInvoke(w ErrorResponseWriter, r *http.Request) {
 	log := ctrllog.FromContext(r.Context()).WithName("sessions-handler").WithValues("operation", "invoke")
 
@@ -122,7 +191,7 @@ func (h *SessionsHandler) HandleSessionInvoke(w ErrorResponseWriter, r *http.Req
 
 	var invokeRequest *autogen_client.InvokeRequest
 	if err := DecodeJSONBody(r, &invokeRequest); err != nil {
-		w.RespondWithError(errors.NewBadRequestError("Invalid request body", err))
+		w.RespondWithError(invalidBodyError(err))
 		return
 	}
 
@@ -131,20 +200,150 @@ func (h *SessionsHandler) HandleSessionInvoke(w ErrorResponseWriter, r *http.Req
 		return
 	}
 
+	autogenClient, backend, err := h.autogenClientFor(r)
+	if err != nil {
+		w.RespondWithError(errors.NewBadRequestError("Invalid Autogen URL", err))
+		return
+	}
+
 	if invokeRequest.TeamConfig == nil {
-		w.RespondWithError(errors.NewBadRequestError("team_config is required", nil))
+		teamConfig, apiErr := sessionTeamConfig(autogenClient, sessionID, userID)
+		if apiErr != nil {
+			w.RespondWithError(apiErr)
+			return
+		}
+		invokeRequest.TeamConfig = teamConfig
+	}
+
+	ctx, cancel, maxDuration, err := withMaxDuration(r)
+	if err != nil {
+		w.RespondWithError(errors.NewBadRequestError("Invalid max duration", err))
+		return
+	}
+	defer cancel()
+	ctx, done, ok := h.runs.start(ctx, streamKey{backend: backend, sessionID: sessionID})
+	if !ok {
+		w.RespondWithError(runInProgressError())
 		return
 	}
+	defer done()
+
+	// Remember the latest run so the partial result can find the run this invocation creates
+	previousRunID := 0
+	if maxDuration > 0 {
+		previousRunID = latestRunID(autogenClient, sessionID, userID)
+	}
 
-	result, err := h.AutogenClient.InvokeSession(sessionID, userID, invokeRequest)
+	start := time.Now()
+	result, err := autogenClient.InvokeSession(ctx, sessionID, userID, invokeRequest)
 	if err != nil {
-		w.RespondWithError(errors.NewInternalServerError("Failed to invoke session", err))
+		if context.Cause(ctx) == errSessionDeleted {
+			w.RespondWithError(errors.NewConflictError("Session was deleted and its run stopped", nil).WithCode(errors.CodeRunStopped))
+			return
+		}
+		if r.Context().Err() != nil {
+			// The client went away, which cancelled the invocation in Autogen
+			log.Info("Client disconnected, stopping the session run")
+			if runID, err := stopInvocationRun(autogenClient, sessionID, userID, invokeRequest.Task); err != nil {
+				log.Error(err, "Failed to mark the session run as stopped")
+			} else if runID != 0 {
+				log.Info("Marked the session run as stopped", "runID", runID)
+			}
+			w.RespondWithError(errors.NewConflictError("Client disconnected and the run was stopped", r.Context().Err()).WithCode(errors.CodeRunStopped))
+			return
+		}
+		if deadlineExceeded(ctx, err) {
+			log.Info("Session invocation exceeded its max duration, returning partial result", "maxDuration", maxDuration)
+			RespondWithJSON(w, http.StatusOK, partialSessionResult(autogenClient, sessionID, userID, previousRunID, time.Since(start)))
+			return
+		}
+		w.RespondWithError(sessionError("Failed to invoke session", err))
 		return
 	}
 
 	RespondWithJSON(w, http.StatusOK, result)
 }
 
+// DisconnectedRunMessage is the error message of runs stopped because their client disconnected
+const DisconnectedRunMessage = "The client disconnected before the run finished"
+
+// stopInvocationRun marks the run of an invocation of task as stopped and returns its ID, or 0
+// if there's no such run. Since a session runs one invocation at a time, the invocation's run
+// is the newest unfinished run of the session for the task.
+func stopInvocationRun(autogenClient autogen_client.Client, sessionID int, userID string, task string) (int, error) {
+	runs, err := autogenClient.ListSessionRuns(sessionID, userID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list runs of session %d: %w", sessionID, err)
+	}
+
+	var latest *autogen_client.Run
+	for _, run := range runs {
+		if run.Status == autogen_client.RunStatusComplete || run.Status == autogen_client.RunStatusError || run.Status == autogen_client.RunStatusStopped {
+			continue
+		}
+		if content, ok := run.Task.Content.(string); !ok || content != task {
+			continue
+		}
+		if latest == nil || run.ID > latest.ID {
+			latest = run
+		}
+	}
+	if latest == nil {
+		return 0, nil
+	}
+
+	if err := autogenClient.UpdateRunStatus(latest.ID, autogen_client.RunStatusStopped, DisconnectedRunMessage); err != nil {
+		return 0, fmt.Errorf("failed to stop run %d: %w", latest.ID, err)
+	}
+	return latest.ID, nil
+}
+
+// runInProgressError is the error of an invocation refused because its session already has a
+// run in progress
+func runInProgressError() *errors.APIError {
+	return errors.NewConflictError("Session has a run in progress: wait for it to finish or resume its stream", nil).
+		WithCode(errors.CodeRunInProgress)
+}
+
+// sessionError returns the error to respond with when a session request to Autogen fails.
+// Autogen reports the sessions of other users as not found too.
+func sessionError(message string, err error) *errors.APIError {
+	if stderrors.Is(err, autogen_client.NotFoundError) {
+		return errors.NewNotFoundError("Session not found", err).WithCode(errors.CodeSessionNotFound)
+	}
+	return errors.NewInternalServerError(message, err)
+}
+
+// sessionTeamConfig resolves the team component of the agent the session is bound to, for
+// invocations that don't send a team_config
+func sessionTeamConfig(autogenClient autogen_client.Client, sessionID int, userID string) (*api.Component, *errors.APIError) {
+	session, err := autogenClient.GetSessionById(sessionID, userID)
+	if err != nil {
+		return nil, sessionError("Failed to get session", err)
+	}
+	if session == nil {
+		return nil, errors.NewNotFoundError("Session not found", nil).WithCode(errors.CodeSessionNotFound)
+	}
+	if session.TeamID == nil {
+		return nil, errors.NewValidationError("Session has no agent bound: set team_config or bind the session to an agent", nil)
+	}
+
+	team, err := autogenClient.GetTeamByID(*session.TeamID, userID)
+	if err != nil || team == nil || team.Component == nil {
+		return nil, errors.NewValidationError(fmt.Sprintf("Agent %d bound to the session could not be resolved", *session.TeamID), err).
+			WithCode(errors.CodeAgentNotFound)
+	}
+	return team.Component, nil
+}
+
+// HandleSessionInvokeStream handles POST /api/sessions/{sessionID}/invoke/stream requests.
+// Every frame carries an incrementing id. A request with a Last-Event-ID header resumes the
+// session's latest stream after that id instead of starting a new run.
+//
+// With the raw query parameter or body field set to true the events of Autogen are written as
+// Autogen sent them instead: the frames keep Autogen's ids, if any, rather than kagent's, the
+// stream can't be resumed, and no completion frame is added when the session is deleted during
+// the run.
 func (h *SessionsHandler) HandleSessionInvokeStream(w ErrorResponseWriter, r *http.Request) {
 	log := ctrllog.FromContext(r.Context()).WithName("sessions-handler").WithValues("operation", "invoke-stream")
 
@@ -162,9 +361,32 @@ func (h *SessionsHandler) HandleSessionInvokeStream(w ErrorResponseWriter, r *ht
 	}
 	log = log.WithValues("userID", userID)
 
+	autogenClient, backend, err := h.autogenClientFor(r)
+	if err != nil {
+		w.RespondWithError(errors.NewBadRequestError("Invalid Autogen URL", err))
+		return
+	}
+	key := streamKey{backend: backend, sessionID: sessionID}
+	raw := r.URL.Query().Get("raw") == "true"
+
+	if lastEventIDStr := r.Header.Get("Last-Event-ID"); lastEventIDStr != "" {
+		if raw {
+			w.RespondWithError(errors.NewBadRequestError("Raw streams can't be resumed", nil))
+			return
+		}
+		lastEventID, err := strconv.Atoi(lastEventIDStr)
+		if err != nil || lastEventID < 0 {
+			w.RespondWithError(errors.NewBadRequestError("Invalid Last-Event-ID header", err))
+			return
+		}
+		log.V(1).Info("Resuming session stream", "lastEventID", lastEventID)
+		h.resumeSessionStream(w, r, autogenClient, key, userID, lastEventID)
+		return
+	}
+
 	var invokeRequest *autogen_client.InvokeRequest
 	if err := DecodeJSONBody(r, &invokeRequest); err != nil {
-		w.RespondWithError(errors.NewBadRequestError("Invalid request body", err))
+		w.RespondWithError(invalidBodyError(err))
 		return
 	}
 
@@ -174,26 +396,242 @@ func (h *SessionsHandler) HandleSessionInvokeStream(w ErrorResponseWriter, r *ht
 	}
 
 	if invokeRequest.TeamConfig == nil {
-		w.RespondWithError(errors.NewBadRequestError("team_config is required", nil))
+		teamConfig, apiErr := sessionTeamConfig(autogenClient, sessionID, userID)
+		if apiErr != nil {
+			w.RespondWithError(apiErr)
+			return
+		}
+		invokeRequest.TeamConfig = teamConfig
+	}
+
+	// Raw is handled here, Autogen always streams its own events
+	raw = raw || invokeRequest.Raw
+	invokeRequest.Raw = false
+
+	if raw {
+		h.rawSessionStream(w, r, autogenClient, key, userID, invokeRequest)
 		return
 	}
 
-	ch, err := h.AutogenClient.InvokeSessionStream(sessionID, userID, invokeRequest)
+	// The run is detached from this request so it keeps going if the client disconnects
+	// and later resumes the stream. It is only stopped if the session is deleted.
+	runCtx, done, ok := h.runs.start(context.WithoutCancel(r.Context()), key)
+	if !ok {
+		w.RespondWithError(runInProgressError())
+		return
+	}
+	ch, err := autogenClient.InvokeSessionStream(runCtx, sessionID, userID, invokeRequest)
 	if err != nil {
-		w.RespondWithError(errors.NewInternalServerError("Failed to invoke session", err))
+		done()
+		w.RespondWithError(sessionError("Failed to invoke session", err))
 		return
 	}
 
-	w.Header().Set("Content-Type", "text/event-stream")
-	w.WriteHeader(http.StatusOK)
-	w.Flush()
+	// The run counts towards the invocation limits until it's done, not only while the
+	// client follows it
+	releaseSlot := HoldInvokeSlot(r)
+	buffer := h.streams.start(key)
+	go func() {
+		defer releaseSlot()
+		defer done()
+		for event := range ch {
+			buffer.append(sseFrame{Event: event.Event, Data: event.Data})
+		}
+		if context.Cause(runCtx) == errSessionDeleted {
+			completion, _ := json.Marshal(map[string]interface{}{
+				"type":   "completion",
+				"status": "stopped",
+				"data":   "session was deleted",
+			})
+			buffer.append(sseFrame{Event: "completion", Data: completion})
+		}
+		h.streams.finish(key, buffer)
+	}()
+
+	startStreamResponse(w, "text/event-stream")
+
+	buffer.follow(r.Context(), w, 0)
+}
+
+// rawSessionStream runs the task and writes the events of Autogen unmodified. The run isn't
+// buffered for resuming, so it is stopped if the client disconnects.
+func (h *SessionsHandler) rawSessionStream(w ErrorResponseWriter, r *http.Request, autogenClient autogen_client.Client, key streamKey, userID string, invokeRequest *autogen_client.InvokeRequest) {
+	runCtx, done, ok := h.runs.start(r.Context(), key)
+	if !ok {
+		w.RespondWithError(runInProgressError())
+		return
+	}
+	defer done()
+	ch, err := autogenClient.InvokeSessionStream(runCtx, key.sessionID, userID, invokeRequest)
+	if err != nil {
+		w.RespondWithError(sessionError("Failed to invoke session", err))
+		return
+	}
+
+	startStreamResponse(w, "text/event-stream")
 
 	for event := range ch {
-		w.Write([]byte(fmt.Sprintf("event: %s\ndata: %s\n\n", event.Event, event.Data)))
+		if event.ID != "" {
+			w.Write([]byte(fmt.Sprintf("id:%s\n", event.ID)))
+		}
+		w.Write([]byte(fmt.Sprintf("event:%s\ndata:%s\n\n", event.Event, event.Data)))
 		w.Flush()
 	}
 }
 
+// resumeSessionStream replays the session's latest stream after lastEventID. If the stream
+// is still buffered, the missed frames are replayed and the stream is followed until it ends,
+// whether or not the run completed in the meantime. Frames the buffer has dropped to stay
+// within its limits are skipped, and the replay starts at the oldest frame kept. Once the
+// buffer has expired, the stored messages of a finished run are replayed in full followed by a
+// completion event; since they don't map onto frame ids, the client may see messages it has
+// already received.
+func (h *SessionsHandler) resumeSessionStream(w ErrorResponseWriter, r *http.Request, autogenClient autogen_client.Client, key streamKey, userID string, lastEventID int) {
+	// Streams are buffered by session ID only, so check that the session is the user's first
+	if _, err := autogenClient.GetSessionById(key.sessionID, userID); err != nil {
+		w.RespondWithError(sessionError("Failed to get session", err))
+		return
+	}
+
+	if buffer := h.streams.get(key); buffer != nil {
+		startStreamResponse(w, "text/event-stream")
+		buffer.follow(r.Context(), w, lastEventID)
+		return
+	}
+
+	runs, err := autogenClient.ListSessionRuns(key.sessionID, userID)
+	if err != nil {
+		w.RespondWithError(sessionError("Failed to list session runs", err))
+		return
+	}
+	var latest *autogen_client.Run
+	for _, run := range runs {
+		if latest == nil || run.ID > latest.ID {
+			latest = run
+		}
+	}
+	if latest == nil {
+		w.RespondWithError(errors.NewNotFoundError("No stream to resume for session", nil))
+		return
+	}
+
+	completionStatus := "success"
+	switch latest.Status {
+	case "complete":
+	case "error", "stopped":
+		completionStatus = latest.Status
+	default:
+		w.RespondWithError(errors.NewConflictError("Run is still in progress and its stream can no longer be resumed", nil))
+		return
+	}
+
+	startStreamResponse(w, "text/event-stream")
+
+	for _, message := range latest.Messages {
+		data, err := json.Marshal(message.Config)
+		if err != nil {
+			continue
+		}
+		w.Write([]byte(fmt.Sprintf("event: event\ndata: %s\n\n", data)))
+	}
+	completion, _ := json.Marshal(map[string]interface{}{
+		"type":   "completion",
+		"status": completionStatus,
+		"data":   latest.ErrorMessage,
+	})
+	w.Write([]byte(fmt.Sprintf("event: completion\ndata: %s\n\n", completion)))
+	w.Flush()
+}
+
+// HandleRerunSessionRun handles POST /api/sessions/{sessionID}/runs/{runID}/rerun requests.
+// The task of the run, or the task in the request if set, is run again in a new run linked to
+// the run by parent_run_id. The run itself is left unchanged. The body is optional.
+func (h *SessionsHandler) HandleRerunSessionRun(w ErrorResponseWriter, r *http.Request) {
+	log := ctrllog.FromContext(r.Context()).WithName("sessions-handler").WithValues("operation", "rerun")
+
+	sessionID, err := GetIntPathParam(r, "sessionID")
+	if err != nil {
+		w.RespondWithError(errors.NewBadRequestError("Failed to get session ID from path", err))
+		return
+	}
+	runID, err := GetIntPathParam(r, "runID")
+	if err != nil {
+		w.RespondWithError(errors.NewBadRequestError("Failed to get run ID from path", err))
+		return
+	}
+	log = log.WithValues("sessionID", sessionID, "runID", runID)
+
+	userID, err := GetUserID(r)
+	if err != nil {
+		w.RespondWithError(errors.NewBadRequestError("Failed to get user ID", err))
+		return
+	}
+	log = log.WithValues("userID", userID)
+
+	rerunRequest := &autogen_client.RerunRequest{}
+	if err := DecodeJSONBody(r, rerunRequest); err != nil && !stderrors.Is(err, io.EOF) {
+		w.RespondWithError(invalidBodyError(err))
+		return
+	}
+
+	autogenClient, backend, err := h.autogenClientFor(r)
+	if err != nil {
+		w.RespondWithError(errors.NewBadRequestError("Invalid Autogen URL", err))
+		return
+	}
+
+	runs, err := autogenClient.ListSessionRuns(sessionID, userID)
+	if err != nil {
+		w.RespondWithError(sessionError("Failed to list session runs", err))
+		return
+	}
+	var parent *autogen_client.Run
+	for _, run := range runs {
+		if run.ID == runID {
+			parent = run
+		}
+	}
+	if parent == nil {
+		w.RespondWithError(errors.NewNotFoundError("Run not found", nil))
+		return
+	}
+	if rerunRequest.Task == "" {
+		if task, ok := parent.Task.Content.(string); !ok || task == "" {
+			w.RespondWithError(errors.NewValidationError("Run has no text task to re-run: set task", nil))
+			return
+		}
+	}
+
+	if rerunRequest.TeamConfig == nil {
+		teamConfig, apiErr := sessionTeamConfig(autogenClient, sessionID, userID)
+		if apiErr != nil {
+			w.RespondWithError(apiErr)
+			return
+		}
+		rerunRequest.TeamConfig = teamConfig
+	}
+
+	ctx, done, ok := h.runs.start(r.Context(), streamKey{backend: backend, sessionID: sessionID})
+	if !ok {
+		w.RespondWithError(runInProgressError())
+		return
+	}
+	defer done()
+
+	run, err := autogenClient.RerunSessionRun(ctx, sessionID, runID, userID, rerunRequest)
+	if err != nil {
+		if context.Cause(ctx) == errSessionDeleted {
+			w.RespondWithError(errors.NewConflictError("Session was deleted and its run stopped", nil).WithCode(errors.CodeRunStopped))
+			return
+		}
+		w.RespondWithError(sessionError("Failed to re-run run", err))
+		return
+	}
+
+	log.Info("Successfully re-ran run", "newRunID", run.ID)
+	RespondWithJSON(w, http.StatusCreated, run)
+}
+
 // HandleListSessionMessages handles GET /api/sessions/{sessionID}/messages requests
 func (h *SessionsHandler) HandleListSessionMessages(w ErrorResponseWriter, r *http.Request) {
 	log := ctrllog.FromContext(r.Context()).WithName("sessions-handler").WithValues("operation", "list-session-messages")
@@ -215,7 +653,7 @@ func (h *SessionsHandler) HandleListSessionMessages(w ErrorResponseWriter, r *ht
 	log.V(1).Info("Listing runs for session from Autogen")
 	runs, err := h.AutogenClient.ListSessionRuns(sessionID, userID)
 	if err != nil {
-		w.RespondWithError(errors.NewInternalServerError("Failed to list session runs", err))
+		w.RespondWithError(sessionError("Failed to list session runs", err))
 		return
 	}
 
@@ -223,19 +661,94 @@ func (h *SessionsHandler) HandleListSessionMessages(w ErrorResponseWriter, r *ht
 	configs := []autogen_client.TaskMessageMap{}
 	for _, run := range runs {
 		for _, message := range run.Messages {
-			item := make(autogen_client.TaskMessageMap)
-			if message.Config != nil {
-				for k, v := range message.Config {
-					item[k] = v
-				}
-			}
-			item["id"] = message.ID
-			configs = append(configs, item)
+			configs = append(configs, messageConfig(message))
 		}
 	}
-	RespondWithJSON(w, http.StatusOK, configs)
+	RespondWithJSON(w, http.StatusOK, NewResponse(configs, "Successfully listed session messages"))
 }
 
+// HandleExportSessionRun handles GET /api/sessions/{sessionID}/runs/{runID}/export requests.
+// The messages of the run are rendered as Markdown, or as JSON with format=json.
+func (h *SessionsHandler) HandleExportSessionRun(w ErrorResponseWriter, r *http.Request) {
+	log := ctrllog.FromContext(r.Context()).WithName("sessions-handler").WithValues("operation", "export-run")
+
+	sessionID, err := GetIntPathParam(r, "sessionID")
+	if err != nil {
+		w.RespondWithError(errors.NewBadRequestError("Failed to get session ID from path", err))
+		return
+	}
+	runID, err := GetIntPathParam(r, "runID")
+	if err != nil {
+		w.RespondWithError(errors.NewBadRequestError("Failed to get run ID from path", err))
+		return
+	}
+	log = log.WithValues("sessionID", sessionID, "runID", runID)
+
+	userID, err := GetUserID(r)
+	if err != nil {
+		w.RespondWithError(errors.NewBadRequestError("Failed to get user ID", err))
+		return
+	}
+	log = log.WithValues("userID", userID)
+
+	format := autogen_client.ExportFormat(r.URL.Query().Get("format"))
+	contentType := "text/markdown; charset=utf-8"
+	switch format {
+	case "":
+		format = autogen_client.ExportFormatMarkdown
+	case autogen_client.ExportFormatMarkdown:
+	case autogen_client.ExportFormatJSON:
+		contentType = "application/json"
+	default:
+		w.RespondWithError(errors.NewBadRequestError(fmt.Sprintf("Invalid format %q: must be md or json", format), nil))
+		return
+	}
+
+	export, err := h.AutogenClient.ExportRun(r.Context(), sessionID, runID, userID, format)
+	if err != nil {
+		if stderrors.Is(err, autogen_client.NotFoundError) {
+			w.RespondWithError(errors.NewNotFoundError("Run not found", err))
+			return
+		}
+		w.RespondWithError(errors.NewInternalServerError("Failed to export run", err))
+		return
+	}
+
+	log.V(1).Info("Exported run", "format", format)
+	RespondWithContent(w, http.StatusOK, contentType, export)
+}
+
+// HandleGetSessionStats handles GET /api/sessions/{sessionID}/stats requests. A session
+// without runs has zero statistics.
+func (h *SessionsHandler) HandleGetSessionStats(w ErrorResponseWriter, r *http.Request) {
+	log := ctrllog.FromContext(r.Context()).WithName("sessions-handler").WithValues("operation", "stats")
+
+	sessionID, err := GetIntPathParam(r, "sessionID")
+	if err != nil {
+		w.RespondWithError(errors.NewBadRequestError("Failed to get session ID from path", err))
+		return
+	}
+	log = log.WithValues("sessionID", sessionID)
+
+	userID, err := GetUserID(r)
+	if err != nil {
+		w.RespondWithError(errors.NewBadRequestError("Failed to get user ID", err))
+		return
+	}
+	log = log.WithValues("userID", userID)
+
+	stats, err := h.AutogenClient.GetSessionStats(r.Context(), sessionID, userID)
+	if err != nil {
+		w.RespondWithError(sessionError("Failed to get session stats", err))
+		return
+	}
+
+	log.V(1).Info("Computed session stats", "runCount", stats.RunCount)
+	RespondWithJSON(w, http.StatusOK, NewResponse(stats, "Successfully computed session stats"))
+}
+
+// HandleDeleteSession handles DELETE /api/sessions/{sessionID} requests. A session with runs
+// in progress is only deleted with force=true, which stops the runs first.
 func (h *SessionsHandler) HandleDeleteSession(w ErrorResponseWriter, r *http.Request) {
 	log := ctrllog.FromContext(r.Context()).WithName("sessions-handler").WithValues("operation", "delete-session")
 
@@ -253,9 +766,36 @@ func (h *SessionsHandler) HandleDeleteSession(w ErrorResponseWriter, r *http.Req
 	}
 	log = log.WithValues("sessionID", sessionID)
 
-	err = h.AutogenClient.DeleteSession(sessionID, userID)
+	autogenClient, backend, err := h.autogenClientFor(r)
 	if err != nil {
-		w.RespondWithError(errors.NewInternalServerError("Failed to delete session", err))
+		w.RespondWithError(errors.NewBadRequestError("Invalid Autogen URL", err))
+		return
+	}
+
+	// The runs in progress are tracked by backend and session ID only, so check that the
+	// session is the user's before looking at them
+	if _, err := autogenClient.GetSessionById(sessionID, userID); err != nil {
+		w.RespondWithError(sessionError("Failed to get session", err))
+		return
+	}
+
+	// Runs in progress are stopped rather than left running against a deleted session, but
+	// only if the client confirms it with force=true
+	key := streamKey{backend: backend, sessionID: sessionID}
+	if active := h.runs.active(key); active > 0 {
+		if r.URL.Query().Get("force") != "true" {
+			w.RespondWithError(errors.NewConflictError(
+				fmt.Sprintf("Session has %d run(s) in progress: delete it with force=true to stop them", active), nil).
+				WithCode(errors.CodeRunInProgress))
+			return
+		}
+		stopped := h.runs.stop(key, errSessionDeleted)
+		log.Info("Stopped runs in progress of deleted session", "runs", stopped)
+	}
+
+	err = autogenClient.DeleteSession(sessionID, userID)
+	if err != nil {
+		w.RespondWithError(sessionError("Failed to delete session", err))
 		return
 	}
 
@@ -281,15 +821,64 @@ func (h *SessionsHandler) HandleUpdateSession(w ErrorResponseWriter, r *http.Req
 
 	var sessionRequest *autogen_client.Session
 	if err := DecodeJSONBody(r, &sessionRequest); err != nil {
-		w.RespondWithError(errors.NewBadRequestError("Invalid request body", err))
+		w.RespondWithError(invalidBodyError(err))
 		return
 	}
 
 	updatedSession, err := h.AutogenClient.UpdateSession(sessionID, userID, sessionRequest)
 	if err != nil {
-		w.RespondWithError(errors.NewInternalServerError("Failed to update session", err))
+		w.RespondWithError(sessionError("Failed to update session", err))
 		return
 	}
 
 	RespondWithJSON(w, http.StatusOK, updatedSession)
 }
+
+// HandlePatchSession handles PATCH /api/sessions/{sessionID} requests with a JSON merge patch
+// of the session: fields absent from the patch are kept, and fields set to null are cleared
+func (h *SessionsHandler) HandlePatchSession(w ErrorResponseWriter, r *http.Request) {
+	log := ctrllog.FromContext(r.Context()).WithName("sessions-handler").WithValues("operation", "patch-session")
+
+	sessionID, err := GetIntPathParam(r, "sessionID")
+	if err != nil {
+		w.RespondWithError(errors.NewBadRequestError("Failed to get session ID from path", err))
+		return
+	}
+	log = log.WithValues("sessionID", sessionID)
+
+	userID, err := GetUserID(r)
+	if err != nil {
+		w.RespondWithError(errors.NewBadRequestError("Failed to get user ID", err))
+		return
+	}
+	log = log.WithValues("userID", userID)
+
+	patch, err := decodeMergePatch(r)
+	if err != nil {
+		w.RespondWithError(invalidBodyError(err))
+		return
+	}
+
+	patchedSession, err := h.AutogenClient.PatchSession(sessionID, userID, patch)
+	if err != nil {
+		if stderrors.Is(err, autogen_client.ErrInvalidPatch) {
+			w.RespondWithError(errors.NewBadRequestError("Invalid session patch", err))
+			return
+		}
+		w.RespondWithError(sessionError("Failed to patch session", err))
+		return
+	}
+
+	log.V(1).Info("Patched session")
+	RespondWithJSON(w, http.StatusOK, patchedSession)
+}
+
+// messageConfig returns the config of a stored message with the message id added
+func messageConfig(message *autogen_client.RunMessage) autogen_client.TaskMessageMap {
+	item := make(autogen_client.TaskMessageMap)
+	for k, v := range message.Config {
+		item[k] = v
+	}
+	item["id"] = message.ID
+	return item
+}
@@ -0,0 +1,509 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kagent-dev/kagent/go/autogen/api"
+	autogen_client "github.com/kagent-dev/kagent/go/autogen/client"
+	autogen_fake "github.com/kagent-dev/kagent/go/autogen/client/fake"
+)
+
+func setupSessionInvoke(t *testing.T) (*SessionsHandler, *autogen_fake.InMemoryAutogenClient, func(path, task string) *http.Request) {
+	handler, userID := setupTestHandler()
+	autogenClient := handler.AutogenClient.(*autogen_fake.InMemoryAutogenClient)
+	session, err := autogenClient.CreateSession(&autogen_client.CreateSession{Name: "session", UserID: userID})
+	require.NoError(t, err)
+
+	newRequest := func(path, task string) *http.Request {
+		body, _ := json.Marshal(&autogen_client.InvokeRequest{Task: task, TeamConfig: &api.Component{}})
+		req := httptest.NewRequest("POST", fmt.Sprintf("/api/sessions/%d/%s?user_id=%s", session.ID, path, userID), bytes.NewBuffer(body))
+		return mux.SetURLVars(req, map[string]string{"sessionID": fmt.Sprintf("%d", session.ID)})
+	}
+	return NewSessionsHandler(handler.Base), autogenClient, newRequest
+}
+
+func TestHandleSessionInvoke(t *testing.T) {
+	sessions, autogenClient, newRequest := setupSessionInvoke(t)
+	autogenClient.SetInvokeResponse("summarize", &autogen_fake.InvokeResponse{
+		TaskResult: autogen_client.TaskResult{
+			Messages:   []json.RawMessage{json.RawMessage(`{"type":"TextMessage","content":"summary","source":"assistant"}`)},
+			StopReason: "done",
+		},
+	})
+	autogenClient.SetInvokeResponse("fail", &autogen_fake.InvokeResponse{Err: fmt.Errorf("model unavailable")})
+
+	invoke := func(task string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		sessions.HandleSessionInvoke(&testErrorResponseWriter{w}, newRequest("invoke", task))
+		return w
+	}
+
+	t.Run("returns the scripted result", func(t *testing.T) {
+		w := invoke("summarize")
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var result autogen_client.TeamResult
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+		assert.Equal(t, "done", result.TaskResult.StopReason)
+		require.Len(t, result.TaskResult.Messages, 1)
+		assert.Contains(t, string(result.TaskResult.Messages[0]), `"content":"summary"`)
+	})
+
+	t.Run("returns 500 when autogen fails", func(t *testing.T) {
+		w := invoke("fail")
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		assert.Contains(t, w.Body.String(), "Failed to invoke session")
+	})
+
+	t.Run("keeps the canned result for other tasks", func(t *testing.T) {
+		w := invoke("hello")
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), "Session task completed: hello")
+	})
+}
+
+func TestHandleSessionInvokeWithoutTeamConfig(t *testing.T) {
+	handler, userID := setupTestHandler()
+	autogenClient := handler.AutogenClient.(*autogen_fake.InMemoryAutogenClient)
+	sessions := NewSessionsHandler(handler.Base)
+	createAutogenTeam(autogenClient, userID, createTestAgent("bound-agent", createTestModelConfig()))
+	teamID := 1
+
+	invoke := func(session *autogen_client.Session) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(&autogen_client.InvokeRequest{Task: "hello"})
+		req := httptest.NewRequest("POST", fmt.Sprintf("/api/sessions/%d/invoke?user_id=%s", session.ID, userID), bytes.NewBuffer(body))
+		req = mux.SetURLVars(req, map[string]string{"sessionID": strconv.Itoa(session.ID)})
+		w := httptest.NewRecorder()
+		sessions.HandleSessionInvoke(&testErrorResponseWriter{w}, req)
+		return w
+	}
+
+	t.Run("returns 422 for a session without an agent", func(t *testing.T) {
+		session, err := autogenClient.CreateSession(&autogen_client.CreateSession{Name: "unbound", UserID: userID})
+		require.NoError(t, err)
+
+		w := invoke(session)
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+		assert.Contains(t, w.Body.String(), "Session has no agent bound")
+	})
+
+	t.Run("returns 422 for a session whose agent is gone", func(t *testing.T) {
+		missingTeamID := 42
+		session, err := autogenClient.CreateSession(&autogen_client.CreateSession{Name: "orphaned", UserID: userID, TeamID: &missingTeamID})
+		require.NoError(t, err)
+
+		w := invoke(session)
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+		assert.Contains(t, w.Body.String(), "could not be resolved")
+	})
+
+	t.Run("invokes the session's agent", func(t *testing.T) {
+		session, err := autogenClient.CreateSession(&autogen_client.CreateSession{Name: "bound", UserID: userID, TeamID: &teamID})
+		require.NoError(t, err)
+
+		w := invoke(session)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		assert.Contains(t, w.Body.String(), "Session task completed: hello")
+	})
+}
+
+func TestHandleSessionInvokeStream(t *testing.T) {
+	sessions, autogenClient, newRequest := setupSessionInvoke(t)
+	autogenClient.SetInvokeResponse("", &autogen_fake.InvokeResponse{
+		Events: []*autogen_client.SseEvent{
+			{Event: "event", Data: []byte(`{"type":"TextMessage","content":"first"}`)},
+			{Event: "event", Data: []byte(`{"type":"TextMessage","content":"second"}`)},
+			{Event: "completion", Data: []byte(`{"type":"completion","status":"success"}`)},
+		},
+	})
+
+	w := httptest.NewRecorder()
+	sessions.HandleSessionInvokeStream(&testErrorResponseWriter{w}, newRequest("invoke/stream", "anything"))
+	require.Equal(t, http.StatusOK, w.Code)
+
+	body := w.Body.String()
+	first := bytes.Index([]byte(body), []byte(`"content":"first"`))
+	second := bytes.Index([]byte(body), []byte(`"content":"second"`))
+	completion := bytes.Index([]byte(body), []byte("event: completion"))
+	require.True(t, first >= 0 && second >= 0 && completion >= 0, body)
+	assert.Less(t, first, second)
+	assert.Less(t, second, completion)
+
+	t.Run("streams raw events", func(t *testing.T) {
+		autogenClient.SetInvokeResponse("raw", &autogen_fake.InvokeResponse{
+			Events: []*autogen_client.SseEvent{
+				{ID: "42", Event: " message", Data: []byte(` {"type":"TextMessage","content":"raw"}`)},
+			},
+		})
+		req := newRequest("invoke/stream", "raw")
+		req.URL.RawQuery += "&raw=true"
+
+		w := httptest.NewRecorder()
+		sessions.HandleSessionInvokeStream(&testErrorResponseWriter{w}, req)
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "id:42\nevent: message\ndata: {\"type\":\"TextMessage\",\"content\":\"raw\"}\n\n", w.Body.String())
+	})
+
+	t.Run("streams raw events requested in the body", func(t *testing.T) {
+		body, _ := json.Marshal(&autogen_client.InvokeRequest{Task: "raw", TeamConfig: &api.Component{}, Raw: true})
+		req := newRequest("invoke/stream", "raw")
+		req.Body = io.NopCloser(bytes.NewReader(body))
+
+		w := httptest.NewRecorder()
+		sessions.HandleSessionInvokeStream(&testErrorResponseWriter{w}, req)
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "id:42\nevent: message\ndata: {\"type\":\"TextMessage\",\"content\":\"raw\"}\n\n", w.Body.String())
+	})
+
+	t.Run("refuses to resume raw streams", func(t *testing.T) {
+		req := newRequest("invoke/stream", "raw")
+		req.URL.RawQuery += "&raw=true"
+		req.Header.Set("Last-Event-ID", "1")
+
+		w := httptest.NewRecorder()
+		sessions.HandleSessionInvokeStream(&testErrorResponseWriter{w}, req)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestHandleDeleteSessionWithRunInProgress(t *testing.T) {
+	sessions, autogenClient, newRequest := setupSessionInvoke(t)
+	autogenClient.InvokeDelay = time.Minute
+
+	invoked := make(chan *httptest.ResponseRecorder)
+	go func() {
+		w := httptest.NewRecorder()
+		sessions.HandleSessionInvoke(&testErrorResponseWriter{w}, newRequest("invoke", "long task"))
+		invoked <- w
+	}()
+
+	deleteRequest := newRequest("", "")
+	deleteRequest.Method = http.MethodDelete
+	key := streamKey{sessionID: mustAtoi(t, mux.Vars(deleteRequest)["sessionID"])}
+	require.Eventually(t, func() bool { return sessions.runs.active(key) == 1 }, time.Second, 10*time.Millisecond)
+
+	t.Run("refuses to delete without force", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		sessions.HandleDeleteSession(&testErrorResponseWriter{w}, deleteRequest)
+		assert.Equal(t, http.StatusConflict, w.Code)
+		assert.Contains(t, w.Body.String(), "1 run(s) in progress")
+		assert.Equal(t, 1, sessions.runs.active(key))
+	})
+
+	t.Run("stops the run and deletes with force", func(t *testing.T) {
+		deleteRequest.URL.RawQuery += "&force=true"
+		w := httptest.NewRecorder()
+		sessions.HandleDeleteSession(&testErrorResponseWriter{w}, deleteRequest)
+		require.Equal(t, http.StatusOK, w.Code)
+
+		select {
+		case w := <-invoked:
+			assert.Equal(t, http.StatusConflict, w.Code)
+			assert.Contains(t, w.Body.String(), "Session was deleted and its run stopped")
+		case <-time.After(time.Second):
+			t.Fatal("run was not stopped")
+		}
+		assert.Equal(t, 0, sessions.runs.active(key))
+	})
+}
+
+func TestHandleSessionInvokeClientDisconnect(t *testing.T) {
+	sessions, autogenClient, newRequest := setupSessionInvoke(t)
+	autogenClient.InvokeDelay = time.Minute
+
+	req := newRequest("invoke", "long task")
+	ctx, disconnect := context.WithCancel(req.Context())
+	defer disconnect()
+	req = req.WithContext(ctx)
+	sessionID := mustAtoi(t, mux.Vars(req)["sessionID"])
+
+	invoked := make(chan *httptest.ResponseRecorder)
+	go func() {
+		w := httptest.NewRecorder()
+		sessions.HandleSessionInvoke(&testErrorResponseWriter{w}, req)
+		invoked <- w
+	}()
+	require.Eventually(t, func() bool { return sessions.runs.active(streamKey{sessionID: sessionID}) == 1 }, time.Second, 10*time.Millisecond)
+	userID := req.URL.Query().Get("user_id")
+
+	t.Run("refuses a concurrent invocation", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		sessions.HandleSessionInvoke(&testErrorResponseWriter{w}, newRequest("invoke", "other task"))
+		assert.Equal(t, http.StatusConflict, w.Code)
+		assert.Contains(t, w.Body.String(), "Session has a run in progress")
+	})
+
+	t.Run("stops the run of the invocation on disconnect", func(t *testing.T) {
+		var runs []*autogen_client.Run
+		require.Eventually(t, func() bool {
+			runs, _ = autogenClient.ListSessionRuns(sessionID, userID)
+			return len(runs) == 1
+		}, time.Second, 10*time.Millisecond)
+
+		disconnect()
+		select {
+		case w := <-invoked:
+			assert.Equal(t, http.StatusConflict, w.Code)
+			assert.Contains(t, w.Body.String(), "Client disconnected")
+		case <-time.After(time.Second):
+			t.Fatal("invocation was not cancelled")
+		}
+
+		stopped, err := autogenClient.GetRun(runs[0].ID)
+		require.NoError(t, err)
+		assert.Equal(t, autogen_client.RunStatusStopped, stopped.Status)
+		assert.Equal(t, DisconnectedRunMessage, stopped.ErrorMessage)
+	})
+}
+
+func mustAtoi(t *testing.T, s string) int {
+	i, err := strconv.Atoi(s)
+	require.NoError(t, err)
+	return i
+}
+
+func TestHandleRerunSessionRun(t *testing.T) {
+	handler, userID := setupTestHandler()
+	autogenClient := handler.AutogenClient.(*autogen_fake.InMemoryAutogenClient)
+	sessions := NewSessionsHandler(handler.Base)
+	session, err := autogenClient.CreateSession(&autogen_client.CreateSession{Name: "session", UserID: userID})
+	require.NoError(t, err)
+	_, err = autogenClient.CreateRun(&autogen_client.CreateRunRequest{SessionID: session.ID, UserID: userID})
+	require.NoError(t, err)
+	runs, err := autogenClient.ListSessionRuns(session.ID, userID)
+	require.NoError(t, err)
+	parent := runs[0]
+	parent.Status = autogen_client.RunStatusComplete
+	parent.Task = autogen_client.Task{Source: "user", Content: "lsit the pods"}
+
+	rerun := func(runID int, user string, body interface{}) *httptest.ResponseRecorder {
+		var buf bytes.Buffer
+		if body != nil {
+			require.NoError(t, json.NewEncoder(&buf).Encode(body))
+		}
+		req := httptest.NewRequest("POST", fmt.Sprintf("/api/sessions/%d/runs/%d/rerun?user_id=%s", session.ID, runID, user), &buf)
+		req = mux.SetURLVars(req, map[string]string{"sessionID": strconv.Itoa(session.ID), "runID": strconv.Itoa(runID)})
+		w := httptest.NewRecorder()
+		sessions.HandleRerunSessionRun(&testErrorResponseWriter{w}, req)
+		return w
+	}
+	teamConfig := &api.Component{}
+
+	t.Run("re-runs the run with the edited task", func(t *testing.T) {
+		w := rerun(parent.ID, userID, &autogen_client.RerunRequest{Task: "list the pods", TeamConfig: teamConfig})
+		require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+
+		var run autogen_client.Run
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &run))
+		assert.NotEqual(t, parent.ID, run.ID)
+		assert.Equal(t, "list the pods", run.Task.Content)
+		require.NotNil(t, run.ParentRunID)
+		assert.Equal(t, parent.ID, *run.ParentRunID)
+
+		assert.Equal(t, "lsit the pods", parent.Task.Content)
+		assert.Equal(t, autogen_client.RunStatusComplete, parent.Status)
+	})
+
+	t.Run("re-runs the run's own task without a body task", func(t *testing.T) {
+		w := rerun(parent.ID, userID, &autogen_client.RerunRequest{TeamConfig: teamConfig})
+		require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+		assert.Contains(t, w.Body.String(), "lsit the pods")
+	})
+
+	t.Run("returns 404 for an unknown run", func(t *testing.T) {
+		w := rerun(42, userID, &autogen_client.RerunRequest{TeamConfig: teamConfig})
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Contains(t, w.Body.String(), "Run not found")
+	})
+
+	t.Run("returns 404 for the session of another user", func(t *testing.T) {
+		w := rerun(parent.ID, "bob@example.com", &autogen_client.RerunRequest{TeamConfig: teamConfig})
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Contains(t, w.Body.String(), "Session not found")
+	})
+
+	t.Run("returns 422 for a session without an agent and no team config", func(t *testing.T) {
+		w := rerun(parent.ID, userID, nil)
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+		assert.Contains(t, w.Body.String(), "Session has no agent bound")
+	})
+}
+
+func TestHandleExportSessionRun(t *testing.T) {
+	handler, userID := setupTestHandler()
+	autogenClient := handler.AutogenClient.(*autogen_fake.InMemoryAutogenClient)
+	sessions := NewSessionsHandler(handler.Base)
+	session, err := autogenClient.CreateSession(&autogen_client.CreateSession{Name: "session", UserID: userID})
+	require.NoError(t, err)
+	_, err = autogenClient.CreateRun(&autogen_client.CreateRunRequest{SessionID: session.ID, UserID: userID})
+	require.NoError(t, err)
+	runs, err := autogenClient.ListSessionRuns(session.ID, userID)
+	require.NoError(t, err)
+	run := runs[0]
+	run.Status = autogen_client.RunStatusComplete
+	run.Task = autogen_client.Task{Source: "user", Content: "list the pods"}
+	run.Messages = []*autogen_client.RunMessage{
+		{Config: map[string]interface{}{"type": "TextMessage", "source": "user", "content": "list the pods"}},
+		{Config: map[string]interface{}{"type": "ToolCallRequestEvent", "source": "k8s_agent", "content": []interface{}{
+			map[string]interface{}{"id": "call_1", "name": "k8s_get_resources", "arguments": `{"resource_type":"pod"}`},
+		}}},
+		{Config: map[string]interface{}{"type": "ToolCallExecutionEvent", "source": "k8s_agent", "content": []interface{}{
+			map[string]interface{}{"call_id": "call_1", "content": "nginx-1 Running"},
+		}}},
+		{Config: map[string]interface{}{"type": "TextMessage", "source": "k8s_agent", "content": "nginx-1 is running"}},
+	}
+
+	export := func(runID int, user, format string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", fmt.Sprintf("/api/sessions/%d/runs/%d/export?user_id=%s&format=%s", session.ID, runID, user, format), nil)
+		req = mux.SetURLVars(req, map[string]string{"sessionID": strconv.Itoa(session.ID), "runID": strconv.Itoa(runID)})
+		w := httptest.NewRecorder()
+		sessions.HandleExportSessionRun(&testErrorResponseWriter{w}, req)
+		return w
+	}
+
+	t.Run("exports the run as Markdown", func(t *testing.T) {
+		w := export(run.ID, userID, "")
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		assert.Equal(t, "text/markdown; charset=utf-8", w.Header().Get("Content-Type"))
+		body := w.Body.String()
+		assert.Contains(t, body, "## Task\n\nlist the pods")
+		assert.Contains(t, body, "### k8s_agent called k8s_get_resources\n\n```json\n{\n  \"resource_type\": \"pod\"\n}\n```")
+		assert.Contains(t, body, "### Result of call_1\n\n```\nnginx-1 Running\n```")
+		assert.Contains(t, body, "## Final answer\n\nnginx-1 is running")
+	})
+
+	t.Run("exports the run as JSON", func(t *testing.T) {
+		w := export(run.ID, userID, "json")
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var runExport autogen_client.RunExport
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &runExport))
+		assert.Equal(t, run.ID, runExport.RunID)
+		assert.Len(t, runExport.Messages, 4)
+		assert.Equal(t, "nginx-1 is running", runExport.FinalAnswer)
+	})
+
+	t.Run("returns 400 for an unknown format", func(t *testing.T) {
+		assert.Equal(t, http.StatusBadRequest, export(run.ID, userID, "pdf").Code)
+	})
+
+	t.Run("returns 404 for an unknown run", func(t *testing.T) {
+		assert.Equal(t, http.StatusNotFound, export(42, userID, "md").Code)
+	})
+
+	t.Run("returns 404 for the session of another user", func(t *testing.T) {
+		assert.Equal(t, http.StatusNotFound, export(run.ID, "bob@example.com", "md").Code)
+	})
+}
+
+func TestHandleGetSessionStats(t *testing.T) {
+	handler, userID := setupTestHandler()
+	autogenClient := handler.AutogenClient.(*autogen_fake.InMemoryAutogenClient)
+	sessions := NewSessionsHandler(handler.Base)
+	session, err := autogenClient.CreateSession(&autogen_client.CreateSession{Name: "session", UserID: userID})
+	require.NoError(t, err)
+
+	getStats := func() *autogen_client.SessionStats {
+		req := httptest.NewRequest("GET", fmt.Sprintf("/api/sessions/%d/stats?user_id=%s", session.ID, userID), nil)
+		req = mux.SetURLVars(req, map[string]string{"sessionID": strconv.Itoa(session.ID)})
+		w := httptest.NewRecorder()
+		sessions.HandleGetSessionStats(&testErrorResponseWriter{w}, req)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+		var response struct {
+			Data *autogen_client.SessionStats `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		return response.Data
+	}
+
+	t.Run("returns zeros for a session without runs", func(t *testing.T) {
+		assert.Equal(t, &autogen_client.SessionStats{SessionID: session.ID}, getStats())
+	})
+
+	t.Run("aggregates the runs", func(t *testing.T) {
+		for i := 0; i < 3; i++ {
+			_, err := autogenClient.CreateRun(&autogen_client.CreateRunRequest{SessionID: session.ID, UserID: userID})
+			require.NoError(t, err)
+		}
+		runs, err := autogenClient.ListSessionRuns(session.ID, userID)
+		require.NoError(t, err)
+		usage := map[string]interface{}{"prompt_tokens": 10, "completion_tokens": 5}
+		for i, status := range []string{autogen_client.RunStatusComplete, autogen_client.RunStatusError, autogen_client.RunStatusActive} {
+			runs[i].Status = status
+			runs[i].TeamResult.Duration = float64(2 * (i + 1))
+			runs[i].Messages = []*autogen_client.RunMessage{
+				{Config: map[string]interface{}{"type": "TextMessage", "source": "agent", "content": "done", "models_usage": usage}},
+			}
+		}
+
+		stats := getStats()
+		assert.Equal(t, 3, stats.RunCount)
+		assert.Equal(t, 1, stats.CompletedRuns)
+		assert.Equal(t, 1, stats.FailedRuns)
+		assert.Equal(t, 0.5, stats.SuccessRate, "runs in progress aren't counted")
+		assert.Equal(t, 30, stats.PromptTokens)
+		assert.Equal(t, 45, stats.TotalTokens)
+		assert.Equal(t, 4.0, stats.AverageDuration)
+	})
+}
+
+func TestHandlePatchSession(t *testing.T) {
+	handler, userID := setupTestHandler()
+	autogenClient := handler.AutogenClient.(*autogen_fake.InMemoryAutogenClient)
+	sessions := NewSessionsHandler(handler.Base)
+	teamID := 3
+	session, err := autogenClient.CreateSession(&autogen_client.CreateSession{Name: "session", UserID: userID, TeamID: &teamID})
+	require.NoError(t, err)
+
+	patch := func(body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("PATCH", fmt.Sprintf("/api/sessions/%d?user_id=%s", session.ID, userID), bytes.NewBufferString(body))
+		req = mux.SetURLVars(req, map[string]string{"sessionID": strconv.Itoa(session.ID)})
+		w := httptest.NewRecorder()
+		sessions.HandlePatchSession(&testErrorResponseWriter{w}, req)
+		return w
+	}
+
+	t.Run("keeps omitted fields", func(t *testing.T) {
+		w := patch(`{"name":"renamed"}`)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+		stored, err := autogenClient.GetSessionById(session.ID, userID)
+		require.NoError(t, err)
+		assert.Equal(t, "renamed", stored.Name)
+		require.NotNil(t, stored.TeamID)
+		assert.Equal(t, teamID, *stored.TeamID)
+	})
+
+	t.Run("clears fields set to null", func(t *testing.T) {
+		w := patch(`{"team_id":null}`)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+		stored, err := autogenClient.GetSessionById(session.ID, userID)
+		require.NoError(t, err)
+		assert.Nil(t, stored.TeamID)
+		assert.Equal(t, "renamed", stored.Name)
+	})
+
+	t.Run("rejects invalid patches", func(t *testing.T) {
+		for _, body := range []string{`["name"]`, `null`, `{"unknown":true}`, `{"name":5}`} {
+			w := patch(body)
+			assert.Equal(t, http.StatusBadRequest, w.Code, body)
+		}
+		stored, err := autogenClient.GetSessionById(session.ID, userID)
+		require.NoError(t, err)
+		assert.Equal(t, "renamed", stored.Name)
+	})
+}
@@ -0,0 +1,156 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// streamRetention is how long the frames of a finished session stream are kept for clients resuming it
+const streamRetention = 5 * time.Minute
+
+// maxStreamFrames and maxStreamBytes bound the frames a stream buffer keeps. Once either is
+// exceeded the oldest frames are dropped, and clients resuming before them miss them.
+const (
+	maxStreamFrames = 1000
+	maxStreamBytes  = 4 << 20
+)
+
+// sseFrame is a single server-sent event. Its id is its 1-based position in the stream.
+type sseFrame struct {
+	Event string
+	Data  []byte
+}
+
+// streamBuffer records the frames of a session stream so clients can resume it with Last-Event-ID
+type streamBuffer struct {
+	mu        sync.Mutex
+	maxFrames int
+	maxBytes  int
+	frames    []sseFrame
+	// dropped is the number of frames dropped from the front of frames, so frames[0] has id dropped+1
+	dropped int
+	bytes   int
+	done    bool
+	// notify is closed and replaced whenever a frame is appended or the stream finishes
+	notify chan struct{}
+}
+
+func newStreamBuffer() *streamBuffer {
+	return &streamBuffer{maxFrames: maxStreamFrames, maxBytes: maxStreamBytes, notify: make(chan struct{})}
+}
+
+// append adds frame to the buffer, dropping the oldest frames if it is full. The newest frame
+// is always kept.
+func (b *streamBuffer) append(frame sseFrame) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.frames = append(b.frames, frame)
+	b.bytes += frameSize(frame)
+	for len(b.frames) > 1 && (len(b.frames) > b.maxFrames || b.bytes > b.maxBytes) {
+		b.bytes -= frameSize(b.frames[0])
+		b.frames[0] = sseFrame{}
+		b.frames = b.frames[1:]
+		b.dropped++
+	}
+	close(b.notify)
+	b.notify = make(chan struct{})
+}
+
+func (b *streamBuffer) finish() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.done = true
+	close(b.notify)
+	b.notify = make(chan struct{})
+}
+
+func frameSize(frame sseFrame) int {
+	return len(frame.Event) + len(frame.Data)
+}
+
+// since returns the frames after the given id, or from the oldest frame kept if those were
+// dropped, along with the id of the frame before the first returned, whether the stream has
+// finished, and a channel that is closed when more frames are available
+func (b *streamBuffer) since(id int) ([]sseFrame, int, bool, <-chan struct{}) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if id < b.dropped {
+		id = b.dropped
+	}
+	if id > b.dropped+len(b.frames) {
+		id = b.dropped + len(b.frames)
+	}
+	return b.frames[id-b.dropped:], id, b.done, b.notify
+}
+
+// follow writes the frames after the given id to w as they arrive, until the stream
+// finishes or ctx is done
+func (b *streamBuffer) follow(ctx context.Context, w ErrorResponseWriter, after int) {
+	next := after
+	for {
+		frames, before, done, notify := b.since(next)
+		next = before
+		for _, frame := range frames {
+			next++
+			w.Write([]byte(fmt.Sprintf("id: %d\nevent: %s\ndata: %s\n\n", next, frame.Event, frame.Data)))
+		}
+		if len(frames) > 0 {
+			w.Flush()
+		}
+		if done {
+			return
+		}
+
+		select {
+		case <-notify:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// streamKey identifies a session's stream. Session IDs are only unique within an Autogen
+// backend, so the key includes the backend URL, empty for the default backend.
+type streamKey struct {
+	backend   string
+	sessionID int
+}
+
+// streamRegistry holds the buffer of the most recent stream of each session
+type streamRegistry struct {
+	mu      sync.Mutex
+	streams map[streamKey]*streamBuffer
+}
+
+func newStreamRegistry() *streamRegistry {
+	return &streamRegistry{streams: map[streamKey]*streamBuffer{}}
+}
+
+// start registers a new buffer for the session, replacing any previous stream
+func (s *streamRegistry) start(key streamKey) *streamBuffer {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	buffer := newStreamBuffer()
+	s.streams[key] = buffer
+	return buffer
+}
+
+func (s *streamRegistry) get(key streamKey) *streamBuffer {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.streams[key]
+}
+
+// finish marks the stream as done and drops it once the retention period has passed
+func (s *streamRegistry) finish(key streamKey, buffer *streamBuffer) {
+	buffer.finish()
+	time.AfterFunc(streamRetention, func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if s.streams[key] == buffer {
+			delete(s.streams, key)
+		}
+	})
+}
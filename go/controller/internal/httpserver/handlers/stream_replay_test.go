@@ -0,0 +1,132 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kagent-dev/kagent/go/autogen/api"
+	autogen_client "github.com/kagent-dev/kagent/go/autogen/client"
+)
+
+func TestSessionInvokeStreamResume(t *testing.T) {
+	handler, userID := setupTestHandler()
+	sessions := NewSessionsHandler(handler.Base)
+
+	session, err := handler.AutogenClient.CreateSession(&autogen_client.CreateSession{Name: "session", UserID: userID})
+	require.NoError(t, err)
+
+	invoke := func(sessionID int, lastEventID string) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(&autogen_client.InvokeRequest{Task: "hello", TeamConfig: &api.Component{}})
+		req := httptest.NewRequest("POST", fmt.Sprintf("/api/sessions/%d/invoke/stream?user_id=%s", sessionID, userID), bytes.NewBuffer(body))
+		req = mux.SetURLVars(req, map[string]string{"sessionID": fmt.Sprintf("%d", sessionID)})
+		if lastEventID != "" {
+			req.Header.Set("Last-Event-ID", lastEventID)
+		}
+		w := httptest.NewRecorder()
+		sessions.HandleSessionInvokeStream(&testErrorResponseWriter{w}, req)
+		return w
+	}
+
+	t.Run("frames carry ids", func(t *testing.T) {
+		w := invoke(session.ID, "")
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), "id: 1\nevent: message\ndata: ")
+	})
+
+	t.Run("resume replays frames after the last event id", func(t *testing.T) {
+		w := invoke(session.ID, "0")
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), "id: 1\n")
+
+		w = invoke(session.ID, "1")
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Empty(t, w.Body.String())
+	})
+
+	t.Run("invalid last event id", func(t *testing.T) {
+		w := invoke(session.ID, "abc")
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("expired stream of a completed run replays stored messages", func(t *testing.T) {
+		other, err := handler.AutogenClient.CreateSession(&autogen_client.CreateSession{Name: "other", UserID: userID})
+		require.NoError(t, err)
+		_, err = handler.AutogenClient.CreateRun(&autogen_client.CreateRunRequest{SessionID: other.ID, UserID: userID})
+		require.NoError(t, err)
+
+		runs, err := handler.AutogenClient.ListSessionRuns(other.ID, userID)
+		require.NoError(t, err)
+		require.Len(t, runs, 1)
+
+		runs[0].Status = "active"
+		w := invoke(other.ID, "3")
+		assert.Equal(t, http.StatusConflict, w.Code)
+
+		runs[0].Status = "complete"
+		runs[0].Messages = []*autogen_client.RunMessage{{Config: map[string]interface{}{"content": "stored"}}}
+		w = invoke(other.ID, "3")
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `data: {"content":"stored"}`)
+		assert.Contains(t, w.Body.String(), `event: completion`)
+		assert.Contains(t, w.Body.String(), `"status":"success"`)
+	})
+
+	t.Run("no stream to resume", func(t *testing.T) {
+		w := invoke(999, "1")
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
+
+func TestStreamBufferDropsOldestFrames(t *testing.T) {
+	frame := func(i int) sseFrame { return sseFrame{Event: "message", Data: []byte(fmt.Sprintf("%02d", i))} }
+
+	t.Run("beyond the frame limit", func(t *testing.T) {
+		buffer := newStreamBuffer()
+		buffer.maxFrames = 3
+		for i := 1; i <= 5; i++ {
+			buffer.append(frame(i))
+		}
+
+		frames, before, _, _ := buffer.since(0)
+		assert.Equal(t, 2, before)
+		assert.Equal(t, []sseFrame{frame(3), frame(4), frame(5)}, frames)
+
+		frames, before, _, _ = buffer.since(3)
+		assert.Equal(t, 3, before)
+		assert.Equal(t, []sseFrame{frame(4), frame(5)}, frames)
+	})
+
+	t.Run("beyond the byte limit", func(t *testing.T) {
+		buffer := newStreamBuffer()
+		buffer.maxBytes = 2 * frameSize(frame(1))
+		for i := 1; i <= 4; i++ {
+			buffer.append(frame(i))
+		}
+
+		frames, before, _, _ := buffer.since(1)
+		assert.Equal(t, 2, before)
+		assert.Equal(t, []sseFrame{frame(3), frame(4)}, frames)
+	})
+
+	t.Run("resume keeps the ids of the kept frames", func(t *testing.T) {
+		buffer := newStreamBuffer()
+		buffer.maxFrames = 2
+		for i := 1; i <= 4; i++ {
+			buffer.append(frame(i))
+		}
+		buffer.finish()
+
+		w := httptest.NewRecorder()
+		buffer.follow(context.Background(), &testErrorResponseWriter{w}, 1)
+		assert.Equal(t, "id: 3\nevent: message\ndata: 03\n\nid: 4\nevent: message\ndata: 04\n\n", w.Body.String())
+	})
+}
@@ -0,0 +1,282 @@
+package handlers
+
+import (
+	"container/list"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	autogen_client "github.com/kagent-dev/kagent/go/autogen/client"
+	"github.com/kagent-dev/kagent/go/controller/internal/httpserver/errors"
+	ctrllog "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const (
+	// maxSummaryChunkChars bounds the transcript sent in a single summarization task. Longer
+	// histories are summarized chunk by chunk and the chunk summaries summarized again.
+	maxSummaryChunkChars = 12000
+
+	// maxCachedSummaries bounds the summaries kept in memory, the least recently used are
+	// evicted first
+	maxCachedSummaries = 1000
+
+	summaryPrompt = "Summarize the following conversation between a user and an AI agent in a few " +
+		"sentences. Focus on what the user asked for, what was done and any open questions. " +
+		"Reply with the summary only.\n\n"
+	combineSummariesPrompt = "The following are summaries of consecutive parts of one conversation " +
+		"between a user and an AI agent. Combine them into a single summary of a few sentences. " +
+		"Reply with the summary only.\n\n"
+)
+
+// SessionSummary is returned by HandleGetSessionSummary
+type SessionSummary struct {
+	SessionID    int    `json:"session_id"`
+	Summary      string `json:"summary"`
+	MessageCount int    `json:"message_count"`
+	// LastMessageID is the id of the last message covered by the summary
+	LastMessageID int `json:"last_message_id,omitempty"`
+}
+
+type summaryKey struct {
+	sessionID int
+	userID    string
+}
+
+// summaryCache keeps the latest summary of each session until new messages arrive, for up to
+// maxEntries sessions
+type summaryCache struct {
+	mu         sync.Mutex
+	maxEntries int
+	// order holds the keys from most to least recently used
+	order   *list.List
+	entries map[summaryKey]*list.Element
+}
+
+type summaryEntry struct {
+	key     summaryKey
+	summary *SessionSummary
+}
+
+func newSummaryCache() *summaryCache {
+	return &summaryCache{
+		maxEntries: maxCachedSummaries,
+		order:      list.New(),
+		entries:    make(map[summaryKey]*list.Element),
+	}
+}
+
+// get returns the cached summary if it still covers the session's messages
+func (c *summaryCache) get(key summaryKey, messageCount, lastMessageID int) *SessionSummary {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	element, ok := c.entries[key]
+	if !ok {
+		return nil
+	}
+	summary := element.Value.(*summaryEntry).summary
+	if summary.MessageCount != messageCount || summary.LastMessageID != lastMessageID {
+		return nil
+	}
+	c.order.MoveToFront(element)
+	return summary
+}
+
+func (c *summaryCache) set(key summaryKey, summary *SessionSummary) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if element, ok := c.entries[key]; ok {
+		element.Value.(*summaryEntry).summary = summary
+		c.order.MoveToFront(element)
+		return
+	}
+	c.entries[key] = c.order.PushFront(&summaryEntry{key: key, summary: summary})
+	for c.order.Len() > c.maxEntries {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*summaryEntry).key)
+	}
+}
+
+// HandleGetSessionSummary handles GET /api/sessions/{sessionID}/summary requests. The summary
+// is generated by the session's agent and cached until new messages are added to the session.
+func (h *SessionsHandler) HandleGetSessionSummary(w ErrorResponseWriter, r *http.Request) {
+	log := ctrllog.FromContext(r.Context()).WithName("sessions-handler").WithValues("operation", "summary")
+
+	sessionID, err := GetIntPathParam(r, "sessionID")
+	if err != nil {
+		w.RespondWithError(errors.NewBadRequestError("Failed to get session ID from path", err))
+		return
+	}
+	log = log.WithValues("sessionID", sessionID)
+
+	userID, err := GetUserID(r)
+	if err != nil {
+		w.RespondWithError(errors.NewBadRequestError("Failed to get user ID", err))
+		return
+	}
+	log = log.WithValues("userID", userID)
+
+	session, err := h.AutogenClient.GetSessionById(sessionID, userID)
+	if err != nil {
+		w.RespondWithError(sessionError("Failed to get session", err))
+		return
+	}
+	if session == nil {
+		w.RespondWithError(errors.NewNotFoundError("Session not found", nil).WithCode(errors.CodeSessionNotFound))
+		return
+	}
+
+	runs, err := h.AutogenClient.ListSessionRuns(sessionID, userID)
+	if err != nil {
+		w.RespondWithError(sessionError("Failed to list session runs", err))
+		return
+	}
+	var messages []*autogen_client.RunMessage
+	for _, run := range runs {
+		messages = append(messages, run.Messages...)
+	}
+
+	if len(messages) == 0 {
+		RespondWithJSON(w, http.StatusOK, NewResponse(&SessionSummary{SessionID: sessionID}, "Session has no messages to summarize"))
+		return
+	}
+
+	key := summaryKey{sessionID: sessionID, userID: userID}
+	lastMessageID := messages[len(messages)-1].ID
+	if summary := h.summaries.get(key, len(messages), lastMessageID); summary != nil {
+		log.V(1).Info("Returning cached session summary")
+		RespondWithJSON(w, http.StatusOK, NewResponse(summary, "Successfully summarized session"))
+		return
+	}
+
+	if session.TeamID == nil {
+		w.RespondWithError(errors.NewValidationError("Session has no agent to generate the summary", nil))
+		return
+	}
+	team, err := h.AutogenClient.GetTeamByID(*session.TeamID, userID)
+	if err != nil {
+		w.RespondWithError(errors.NewInternalServerError("Failed to get the session's agent", err))
+		return
+	}
+
+	autogenClient, _, err := h.autogenClientFor(r)
+	if err != nil {
+		w.RespondWithError(errors.NewBadRequestError("Invalid Autogen URL", err))
+		return
+	}
+
+	ctx, cancel, _, err := withMaxDuration(r)
+	if err != nil {
+		w.RespondWithError(errors.NewBadRequestError("Invalid max duration", err))
+		return
+	}
+	defer cancel()
+
+	summarize := func(prompt, text string) (string, error) {
+		result, err := autogenClient.InvokeTask(ctx, &autogen_client.InvokeTaskRequest{
+			Task:       prompt + text,
+			TeamConfig: team.Component,
+		})
+		if err != nil {
+			return "", err
+		}
+		return lastReply(result.TaskResult.Messages), nil
+	}
+
+	chunks := transcriptChunks(messages, maxSummaryChunkChars)
+	log.V(1).Info("Summarizing session", "messages", len(messages), "chunks", len(chunks))
+
+	var summaries []string
+	for _, chunk := range chunks {
+		summary, err := summarize(summaryPrompt, chunk)
+		if err != nil {
+			w.RespondWithError(errors.NewInternalServerError("Failed to summarize session", err))
+			return
+		}
+		summaries = append(summaries, summary)
+	}
+	text := summaries[0]
+	if len(summaries) > 1 {
+		text, err = summarize(combineSummariesPrompt, strings.Join(summaries, "\n\n"))
+		if err != nil {
+			w.RespondWithError(errors.NewInternalServerError("Failed to summarize session", err))
+			return
+		}
+	}
+
+	summary := &SessionSummary{
+		SessionID:     sessionID,
+		Summary:       text,
+		MessageCount:  len(messages),
+		LastMessageID: lastMessageID,
+	}
+	h.summaries.set(key, summary)
+
+	log.Info("Successfully summarized session")
+	RespondWithJSON(w, http.StatusOK, NewResponse(summary, "Successfully summarized session"))
+}
+
+// transcriptChunks renders messages as "source: content" lines and splits them into chunks
+// of at most maxChars bytes. A single line longer than maxChars is truncated at a rune boundary.
+func transcriptChunks(messages []*autogen_client.RunMessage, maxChars int) []string {
+	var chunks []string
+	var current strings.Builder
+	for _, message := range messages {
+		line := transcriptLine(message)
+		if len(line) > maxChars {
+			line = truncateRunes(line, maxChars)
+		}
+		if current.Len() > 0 && current.Len()+len(line) > maxChars {
+			chunks = append(chunks, current.String())
+			current.Reset()
+		}
+		current.WriteString(line)
+	}
+	if current.Len() > 0 {
+		chunks = append(chunks, current.String())
+	}
+	return chunks
+}
+
+// truncateRunes returns the longest prefix of s of at most maxBytes that doesn't split a rune
+func truncateRunes(s string, maxBytes int) string {
+	end := maxBytes
+	for end > 0 && !utf8.RuneStart(s[end]) {
+		end--
+	}
+	return s[:end]
+}
+
+func transcriptLine(message *autogen_client.RunMessage) string {
+	source, _ := message.Config["source"].(string)
+	if source == "" {
+		source = "unknown"
+	}
+	content, ok := message.Config["content"].(string)
+	if !ok {
+		// Tool calls and their results are lists, include them as JSON
+		b, _ := json.Marshal(message.Config["content"])
+		content = string(b)
+	}
+	return fmt.Sprintf("%s: %s\n", source, content)
+}
+
+// lastReply returns the text content of the last message not sent by the user
+func lastReply(messages []json.RawMessage) string {
+	for i := len(messages) - 1; i >= 0; i-- {
+		var message struct {
+			Source  string      `json:"source"`
+			Content interface{} `json:"content"`
+		}
+		if err := json.Unmarshal(messages[i], &message); err != nil || message.Source == "user" {
+			continue
+		}
+		if content, ok := message.Content.(string); ok && content != "" {
+			return content
+		}
+	}
+	return ""
+}
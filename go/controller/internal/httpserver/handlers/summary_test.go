@@ -0,0 +1,143 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kagent-dev/kagent/go/autogen/api"
+	autogen_client "github.com/kagent-dev/kagent/go/autogen/client"
+	autogen_fake "github.com/kagent-dev/kagent/go/autogen/client/fake"
+)
+
+func TestHandleGetSessionSummary(t *testing.T) {
+	handler, userID := setupTestHandler()
+	autogenClient := handler.AutogenClient.(*autogen_fake.InMemoryAutogenClient)
+	sessions := NewSessionsHandler(handler.Base)
+
+	require.NoError(t, autogenClient.CreateTeam(&autogen_client.Team{
+		BaseObject: autogen_client.BaseObject{Id: 1, UserID: userID},
+		Component:  &api.Component{Label: "default/test-agent"},
+	}))
+	session, err := autogenClient.CreateSession(&autogen_client.CreateSession{Name: "session", UserID: userID})
+	require.NoError(t, err)
+	teamID := 1
+	session.TeamID = &teamID
+	reply := func(summary string) {
+		autogenClient.SetInvokeResponse("", &autogen_fake.InvokeResponse{
+			TaskResult: autogen_client.TaskResult{Messages: []json.RawMessage{
+				json.RawMessage(`{"type":"TextMessage","content":"thinking","source":"k8s_agent"}`),
+				json.RawMessage(`{"type":"TextMessage","content":"` + summary + `","source":"k8s_agent"}`),
+			}},
+		})
+	}
+
+	summarize := func() (*httptest.ResponseRecorder, *SessionSummary) {
+		req := httptest.NewRequest("GET", fmt.Sprintf("/api/sessions/%d/summary?user_id=%s", session.ID, userID), nil)
+		req = mux.SetURLVars(req, map[string]string{"sessionID": fmt.Sprintf("%d", session.ID)})
+		w := httptest.NewRecorder()
+		sessions.HandleGetSessionSummary(&testErrorResponseWriter{w}, req)
+		if w.Code != http.StatusOK {
+			return w, nil
+		}
+		var summary SessionSummary
+		require.NoError(t, DecodeResponseData(w.Body.Bytes(), &summary))
+		return w, &summary
+	}
+
+	t.Run("returns a notice for an empty session", func(t *testing.T) {
+		w, summary := summarize()
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), "Session has no messages to summarize")
+		assert.Empty(t, summary.Summary)
+	})
+
+	_, err = autogenClient.CreateRun(&autogen_client.CreateRunRequest{SessionID: session.ID, UserID: userID})
+	require.NoError(t, err)
+	runs, err := autogenClient.ListSessionRuns(session.ID, userID)
+	require.NoError(t, err)
+	runs[0].Messages = []*autogen_client.RunMessage{
+		{ID: 1, Config: map[string]interface{}{"source": "user", "content": "list the pods"}},
+		{ID: 2, Config: map[string]interface{}{"source": "k8s_agent", "content": "there are 3 pods"}},
+	}
+
+	t.Run("summarizes the history with the session's agent", func(t *testing.T) {
+		reply("The user listed 3 pods")
+		w, summary := summarize()
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "The user listed 3 pods", summary.Summary)
+		assert.Equal(t, 2, summary.MessageCount)
+		assert.Equal(t, 2, summary.LastMessageID)
+	})
+
+	t.Run("caches the summary until new messages arrive", func(t *testing.T) {
+		autogenClient.SetInvokeResponse("", &autogen_fake.InvokeResponse{Err: fmt.Errorf("model unavailable")})
+
+		w, _ := summarize()
+		require.Equal(t, http.StatusOK, w.Code)
+
+		runs[0].Messages = append(runs[0].Messages, &autogen_client.RunMessage{
+			ID: 3, Config: map[string]interface{}{"source": "user", "content": "thanks"},
+		})
+		w, _ = summarize()
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		assert.Contains(t, w.Body.String(), "Failed to summarize session")
+	})
+
+	t.Run("regenerates the summary after new messages", func(t *testing.T) {
+		reply("The user listed 3 pods and said thanks")
+		w, summary := summarize()
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "The user listed 3 pods and said thanks", summary.Summary)
+		assert.Equal(t, 3, summary.LastMessageID)
+	})
+}
+
+func TestTranscriptChunks(t *testing.T) {
+	messages := []*autogen_client.RunMessage{
+		{Config: map[string]interface{}{"source": "user", "content": "hello"}},
+		{Config: map[string]interface{}{"source": "agent", "content": strings.Repeat("a", 30)}},
+		{Config: map[string]interface{}{"source": "agent", "content": []interface{}{map[string]interface{}{"name": "get_pods"}}}},
+	}
+
+	chunks := transcriptChunks(messages, 50)
+	assert.Equal(t, []string{
+		"user: hello\nagent: " + strings.Repeat("a", 30) + "\n",
+		`agent: [{"name":"get_pods"}]` + "\n",
+	}, chunks)
+
+	// Lines longer than a chunk are truncated
+	chunks = transcriptChunks(messages[1:2], 10)
+	assert.Equal(t, []string{"agent: aaa"}, chunks)
+
+	// without splitting a rune
+	chunks = transcriptChunks([]*autogen_client.RunMessage{
+		{Config: map[string]interface{}{"source": "agent", "content": "héllo"}},
+	}, 9)
+	assert.Equal(t, []string{"agent: h"}, chunks)
+}
+
+func TestSummaryCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	cache := newSummaryCache()
+	cache.maxEntries = 2
+	set := func(sessionID int) {
+		cache.set(summaryKey{sessionID: sessionID}, &SessionSummary{SessionID: sessionID, MessageCount: 1})
+	}
+
+	set(1)
+	set(2)
+	require.NotNil(t, cache.get(summaryKey{sessionID: 1}, 1, 0))
+	set(3)
+
+	assert.NotNil(t, cache.get(summaryKey{sessionID: 1}, 1, 0))
+	assert.Nil(t, cache.get(summaryKey{sessionID: 2}, 1, 0))
+	assert.NotNil(t, cache.get(summaryKey{sessionID: 3}, 1, 0))
+	assert.Len(t, cache.entries, 2)
+}
@@ -1,16 +1,22 @@
 package handlers
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"strings"
 
+	jsonpatch "github.com/evanphx/json-patch/v5"
+	"github.com/go-logr/logr"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	ctrllog "sigs.k8s.io/controller-runtime/pkg/log"
 
 	"github.com/kagent-dev/kagent/go/autogen/api"
 	autogen_client "github.com/kagent-dev/kagent/go/autogen/client"
 	"github.com/kagent-dev/kagent/go/controller/api/v1alpha1"
+	"github.com/kagent-dev/kagent/go/controller/internal/a2a"
 	"github.com/kagent-dev/kagent/go/controller/internal/autogen"
 	"github.com/kagent-dev/kagent/go/controller/internal/client_wrapper"
 	"github.com/kagent-dev/kagent/go/controller/internal/httpserver/errors"
@@ -31,14 +37,18 @@ type TeamResponse struct {
 // TeamsHandler handles team-related requests
 type TeamsHandler struct {
 	*Base
+	modelProviders *modelProviderCache
 }
 
 // NewTeamsHandler creates a new TeamsHandler
 func NewTeamsHandler(base *Base) *TeamsHandler {
-	return &TeamsHandler{Base: base}
+	return &TeamsHandler{Base: base, modelProviders: newModelProviderCache()}
 }
 
-// HandleListTeams handles GET /api/teams requests
+// HandleListTeams handles GET /api/teams and GET /api/agents requests.
+// The optional skill query parameter keeps only agents whose A2A config has a skill with
+// that ID. Skills live in the Agent spec, so the filter runs on the controller's cached
+// view of the agents, before any call to Autogen.
 func (h *TeamsHandler) HandleListTeams(w ErrorResponseWriter, r *http.Request) {
 	log := ctrllog.FromContext(r.Context()).WithName("teams-handler").WithValues("operation", "list")
 	log.Info("Received request to list Teams")
@@ -50,18 +60,38 @@ func (h *TeamsHandler) HandleListTeams(w ErrorResponseWriter, r *http.Request) {
 	}
 	log = log.WithValues("userID", userID)
 
+	skill := r.URL.Query().Get("skill")
+	if skill != "" {
+		log = log.WithValues("skill", skill)
+	}
+
 	agentList := &v1alpha1.AgentList{}
 	if err := h.KubeClient.List(r.Context(), agentList); err != nil {
 		w.RespondWithError(errors.NewInternalServerError("Failed to list Teams from Kubernetes", err))
 		return
 	}
 
+	// List the Autogen teams once rather than once per agent
+	autogenTeams, err := h.AutogenClient.ListTeams(userID)
+	if err != nil {
+		w.RespondWithError(errors.NewInternalServerError("Failed to list Teams from Autogen", err))
+		return
+	}
+
 	teamsWithID := make([]TeamResponse, 0)
 	for _, team := range agentList.Items {
+		if err := r.Context().Err(); err != nil {
+			log.Info("Request cancelled, stopping list", "error", err.Error())
+			return
+		}
+		if skill != "" && !hasSkill(&team, skill) {
+			continue
+		}
+
 		teamRef := common.GetObjectRef(&team)
 		log.V(1).Info("Processing Team", "teamRef", teamRef)
 
-		autogenTeam, err := h.AutogenClient.GetTeam(teamRef, userID)
+		autogenTeam, err := autogen_client.FindAgentByRef(autogenTeams, teamRef)
 		if err != nil {
 			if err == autogen_client.NotFoundError {
 				log.V(1).Info("Team not found in Autogen", "teamRef", teamRef)
@@ -89,49 +119,8 @@ func (h *TeamsHandler) HandleListTeams(w ErrorResponseWriter, r *http.Request) {
 			continue
 		}
 
-		// Get the MemoryRefs for the team
-		memoryRefs := make([]string, 0, len(team.Spec.Memory))
-		for _, memory := range team.Spec.Memory {
-			memoryRef, err := common.ParseRefString(memory, team.Namespace)
-			if err != nil {
-				log.Error(err, "Failed to parse memory reference", "memoryRef", memory)
-				continue
-			}
-			memoryRefs = append(memoryRefs, memoryRef.String())
-		}
-
-		// Get the tools for the team
-		tools := make([]*v1alpha1.Tool, 0, len(team.Spec.Tools))
-		for _, tool := range team.Spec.Tools {
-			toolCopy := tool.DeepCopy()
-
-			switch toolCopy.Type {
-			case v1alpha1.ToolProviderType_Agent:
-				if toolCopy.Agent == nil {
-					log.Info("Agent tool has nil Agent field", "tool", toolCopy)
-					continue
-				}
-				if err := updateRef(&toolCopy.Agent.Ref, team.Namespace); err != nil {
-					log.Error(err, "Failed to parse agent tool reference", "toolRef", toolCopy.Agent.Ref)
-					continue
-				}
-				tools = append(tools, toolCopy)
-
-			case v1alpha1.ToolProviderType_McpServer:
-				if toolCopy.McpServer == nil {
-					log.Info("McpServer tool has nil McpServer field", "tool", toolCopy)
-					continue
-				}
-				if err := updateRef(&toolCopy.McpServer.ToolServer, team.Namespace); err != nil {
-					log.Error(err, "Failed to parse server tool reference", "toolRef", toolCopy.McpServer.ToolServer)
-					continue
-				}
-				tools = append(tools, toolCopy)
-
-			default:
-				log.Info("Unknown tool type", "toolType", toolCopy.Type)
-			}
-		}
+		memoryRefs := teamMemoryRefs(log, &team)
+		tools := teamTools(log, &team)
 
 		teamsWithID = append(teamsWithID, TeamResponse{
 			Id:             autogenTeam.Id,
@@ -146,7 +135,20 @@ func (h *TeamsHandler) HandleListTeams(w ErrorResponseWriter, r *http.Request) {
 	}
 
 	log.Info("Successfully listed teams", "count", len(teamsWithID))
-	RespondWithJSON(w, http.StatusOK, teamsWithID)
+	RespondWithJSON(w, http.StatusOK, NewResponse(teamsWithID, "Successfully listed teams"))
+}
+
+// hasSkill reports whether the agent's A2A config has a skill with the given ID
+func hasSkill(team *v1alpha1.Agent, skillID string) bool {
+	if team.Spec.A2AConfig == nil {
+		return false
+	}
+	for _, skill := range team.Spec.A2AConfig.Skills {
+		if skill.ID == skillID {
+			return true
+		}
+	}
+	return false
 }
 
 // HandleUpdateTeam handles PUT /api/teams requests
@@ -156,7 +158,7 @@ func (h *TeamsHandler) HandleUpdateTeam(w ErrorResponseWriter, r *http.Request)
 
 	var teamRequest *v1alpha1.Agent
 	if err := DecodeJSONBody(r, &teamRequest); err != nil {
-		w.RespondWithError(errors.NewBadRequestError("Invalid request body", err))
+		w.RespondWithError(invalidBodyError(err))
 		return
 	}
 
@@ -187,7 +189,7 @@ func (h *TeamsHandler) HandleUpdateTeam(w ErrorResponseWriter, r *http.Request)
 	if err != nil {
 		if k8serrors.IsNotFound(err) {
 			log.Info("Team not found")
-			w.RespondWithError(errors.NewNotFoundError("Team not found", nil))
+			w.RespondWithError(errors.NewNotFoundError("Team not found", nil).WithCode(errors.CodeAgentNotFound))
 			return
 		}
 		log.Error(err, "Failed to get Team")
@@ -208,6 +210,97 @@ func (h *TeamsHandler) HandleUpdateTeam(w ErrorResponseWriter, r *http.Request)
 	RespondWithJSON(w, http.StatusOK, teamRequest)
 }
 
+// HandlePatchTeam handles PATCH /api/teams/{namespace}/{teamName} requests with a JSON merge
+// patch of the Agent. Like updates, the patched spec is validated and only the spec is applied.
+func (h *TeamsHandler) HandlePatchTeam(w ErrorResponseWriter, r *http.Request) {
+	log := ctrllog.FromContext(r.Context()).WithName("teams-handler").WithValues("operation", "patch")
+	log.Info("Received request to patch Team")
+
+	namespace, err := GetPathParam(r, "namespace")
+	if err != nil {
+		w.RespondWithError(errors.NewBadRequestError("Failed to get namespace from path", err))
+		return
+	}
+
+	teamName, err := GetPathParam(r, "teamName")
+	if err != nil {
+		w.RespondWithError(errors.NewBadRequestError("Failed to get teamName from path", err))
+		return
+	}
+
+	log = log.WithValues(
+		"teamNamespace", namespace,
+		"teamName", teamName,
+	)
+
+	patch, err := decodeMergePatch(r)
+	if err != nil {
+		w.RespondWithError(invalidBodyError(err))
+		return
+	}
+
+	log.V(1).Info("Getting existing Team")
+	existingTeam := &v1alpha1.Agent{}
+	err = common.GetObject(
+		r.Context(),
+		h.KubeClient,
+		existingTeam,
+		teamName,
+		namespace,
+	)
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			log.Info("Team not found")
+			w.RespondWithError(errors.NewNotFoundError("Team not found", nil).WithCode(errors.CodeAgentNotFound))
+			return
+		}
+		log.Error(err, "Failed to get Team")
+		w.RespondWithError(errors.NewInternalServerError("Failed to get Team", err))
+		return
+	}
+
+	patchedTeam, err := mergePatchAgent(existingTeam, patch)
+	if err != nil {
+		w.RespondWithError(errors.NewBadRequestError("Invalid Agent patch", err))
+		return
+	}
+	existingTeam.Spec = patchedTeam.Spec
+
+	if apiErr := h.validateTeamForStore(r.Context(), log, existingTeam); apiErr != nil {
+		w.RespondWithError(apiErr)
+		return
+	}
+
+	if err := h.KubeClient.Update(r.Context(), existingTeam); err != nil {
+		w.RespondWithError(errors.NewInternalServerError("Failed to update Team", err))
+		return
+	}
+
+	log.Info("Successfully patched Team")
+	RespondWithJSON(w, http.StatusOK, existingTeam)
+}
+
+// mergePatchAgent returns a copy of the agent with the JSON merge patch applied. Fields the
+// Agent doesn't have are rejected, as they would otherwise be dropped silently.
+func mergePatchAgent(agent *v1alpha1.Agent, patch []byte) (*v1alpha1.Agent, error) {
+	current, err := json.Marshal(agent)
+	if err != nil {
+		return nil, err
+	}
+	merged, err := jsonpatch.MergePatch(current, patch)
+	if err != nil {
+		return nil, err
+	}
+
+	patched := &v1alpha1.Agent{}
+	decoder := json.NewDecoder(bytes.NewReader(merged))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(patched); err != nil {
+		return nil, err
+	}
+	return patched, nil
+}
+
 // HandleCreateTeam handles POST /api/teams requests
 func (h *TeamsHandler) HandleCreateTeam(w ErrorResponseWriter, r *http.Request) {
 	log := ctrllog.FromContext(r.Context()).WithName("teams-handler").WithValues("operation", "create")
@@ -215,7 +308,7 @@ func (h *TeamsHandler) HandleCreateTeam(w ErrorResponseWriter, r *http.Request)
 
 	var teamRequest *v1alpha1.Agent
 	if err := DecodeJSONBody(r, &teamRequest); err != nil {
-		w.RespondWithError(errors.NewBadRequestError("Invalid request body", err))
+		w.RespondWithError(invalidBodyError(err))
 		return
 	}
 
@@ -234,19 +327,32 @@ func (h *TeamsHandler) HandleCreateTeam(w ErrorResponseWriter, r *http.Request)
 		"teamName", teamRef.Name,
 	)
 
-	kubeClientWrapper := client_wrapper.NewKubeClientWrapper(h.KubeClient)
-	kubeClientWrapper.AddInMemory(teamRequest)
+	if apiErr := h.validateTeamForStore(r.Context(), log, teamRequest); apiErr != nil {
+		w.RespondWithError(apiErr)
+		return
+	}
 
-	apiTranslator := autogen.NewAutogenApiTranslator(
-		kubeClientWrapper,
-		h.DefaultModelConfig,
-	)
+	// Team is valid, we can store it
+	log.V(1).Info("Creating Team in Kubernetes")
+	if err := h.KubeClient.Create(r.Context(), teamRequest); err != nil {
+		w.RespondWithError(errors.NewInternalServerError("Failed to create Team in Kubernetes", err))
+		return
+	}
 
+	log.V(1).Info("Successfully created Team")
+	RespondWithJSON(w, http.StatusCreated, teamRequest)
+}
+
+// validateTeamForStore translates the Agent and validates it with Autogen before it is stored
+func (h *TeamsHandler) validateTeamForStore(ctx context.Context, log logr.Logger, teamRequest *v1alpha1.Agent) *errors.APIError {
 	log.V(1).Info("Translating Team to Autogen format")
-	autogenTeam, err := apiTranslator.TranslateGroupChatForAgent(r.Context(), teamRequest)
+	autogenTeam, err := h.translateTeam(ctx, teamRequest)
 	if err != nil {
-		w.RespondWithError(errors.NewInternalServerError("Failed to translate Team to Autogen format", err))
-		return
+		return errors.NewInternalServerError("Failed to translate Team to Autogen format", err)
+	}
+
+	if apiErr := h.validateModelProviders(log, autogenTeam.Component); apiErr != nil {
+		return apiErr
 	}
 
 	validateReq := autogen_client.ValidationRequest{
@@ -257,8 +363,7 @@ func (h *TeamsHandler) HandleCreateTeam(w ErrorResponseWriter, r *http.Request)
 	log.V(1).Info("Validating Team")
 	validationResp, err := h.AutogenClient.Validate(&validateReq)
 	if err != nil {
-		w.RespondWithError(errors.NewInternalServerError("Failed to validate Team", err))
-		return
+		return errors.NewInternalServerError("Failed to validate Team", err)
 	}
 
 	if !validationResp.IsValid {
@@ -282,19 +387,170 @@ func (h *TeamsHandler) HandleCreateTeam(w ErrorResponseWriter, r *http.Request)
 			errorMsg += "unknown validation error"
 		}
 
-		w.RespondWithError(errors.NewValidationError(errorMsg, nil))
+		return errors.NewValidationError(errorMsg, nil)
+	}
+	return nil
+}
+
+// validateModelProviders checks that Autogen supports the provider of every model client
+// component of the translated team
+func (h *TeamsHandler) validateModelProviders(log logr.Logger, component *api.Component) *errors.APIError {
+	for _, modelClient := range modelClientComponents(component) {
+		unsupported, err := h.modelProviders.check(h.AutogenClient, modelClient)
+		if err != nil {
+			return errors.NewInternalServerError("Failed to validate model provider", err)
+		}
+		if unsupported != "" {
+			log.Info("Model provider is not supported", "provider", modelClient.Provider, "error", unsupported)
+			return errors.NewBadRequestError(
+				fmt.Sprintf("Model provider %s is not supported: %s", modelClient.Provider, unsupported),
+				nil,
+			)
+		}
+	}
+	return nil
+}
+
+// HandleUpsertTeam handles PUT /api/agents/{namespace}/{teamName} requests. It creates the
+// Agent if it doesn't exist and replaces its spec otherwise, so applying the same Agent
+// repeatedly is idempotent. It responds 201 on create and 200 on update.
+func (h *TeamsHandler) HandleUpsertTeam(w ErrorResponseWriter, r *http.Request) {
+	log := ctrllog.FromContext(r.Context()).WithName("teams-handler").WithValues("operation", "upsert")
+
+	namespace, err := GetPathParam(r, "namespace")
+	if err != nil {
+		w.RespondWithError(errors.NewBadRequestError("Failed to get namespace from path", err))
+		return
+	}
+
+	teamName, err := GetPathParam(r, "teamName")
+	if err != nil {
+		w.RespondWithError(errors.NewBadRequestError("Failed to get teamName from path", err))
 		return
 	}
 
-	// Team is valid, we can store it
-	log.V(1).Info("Creating Team in Kubernetes")
-	if err := h.KubeClient.Create(r.Context(), teamRequest); err != nil {
-		w.RespondWithError(errors.NewInternalServerError("Failed to create Team in Kubernetes", err))
+	log = log.WithValues(
+		"teamNamespace", namespace,
+		"teamName", teamName,
+	)
+
+	var teamRequest *v1alpha1.Agent
+	if err := DecodeJSONBody(r, &teamRequest); err != nil {
+		w.RespondWithError(invalidBodyError(err))
+		return
+	}
+	if (teamRequest.Name != "" && teamRequest.Name != teamName) ||
+		(teamRequest.Namespace != "" && teamRequest.Namespace != namespace) {
+		w.RespondWithError(errors.NewBadRequestError("Agent metadata does not match the path", nil))
 		return
 	}
+	teamRequest.Name = teamName
+	teamRequest.Namespace = namespace
 
-	log.V(1).Info("Successfully created Team")
-	RespondWithJSON(w, http.StatusCreated, teamRequest)
+	if apiErr := h.validateTeamForStore(r.Context(), log, teamRequest); apiErr != nil {
+		w.RespondWithError(apiErr)
+		return
+	}
+
+	existingTeam := &v1alpha1.Agent{}
+	err = common.GetObject(
+		r.Context(),
+		h.KubeClient,
+		existingTeam,
+		teamName,
+		namespace,
+	)
+	if err != nil {
+		if !k8serrors.IsNotFound(err) {
+			w.RespondWithError(errors.NewInternalServerError("Failed to get Team", err))
+			return
+		}
+
+		log.V(1).Info("Creating Team in Kubernetes")
+		teamRequest.ResourceVersion = ""
+		if err := h.KubeClient.Create(r.Context(), teamRequest); err != nil {
+			w.RespondWithError(errors.NewInternalServerError("Failed to create Team in Kubernetes", err))
+			return
+		}
+		log.Info("Successfully created Team")
+		RespondWithJSON(w, http.StatusCreated, teamRequest)
+		return
+	}
+
+	log.V(1).Info("Updating Team in Kubernetes")
+	existingTeam.Spec = teamRequest.Spec
+	if err := h.KubeClient.Update(r.Context(), existingTeam); err != nil {
+		if k8serrors.IsConflict(err) {
+			w.RespondWithError(errors.NewConflictError("Team was modified concurrently, retry the request", err))
+			return
+		}
+		w.RespondWithError(errors.NewInternalServerError("Failed to update Team", err))
+		return
+	}
+
+	log.Info("Successfully updated Team")
+	RespondWithJSON(w, http.StatusOK, existingTeam)
+}
+
+// HandleValidateTeam handles POST /api/teams/validate and /api/agents/validate requests.
+// It translates the Agent and runs it through Autogen validation without persisting it.
+func (h *TeamsHandler) HandleValidateTeam(w ErrorResponseWriter, r *http.Request) {
+	log := ctrllog.FromContext(r.Context()).WithName("teams-handler").WithValues("operation", "validate")
+	log.V(1).Info("Received request to validate Team")
+
+	var teamRequest *v1alpha1.Agent
+	if err := DecodeJSONBody(r, &teamRequest); err != nil {
+		w.RespondWithError(invalidBodyError(err))
+		return
+	}
+
+	if teamRequest.Namespace == "" {
+		teamRequest.Namespace = common.GetResourceNamespace()
+	}
+	log = log.WithValues(
+		"teamNamespace", teamRequest.Namespace,
+		"teamName", teamRequest.Name,
+	)
+
+	var validationResp *autogen_client.ValidationResponse
+	autogenTeam, err := h.translateTeam(r.Context(), teamRequest)
+	if err != nil {
+		// Translation failures are configuration problems, so report them as validation errors
+		log.Info("Failed to translate Team to Autogen format", "error", err.Error())
+		validationResp = &autogen_client.ValidationResponse{
+			IsValid: false,
+			Errors: []*autogen_client.ValidationError{{
+				Field: "spec",
+				Error: err.Error(),
+			}},
+			Warnings: []*autogen_client.ValidationError{},
+		}
+	} else {
+		validationResp, err = h.AutogenClient.Validate(&autogen_client.ValidationRequest{
+			Component: autogenTeam.Component,
+		})
+		if err != nil {
+			w.RespondWithError(errors.NewInternalServerError("Failed to validate Team", err))
+			return
+		}
+	}
+
+	log.V(1).Info("Validated Team", "isValid", validationResp.IsValid)
+	RespondWithJSON(w, http.StatusOK, validationResp)
+}
+
+// translateTeam translates the Agent into its Autogen representation.
+// The Agent does not need to exist in Kubernetes yet.
+func (h *TeamsHandler) translateTeam(ctx context.Context, teamRequest *v1alpha1.Agent) (*autogen_client.Team, error) {
+	kubeClientWrapper := client_wrapper.NewKubeClientWrapper(h.KubeClient)
+	kubeClientWrapper.AddInMemory(teamRequest)
+
+	apiTranslator := autogen.NewAutogenApiTranslator(
+		kubeClientWrapper,
+		h.DefaultModelConfig,
+	)
+
+	return apiTranslator.TranslateGroupChatForAgent(ctx, teamRequest)
 }
 
 // HandleGetTeam handles GET /api/teams/{teamID} requests
@@ -335,7 +591,7 @@ func (h *TeamsHandler) HandleGetTeam(w ErrorResponseWriter, r *http.Request) {
 		teamLabel,
 		common.GetResourceNamespace(),
 	); err != nil {
-		w.RespondWithError(errors.NewNotFoundError("Team not found in Kubernetes", err))
+		w.RespondWithError(errors.NewNotFoundError("Team not found in Kubernetes", err).WithCode(errors.CodeAgentNotFound))
 		return
 	}
 
@@ -356,49 +612,8 @@ func (h *TeamsHandler) HandleGetTeam(w ErrorResponseWriter, r *http.Request) {
 		log.Error(err, "Failed to get ModelConfig", "modelConfigRef", modelConfigRef)
 	}
 
-	// Get the MemoryRefs for the team
-	memoryRefs := make([]string, 0, len(team.Spec.Memory))
-	for _, memory := range team.Spec.Memory {
-		memoryRef, err := common.ParseRefString(memory, team.Namespace)
-		if err != nil {
-			log.Error(err, "Failed to parse memory reference", "memoryRef", memory)
-			continue
-		}
-		memoryRefs = append(memoryRefs, memoryRef.String())
-	}
-
-	// Get the tools for the team
-	tools := make([]*v1alpha1.Tool, 0, len(team.Spec.Tools))
-	for _, tool := range team.Spec.Tools {
-		toolCopy := tool.DeepCopy()
-
-		switch toolCopy.Type {
-		case v1alpha1.ToolProviderType_Agent:
-			if toolCopy.Agent == nil {
-				log.Info("Agent tool has nil Agent field", "tool", toolCopy)
-				continue
-			}
-			if err := updateRef(&toolCopy.Agent.Ref, team.Namespace); err != nil {
-				log.Error(err, "Failed to parse agent tool reference", "toolRef", toolCopy.Agent.Ref)
-				continue
-			}
-			tools = append(tools, toolCopy)
-
-		case v1alpha1.ToolProviderType_McpServer:
-			if toolCopy.McpServer == nil {
-				log.Info("McpServer tool has nil McpServer field", "tool", toolCopy)
-				continue
-			}
-			if err := updateRef(&toolCopy.McpServer.ToolServer, team.Namespace); err != nil {
-				log.Error(err, "Failed to parse server tool reference", "toolRef", toolCopy.McpServer.ToolServer)
-				continue
-			}
-			tools = append(tools, toolCopy)
-
-		default:
-			log.Info("Unknown tool type", "toolType", toolCopy.Type)
-		}
-	}
+	memoryRefs := teamMemoryRefs(log, team)
+	tools := teamTools(log, team)
 
 	// Create a new object that contains the Team information from Team and the ID from the autogenTeam
 	teamWithID := &TeamResponse{
@@ -416,6 +631,54 @@ func (h *TeamsHandler) HandleGetTeam(w ErrorResponseWriter, r *http.Request) {
 	RespondWithJSON(w, http.StatusOK, teamWithID)
 }
 
+// HandleGetTeamSkills handles GET /api/agents/{namespace}/{teamName}/skills requests
+func (h *TeamsHandler) HandleGetTeamSkills(w ErrorResponseWriter, r *http.Request) {
+	log := ctrllog.FromContext(r.Context()).WithName("teams-handler").WithValues("operation", "get-skills")
+
+	namespace, err := GetPathParam(r, "namespace")
+	if err != nil {
+		w.RespondWithError(errors.NewBadRequestError("Failed to get namespace from path", err))
+		return
+	}
+
+	teamName, err := GetPathParam(r, "teamName")
+	if err != nil {
+		w.RespondWithError(errors.NewBadRequestError("Failed to get teamName from path", err))
+		return
+	}
+
+	log = log.WithValues(
+		"teamNamespace", namespace,
+		"teamName", teamName,
+	)
+
+	log.V(1).Info("Getting Team from Kubernetes")
+	team := &v1alpha1.Agent{}
+	if err := common.GetObject(
+		r.Context(),
+		h.KubeClient,
+		team,
+		teamName,
+		namespace,
+	); err != nil {
+		if k8serrors.IsNotFound(err) {
+			w.RespondWithError(errors.NewNotFoundError("Team not found", nil).WithCode(errors.CodeAgentNotFound))
+			return
+		}
+		w.RespondWithError(errors.NewInternalServerError("Failed to get Team", err))
+		return
+	}
+
+	if team.Spec.A2AConfig == nil || len(team.Spec.A2AConfig.Skills) == 0 {
+		w.RespondWithError(errors.NewNotFoundError(
+			fmt.Sprintf("Team %s has no skills: skills are set in spec.a2aConfig", common.GetObjectRef(team)), nil))
+		return
+	}
+
+	log.Info("Successfully retrieved Team skills")
+	RespondWithJSON(w, http.StatusOK, a2a.ConvertAgentSkills(team.Spec.A2AConfig.Skills))
+}
+
 // HandleDeleteTeam handles DELETE /api/teams/{namespace}/{teamName} requests
 func (h *TeamsHandler) HandleDeleteTeam(w ErrorResponseWriter, r *http.Request) {
 	log := ctrllog.FromContext(r.Context()).WithName("teams-handler").WithValues("operation", "delete")
@@ -450,7 +713,7 @@ func (h *TeamsHandler) HandleDeleteTeam(w ErrorResponseWriter, r *http.Request)
 	if err != nil {
 		if k8serrors.IsNotFound(err) {
 			log.Info("Team not found")
-			w.RespondWithError(errors.NewNotFoundError("Team not found", nil))
+			w.RespondWithError(errors.NewNotFoundError("Team not found", nil).WithCode(errors.CodeAgentNotFound))
 			return
 		}
 		log.Error(err, "Failed to get Team")
@@ -467,3 +730,169 @@ func (h *TeamsHandler) HandleDeleteTeam(w ErrorResponseWriter, r *http.Request)
 	log.Info("Successfully deleted Team")
 	w.WriteHeader(http.StatusNoContent)
 }
+
+// maxBatchGetRefs is the maximum number of refs accepted by a single batch get request
+const maxBatchGetRefs = 100
+
+// BatchGetTeamsRequest is the request body for POST /api/agents/batchGet
+type BatchGetTeamsRequest struct {
+	Refs []string `json:"refs"`
+}
+
+// BatchGetTeamsResponse holds the teams found for a batch get request and the refs that were not
+type BatchGetTeamsResponse struct {
+	Agents   []TeamResponse `json:"agents"`
+	NotFound []string       `json:"not_found"`
+}
+
+// HandleBatchGetTeams handles POST /api/agents/batchGet requests
+func (h *TeamsHandler) HandleBatchGetTeams(w ErrorResponseWriter, r *http.Request) {
+	log := ctrllog.FromContext(r.Context()).WithName("teams-handler").WithValues("operation", "batch-get")
+
+	userID, err := GetUserID(r)
+	if err != nil {
+		w.RespondWithError(errors.NewBadRequestError("Failed to get user ID", err))
+		return
+	}
+	log = log.WithValues("userID", userID)
+
+	var req BatchGetTeamsRequest
+	if err := DecodeJSONBody(r, &req); err != nil {
+		w.RespondWithError(invalidBodyError(err))
+		return
+	}
+	if len(req.Refs) == 0 {
+		w.RespondWithError(errors.NewBadRequestError("refs is required", nil))
+		return
+	}
+	if len(req.Refs) > maxBatchGetRefs {
+		w.RespondWithError(errors.NewBadRequestError(fmt.Sprintf("at most %d refs can be requested at once", maxBatchGetRefs), nil))
+		return
+	}
+
+	response := BatchGetTeamsResponse{
+		Agents:   make([]TeamResponse, 0, len(req.Refs)),
+		NotFound: make([]string, 0),
+	}
+
+	// List the Autogen teams once rather than once per ref
+	autogenTeams, err := h.AutogenClient.ListTeams(userID)
+	if err != nil {
+		w.RespondWithError(errors.NewInternalServerError("Failed to list Teams from Autogen", err))
+		return
+	}
+
+	seen := map[string]bool{}
+	for _, ref := range req.Refs {
+		if err := r.Context().Err(); err != nil {
+			log.Info("Request cancelled, stopping batch get", "error", err.Error())
+			return
+		}
+		if seen[ref] {
+			continue
+		}
+		seen[ref] = true
+
+		teamRef, err := common.ParseRefString(ref, common.GetResourceNamespace())
+		if err != nil {
+			w.RespondWithError(errors.NewBadRequestError(fmt.Sprintf("Invalid ref %q", ref), err))
+			return
+		}
+
+		team := &v1alpha1.Agent{}
+		if err := h.KubeClient.Get(r.Context(), teamRef, team); err != nil {
+			if k8serrors.IsNotFound(err) {
+				response.NotFound = append(response.NotFound, ref)
+				continue
+			}
+			w.RespondWithError(errors.NewInternalServerError("Failed to get Team from Kubernetes", err))
+			return
+		}
+
+		// Teams are scoped to the user in Autogen, so a team the user can't see is not found
+		autogenTeam, err := autogen_client.FindAgentByRef(autogenTeams, teamRef.String())
+		if err != nil {
+			if err == autogen_client.NotFoundError {
+				response.NotFound = append(response.NotFound, ref)
+				continue
+			}
+			w.RespondWithError(errors.NewInternalServerError("Failed to get Team from Autogen", err))
+			return
+		}
+
+		modelConfig := &v1alpha1.ModelConfig{}
+		if err := common.GetObject(
+			r.Context(),
+			h.KubeClient,
+			modelConfig,
+			team.Spec.ModelConfig,
+			team.Namespace,
+		); err != nil {
+			log.Error(err, "Failed to get ModelConfig", "modelConfigRef", team.Spec.ModelConfig)
+		}
+
+		response.Agents = append(response.Agents, TeamResponse{
+			Id:             autogenTeam.Id,
+			Agent:          team,
+			Component:      autogenTeam.Component,
+			ModelProvider:  modelConfig.Spec.Provider,
+			Model:          modelConfig.Spec.Model,
+			ModelConfigRef: common.GetObjectRef(modelConfig),
+			MemoryRefs:     teamMemoryRefs(log, team),
+			Tools:          teamTools(log, team),
+		})
+	}
+
+	log.Info("Successfully retrieved teams", "found", len(response.Agents), "notFound", len(response.NotFound))
+	RespondWithJSON(w, http.StatusOK, NewResponse(response, "Successfully retrieved teams"))
+}
+
+// teamMemoryRefs returns the fully qualified memory references of a team
+func teamMemoryRefs(log logr.Logger, team *v1alpha1.Agent) []string {
+	memoryRefs := make([]string, 0, len(team.Spec.Memory))
+	for _, memory := range team.Spec.Memory {
+		memoryRef, err := common.ParseRefString(memory, team.Namespace)
+		if err != nil {
+			log.Error(err, "Failed to parse memory reference", "memoryRef", memory)
+			continue
+		}
+		memoryRefs = append(memoryRefs, memoryRef.String())
+	}
+	return memoryRefs
+}
+
+// teamTools returns copies of a team's tools with fully qualified references
+func teamTools(log logr.Logger, team *v1alpha1.Agent) []*v1alpha1.Tool {
+	tools := make([]*v1alpha1.Tool, 0, len(team.Spec.Tools))
+	for _, tool := range team.Spec.Tools {
+		toolCopy := tool.DeepCopy()
+
+		switch toolCopy.Type {
+		case v1alpha1.ToolProviderType_Agent:
+			if toolCopy.Agent == nil {
+				log.Info("Agent tool has nil Agent field", "tool", toolCopy)
+				continue
+			}
+			if err := updateRef(&toolCopy.Agent.Ref, team.Namespace); err != nil {
+				log.Error(err, "Failed to parse agent tool reference", "toolRef", toolCopy.Agent.Ref)
+				continue
+			}
+			tools = append(tools, toolCopy)
+
+		case v1alpha1.ToolProviderType_McpServer:
+			if toolCopy.McpServer == nil {
+				log.Info("McpServer tool has nil McpServer field", "tool", toolCopy)
+				continue
+			}
+			if err := updateRef(&toolCopy.McpServer.ToolServer, team.Namespace); err != nil {
+				log.Error(err, "Failed to parse server tool reference", "toolRef", toolCopy.McpServer.ToolServer)
+				continue
+			}
+			tools = append(tools, toolCopy)
+
+		default:
+			log.Info("Unknown tool type", "toolType", toolCopy.Type)
+		}
+	}
+	return tools
+}
@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -11,6 +12,7 @@ import (
 	"github.com/gorilla/mux"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -21,6 +23,7 @@ import (
 	autogen_fake "github.com/kagent-dev/kagent/go/autogen/client/fake"
 	"github.com/kagent-dev/kagent/go/controller/api/v1alpha1"
 	common "github.com/kagent-dev/kagent/go/controller/internal/utils"
+	"trpc.group/trpc-go/trpc-a2a-go/server"
 )
 
 // Test fixtures and helper functions
@@ -166,6 +169,90 @@ func TestHandleUpdateTeam(t *testing.T) {
 	})
 }
 
+func TestHandlePatchTeam(t *testing.T) {
+	patch := func(handler *TeamsHandler, name, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("PATCH", "/api/teams/default/"+name, bytes.NewBufferString(body))
+		req = mux.SetURLVars(req, map[string]string{"namespace": "default", "teamName": name})
+		w := httptest.NewRecorder()
+		handler.HandlePatchTeam(&testErrorResponseWriter{w}, req)
+		return w
+	}
+	getTeam := func(t *testing.T, handler *TeamsHandler) *v1alpha1.Agent {
+		team := &v1alpha1.Agent{}
+		require.NoError(t, handler.KubeClient.Get(context.Background(), types.NamespacedName{Name: "test-team", Namespace: "default"}, team))
+		return team
+	}
+	modelConfig := func(namespace, name string) *v1alpha1.ModelConfig {
+		return &v1alpha1.ModelConfig{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+			Spec: v1alpha1.ModelConfigSpec{
+				Model:    "llama3.2",
+				Provider: v1alpha1.Ollama,
+				Ollama:   &v1alpha1.OllamaConfig{Host: "http://test-host"},
+			},
+		}
+	}
+	newHandler := func() *TeamsHandler {
+		handler, _ := setupTestHandler(modelConfig("default", "old-model-config"), modelConfig("kagent", "new-model-config"), &v1alpha1.Agent{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-team", Namespace: "default"},
+			Spec: v1alpha1.AgentSpec{
+				Description:   "a team",
+				SystemMessage: "be helpful",
+				ModelConfig:   "default/old-model-config",
+				A2AConfig:     &v1alpha1.A2AConfig{Skills: []v1alpha1.AgentSkill{{ID: "summarize", Name: "Summarize"}}},
+			},
+		})
+		return handler
+	}
+
+	t.Run("keeps omitted fields", func(t *testing.T) {
+		handler := newHandler()
+		w := patch(handler, "test-team", `{"spec":{"modelConfig":"kagent/new-model-config"}}`)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+		team := getTeam(t, handler)
+		assert.Equal(t, "kagent/new-model-config", team.Spec.ModelConfig)
+		assert.Equal(t, "a team", team.Spec.Description)
+		assert.Equal(t, "be helpful", team.Spec.SystemMessage)
+		assert.NotNil(t, team.Spec.A2AConfig)
+	})
+
+	t.Run("clears fields set to null", func(t *testing.T) {
+		handler := newHandler()
+		w := patch(handler, "test-team", `{"spec":{"a2aConfig":null}}`)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+		team := getTeam(t, handler)
+		assert.Nil(t, team.Spec.A2AConfig)
+		assert.Equal(t, "default/old-model-config", team.Spec.ModelConfig)
+	})
+
+	t.Run("rejects invalid patches", func(t *testing.T) {
+		handler := newHandler()
+		for _, body := range []string{`"spec"`, `{"spec":{"unknown":true}}`, `{"spec":{"modelConfig":5}}`} {
+			w := patch(handler, "test-team", body)
+			assert.Equal(t, http.StatusBadRequest, w.Code, body)
+		}
+		assert.Equal(t, "default/old-model-config", getTeam(t, handler).Spec.ModelConfig)
+	})
+
+	t.Run("rejects unsupported model provider", func(t *testing.T) {
+		handler := newHandler()
+		provider := "autogen_ext.models.ollama.OllamaChatCompletionClient"
+		handler.AutogenClient.(*autogen_fake.InMemoryAutogenClient).UnsupportedProviders = []string{provider}
+
+		w := patch(handler, "test-team", `{"spec":{"modelConfig":"kagent/new-model-config"}}`)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "Model provider "+provider+" is not supported")
+		assert.Equal(t, "default/old-model-config", getTeam(t, handler).Spec.ModelConfig)
+	})
+
+	t.Run("returns 404 for non-existent team", func(t *testing.T) {
+		w := patch(newHandler(), "non-existent", `{"spec":{"description":"updated"}}`)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
+
 func TestHandleListTeams(t *testing.T) {
 	t.Run("lists teams successfully", func(t *testing.T) {
 		modelConfig := createTestModelConfig()
@@ -182,12 +269,48 @@ func TestHandleListTeams(t *testing.T) {
 		assert.Equal(t, http.StatusOK, w.Code)
 
 		var response []TeamResponse
-		err := json.Unmarshal(w.Body.Bytes(), &response)
+		err := DecodeResponseData(w.Body.Bytes(), &response)
 		require.NoError(t, err)
 		assert.Len(t, response, 1)
 		assert.Equal(t, "test-team", response[0].Agent.Name)
 	})
 
+	t.Run("filters by skill", func(t *testing.T) {
+		modelConfig := createTestModelConfig()
+		withSkill := createTestAgent("with-skill", modelConfig)
+		withSkill.Spec.A2AConfig = &v1alpha1.A2AConfig{
+			Skills: []v1alpha1.AgentSkill{{ID: "summarize", Name: "Summarize"}},
+		}
+		withoutSkill := createTestAgent("without-skill", modelConfig)
+
+		handler, userID := setupTestHandler(withSkill, withoutSkill, modelConfig)
+		autogenClient := handler.Base.AutogenClient.(*autogen_fake.InMemoryAutogenClient)
+		createAutogenTeam(autogenClient, userID, withSkill)
+		require.NoError(t, autogenClient.CreateTeam(&autogen_client.Team{
+			BaseObject: autogen_client.BaseObject{Id: 2, UserID: userID},
+			Component:  &api.Component{Label: common.GetObjectRef(withoutSkill)},
+		}))
+
+		list := func(query string) []TeamResponse {
+			req := httptest.NewRequest("GET", fmt.Sprintf("/api/agents?user_id=%s%s", userID, query), nil)
+			w := httptest.NewRecorder()
+			handler.HandleListTeams(&testErrorResponseWriter{w}, req)
+			require.Equal(t, http.StatusOK, w.Code)
+
+			var response []TeamResponse
+			require.NoError(t, DecodeResponseData(w.Body.Bytes(), &response))
+			return response
+		}
+
+		assert.Len(t, list(""), 2)
+
+		response := list("&skill=summarize")
+		require.Len(t, response, 1)
+		assert.Equal(t, "with-skill", response[0].Agent.Name)
+
+		assert.Empty(t, list("&skill=translate"))
+	})
+
 	t.Run("returns 400 for missing user ID", func(t *testing.T) {
 		handler, _ := setupTestHandler()
 
@@ -240,6 +363,297 @@ func TestHandleCreateTeam(t *testing.T) {
 		require.NoError(t, err)
 		assert.Equal(t, "test-team", response.Name)
 	})
+
+	t.Run("rejects unsupported model provider", func(t *testing.T) {
+		modelConfig := &v1alpha1.ModelConfig{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-model-config", Namespace: "default"},
+			Spec: v1alpha1.ModelConfigSpec{
+				Model:    "llama3.2",
+				Provider: v1alpha1.Ollama,
+				Ollama:   &v1alpha1.OllamaConfig{Host: "http://test-host"},
+			},
+		}
+
+		handler, _ := setupTestHandler(modelConfig)
+		provider := "autogen_ext.models.ollama.OllamaChatCompletionClient"
+		handler.AutogenClient.(*autogen_fake.InMemoryAutogenClient).UnsupportedProviders = []string{provider}
+
+		team := &v1alpha1.Agent{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-team", Namespace: "default"},
+			Spec: v1alpha1.AgentSpec{
+				ModelConfig:   common.GetObjectRef(modelConfig),
+				SystemMessage: "You are an imagenary agent",
+				Description:   "Test team description",
+			},
+		}
+
+		body, _ := json.Marshal(team)
+		req := httptest.NewRequest("POST", "/api/teams", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		handler.HandleCreateTeam(&testErrorResponseWriter{w}, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "Model provider "+provider+" is not supported")
+		assert.Contains(t, w.Body.String(), "Could not import provider "+provider)
+	})
+}
+
+func TestHandleUpsertTeam(t *testing.T) {
+	modelConfig := &v1alpha1.ModelConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-model-config", Namespace: "default"},
+		Spec: v1alpha1.ModelConfigSpec{
+			Model:    "test",
+			Provider: "Ollama",
+			Ollama:   &v1alpha1.OllamaConfig{Host: "http://test-host"},
+			ModelInfo: &v1alpha1.ModelInfo{
+				JSONOutput:       true,
+				StructuredOutput: true,
+			},
+		},
+	}
+
+	upsert := func(handler *TeamsHandler, name string, team *v1alpha1.Agent) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(team)
+		req := httptest.NewRequest("PUT", "/api/agents/default/"+name, bytes.NewBuffer(body))
+		req = mux.SetURLVars(req, map[string]string{
+			"namespace": "default",
+			"teamName":  name,
+		})
+		w := httptest.NewRecorder()
+		handler.HandleUpsertTeam(&testErrorResponseWriter{w}, req)
+		return w
+	}
+
+	newTeam := func(description string) *v1alpha1.Agent {
+		return &v1alpha1.Agent{
+			Spec: v1alpha1.AgentSpec{
+				ModelConfig:   common.GetObjectRef(modelConfig),
+				SystemMessage: "You are an imagenary agent",
+				Description:   description,
+			},
+		}
+	}
+
+	t.Run("applying the same agent twice keeps a single agent", func(t *testing.T) {
+		handler, _ := setupTestHandler(modelConfig)
+
+		w := upsert(handler, "test-team", newTeam("first"))
+		require.Equal(t, http.StatusCreated, w.Code)
+
+		w = upsert(handler, "test-team", newTeam("second"))
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var response v1alpha1.Agent
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, "second", response.Spec.Description)
+
+		agents := &v1alpha1.AgentList{}
+		require.NoError(t, handler.KubeClient.List(context.Background(), agents))
+		require.Len(t, agents.Items, 1)
+		assert.Equal(t, "test-team", agents.Items[0].Name)
+		assert.Equal(t, "second", agents.Items[0].Spec.Description)
+	})
+
+	t.Run("returns 400 when the body names another agent", func(t *testing.T) {
+		handler, _ := setupTestHandler(modelConfig)
+
+		team := newTeam("first")
+		team.Name = "other-team"
+		w := upsert(handler, "test-team", team)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestHandleValidateTeam(t *testing.T) {
+	t.Run("returns validation result without persisting", func(t *testing.T) {
+		modelConfig := &v1alpha1.ModelConfig{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-model-config", Namespace: "default"},
+			Spec: v1alpha1.ModelConfigSpec{
+				Model:    "test",
+				Provider: "Ollama",
+				Ollama:   &v1alpha1.OllamaConfig{Host: "http://test-host"},
+			},
+		}
+
+		handler, _ := setupTestHandler(modelConfig)
+
+		team := &v1alpha1.Agent{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-team", Namespace: "default"},
+			Spec: v1alpha1.AgentSpec{
+				ModelConfig:   common.GetObjectRef(modelConfig),
+				SystemMessage: "You are an imagenary agent",
+			},
+		}
+
+		body, _ := json.Marshal(team)
+		req := httptest.NewRequest("POST", "/api/agents/validate", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		handler.HandleValidateTeam(&testErrorResponseWriter{w}, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response autogen_client.ValidationResponse
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		require.NoError(t, err)
+		assert.True(t, response.IsValid)
+
+		// Validation must not create the Agent
+		err = handler.KubeClient.Get(req.Context(), types.NamespacedName{Name: "test-team", Namespace: "default"}, &v1alpha1.Agent{})
+		assert.True(t, k8serrors.IsNotFound(err))
+	})
+
+	t.Run("reports translation failures as validation errors", func(t *testing.T) {
+		handler, _ := setupTestHandler()
+
+		team := &v1alpha1.Agent{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-team", Namespace: "default"},
+			Spec: v1alpha1.AgentSpec{
+				ModelConfig:   "default/missing-model-config",
+				SystemMessage: "You are an imagenary agent",
+			},
+		}
+
+		body, _ := json.Marshal(team)
+		req := httptest.NewRequest("POST", "/api/teams/validate", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		handler.HandleValidateTeam(&testErrorResponseWriter{w}, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response autogen_client.ValidationResponse
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		require.NoError(t, err)
+		assert.False(t, response.IsValid)
+		require.Len(t, response.Errors, 1)
+		assert.Equal(t, "spec", response.Errors[0].Field)
+	})
+
+	t.Run("returns 400 for invalid body", func(t *testing.T) {
+		handler, _ := setupTestHandler()
+
+		req := httptest.NewRequest("POST", "/api/agents/validate", bytes.NewBufferString("{"))
+		w := httptest.NewRecorder()
+
+		handler.HandleValidateTeam(&testErrorResponseWriter{w}, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestHandleBatchGetTeams(t *testing.T) {
+	batchGet := func(handler *TeamsHandler, userID string, refs ...string) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(BatchGetTeamsRequest{Refs: refs})
+		req := httptest.NewRequest("POST", fmt.Sprintf("/api/agents/batchGet?user_id=%s", userID), bytes.NewBuffer(body))
+		w := httptest.NewRecorder()
+		handler.HandleBatchGetTeams(&testErrorResponseWriter{w}, req)
+		return w
+	}
+
+	t.Run("returns found teams and not found refs", func(t *testing.T) {
+		modelConfig := createTestModelConfig()
+		team := createTestAgent("test-team", modelConfig)
+		otherTeam := createTestAgent("other-team", modelConfig)
+
+		handler, userID := setupTestHandler(team, otherTeam, modelConfig)
+		autogenClient := handler.Base.AutogenClient.(*autogen_fake.InMemoryAutogenClient)
+		createAutogenTeam(autogenClient, userID, team)
+		// other-team belongs to another user
+		autogenClient.CreateTeam(&autogen_client.Team{
+			BaseObject: autogen_client.BaseObject{Id: 2, UserID: "other-user"},
+			Component:  &api.Component{Label: common.GetObjectRef(otherTeam)},
+		})
+
+		w := batchGet(handler, userID, "default/test-team", "default/other-team", "default/missing", "default/test-team")
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var response BatchGetTeamsResponse
+		require.NoError(t, DecodeResponseData(w.Body.Bytes(), &response))
+		require.Len(t, response.Agents, 1)
+		assert.Equal(t, 1, response.Agents[0].Id)
+		assert.Equal(t, "test-team", response.Agents[0].Agent.Name)
+		assert.Equal(t, "gpt-4", response.Agents[0].Model)
+		assert.Equal(t, []string{"default/other-team", "default/missing"}, response.NotFound)
+	})
+
+	t.Run("stops when the request is cancelled", func(t *testing.T) {
+		modelConfig := createTestModelConfig()
+		team := createTestAgent("test-team", modelConfig)
+		handler, userID := setupTestHandler(team, modelConfig)
+		createAutogenTeam(handler.Base.AutogenClient.(*autogen_fake.InMemoryAutogenClient), userID, team)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		body, _ := json.Marshal(BatchGetTeamsRequest{Refs: []string{"default/test-team"}})
+		req := httptest.NewRequest("POST", fmt.Sprintf("/api/agents/batchGet?user_id=%s", userID), bytes.NewBuffer(body)).WithContext(ctx)
+		w := httptest.NewRecorder()
+		handler.HandleBatchGetTeams(&testErrorResponseWriter{w}, req)
+
+		assert.Empty(t, w.Body.String())
+	})
+
+	t.Run("returns 400 for empty refs", func(t *testing.T) {
+		handler, userID := setupTestHandler()
+		w := batchGet(handler, userID)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("returns 400 for missing user ID", func(t *testing.T) {
+		handler, _ := setupTestHandler()
+		w := batchGet(handler, "", "default/test-team")
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestHandleGetTeamSkills(t *testing.T) {
+	getSkills := func(handler *TeamsHandler, name string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", "/api/agents/default/"+name+"/skills", nil)
+		req = mux.SetURLVars(req, map[string]string{
+			"namespace": "default",
+			"teamName":  name,
+		})
+		w := httptest.NewRecorder()
+		handler.HandleGetTeamSkills(&testErrorResponseWriter{w}, req)
+		return w
+	}
+
+	t.Run("returns converted skills", func(t *testing.T) {
+		team := createTestAgent("a2a-team", createTestModelConfig())
+		team.Spec.A2AConfig = &v1alpha1.A2AConfig{
+			Skills: []v1alpha1.AgentSkill{{ID: "summarize", Name: "Summarize", Tags: []string{"text"}}},
+		}
+		handler, _ := setupTestHandler(team)
+
+		w := getSkills(handler, "a2a-team")
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var skills []server.AgentSkill
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &skills))
+		require.Len(t, skills, 1)
+		assert.Equal(t, "summarize", skills[0].ID)
+		assert.Equal(t, []string{"text"}, skills[0].Tags)
+	})
+
+	t.Run("returns 404 for team without a2a config", func(t *testing.T) {
+		handler, _ := setupTestHandler(createTestAgent("plain-team", createTestModelConfig()))
+
+		w := getSkills(handler, "plain-team")
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Contains(t, w.Body.String(), "has no skills")
+	})
+
+	t.Run("returns 404 for non-existent team", func(t *testing.T) {
+		handler, _ := setupTestHandler()
+
+		w := getSkills(handler, "non-existent")
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
 }
 
 func TestHandleDeleteTeam(t *testing.T) {
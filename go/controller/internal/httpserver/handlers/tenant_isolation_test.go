@@ -0,0 +1,192 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kagent-dev/kagent/go/autogen/api"
+	autogen_client "github.com/kagent-dev/kagent/go/autogen/client"
+	autogen_fake "github.com/kagent-dev/kagent/go/autogen/client/fake"
+)
+
+// TestTenantIsolation checks that a user can't read, change or delete the sessions, runs and
+// feedback of another user. Sessions of other users are reported as not found, so their IDs
+// can't be probed.
+//
+// Isolation is intentionally absent for agents, tool servers, tools, model configs and
+// memories: they are Kubernetes resources shared by all users and stored in Autogen under
+// the global user. A2A conversations are served for the global user only.
+func TestTenantIsolation(t *testing.T) {
+	const alice, bob = "alice@example.com", "bob@example.com"
+
+	handler, _ := setupTestHandler()
+	autogenClient := handler.AutogenClient.(*autogen_fake.InMemoryAutogenClient)
+	sessions := NewSessionsHandler(handler.Base)
+	feedback := NewFeedbackHandler(handler.Base)
+
+	require.NoError(t, autogenClient.CreateTeam(&autogen_client.Team{
+		BaseObject: autogen_client.BaseObject{Id: 1, UserID: alice},
+		Component:  &api.Component{Label: "default/test-agent"},
+	}))
+	teamID := 1
+	session, err := autogenClient.CreateSession(&autogen_client.CreateSession{Name: "alice-session", UserID: alice, TeamID: &teamID})
+	require.NoError(t, err)
+	_, err = autogenClient.CreateRun(&autogen_client.CreateRunRequest{SessionID: session.ID, UserID: alice})
+	require.NoError(t, err)
+	runs, err := autogenClient.ListSessionRuns(session.ID, alice)
+	require.NoError(t, err)
+	runs[0].Status = "complete"
+	runs[0].Messages = []*autogen_client.RunMessage{
+		{ID: 1, Config: map[string]interface{}{"source": "user", "content": "alice's secret"}},
+	}
+	require.NoError(t, autogenClient.CreateFeedback(&autogen_client.FeedbackSubmission{UserID: alice, FeedbackText: "alice's feedback"}))
+
+	sessionID := strconv.Itoa(session.ID)
+	request := func(method, path, userID string, body interface{}) *http.Request {
+		var buf bytes.Buffer
+		if body != nil {
+			require.NoError(t, json.NewEncoder(&buf).Encode(body))
+		}
+		req := httptest.NewRequest(method, fmt.Sprintf("%s?user_id=%s", path, userID), &buf)
+		return mux.SetURLVars(req, map[string]string{"sessionID": sessionID})
+	}
+	serve := func(handle func(ErrorResponseWriter, *http.Request), req *http.Request) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		handle(&testErrorResponseWriter{w}, req)
+		return w
+	}
+
+	sessionPath := "/api/sessions/" + sessionID
+	crossTenant := []struct {
+		name    string
+		handle  func(ErrorResponseWriter, *http.Request)
+		request func() *http.Request
+	}{
+		{"get session", sessions.HandleGetSession, func() *http.Request {
+			return request("GET", sessionPath, bob, nil)
+		}},
+		{"list session messages", sessions.HandleListSessionMessages, func() *http.Request {
+			return request("GET", sessionPath+"/messages", bob, nil)
+		}},
+		{"summarize session", sessions.HandleGetSessionSummary, func() *http.Request {
+			return request("GET", sessionPath+"/summary", bob, nil)
+		}},
+		{"update session", sessions.HandleUpdateSession, func() *http.Request {
+			return request("PUT", sessionPath, bob, &autogen_client.Session{Name: "taken over"})
+		}},
+		{"patch session", sessions.HandlePatchSession, func() *http.Request {
+			return request("PATCH", sessionPath, bob, map[string]string{"name": "taken over"})
+		}},
+		{"invoke session", sessions.HandleSessionInvoke, func() *http.Request {
+			return request("POST", sessionPath+"/invoke", bob, &autogen_client.InvokeRequest{Task: "hello", TeamConfig: &api.Component{}})
+		}},
+		{"invoke session without team config", sessions.HandleSessionInvoke, func() *http.Request {
+			return request("POST", sessionPath+"/invoke", bob, &autogen_client.InvokeRequest{Task: "hello"})
+		}},
+		{"stream session", sessions.HandleSessionInvokeStream, func() *http.Request {
+			return request("POST", sessionPath+"/invoke/stream", bob, &autogen_client.InvokeRequest{Task: "hello", TeamConfig: &api.Component{}})
+		}},
+		{"resume session stream", sessions.HandleSessionInvokeStream, func() *http.Request {
+			req := request("POST", sessionPath+"/invoke/stream", bob, nil)
+			req.Header.Set("Last-Event-ID", "0")
+			return req
+		}},
+		{"delete session", sessions.HandleDeleteSession, func() *http.Request {
+			return request("DELETE", sessionPath, bob, nil)
+		}},
+		{"force delete session", sessions.HandleDeleteSession, func() *http.Request {
+			req := request("DELETE", sessionPath, bob, nil)
+			req.URL.RawQuery += "&force=true"
+			return req
+		}},
+	}
+	for _, tc := range crossTenant {
+		t.Run(tc.name+" of another user is not found", func(t *testing.T) {
+			w := serve(tc.handle, tc.request())
+			assert.Equal(t, http.StatusNotFound, w.Code, w.Body.String())
+			assert.Contains(t, w.Body.String(), "Session not found")
+			assert.NotContains(t, w.Body.String(), "alice's secret")
+		})
+	}
+
+	t.Run("session is unchanged by the other user", func(t *testing.T) {
+		w := serve(sessions.HandleGetSession, request("GET", sessionPath, alice, nil))
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		assert.Contains(t, w.Body.String(), "alice-session")
+
+		w = serve(sessions.HandleListSessionMessages, request("GET", sessionPath+"/messages", alice, nil))
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		assert.Contains(t, w.Body.String(), "alice's secret")
+	})
+
+	t.Run("runs in progress can't be stopped by the other user", func(t *testing.T) {
+		autogenClient.InvokeDelay = time.Minute
+		defer func() { autogenClient.InvokeDelay = 0 }()
+
+		invoked := make(chan struct{})
+		go func() {
+			defer close(invoked)
+			serve(sessions.HandleSessionInvoke, request("POST", sessionPath+"/invoke", alice, &autogen_client.InvokeRequest{Task: "long task", TeamConfig: &api.Component{}}))
+		}()
+		key := streamKey{sessionID: session.ID}
+		require.Eventually(t, func() bool { return sessions.runs.active(key) == 1 }, time.Second, 10*time.Millisecond)
+
+		req := request("DELETE", sessionPath, bob, nil)
+		req.URL.RawQuery += "&force=true"
+		w := serve(sessions.HandleDeleteSession, req)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Equal(t, 1, sessions.runs.active(key))
+
+		sessions.runs.stop(key, errSessionDeleted)
+		<-invoked
+	})
+
+	t.Run("session lists only contain the user's sessions", func(t *testing.T) {
+		w := serve(sessions.HandleListSessions, request("GET", "/api/sessions", bob, nil))
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		assert.NotContains(t, w.Body.String(), "alice-session")
+	})
+
+	t.Run("feedback lists only contain the user's feedback", func(t *testing.T) {
+		w := serve(feedback.HandleListFeedback, request("GET", "/api/feedback", bob, nil))
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		assert.NotContains(t, w.Body.String(), "alice's feedback")
+
+		w = serve(feedback.HandleListFeedback, request("GET", "/api/feedback", alice, nil))
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		assert.Contains(t, w.Body.String(), "alice's feedback")
+	})
+
+	t.Run("feedback can't be submitted as another user", func(t *testing.T) {
+		w := serve(feedback.HandleCreateFeedback, request("POST", "/api/feedback", bob, &autogen_client.FeedbackSubmission{
+			UserID:       alice,
+			FeedbackText: "bob posing as alice",
+		}))
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+		aliceFeedback, err := autogenClient.ListFeedback(alice)
+		require.NoError(t, err)
+		for _, submission := range aliceFeedback {
+			assert.NotEqual(t, "bob posing as alice", submission.FeedbackText)
+		}
+		bobFeedback, err := autogenClient.ListFeedback(bob)
+		require.NoError(t, err)
+		require.Len(t, bobFeedback, 1)
+		assert.Equal(t, "bob posing as alice", bobFeedback[0].FeedbackText)
+	})
+
+	t.Run("the owner can delete the session", func(t *testing.T) {
+		w := serve(sessions.HandleDeleteSession, request("DELETE", sessionPath, alice, nil))
+		assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	})
+}
@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	stderrors "errors"
 	"net/http"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -20,6 +21,8 @@ func setupScheme() *runtime.Scheme {
 		&v1alpha1.AgentList{},
 		&v1alpha1.ModelConfig{},
 		&v1alpha1.ModelConfigList{},
+		&v1alpha1.ToolServer{},
+		&v1alpha1.ToolServerList{},
 	)
 
 	metav1.AddToGroupVersion(s, schema.GroupVersion{Group: "kagent.dev", Version: "v1alpha1"})
@@ -38,10 +41,11 @@ func (t *testErrorResponseWriter) Flush() {
 }
 
 func (t *testErrorResponseWriter) RespondWithError(err error) {
-	if apiErr, ok := err.(*errors.APIError); ok {
+	var apiErr *errors.APIError
+	if stderrors.As(err, &apiErr) {
 		http.Error(t.ResponseWriter, apiErr.Message, apiErr.StatusCode())
 	} else {
-		http.Error(t.ResponseWriter, err.Error(), http.StatusInternalServerError)
+		http.Error(t.ResponseWriter, err.Error(), errors.StatusOf(err))
 	}
 }
 
@@ -4,9 +4,11 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"sort"
 	"strings"
 
 	"github.com/kagent-dev/kagent/go/autogen/api"
+	autogen_client "github.com/kagent-dev/kagent/go/autogen/client"
 	"github.com/kagent-dev/kagent/go/controller/api/v1alpha1"
 	"github.com/kagent-dev/kagent/go/controller/internal/httpserver/errors"
 	common "github.com/kagent-dev/kagent/go/controller/internal/utils"
@@ -32,7 +34,8 @@ func NewToolsHandler(base *Base) *ToolsHandler {
 	return &ToolsHandler{Base: base}
 }
 
-// HandleListTools handles GET /api/tools requests
+// HandleListTools handles GET /api/tools requests. With stream=true the tools are written as
+// JSON lines while they are gathered, without the response envelope and in server order.
 func (h *ToolsHandler) HandleListTools(w ErrorResponseWriter, r *http.Request) {
 	log := ctrllog.FromContext(r.Context()).WithName("tools-handler").WithValues("operation", "list")
 
@@ -57,18 +60,20 @@ func (h *ToolsHandler) HandleListTools(w ErrorResponseWriter, r *http.Request) {
 		return
 	}
 
+	if r.URL.Query().Get("stream") == "true" {
+		count, err := streamTools(w, allToolServers.Items, tools)
+		if err != nil {
+			log.Error(err, "Failed to stream tools", "count", count)
+			return
+		}
+		log.Info("Successfully streamed tools", "count", count)
+		return
+	}
+
 	discoveredTools := make([]*api.Component, 0)
-	for _, toolServer := range allToolServers.Items {
-		for _, t := range toolServer.Status.DiscoveredTools {
-			// Set the server name in the component label
-			t.Component.Label = common.GetObjectRef(&toolServer)
-			discoveredTools = append(discoveredTools, &api.Component{
-				Provider:      t.Component.Provider,
-				Label:         t.Component.Label,
-				Description:   t.Component.Description,
-				Config:        convertAnyTypeMapToInterfaceMap(t.Component.Config),
-				ComponentType: t.Component.ComponentType,
-			})
+	for i := range allToolServers.Items {
+		for _, t := range allToolServers.Items[i].Status.DiscoveredTools {
+			discoveredTools = append(discoveredTools, discoveredToolComponent(&allToolServers.Items[i], t))
 		}
 	}
 
@@ -78,8 +83,122 @@ func (h *ToolsHandler) HandleListTools(w ErrorResponseWriter, r *http.Request) {
 		}
 	}
 
-	log.Info("Successfully listed tools", "count", len(tools))
-	RespondWithJSON(w, http.StatusOK, discoveredTools)
+	discoveredTools = sortAndDedupTools(discoveredTools)
+
+	log.Info("Successfully listed tools", "count", len(discoveredTools))
+	RespondWithJSON(w, http.StatusOK, NewResponse(discoveredTools, "Successfully listed tools"))
+}
+
+// discoveredToolComponent converts a tool discovered from toolServer to a component labelled with the server
+func discoveredToolComponent(toolServer *v1alpha1.ToolServer, t *v1alpha1.MCPTool) *api.Component {
+	return &api.Component{
+		Provider:      t.Component.Provider,
+		Label:         common.GetObjectRef(toolServer),
+		Description:   t.Component.Description,
+		Config:        convertAnyTypeMapToInterfaceMap(t.Component.Config),
+		ComponentType: t.Component.ComponentType,
+	}
+}
+
+// streamTools writes the discovered tools of each tool server, then the builtin kagent tools, as
+// JSON lines, flushing after each server. Duplicates are skipped like in sortAndDedupTools.
+// It returns the number of tools written.
+func streamTools(w ErrorResponseWriter, toolServers []v1alpha1.ToolServer, tools []*autogen_client.Tool) (int, error) {
+	sort.SliceStable(toolServers, func(i, j int) bool {
+		return common.GetObjectRef(&toolServers[i]) < common.GetObjectRef(&toolServers[j])
+	})
+
+	startStreamResponse(w, "application/x-ndjson")
+
+	encoder := json.NewEncoder(w)
+	seen := make(map[[3]string]bool)
+	count := 0
+	write := func(tool *api.Component) error {
+		key := toolKey(tool)
+		if seen[key] {
+			return nil
+		}
+		seen[key] = true
+		if err := encoder.Encode(tool); err != nil {
+			return err
+		}
+		count++
+		return nil
+	}
+
+	for i := range toolServers {
+		for _, t := range toolServers[i].Status.DiscoveredTools {
+			if err := write(discoveredToolComponent(&toolServers[i], t)); err != nil {
+				return count, err
+			}
+		}
+		w.Flush()
+	}
+
+	for _, tool := range tools {
+		if strings.HasPrefix(tool.Component.Provider, "kagent") {
+			if err := write(tool.Component); err != nil {
+				return count, err
+			}
+		}
+	}
+	w.Flush()
+
+	return count, nil
+}
+
+// toolKey identifies a tool by provider, label and, for MCP tools that share a provider,
+// the tool name from the config
+func toolKey(tool *api.Component) [3]string {
+	return [3]string{tool.Provider, tool.Label, toolName(tool)}
+}
+
+// toolName returns the name in the tool's "tool" config, if any
+func toolName(tool *api.Component) string {
+	raw, ok := tool.Config["tool"]
+	if !ok {
+		return ""
+	}
+	data, ok := raw.(json.RawMessage)
+	if !ok {
+		var err error
+		if data, err = json.Marshal(raw); err != nil {
+			return ""
+		}
+	}
+	var named struct {
+		Name string `json:"name"`
+	}
+	if err := json.Unmarshal(data, &named); err != nil {
+		return ""
+	}
+	return named.Name
+}
+
+// sortAndDedupTools sorts the tools by provider, label and tool name and keeps the first
+// of each set of duplicates
+func sortAndDedupTools(tools []*api.Component) []*api.Component {
+	seen := make(map[[3]string]bool, len(tools))
+	unique := make([]*api.Component, 0, len(tools))
+	for _, tool := range tools {
+		key := toolKey(tool)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		unique = append(unique, tool)
+	}
+
+	sort.SliceStable(unique, func(i, j int) bool {
+		a, b := toolKey(unique[i]), toolKey(unique[j])
+		for k := range a {
+			if a[k] != b[k] {
+				return a[k] < b[k]
+			}
+		}
+		return false
+	})
+	return unique
 }
 
 func convertMapToMCPToolConfig(data map[string]v1alpha1.AnyType) (api.MCPToolConfig, error) {
@@ -0,0 +1,143 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrllog "sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/kagent-dev/kagent/go/autogen/api"
+	"github.com/kagent-dev/kagent/go/controller/api/v1alpha1"
+	"github.com/kagent-dev/kagent/go/controller/internal/autogen"
+	"github.com/kagent-dev/kagent/go/controller/internal/httpserver/errors"
+	common "github.com/kagent-dev/kagent/go/controller/internal/utils"
+)
+
+// importedToolServerNamespace prefixes the labels of tool servers created by tool imports, so
+// they can't be mistaken for the tool server of a ToolServer resource
+const importedToolServerNamespace = "imported"
+
+// ImportToolsRequest is the body of POST /api/tools/import requests
+type ImportToolsRequest struct {
+	// Name identifies the import. The tool server is registered in Autogen as imported/<name>.
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	// Namespace is where secrets referenced by the config are looked up. It defaults to the
+	// controller's namespace and must be watched by the controller.
+	Namespace string                    `json:"namespace,omitempty"`
+	Config    v1alpha1.ToolServerConfig `json:"config"`
+}
+
+// ImportToolsResponse is returned by a successful tool import
+type ImportToolsResponse struct {
+	ServerID int              `json:"server_id"`
+	Label    string           `json:"label"`
+	Count    int              `json:"count"`
+	Tools    []*api.Component `json:"tools"`
+}
+
+// HandleImportTools handles POST /api/tools/import requests. The tool server is registered in
+// Autogen only, without a ToolServer resource, and Autogen discovers and stores its tools. If
+// discovery fails, the tool server is deleted again so no partial import is left behind.
+func (h *ToolsHandler) HandleImportTools(w ErrorResponseWriter, r *http.Request) {
+	log := ctrllog.FromContext(r.Context()).WithName("tools-handler").WithValues("operation", "import")
+
+	userID, err := GetUserID(r)
+	if err != nil {
+		w.RespondWithError(errors.NewBadRequestError("Failed to get user ID", err))
+		return
+	}
+	log = log.WithValues("userID", userID)
+
+	var importRequest ImportToolsRequest
+	if err := DecodeJSONBody(r, &importRequest); err != nil {
+		w.RespondWithError(invalidBodyError(err))
+		return
+	}
+	if err := validateResourceName(importRequest.Name); err != nil {
+		w.RespondWithError(errors.NewBadRequestError("Invalid import name", err))
+		return
+	}
+	if importRequest.Namespace == "" {
+		importRequest.Namespace = common.GetResourceNamespace()
+	}
+	if !h.isWatchedNamespace(importRequest.Namespace) {
+		w.RespondWithError(errors.NewBadRequestError(
+			fmt.Sprintf("Namespace %s is not watched by the controller", importRequest.Namespace), nil))
+		return
+	}
+	label := common.ResourceRefString(importedToolServerNamespace, importRequest.Name)
+	log = log.WithValues("label", label)
+
+	toolServer, err := autogen.NewAutogenApiTranslator(h.KubeClient, h.DefaultModelConfig).TranslateToolServer(r.Context(), &v1alpha1.ToolServer{
+		ObjectMeta: metav1.ObjectMeta{Name: importRequest.Name, Namespace: importRequest.Namespace},
+		Spec: v1alpha1.ToolServerSpec{
+			Description: importRequest.Description,
+			Config:      importRequest.Config,
+		},
+	})
+	if err != nil {
+		w.RespondWithError(errors.NewBadRequestError("Invalid tool server config", err))
+		return
+	}
+	toolServer.UserID = userID
+	toolServer.Component.Label = label
+
+	existing, err := h.AutogenClient.ListToolServers(userID)
+	if err != nil {
+		w.RespondWithError(errors.NewInternalServerError("Failed to list tool servers", err))
+		return
+	}
+	for _, server := range existing {
+		if server.Component.Label == label {
+			w.RespondWithError(errors.NewConflictError(fmt.Sprintf("Tools were already imported as %s", label), nil))
+			return
+		}
+	}
+
+	// An import adds a tool server, so it counts towards the user's quota like a created one
+	if err := h.checkToolServerQuota(r.Context(), userID); err != nil {
+		log.Info("ToolServer quota check failed", "error", err.Error())
+		w.RespondWithError(err)
+		return
+	}
+
+	log.V(1).Info("Registering tool server in Autogen")
+	created, err := h.AutogenClient.CreateToolServer(toolServer, userID)
+	if err != nil {
+		w.RespondWithError(errors.NewInternalServerError("Failed to create tool server", err))
+		return
+	}
+
+	rollback := func() {
+		if err := h.AutogenClient.DeleteToolServer(&created.Id, userID); err != nil {
+			log.Error(err, "Failed to delete tool server of failed import", "serverID", created.Id)
+		}
+	}
+
+	if err := h.AutogenClient.RefreshToolServer(created.Id, userID); err != nil {
+		rollback()
+		w.RespondWithError(errors.NewInternalServerError("Failed to discover tools", err))
+		return
+	}
+	tools, err := h.AutogenClient.ListToolsForServer(&created.Id, userID)
+	if err != nil {
+		rollback()
+		w.RespondWithError(errors.NewInternalServerError("Failed to list discovered tools", err))
+		return
+	}
+
+	components := make([]*api.Component, 0, len(tools))
+	for _, tool := range tools {
+		components = append(components, tool.Component)
+	}
+
+	log.Info("Successfully imported tools", "serverID", created.Id, "count", len(components))
+	RespondWithJSON(w, http.StatusCreated, NewResponse(&ImportToolsResponse{
+		ServerID: created.Id,
+		Label:    label,
+		Count:    len(components),
+		Tools:    components,
+	}, "Successfully imported tools"))
+}
@@ -0,0 +1,83 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kagent-dev/kagent/go/autogen/api"
+	autogen_client "github.com/kagent-dev/kagent/go/autogen/client"
+	autogen_fake "github.com/kagent-dev/kagent/go/autogen/client/fake"
+	"github.com/kagent-dev/kagent/go/controller/api/v1alpha1"
+)
+
+func TestHandleImportTools(t *testing.T) {
+	handler, userID := setupTestHandler()
+	autogenClient := handler.AutogenClient.(*autogen_fake.InMemoryAutogenClient)
+	tools := NewToolsHandler(handler.Base)
+
+	importTools := func(request *ImportToolsRequest) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(request)
+		req := httptest.NewRequest("POST", "/api/tools/import?user_id="+userID, bytes.NewBuffer(body))
+		w := httptest.NewRecorder()
+		tools.HandleImportTools(&testErrorResponseWriter{w}, req)
+		return w
+	}
+	sseConfig := v1alpha1.ToolServerConfig{
+		Sse: &v1alpha1.SseMcpServerConfig{HttpToolServerConfig: v1alpha1.HttpToolServerConfig{URL: "http://mcp.example.com/sse"}},
+	}
+
+	t.Run("registers the server and returns its discovered tools", func(t *testing.T) {
+		// The fake assigns tool server IDs from 1, so the discovered tools can be set up front
+		autogenClient.AddToolsForServer(1,
+			&autogen_client.Tool{Component: &api.Component{Provider: "mcp.search", Label: "imported/search"}},
+			&autogen_client.Tool{Component: &api.Component{Provider: "mcp.fetch", Label: "imported/search"}},
+		)
+
+		w := importTools(&ImportToolsRequest{Name: "search", Config: sseConfig})
+		require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+
+		var response ImportToolsResponse
+		require.NoError(t, DecodeResponseData(w.Body.Bytes(), &response))
+		assert.Equal(t, 1, response.ServerID)
+		assert.Equal(t, "imported/search", response.Label)
+		assert.Equal(t, 2, response.Count)
+
+		server, err := autogenClient.GetToolServer(1, userID)
+		require.NoError(t, err)
+		assert.Equal(t, "kagent.tool_servers.SseMcpToolServer", server.Component.Provider)
+	})
+
+	t.Run("refuses to import the same name twice", func(t *testing.T) {
+		w := importTools(&ImportToolsRequest{Name: "search", Config: sseConfig})
+		assert.Equal(t, http.StatusConflict, w.Code)
+	})
+
+	t.Run("rejects invalid requests", func(t *testing.T) {
+		assert.Equal(t, http.StatusBadRequest, importTools(&ImportToolsRequest{Name: "Bad Name", Config: sseConfig}).Code)
+		assert.Equal(t, http.StatusBadRequest, importTools(&ImportToolsRequest{Name: "empty"}).Code)
+	})
+
+	t.Run("rejects namespaces the controller doesn't watch", func(t *testing.T) {
+		handler.WatchedNamespaces = []string{"team-a"}
+		defer func() { handler.WatchedNamespaces = nil }()
+
+		w := importTools(&ImportToolsRequest{Name: "unwatched", Namespace: "team-b", Config: sseConfig})
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "Namespace team-b is not watched")
+	})
+
+	t.Run("counts imports towards the tool server quota", func(t *testing.T) {
+		handler.Quotas = QuotaConfig{Default: QuotaLimits{MaxToolServers: 1}}
+		defer func() { handler.Quotas = QuotaConfig{} }()
+
+		w := importTools(&ImportToolsRequest{Name: "fetch", Config: sseConfig})
+		assert.Equal(t, http.StatusTooManyRequests, w.Code)
+		assert.Contains(t, w.Body.String(), "1 of 1 tool servers used")
+	})
+}
@@ -0,0 +1,120 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kagent-dev/kagent/go/autogen/api"
+	"github.com/kagent-dev/kagent/go/controller/api/v1alpha1"
+)
+
+func mcpTool(name string) *v1alpha1.MCPTool {
+	return &v1alpha1.MCPTool{
+		Name: name,
+		Component: v1alpha1.Component{
+			Provider: "autogen_ext.tools.mcp.SseMcpToolAdapter",
+			Config: map[string]v1alpha1.AnyType{
+				"tool": {RawMessage: json.RawMessage(`{"name":"` + name + `"}`)},
+			},
+		},
+	}
+}
+
+func TestHandleListTools(t *testing.T) {
+	t.Run("returns a single sorted entry per tool", func(t *testing.T) {
+		serverB := &v1alpha1.ToolServer{
+			ObjectMeta: metav1.ObjectMeta{Name: "server-b", Namespace: "default"},
+			Status: v1alpha1.ToolServerStatus{
+				DiscoveredTools: []*v1alpha1.MCPTool{mcpTool("search"), mcpTool("fetch"), mcpTool("search")},
+			},
+		}
+		serverA := &v1alpha1.ToolServer{
+			ObjectMeta: metav1.ObjectMeta{Name: "server-a", Namespace: "default"},
+			Status: v1alpha1.ToolServerStatus{
+				DiscoveredTools: []*v1alpha1.MCPTool{mcpTool("search")},
+			},
+		}
+		handler, userID := setupTestHandler(serverB, serverA)
+		tools := NewToolsHandler(handler.Base)
+
+		req := httptest.NewRequest("GET", "/api/tools?user_id="+userID, nil)
+		w := httptest.NewRecorder()
+		tools.HandleListTools(&testErrorResponseWriter{w}, req)
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var response []*api.Component
+		require.NoError(t, DecodeResponseData(w.Body.Bytes(), &response))
+
+		var got []string
+		for _, tool := range response {
+			got = append(got, tool.Label+" "+toolName(tool))
+		}
+		assert.Equal(t, []string{
+			"default/server-a search",
+			"default/server-b fetch",
+			"default/server-b search",
+		}, got)
+	})
+
+	t.Run("streams one tool per line", func(t *testing.T) {
+		serverB := &v1alpha1.ToolServer{
+			ObjectMeta: metav1.ObjectMeta{Name: "server-b", Namespace: "default"},
+			Status: v1alpha1.ToolServerStatus{
+				DiscoveredTools: []*v1alpha1.MCPTool{mcpTool("search"), mcpTool("fetch"), mcpTool("search")},
+			},
+		}
+		serverA := &v1alpha1.ToolServer{
+			ObjectMeta: metav1.ObjectMeta{Name: "server-a", Namespace: "default"},
+			Status: v1alpha1.ToolServerStatus{
+				DiscoveredTools: []*v1alpha1.MCPTool{mcpTool("search")},
+			},
+		}
+		handler, userID := setupTestHandler(serverB, serverA)
+		tools := NewToolsHandler(handler.Base)
+
+		req := httptest.NewRequest("GET", "/api/tools?stream=true&user_id="+userID, nil)
+		w := httptest.NewRecorder()
+		tools.HandleListTools(&testErrorResponseWriter{w}, req)
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "application/x-ndjson", w.Header().Get("Content-Type"))
+
+		var got []string
+		decoder := json.NewDecoder(w.Body)
+		for decoder.More() {
+			var tool api.Component
+			require.NoError(t, decoder.Decode(&tool))
+			got = append(got, tool.Label+" "+toolName(&tool))
+		}
+		assert.Equal(t, []string{
+			"default/server-a search",
+			"default/server-b search",
+			"default/server-b fetch",
+		}, got)
+	})
+}
+
+func TestSortAndDedupTools(t *testing.T) {
+	// Autogen tools carry decoded configs while discovered tools carry raw JSON
+	tools := []*api.Component{
+		{Provider: "kagent.tools.k8s.GetPods", Label: "k8s"},
+		{Provider: "kagent.tools.helm.ListReleases", Label: "helm"},
+		{Provider: "mcp", Label: "default/server", Config: map[string]interface{}{"tool": map[string]interface{}{"name": "b"}}},
+		{Provider: "mcp", Label: "default/server", Config: map[string]interface{}{"tool": json.RawMessage(`{"name":"a"}`)}},
+		{Provider: "mcp", Label: "default/server", Config: map[string]interface{}{"tool": json.RawMessage(`{"name":"b"}`)}},
+		{Provider: "kagent.tools.k8s.GetPods", Label: "k8s", Description: "duplicate"},
+	}
+
+	result := sortAndDedupTools(tools)
+	require.Len(t, result, 4)
+	assert.Equal(t, "kagent.tools.helm.ListReleases", result[0].Provider)
+	assert.Equal(t, "kagent.tools.k8s.GetPods", result[1].Provider)
+	assert.Empty(t, result[1].Description, "the first duplicate is kept")
+	assert.Equal(t, "a", toolName(result[2]))
+	assert.Equal(t, "b", toolName(result[3]))
+}
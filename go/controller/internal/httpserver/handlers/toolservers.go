@@ -1,8 +1,10 @@
 package handlers
 
 import (
+	"fmt"
 	"net/http"
 
+	autogen_client "github.com/kagent-dev/kagent/go/autogen/client"
 	"github.com/kagent-dev/kagent/go/controller/api/v1alpha1"
 	"github.com/kagent-dev/kagent/go/controller/internal/httpserver/errors"
 	common "github.com/kagent-dev/kagent/go/controller/internal/utils"
@@ -47,10 +49,36 @@ func (h *ToolServersHandler) HandleListToolServers(w ErrorResponseWriter, r *htt
 	}
 
 	log.Info("Successfully listed ToolServers", "count", len(toolServerWithTools))
-	RespondWithJSON(w, http.StatusOK, toolServerWithTools)
+	RespondWithJSON(w, http.StatusOK, NewResponse(toolServerWithTools, "Successfully listed ToolServers"))
 }
 
-// HandleCreateToolServer handles POST /api/toolservers requests
+// HandleToolServersHealth handles GET /api/toolservers/health requests. It attempts a connection
+// to each tool server registered in Autogen and reports their statuses, even if some are
+// unreachable.
+func (h *ToolServersHandler) HandleToolServersHealth(w ErrorResponseWriter, r *http.Request) {
+	log := ctrllog.FromContext(r.Context()).WithName("toolservers-handler").WithValues("operation", "health")
+	log.V(1).Info("Received request to check ToolServer health")
+
+	// The controller registers the tool servers of all ToolServers as the global user
+	health, err := h.AutogenClient.ToolServerHealth(r.Context(), common.GetGlobalUserID(), autogen_client.ToolServerHealthOptions{})
+	if err != nil {
+		w.RespondWithError(errors.NewInternalServerError("Failed to check ToolServer health", err))
+		return
+	}
+
+	unhealthy := 0
+	for _, server := range health {
+		if server.Status != autogen_client.ToolServerStatusHealthy {
+			unhealthy++
+		}
+	}
+
+	log.Info("Checked ToolServer health", "count", len(health), "unhealthy", unhealthy)
+	RespondWithJSON(w, http.StatusOK, NewResponse(health, "Successfully checked ToolServer health"))
+}
+
+// HandleCreateToolServer handles POST /api/toolservers requests.
+// If a ToolServer with the same name already exists, its spec is replaced (upsert).
 func (h *ToolServersHandler) HandleCreateToolServer(w ErrorResponseWriter, r *http.Request) {
 	log := ctrllog.FromContext(r.Context()).WithName("toolservers-handler").WithValues("operation", "create")
 	log.Info("Received request to create ToolServer")
@@ -58,7 +86,7 @@ func (h *ToolServersHandler) HandleCreateToolServer(w ErrorResponseWriter, r *ht
 	var toolServerRequest *v1alpha1.ToolServer
 	if err := DecodeJSONBody(r, &toolServerRequest); err != nil {
 		log.Error(err, "Invalid request body")
-		w.RespondWithError(errors.NewBadRequestError("Invalid request body", err))
+		w.RespondWithError(invalidBodyError(err))
 		return
 	}
 
@@ -68,6 +96,7 @@ func (h *ToolServersHandler) HandleCreateToolServer(w ErrorResponseWriter, r *ht
 	toolRef, err := common.ParseRefString(toolServerRequest.Name, toolServerRequest.Namespace)
 	if err != nil {
 		w.RespondWithError(errors.NewBadRequestError("Invalid ToolServer metadata", err))
+		return
 	}
 	if toolRef.Namespace == common.GetResourceNamespace() {
 		log.V(4).Info("Namespace not provided in request. Creating in controller installation namespace",
@@ -79,8 +108,73 @@ func (h *ToolServersHandler) HandleCreateToolServer(w ErrorResponseWriter, r *ht
 		"toolServerNamespace", toolRef.Namespace,
 	)
 
+	// The user ID is optional unless quotas are configured
+	userID, err := GetUserID(r)
+	if err != nil && h.Quotas.Enabled() {
+		w.RespondWithError(errors.NewBadRequestError("user_id is required when quotas are enabled", err))
+		return
+	}
+	if userID != "" {
+		log = log.WithValues("userID", userID)
+
+		existing := &v1alpha1.ToolServer{}
+		err := common.GetObject(r.Context(), h.KubeClient, existing, toolRef.Name, toolRef.Namespace)
+		if err != nil && !k8serrors.IsNotFound(err) {
+			w.RespondWithError(errors.NewInternalServerError("Failed to get existing ToolServer", err))
+			return
+		}
+		exists := err == nil
+		owner := existing.Annotations[ToolServerCreatedByAnnotation]
+		if exists && owner != "" && owner != userID {
+			w.RespondWithError(errors.NewConflictError(
+				fmt.Sprintf("ToolServer %s already exists and belongs to another user", toolRef.String()), nil))
+			return
+		}
+
+		// Re-applying a ToolServer the user already owns does not consume quota
+		if !exists || owner != userID {
+			if err := h.checkToolServerQuota(r.Context(), userID); err != nil {
+				log.Info("ToolServer quota check failed", "error", err.Error())
+				w.RespondWithError(err)
+				return
+			}
+		}
+
+		if toolServerRequest.Annotations == nil {
+			toolServerRequest.Annotations = map[string]string{}
+		}
+		toolServerRequest.Annotations[ToolServerCreatedByAnnotation] = userID
+	}
+
 	if err := h.KubeClient.Create(r.Context(), toolServerRequest); err != nil {
-		w.RespondWithError(errors.NewInternalServerError("Failed to create ToolServer in Kubernetes", err))
+		if !k8serrors.IsAlreadyExists(err) {
+			w.RespondWithError(errors.NewInternalServerError("Failed to create ToolServer in Kubernetes", err))
+			return
+		}
+
+		// Re-applying the same ToolServer updates it in place. The update triggers
+		// a reconcile of the ToolServer, which re-runs tool discovery.
+		log.V(1).Info("ToolServer already exists, updating it")
+		existingToolServer := &v1alpha1.ToolServer{}
+		if err := common.GetObject(
+			r.Context(),
+			h.KubeClient,
+			existingToolServer,
+			toolRef.Name,
+			toolRef.Namespace,
+		); err != nil {
+			w.RespondWithError(errors.NewInternalServerError("Failed to get existing ToolServer", err))
+			return
+		}
+
+		existingToolServer.Spec = toolServerRequest.Spec
+		if err := h.KubeClient.Update(r.Context(), existingToolServer); err != nil {
+			w.RespondWithError(errors.NewInternalServerError("Failed to update ToolServer in Kubernetes", err))
+			return
+		}
+
+		log.Info("Successfully updated existing ToolServer")
+		RespondWithJSON(w, http.StatusOK, existingToolServer)
 		return
 	}
 
@@ -89,6 +183,7 @@ func (h *ToolServersHandler) HandleCreateToolServer(w ErrorResponseWriter, r *ht
 }
 
 // HandleDeleteToolServer handles DELETE /api/toolservers/{namespace}/{toolServerName} requests
+// The controller then removes the tool server from autogen together with the tools discovered from it
 func (h *ToolServersHandler) HandleDeleteToolServer(w ErrorResponseWriter, r *http.Request) {
 	log := ctrllog.FromContext(r.Context()).WithName("toolservers-handler").WithValues("operation", "delete")
 	log.Info("Received request to delete ToolServer")
@@ -19,6 +19,9 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
+	"github.com/kagent-dev/kagent/go/autogen/api"
+	autogen_client "github.com/kagent-dev/kagent/go/autogen/client"
+	autogen_fake "github.com/kagent-dev/kagent/go/autogen/client/fake"
 	"github.com/kagent-dev/kagent/go/controller/api/v1alpha1"
 	"github.com/kagent-dev/kagent/go/controller/internal/httpserver/handlers"
 	common "github.com/kagent-dev/kagent/go/controller/internal/utils"
@@ -121,7 +124,7 @@ func TestToolServersHandler(t *testing.T) {
 			assert.Equal(t, http.StatusOK, responseRecorder.Code)
 
 			var toolServers []handlers.ToolServerResponse
-			err = json.Unmarshal(responseRecorder.Body.Bytes(), &toolServers)
+			err = handlers.DecodeResponseData(responseRecorder.Body.Bytes(), &toolServers)
 			require.NoError(t, err)
 			assert.Len(t, toolServers, 2)
 
@@ -150,7 +153,7 @@ func TestToolServersHandler(t *testing.T) {
 			assert.Equal(t, http.StatusOK, responseRecorder.Code)
 
 			var toolServers []handlers.ToolServerResponse
-			err := json.Unmarshal(responseRecorder.Body.Bytes(), &toolServers)
+			err := handlers.DecodeResponseData(responseRecorder.Body.Bytes(), &toolServers)
 			require.NoError(t, err)
 			assert.Len(t, toolServers, 0)
 		})
@@ -336,8 +339,53 @@ func TestToolServersHandler(t *testing.T) {
 
 			handler.HandleCreateToolServer(responseRecorder, req)
 
-			assert.Equal(t, http.StatusInternalServerError, responseRecorder.Code)
-			assert.NotNil(t, responseRecorder.errorReceived)
+			assert.Equal(t, http.StatusOK, responseRecorder.Code)
+			assert.Nil(t, responseRecorder.errorReceived)
+
+			var toolServer v1alpha1.ToolServer
+			err = json.Unmarshal(responseRecorder.Body.Bytes(), &toolServer)
+			require.NoError(t, err)
+			assert.Equal(t, "New tool server", toolServer.Spec.Description)
+
+			updated := &v1alpha1.ToolServer{}
+			err = kubeClient.Get(context.Background(), types.NamespacedName{Name: "test-toolserver", Namespace: "default"}, updated)
+			require.NoError(t, err)
+			assert.Equal(t, "node", updated.Spec.Config.Stdio.Command)
+		})
+
+		t.Run("PostTwiceIsIdempotent", func(t *testing.T) {
+			handler, kubeClient, _ := setupHandler()
+
+			reqBody := &v1alpha1.ToolServer{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "gitops-toolserver",
+					Namespace: "default",
+				},
+				Spec: v1alpha1.ToolServerSpec{
+					Description: "GitOps tool server",
+					Config: v1alpha1.ToolServerConfig{
+						Stdio: &v1alpha1.StdioMcpServerConfig{
+							Command: "python",
+						},
+					},
+				},
+			}
+			jsonBody, _ := json.Marshal(reqBody)
+
+			first := newMockErrorResponseWriter()
+			req := httptest.NewRequest("POST", "/api/toolservers/", bytes.NewBuffer(jsonBody))
+			handler.HandleCreateToolServer(first, req)
+			assert.Equal(t, http.StatusCreated, first.Code)
+
+			second := newMockErrorResponseWriter()
+			req = httptest.NewRequest("POST", "/api/toolservers/", bytes.NewBuffer(jsonBody))
+			handler.HandleCreateToolServer(second, req)
+			assert.Equal(t, http.StatusOK, second.Code)
+
+			toolServerList := &v1alpha1.ToolServerList{}
+			err := kubeClient.List(context.Background(), toolServerList)
+			require.NoError(t, err)
+			assert.Len(t, toolServerList.Items, 1)
 		})
 	})
 
@@ -419,4 +467,46 @@ func TestToolServersHandler(t *testing.T) {
 			assert.NotNil(t, responseRecorder.errorReceived)
 		})
 	})
+
+	t.Run("HandleToolServersHealth", func(t *testing.T) {
+		reachable := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer reachable.Close()
+
+		autogenClient := autogen_fake.NewInMemoryAutogenClient()
+		userID := common.GetGlobalUserID()
+		_, err := autogenClient.CreateToolServer(&autogen_client.ToolServer{Component: api.Component{
+			Provider: "kagent.tool_servers.SseMcpToolServer",
+			Label:    "kagent/reachable",
+			Config:   map[string]interface{}{"url": reachable.URL},
+		}}, userID)
+		require.NoError(t, err)
+		_, err = autogenClient.CreateToolServer(&autogen_client.ToolServer{Component: api.Component{
+			Provider: "kagent.tool_servers.StdioMcpToolServer",
+			Label:    "kagent/stdio",
+			Config:   map[string]interface{}{"command": "python"},
+		}}, userID)
+		require.NoError(t, err)
+
+		handler := handlers.NewToolServersHandler(&handlers.Base{AutogenClient: autogenClient})
+		responseRecorder := newMockErrorResponseWriter()
+		req := httptest.NewRequest("GET", "/api/toolservers/health", nil)
+		handler.HandleToolServersHealth(responseRecorder, req)
+
+		require.Equal(t, http.StatusOK, responseRecorder.Code)
+		var response struct {
+			Data []autogen_client.ToolServerHealth `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(responseRecorder.Body.Bytes(), &response))
+		require.Len(t, response.Data, 2)
+		assert.Equal(t, "kagent/reachable", response.Data[0].Label)
+		assert.Equal(t, autogen_client.ToolServerStatusHealthy, response.Data[0].Status)
+		assert.NotEmpty(t, response.Data[0].LastConnected)
+		assert.Equal(t, autogen_client.ToolServerStatusUnknown, response.Data[1].Status)
+
+		stored, err := autogenClient.GetToolServerByLabel("kagent/reachable", userID)
+		require.NoError(t, err)
+		assert.Equal(t, response.Data[0].LastConnected, stored.LastConnected)
+	})
 }
@@ -0,0 +1,202 @@
+package handlers
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// UserIDMode selects where the user ID of API requests is read from
+type UserIDMode string
+
+const (
+	// UserIDFromQuery reads the user ID from the user_id query parameter. Clients can claim any
+	// user ID, so it is only suited to trusted clients.
+	UserIDFromQuery UserIDMode = "query"
+	// UserIDFromHeader reads the user ID from a header set by a trusted proxy
+	UserIDFromHeader UserIDMode = "header"
+	// UserIDFromJWT reads the user ID from a claim of the bearer token, whose signature is
+	// verified with the public key of the UserIDSource
+	UserIDFromJWT UserIDMode = "jwt"
+
+	DefaultUserIDHeader = "X-User-ID"
+	DefaultUserIDClaim  = "sub"
+)
+
+// UserIDSource configures how GetUserID identifies the user of a request
+type UserIDSource struct {
+	Mode UserIDMode
+	// Header is read in header mode. It defaults to DefaultUserIDHeader.
+	Header string
+	// Claim is read in jwt mode. It defaults to DefaultUserIDClaim.
+	Claim string
+	// JWTKeyFile is the PEM file of the RSA, ECDSA or Ed25519 public key that bearer tokens
+	// are verified with. It is required in jwt mode.
+	JWTKeyFile string
+
+	// jwtKey and jwtMethods are loaded from JWTKeyFile by Validate
+	jwtKey     interface{}
+	jwtMethods []string
+}
+
+// Validate checks the mode and fills in the defaults of the source
+func (s *UserIDSource) Validate() error {
+	switch s.Mode {
+	case "":
+		s.Mode = UserIDFromQuery
+	case UserIDFromQuery, UserIDFromHeader, UserIDFromJWT:
+	default:
+		return fmt.Errorf("invalid user ID source %q: must be one of %s, %s or %s", s.Mode, UserIDFromQuery, UserIDFromHeader, UserIDFromJWT)
+	}
+	if s.Header == "" {
+		s.Header = DefaultUserIDHeader
+	}
+	if s.Claim == "" {
+		s.Claim = DefaultUserIDClaim
+	}
+	if s.Mode == UserIDFromJWT {
+		if s.JWTKeyFile == "" {
+			return fmt.Errorf("a public key to verify bearer tokens with is required with the %s user ID source", UserIDFromJWT)
+		}
+		if err := s.loadJWTKey(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// loadJWTKey reads the public key of JWTKeyFile and the signing methods it verifies
+func (s *UserIDSource) loadJWTKey() error {
+	data, err := os.ReadFile(s.JWTKeyFile)
+	if err != nil {
+		return fmt.Errorf("failed to read JWT public key: %w", err)
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return fmt.Errorf("JWT public key %s is not PEM encoded", s.JWTKeyFile)
+	}
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return fmt.Errorf("invalid JWT public key %s: %w", s.JWTKeyFile, err)
+	}
+
+	switch key.(type) {
+	case *rsa.PublicKey:
+		s.jwtMethods = []string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512"}
+	case *ecdsa.PublicKey:
+		s.jwtMethods = []string{"ES256", "ES384", "ES512"}
+	case ed25519.PublicKey:
+		s.jwtMethods = []string{"EdDSA"}
+	default:
+		return fmt.Errorf("unsupported JWT public key type %T", key)
+	}
+	s.jwtKey = key
+	return nil
+}
+
+type userIDKey struct{}
+
+type resolvedUserID struct {
+	userID string
+	err    error
+}
+
+// Middleware resolves the user ID of each request with the source, so GetUserID returns it
+// instead of reading the user_id query parameter
+func (s UserIDSource) Middleware(next http.Handler) http.Handler {
+	if s.Mode == "" || s.Mode == UserIDFromQuery {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		userID, err := s.resolve(r)
+		ctx := context.WithValue(r.Context(), userIDKey{}, resolvedUserID{userID: userID, err: err})
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+func (s UserIDSource) resolve(r *http.Request) (string, error) {
+	switch s.Mode {
+	case UserIDFromHeader:
+		userID := strings.TrimSpace(r.Header.Get(s.Header))
+		if userID == "" {
+			return "", fmt.Errorf("%s header is required", s.Header)
+		}
+		return userID, nil
+	case UserIDFromJWT:
+		return s.userIDFromBearerToken(r)
+	}
+	return "", fmt.Errorf("unsupported user ID source %q", s.Mode)
+}
+
+// userIDFromBearerToken verifies the signature and expiry of the bearer token, and returns
+// its user ID claim
+func (s UserIDSource) userIDFromBearerToken(r *http.Request) (string, error) {
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok || strings.TrimSpace(token) == "" {
+		return "", fmt.Errorf("bearer token is required")
+	}
+	if s.jwtKey == nil {
+		return "", fmt.Errorf("no public key is configured to verify bearer tokens")
+	}
+
+	claims := jwt.MapClaims{}
+	parser := jwt.NewParser(jwt.WithValidMethods(s.jwtMethods))
+	if _, err := parser.ParseWithClaims(strings.TrimSpace(token), claims, func(*jwt.Token) (interface{}, error) {
+		return s.jwtKey, nil
+	}); err != nil {
+		return "", fmt.Errorf("invalid bearer token: %w", err)
+	}
+	userID, _ := claims[s.Claim].(string)
+	if userID == "" {
+		return "", fmt.Errorf("bearer token has no %s claim", s.Claim)
+	}
+	return userID, nil
+}
+
+// userIDResolved reports whether the user ID of the request was resolved by a UserIDSource
+// rather than read from the user_id query parameter
+func userIDResolved(r *http.Request) bool {
+	_, ok := r.Context().Value(userIDKey{}).(resolvedUserID)
+	return ok
+}
+
+// LoggedUserID returns the user ID to log for the request: the user ID resolved by the
+// UserIDSource, or the user_id query parameter in query mode. It is empty if the request has
+// none, or if it couldn't be resolved.
+func LoggedUserID(r *http.Request) string {
+	if resolved, ok := r.Context().Value(userIDKey{}).(resolvedUserID); ok {
+		return resolved.userID
+	}
+	return r.URL.Query().Get("user_id")
+}
+
+// RequestUserID returns the user a request acts for when its body can name one. With a
+// UserIDSource the resolved user is used, so that bodies can't act for another user: a
+// different body user ID is rejected. Otherwise the body user ID is used, and must match the
+// user_id query parameter if both are set.
+func RequestUserID(r *http.Request, bodyUserID string) (string, error) {
+	if !userIDResolved(r) && bodyUserID != "" {
+		if queryUserID := r.URL.Query().Get("user_id"); queryUserID != "" && queryUserID != bodyUserID {
+			return "", fmt.Errorf("user_id %q of the body doesn't match the user_id %q of the query", bodyUserID, queryUserID)
+		}
+		return bodyUserID, nil
+	}
+
+	userID, err := GetUserID(r)
+	if err != nil {
+		return "", err
+	}
+	if bodyUserID != "" && bodyUserID != userID {
+		return "", fmt.Errorf("user_id %q of the body doesn't match the user of the request", bodyUserID)
+	}
+	return userID, nil
+}
@@ -0,0 +1,195 @@
+package handlers
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUserIDSource(t *testing.T) {
+	// resolve runs the request through the source's middleware and returns what GetUserID sees
+	resolve := func(source UserIDSource, req *http.Request) (string, error) {
+		require.NoError(t, source.Validate())
+		var userID string
+		var err error
+		source.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			userID, err = GetUserID(r)
+		})).ServeHTTP(httptest.NewRecorder(), req)
+		return userID, err
+	}
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKIXPublicKey(publicKey)
+	require.NoError(t, err)
+	keyFile := filepath.Join(t.TempDir(), "jwt.pem")
+	require.NoError(t, os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), 0600))
+	jwtSource := func(claim string) UserIDSource {
+		return UserIDSource{Mode: UserIDFromJWT, Claim: claim, JWTKeyFile: keyFile}
+	}
+
+	token := func(claims jwt.MapClaims) string {
+		signed, err := jwt.NewWithClaims(jwt.SigningMethodEdDSA, claims).SignedString(privateKey)
+		require.NoError(t, err)
+		return signed
+	}
+
+	t.Run("query mode reads the user_id parameter", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/api/sessions?user_id=alice", nil)
+		req.Header.Set(DefaultUserIDHeader, "mallory")
+
+		userID, err := resolve(UserIDSource{}, req)
+		require.NoError(t, err)
+		assert.Equal(t, "alice", userID)
+	})
+
+	t.Run("header mode ignores the user_id parameter", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/api/sessions?user_id=mallory", nil)
+		req.Header.Set("X-Forwarded-User", "alice")
+
+		userID, err := resolve(UserIDSource{Mode: UserIDFromHeader, Header: "X-Forwarded-User"}, req)
+		require.NoError(t, err)
+		assert.Equal(t, "alice", userID)
+
+		_, err = resolve(UserIDSource{Mode: UserIDFromHeader}, httptest.NewRequest("GET", "/api/sessions?user_id=mallory", nil))
+		assert.ErrorContains(t, err, "X-User-ID header is required")
+	})
+
+	t.Run("jwt mode reads the claim of the bearer token", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/api/sessions?user_id=mallory", nil)
+		req.Header.Set("Authorization", "Bearer "+token(jwt.MapClaims{"sub": "alice", "email": "alice@example.com"}))
+
+		userID, err := resolve(jwtSource(""), req)
+		require.NoError(t, err)
+		assert.Equal(t, "alice", userID)
+
+		userID, err = resolve(jwtSource("email"), req)
+		require.NoError(t, err)
+		assert.Equal(t, "alice@example.com", userID)
+
+		_, err = resolve(jwtSource("preferred_username"), req)
+		assert.ErrorContains(t, err, "has no preferred_username claim")
+	})
+
+	t.Run("jwt mode rejects missing and malformed tokens", func(t *testing.T) {
+		_, err := resolve(jwtSource(""), httptest.NewRequest("GET", "/api/sessions", nil))
+		assert.ErrorContains(t, err, "bearer token is required")
+
+		req := httptest.NewRequest("GET", "/api/sessions", nil)
+		req.Header.Set("Authorization", "Bearer not-a-jwt")
+		_, err = resolve(jwtSource(""), req)
+		assert.ErrorContains(t, err, "invalid bearer token")
+	})
+
+	t.Run("jwt mode rejects forged and expired tokens", func(t *testing.T) {
+		forged, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"sub": "alice"}).SignedString([]byte("secret"))
+		require.NoError(t, err)
+		_, otherKey, err := ed25519.GenerateKey(rand.Reader)
+		require.NoError(t, err)
+		otherSigned, err := jwt.NewWithClaims(jwt.SigningMethodEdDSA, jwt.MapClaims{"sub": "alice"}).SignedString(otherKey)
+		require.NoError(t, err)
+		expired := token(jwt.MapClaims{"sub": "alice", "exp": time.Now().Add(-time.Minute).Unix()})
+
+		for name, bearer := range map[string]string{"wrong algorithm": forged, "wrong key": otherSigned, "expired": expired} {
+			req := httptest.NewRequest("GET", "/api/sessions", nil)
+			req.Header.Set("Authorization", "Bearer "+bearer)
+			_, err := resolve(jwtSource(""), req)
+			assert.ErrorContains(t, err, "invalid bearer token", name)
+		}
+	})
+
+	t.Run("jwt mode requires a public key", func(t *testing.T) {
+		source := UserIDSource{Mode: UserIDFromJWT}
+		assert.ErrorContains(t, source.Validate(), "public key")
+
+		source = UserIDSource{Mode: UserIDFromJWT, JWTKeyFile: filepath.Join(t.TempDir(), "missing.pem")}
+		assert.Error(t, source.Validate())
+	})
+
+	t.Run("rejects unknown modes", func(t *testing.T) {
+		source := UserIDSource{Mode: "cookie"}
+		assert.Error(t, source.Validate())
+	})
+}
+
+func TestLoggedUserID(t *testing.T) {
+	loggedUserID := func(source UserIDSource, req *http.Request) string {
+		var userID string
+		source.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			userID = LoggedUserID(r)
+		})).ServeHTTP(httptest.NewRecorder(), req)
+		return userID
+	}
+	headerSource := UserIDSource{Mode: UserIDFromHeader, Header: "X-Forwarded-User"}
+
+	t.Run("query mode logs the user_id parameter", func(t *testing.T) {
+		assert.Equal(t, "alice", loggedUserID(UserIDSource{}, httptest.NewRequest("GET", "/api/sessions?user_id=alice", nil)))
+	})
+
+	t.Run("header mode logs the resolved user", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/api/sessions?user_id=mallory", nil)
+		req.Header.Set("X-Forwarded-User", "alice")
+		assert.Equal(t, "alice", loggedUserID(headerSource, req))
+	})
+
+	t.Run("unresolved users aren't logged", func(t *testing.T) {
+		assert.Empty(t, loggedUserID(headerSource, httptest.NewRequest("GET", "/api/sessions?user_id=mallory", nil)))
+	})
+}
+
+func TestRequestUserID(t *testing.T) {
+	requestUserID := func(source UserIDSource, req *http.Request, bodyUserID string) (string, error) {
+		require.NoError(t, source.Validate())
+		var userID string
+		var err error
+		source.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			userID, err = RequestUserID(r, bodyUserID)
+		})).ServeHTTP(httptest.NewRecorder(), req)
+		return userID, err
+	}
+	headerSource := UserIDSource{Mode: UserIDFromHeader}
+
+	t.Run("query mode uses the body user", func(t *testing.T) {
+		userID, err := requestUserID(UserIDSource{}, httptest.NewRequest("POST", "/api/sessions", nil), "alice")
+		require.NoError(t, err)
+		assert.Equal(t, "alice", userID)
+
+		userID, err = requestUserID(UserIDSource{}, httptest.NewRequest("POST", "/api/sessions?user_id=alice", nil), "")
+		require.NoError(t, err)
+		assert.Equal(t, "alice", userID)
+
+		_, err = requestUserID(UserIDSource{}, httptest.NewRequest("POST", "/api/sessions?user_id=alice", nil), "mallory")
+		assert.ErrorContains(t, err, "doesn't match")
+	})
+
+	t.Run("resolved users can't be overridden by the body", func(t *testing.T) {
+		req := httptest.NewRequest("POST", "/api/sessions", nil)
+		req.Header.Set(DefaultUserIDHeader, "alice")
+
+		userID, err := requestUserID(headerSource, req, "")
+		require.NoError(t, err)
+		assert.Equal(t, "alice", userID)
+
+		userID, err = requestUserID(headerSource, req, "alice")
+		require.NoError(t, err)
+		assert.Equal(t, "alice", userID)
+
+		_, err = requestUserID(headerSource, req, "mallory")
+		assert.ErrorContains(t, err, "doesn't match")
+	})
+
+	t.Run("a body user isn't used when the user can't be resolved", func(t *testing.T) {
+		_, err := requestUserID(headerSource, httptest.NewRequest("POST", "/api/sessions", nil), "mallory")
+		assert.ErrorContains(t, err, "X-User-ID header is required")
+	})
+}
@@ -0,0 +1,70 @@
+package handlers
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// maxPathParamLength bounds path parameters without a more specific rule
+const maxPathParamLength = 253
+
+// contextIDPattern is the character set allowed in A2A context IDs
+var contextIDPattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._:@-]*$`)
+
+// pathParamValidators check path parameters by name, so invalid values are rejected with a
+// 400 before they are used to look up Kubernetes or Autogen resources
+var pathParamValidators = map[string]func(value string) error{
+	"namespace":      validateNamespace,
+	"teamName":       validateResourceName,
+	"configName":     validateResourceName,
+	"memoryName":     validateResourceName,
+	"toolServerName": validateResourceName,
+	"contextID":      validateContextID,
+}
+
+// validatePathParam validates the value of the named path parameter
+func validatePathParam(name, value string) error {
+	if validate, ok := pathParamValidators[name]; ok {
+		if err := validate(value); err != nil {
+			return fmt.Errorf("invalid %s: %w", name, err)
+		}
+		return nil
+	}
+	if len(value) > maxPathParamLength {
+		return fmt.Errorf("invalid %s: must be no more than %d characters", name, maxPathParamLength)
+	}
+	for _, c := range value {
+		if c < 0x20 || c == 0x7f {
+			return fmt.Errorf("invalid %s: must not contain control characters", name)
+		}
+	}
+	return nil
+}
+
+func validateNamespace(value string) error {
+	return validationErrors(validation.IsDNS1123Label(value))
+}
+
+func validateResourceName(value string) error {
+	return validationErrors(validation.IsDNS1123Subdomain(value))
+}
+
+func validateContextID(value string) error {
+	if len(value) > maxPathParamLength {
+		return fmt.Errorf("must be no more than %d characters", maxPathParamLength)
+	}
+	if !contextIDPattern.MatchString(value) {
+		return fmt.Errorf("must start with a letter or digit and contain only letters, digits, '.', '_', ':', '@' or '-'")
+	}
+	return nil
+}
+
+func validationErrors(errs []string) error {
+	if len(errs) == 0 {
+		return nil
+	}
+	return fmt.Errorf("%s", strings.Join(errs, "; "))
+}
@@ -0,0 +1,98 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetPathParamValidation(t *testing.T) {
+	tests := []struct {
+		name    string
+		param   string
+		value   string
+		wantErr string
+	}{
+		{name: "valid namespace", param: "namespace", value: "kagent"},
+		{name: "namespace at the length limit", param: "namespace", value: strings.Repeat("a", 63)},
+		{name: "namespace too long", param: "namespace", value: strings.Repeat("a", 64), wantErr: "invalid namespace"},
+		{name: "namespace with dots", param: "namespace", value: "kagent.dev", wantErr: "invalid namespace"},
+		{name: "valid resource name", param: "teamName", value: "k8s-agent.v2"},
+		{name: "resource name at the length limit", param: "configName", value: strings.Repeat("a", 253)},
+		{name: "resource name too long", param: "memoryName", value: strings.Repeat("a", 254), wantErr: "invalid memoryName"},
+		{name: "uppercase resource name", param: "toolServerName", value: "MyServer", wantErr: "invalid toolServerName"},
+		{name: "resource name with a slash", param: "teamName", value: "kagent/agent", wantErr: "invalid teamName"},
+		{name: "valid context ID", param: "contextID", value: "ctx-1:user@example.com"},
+		{name: "context ID with spaces", param: "contextID", value: "ctx 1", wantErr: "invalid contextID"},
+		{name: "context ID starting with a dash", param: "contextID", value: "-ctx", wantErr: "invalid contextID"},
+		{name: "other parameter", param: "agentId", value: "12"},
+		{name: "other parameter too long", param: "agentId", value: strings.Repeat("1", 254), wantErr: "no more than 253 characters"},
+		{name: "other parameter with control characters", param: "agentId", value: "1\n2", wantErr: "control characters"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := mux.SetURLVars(httptest.NewRequest("GET", "/", nil), map[string]string{tt.param: tt.value})
+			value, err := GetPathParam(r, tt.param)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.value, value)
+		})
+	}
+}
+
+func TestGetIntPathParamValidation(t *testing.T) {
+	for value, wantErr := range map[string]string{
+		"1":   "",
+		"0":   "must be a positive integer",
+		"-5":  "must be a positive integer",
+		"abc": "must be an integer",
+	} {
+		t.Run(value, func(t *testing.T) {
+			r := mux.SetURLVars(httptest.NewRequest("GET", "/", nil), map[string]string{"sessionID": value})
+			_, err := GetIntPathParam(r, "sessionID")
+			if wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), wantErr)
+		})
+	}
+}
+
+func TestInvalidPathParamsAreRejected(t *testing.T) {
+	handler, userID := setupTestHandler()
+
+	t.Run("session", func(t *testing.T) {
+		req := mux.SetURLVars(httptest.NewRequest("GET", "/api/sessions/-1?user_id="+userID, nil), map[string]string{"sessionID": "-1"})
+		w := httptest.NewRecorder()
+		NewSessionsHandler(handler.Base).HandleGetSession(&testErrorResponseWriter{w}, req)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("team", func(t *testing.T) {
+		req := mux.SetURLVars(httptest.NewRequest("DELETE", "/api/teams/default/Bad_Name?user_id="+userID, nil),
+			map[string]string{"namespace": "default", "teamName": "Bad_Name"})
+		w := httptest.NewRecorder()
+		handler.HandleDeleteTeam(&testErrorResponseWriter{w}, req)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("tool server", func(t *testing.T) {
+		req := mux.SetURLVars(httptest.NewRequest("DELETE", "/api/toolservers/Default/server?user_id="+userID, nil),
+			map[string]string{"namespace": "Default", "toolServerName": "server"})
+		w := httptest.NewRecorder()
+		NewToolServersHandler(handler.Base).HandleDeleteToolServer(&testErrorResponseWriter{w}, req)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
@@ -0,0 +1,30 @@
+package handlers
+
+import (
+	"net/http"
+
+	autogen_client "github.com/kagent-dev/kagent/go/autogen/client"
+	"github.com/kagent-dev/kagent/go/internal/version"
+)
+
+// VersionHandler handles version requests
+type VersionHandler struct {
+	features map[string]bool
+}
+
+// NewVersionHandler creates a new VersionHandler reporting the given features. The features
+// must only say whether each is enabled, as the endpoint is unauthenticated.
+func NewVersionHandler(features map[string]bool) *VersionHandler {
+	return &VersionHandler{features: features}
+}
+
+// HandleVersion handles GET /api/version requests. The response has the shape of Autogen's
+// version endpoint, so autogen_client.Client.GetVersionInfo reads either.
+func (h *VersionHandler) HandleVersion(w http.ResponseWriter, r *http.Request) {
+	RespondWithJSON(w, http.StatusOK, autogen_client.VersionResponse{
+		Version:   version.Version,
+		GitCommit: version.GitCommit,
+		BuildDate: version.BuildDate,
+		Features:  h.features,
+	})
+}
@@ -0,0 +1,26 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	autogen_client "github.com/kagent-dev/kagent/go/autogen/client"
+	"github.com/kagent-dev/kagent/go/internal/version"
+)
+
+func TestHandleVersion(t *testing.T) {
+	features := map[string]bool{"strict_json": true, "quotas": false}
+	w := httptest.NewRecorder()
+	NewVersionHandler(features).HandleVersion(w, httptest.NewRequest("GET", "/api/version", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var response autogen_client.VersionResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, version.Version, response.Version)
+	assert.Equal(t, features, response.Features)
+}
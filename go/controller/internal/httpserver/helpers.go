@@ -1,32 +0,0 @@
-package httpserver
-
-import (
-	"encoding/json"
-	"fmt"
-	"net/http"
-)
-
-// Common HTTP response helpers
-func respondWithJSON(w http.ResponseWriter, code int, payload interface{}) {
-	response, err := json.Marshal(payload)
-	if err != nil {
-		respondWithError(w, http.StatusInternalServerError, "Error marshalling JSON response")
-		return
-	}
-
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(code)
-	w.Write(response)
-}
-
-func respondWithError(w http.ResponseWriter, code int, message string) {
-	respondWithJSON(w, code, map[string]string{"error": message})
-}
-
-func getUserID(r *http.Request) (string, error) {
-	userID := r.URL.Query().Get("user_id")
-	if userID == "" {
-		return "", fmt.Errorf("user_id is required")
-	}
-	return userID, nil
-}
@@ -4,21 +4,34 @@ import (
 	"net/http"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/kagent-dev/kagent/go/controller/internal/httpserver/handlers"
 	ctrllog "sigs.k8s.io/controller-runtime/pkg/log"
 )
 
+// requestIDHeader carries the ID of a request. It is generated unless the client sends one,
+// and echoed in the response so errors can be matched with the controller logs.
+const requestIDHeader = "X-Request-ID"
+
 func loggingMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		start := time.Now()
 
+		requestID := r.Header.Get(requestIDHeader)
+		if requestID == "" {
+			requestID = uuid.NewString()
+		}
+		w.Header().Set(requestIDHeader, requestID)
+
 		log := ctrllog.Log.WithName("http").WithValues(
 			"method", r.Method,
 			"path", r.URL.Path,
 			"remote_addr", r.RemoteAddr,
+			"request_id", requestID,
 		)
 
-		if userID := r.URL.Query().Get("user_id"); userID != "" {
+		// The user ID source middleware runs first, so this is the user the handlers see
+		if userID := handlers.LoggedUserID(r); userID != "" {
 			log = log.WithValues("user_id", userID)
 		}
 
@@ -33,6 +46,14 @@ func loggingMiddleware(next http.Handler) http.Handler {
 	})
 }
 
+// bodyLimitMiddleware sets the default request body limit of all routes. Routes wrapped with
+// handlers.WithMaxBodyBytes override it.
+func bodyLimitMiddleware(limit int64) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return handlers.WithMaxBodyBytes(limit, next.ServeHTTP)
+	}
+}
+
 // For streaming responses in A2A lib
 var _ http.Flusher = &statusResponseWriter{}
 
@@ -51,6 +72,11 @@ func (w *statusResponseWriter) Flush() {
 	}
 }
 
+// Unwrap lets http.ResponseController reach the connection, e.g. to set its deadlines
+func (w *statusResponseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
 func (w *statusResponseWriter) WriteHeader(code int) {
 	w.status = code
 	w.ResponseWriter.WriteHeader(code)
@@ -1,7 +1,7 @@
 package httpserver
 
 import (
-	"encoding/json"
+	stderrors "errors"
 	"net/http"
 
 	"github.com/kagent-dev/kagent/go/controller/internal/httpserver/errors"
@@ -35,15 +35,19 @@ func (w *errorResponseWriter) Flush() {
 	}
 }
 
+// Unwrap lets http.ResponseController reach the connection, e.g. to set its deadlines
+func (w *errorResponseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
 func (w *errorResponseWriter) RespondWithError(err error) {
 	log := ctrllog.FromContext(w.request.Context())
 
-	statusCode := http.StatusInternalServerError
 	message := "Internal server error"
 	detail := ""
 
-	if apiErr, ok := err.(*errors.APIError); ok {
-		statusCode = apiErr.Code
+	var apiErr *errors.APIError
+	if stderrors.As(err, &apiErr) {
 		message = apiErr.Message
 		if apiErr.Err != nil {
 			detail = apiErr.Err.Error()
@@ -61,7 +65,5 @@ func (w *errorResponseWriter) RespondWithError(err error) {
 		responseMessage = message + ": " + detail
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(statusCode)
-	json.NewEncoder(w).Encode(map[string]string{"error": responseMessage})
+	handlers.RespondWithJSON(w, errors.StatusOf(err), map[string]string{"error": responseMessage, "code": string(errors.CodeOf(err))})
 }
@@ -0,0 +1,45 @@
+package httpserver
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	autogen_client "github.com/kagent-dev/kagent/go/autogen/client"
+	"github.com/kagent-dev/kagent/go/controller/internal/httpserver/errors"
+	"github.com/kagent-dev/kagent/go/controller/internal/httpserver/handlers"
+)
+
+func TestErrorResponseWriter(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+		wantCode   errors.ErrorCode
+	}{
+		{"api error", errors.NewConflictError("Session is busy", nil), http.StatusConflict, errors.CodeConflict},
+		{"wrapped api error", fmt.Errorf("invoke: %w", errors.NewNotFoundError("Session not found", nil).WithCode(errors.CodeSessionNotFound)), http.StatusNotFound, errors.CodeSessionNotFound},
+		{"plain sentinel error", fmt.Errorf("run 1: %w", autogen_client.NotFoundError), http.StatusNotFound, errors.CodeNotFound},
+		{"plain error", fmt.Errorf("boom"), http.StatusInternalServerError, errors.CodeInternal},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := errorHandlerMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.(handlers.ErrorResponseWriter).RespondWithError(tt.err)
+			}))
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, httptest.NewRequest("GET", "/api/sessions/1", nil))
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+			var body map[string]string
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+			assert.Equal(t, string(tt.wantCode), body["code"])
+		})
+	}
+}
@@ -0,0 +1,39 @@
+package httpserver
+
+import (
+	"fmt"
+	"net/http"
+	"runtime/debug"
+
+	"github.com/kagent-dev/kagent/go/controller/internal/httpserver/errors"
+	"github.com/kagent-dev/kagent/go/controller/internal/httpserver/handlers"
+	ctrllog "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// recoveryMiddleware turns a panic in a handler into a 500 response. The stack is logged with
+// the request ID, and only the request ID is returned to the client.
+func recoveryMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer func() {
+			recovered := recover()
+			if recovered == nil {
+				return
+			}
+			// Let net/http abort the response as the handler intended
+			if recovered == http.ErrAbortHandler {
+				panic(recovered)
+			}
+
+			ctrllog.FromContext(r.Context()).Error(fmt.Errorf("panic: %v", recovered), "Handler panicked",
+				"stack", string(debug.Stack()))
+
+			handlers.RespondWithJSON(w, http.StatusInternalServerError, map[string]string{
+				"error":      "Internal server error",
+				"code":       string(errors.CodeInternal),
+				"request_id": w.Header().Get(requestIDHeader),
+			})
+		}()
+
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,59 @@
+package httpserver
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecoveryMiddleware(t *testing.T) {
+	router := mux.NewRouter()
+	router.HandleFunc("/api/panic", func(w http.ResponseWriter, r *http.Request) {
+		panic("something went wrong")
+	})
+	router.HandleFunc("/api/ok", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	router.Use(loggingMiddleware)
+	router.Use(recoveryMiddleware)
+	router.Use(errorHandlerMiddleware)
+
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	t.Run("returns 500 with the request ID", func(t *testing.T) {
+		req, err := http.NewRequest("GET", server.URL+"/api/panic", nil)
+		require.NoError(t, err)
+		req.Header.Set(requestIDHeader, "req-1")
+
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+		assert.Equal(t, "req-1", resp.Header.Get(requestIDHeader))
+
+		var body map[string]string
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+		assert.Equal(t, map[string]string{"error": "Internal server error", "code": "internal_error", "request_id": "req-1"}, body)
+	})
+
+	t.Run("generates a request ID", func(t *testing.T) {
+		resp, err := http.Get(server.URL + "/api/panic")
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+		assert.NotEmpty(t, resp.Header.Get(requestIDHeader))
+	})
+
+	t.Run("keeps serving requests after a panic", func(t *testing.T) {
+		resp, err := http.Get(server.URL + "/api/ok")
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+	})
+}
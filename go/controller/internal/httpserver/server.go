@@ -18,6 +18,8 @@ import (
 const (
 	// API Path constants
 	APIPathHealth      = "/health"
+	APIPathReady       = "/ready"
+	APIPathVersion     = "/api/version"
 	APIPathModelConfig = "/api/modelconfigs"
 	APIPathRuns        = "/api/runs"
 	APIPathSessions    = "/api/sessions"
@@ -31,6 +33,7 @@ const (
 	APIPathNamespaces  = "/api/namespaces"
 	APIPathA2A         = "/api/a2a"
 	APIPathFeedback    = "/api/feedback"
+	APIPathQuota       = "/api/quota"
 )
 
 var defaultModelConfig = types.NamespacedName{
@@ -45,6 +48,59 @@ type ServerConfig struct {
 	KubeClient        client.Client
 	A2AHandler        a2a.A2AHandlerMux
 	WatchedNamespaces []string
+	Quotas            handlers.QuotaConfig
+	// AllowedAutogenURLs are the Autogen backends invocations may select with X-Autogen-URL
+	AllowedAutogenURLs []string
+	// AutogenClientOptions are the options AutogenClient was created with, which the clients of
+	// the alternate backends are created with too
+	AutogenClientOptions []autogen_client.Option
+	// MaxBodyBytes limits the size of request bodies, and MaxInvokeBodyBytes that of invoke requests.
+	// 0 uses handlers.DefaultMaxBodyBytes and handlers.DefaultMaxInvokeBodyBytes.
+	MaxBodyBytes       int64
+	MaxInvokeBodyBytes int64
+	// StrictJSON makes create and update requests fail on unknown fields instead of ignoring them
+	StrictJSON bool
+	// MaxConcurrentInvocations and MaxConcurrentInvocationsPerUser limit the invocations served
+	// at the same time, in total and per user. 0 means unlimited.
+	MaxConcurrentInvocations        int
+	MaxConcurrentInvocationsPerUser int
+	// UserIDSource selects where the user ID of requests is read from. The zero value reads
+	// the user_id query parameter.
+	UserIDSource handlers.UserIDSource
+	// MessageRedactor transforms the tasks and feedback sent to Autogen before they are stored,
+	// e.g. to scrub secrets. nil sends them unchanged.
+	MessageRedactor autogen_client.MessageRedactor
+	// ReadHeaderTimeout, ReadTimeout, WriteTimeout and IdleTimeout are the timeouts of the
+	// server. 0 uses DefaultReadHeaderTimeout, DefaultReadTimeout, DefaultWriteTimeout and
+	// DefaultIdleTimeout. Invocations and A2A requests are exempt from the read and write
+	// timeouts, since they last as long as the agent runs.
+	ReadHeaderTimeout time.Duration
+	ReadTimeout       time.Duration
+	WriteTimeout      time.Duration
+	IdleTimeout       time.Duration
+}
+
+// Optional features reported by the version endpoint
+const (
+	FeatureStrictJSON        = "strict_json"
+	FeatureQuotas            = "quotas"
+	FeatureInvokeLimits      = "invoke_limits"
+	FeatureAlternateBackends = "alternate_backends"
+	FeatureMessageRedaction  = "message_redaction"
+	FeatureProxyUserID       = "proxy_user_id"
+)
+
+// Features returns whether each optional feature is enabled by the config. It only reports
+// enablement, never the values configuring a feature, since the version endpoint is public.
+func (c ServerConfig) Features() map[string]bool {
+	return map[string]bool{
+		FeatureStrictJSON:        c.StrictJSON,
+		FeatureQuotas:            c.Quotas.Enabled(),
+		FeatureInvokeLimits:      c.MaxConcurrentInvocations > 0 || c.MaxConcurrentInvocationsPerUser > 0,
+		FeatureAlternateBackends: len(c.AllowedAutogenURLs) > 0,
+		FeatureMessageRedaction:  c.MessageRedactor != nil,
+		FeatureProxyUserID:       c.UserIDSource.Mode == handlers.UserIDFromHeader || c.UserIDSource.Mode == handlers.UserIDFromJWT,
+	}
 }
 
 // HTTPServer is the structure that manages the HTTP server
@@ -53,6 +109,7 @@ type HTTPServer struct {
 	config     ServerConfig
 	router     *mux.Router
 	handlers   *handlers.Handlers
+	invokes    *handlers.InvokeLimiter
 }
 
 // NewHTTPServer creates a new HTTP server instance
@@ -60,7 +117,8 @@ func NewHTTPServer(config ServerConfig) *HTTPServer {
 	return &HTTPServer{
 		config:   config,
 		router:   mux.NewRouter(),
-		handlers: handlers.NewHandlers(config.KubeClient, config.AutogenClient, defaultModelConfig, config.WatchedNamespaces),
+		handlers: handlers.NewHandlers(config.KubeClient, config.AutogenClient, defaultModelConfig, config.WatchedNamespaces, config.Quotas, config.AllowedAutogenURLs, config.AutogenClientOptions, config.MessageRedactor, config.Features()),
+		invokes:  handlers.NewInvokeLimiter(config.MaxConcurrentInvocations, config.MaxConcurrentInvocationsPerUser),
 	}
 }
 
@@ -73,10 +131,7 @@ func (s *HTTPServer) Start(ctx context.Context) error {
 	s.setupRoutes()
 
 	// Create HTTP server
-	s.httpServer = &http.Server{
-		Addr:    s.config.BindAddr,
-		Handler: s.router,
-	}
+	s.httpServer = s.newHTTPServer()
 
 	// Start the server in a separate goroutine
 	go func() {
@@ -117,42 +172,64 @@ func (s *HTTPServer) NeedLeaderElection() bool {
 func (s *HTTPServer) setupRoutes() {
 	// Health check endpoint
 	s.router.HandleFunc(APIPathHealth, adaptHealthHandler(s.handlers.Health.HandleHealth)).Methods(http.MethodGet)
+	s.router.HandleFunc(APIPathReady, adaptHealthHandler(s.handlers.Health.HandleReady)).Methods(http.MethodGet)
+	s.router.HandleFunc(APIPathVersion, adaptHealthHandler(s.handlers.Version.HandleVersion)).Methods(http.MethodGet)
 
 	// Model configs
 	s.router.HandleFunc(APIPathModelConfig, adaptHandler(s.handlers.ModelConfig.HandleListModelConfigs)).Methods(http.MethodGet)
 	s.router.HandleFunc(APIPathModelConfig+"/{namespace}/{configName}", adaptHandler(s.handlers.ModelConfig.HandleGetModelConfig)).Methods(http.MethodGet)
-	s.router.HandleFunc(APIPathModelConfig, adaptHandler(s.handlers.ModelConfig.HandleCreateModelConfig)).Methods(http.MethodPost)
+	s.router.HandleFunc(APIPathModelConfig, s.strictJSON(adaptHandler(s.handlers.ModelConfig.HandleCreateModelConfig))).Methods(http.MethodPost)
 	s.router.HandleFunc(APIPathModelConfig+"/{namespace}/{configName}", adaptHandler(s.handlers.ModelConfig.HandleDeleteModelConfig)).Methods(http.MethodDelete)
-	s.router.HandleFunc(APIPathModelConfig+"/{namespace}/{configName}", adaptHandler(s.handlers.ModelConfig.HandleUpdateModelConfig)).Methods(http.MethodPut)
+	s.router.HandleFunc(APIPathModelConfig+"/{namespace}/{configName}", s.strictJSON(adaptHandler(s.handlers.ModelConfig.HandleUpdateModelConfig))).Methods(http.MethodPut)
 
 	// Sessions
 	s.router.HandleFunc(APIPathSessions, adaptHandler(s.handlers.Sessions.HandleListSessions)).Methods(http.MethodGet)
-	s.router.HandleFunc(APIPathSessions, adaptHandler(s.handlers.Sessions.HandleCreateSession)).Methods(http.MethodPost)
+	s.router.HandleFunc(APIPathSessions, s.strictJSON(adaptHandler(s.handlers.Sessions.HandleCreateSession))).Methods(http.MethodPost)
 	s.router.HandleFunc(APIPathSessions+"/{sessionID}", adaptHandler(s.handlers.Sessions.HandleGetSession)).Methods(http.MethodGet)
-	s.router.HandleFunc(APIPathSessions+"/{sessionID}/invoke", adaptHandler(s.handlers.Sessions.HandleSessionInvoke)).Methods(http.MethodPost)
-	s.router.HandleFunc(APIPathSessions+"/{sessionID}/invoke/stream", adaptHandler(s.handlers.Sessions.HandleSessionInvokeStream)).Methods(http.MethodPost)
+	s.router.HandleFunc(APIPathSessions+"/{sessionID}/invoke", s.invoke(adaptHandler(s.handlers.Sessions.HandleSessionInvoke))).Methods(http.MethodPost)
+	s.router.HandleFunc(APIPathSessions+"/{sessionID}/invoke/stream", s.invoke(adaptHandler(s.handlers.Sessions.HandleSessionInvokeStream))).Methods(http.MethodPost)
+	s.router.HandleFunc(APIPathSessions+"/{sessionID}/runs/{runID}/rerun", s.invoke(adaptHandler(s.handlers.Sessions.HandleRerunSessionRun))).Methods(http.MethodPost)
 	s.router.HandleFunc(APIPathSessions+"/{sessionID}/messages", adaptHandler(s.handlers.Sessions.HandleListSessionMessages)).Methods(http.MethodGet)
+	s.router.HandleFunc(APIPathSessions+"/{sessionID}/runs/{runID}/export", adaptHandler(s.handlers.Sessions.HandleExportSessionRun)).Methods(http.MethodGet)
+	s.router.HandleFunc(APIPathSessions+"/{sessionID}/summary", s.invoke(adaptHandler(s.handlers.Sessions.HandleGetSessionSummary))).Methods(http.MethodGet)
+	s.router.HandleFunc(APIPathSessions+"/{sessionID}/stats", adaptHandler(s.handlers.Sessions.HandleGetSessionStats)).Methods(http.MethodGet)
 	s.router.HandleFunc(APIPathSessions+"/{sessionID}", adaptHandler(s.handlers.Sessions.HandleDeleteSession)).Methods(http.MethodDelete)
-	s.router.HandleFunc(APIPathSessions+"/{sessionID}", adaptHandler(s.handlers.Sessions.HandleUpdateSession)).Methods(http.MethodPut)
+	s.router.HandleFunc(APIPathSessions+"/{sessionID}", s.strictJSON(adaptHandler(s.handlers.Sessions.HandleUpdateSession))).Methods(http.MethodPut)
+	s.router.HandleFunc(APIPathSessions+"/{sessionID}", adaptHandler(s.handlers.Sessions.HandlePatchSession)).Methods(http.MethodPatch)
+
+	// Runs
+	s.router.HandleFunc(APIPathRuns, adaptHandler(s.handlers.Runs.HandleListRuns)).Methods(http.MethodGet)
 
 	// Tools
 	s.router.HandleFunc(APIPathTools, adaptHandler(s.handlers.Tools.HandleListTools)).Methods(http.MethodGet)
+	s.router.HandleFunc(APIPathTools+"/import", s.strictJSON(adaptHandler(s.handlers.Tools.HandleImportTools))).Methods(http.MethodPost)
 
 	// Tool Servers
 	s.router.HandleFunc(APIPathToolServers, adaptHandler(s.handlers.ToolServers.HandleListToolServers)).Methods(http.MethodGet)
-	s.router.HandleFunc(APIPathToolServers, adaptHandler(s.handlers.ToolServers.HandleCreateToolServer)).Methods(http.MethodPost)
+	s.router.HandleFunc(APIPathToolServers, s.strictJSON(adaptHandler(s.handlers.ToolServers.HandleCreateToolServer))).Methods(http.MethodPost)
+	s.router.HandleFunc(APIPathToolServers+"/health", adaptHandler(s.handlers.ToolServers.HandleToolServersHealth)).Methods(http.MethodGet)
 	s.router.HandleFunc(APIPathToolServers+"/{namespace}/{toolServerName}", adaptHandler(s.handlers.ToolServers.HandleDeleteToolServer)).Methods(http.MethodDelete)
 
 	// Teams
 	s.router.HandleFunc(APIPathTeams, adaptHandler(s.handlers.Teams.HandleListTeams)).Methods(http.MethodGet)
-	s.router.HandleFunc(APIPathTeams, adaptHandler(s.handlers.Teams.HandleCreateTeam)).Methods(http.MethodPost)
-	s.router.HandleFunc(APIPathTeams, adaptHandler(s.handlers.Teams.HandleUpdateTeam)).Methods(http.MethodPut)
+	s.router.HandleFunc(APIPathTeams, s.strictJSON(adaptHandler(s.handlers.Teams.HandleCreateTeam))).Methods(http.MethodPost)
+	s.router.HandleFunc(APIPathTeams, s.strictJSON(adaptHandler(s.handlers.Teams.HandleUpdateTeam))).Methods(http.MethodPut)
+	s.router.HandleFunc(APIPathTeams+"/validate", adaptHandler(s.handlers.Teams.HandleValidateTeam)).Methods(http.MethodPost)
+	s.router.HandleFunc(APIPathTeams+"/batchGet", adaptHandler(s.handlers.Teams.HandleBatchGetTeams)).Methods(http.MethodPost)
 	s.router.HandleFunc(APIPathTeams+"/{teamID}", adaptHandler(s.handlers.Teams.HandleGetTeam)).Methods(http.MethodGet)
 	s.router.HandleFunc(APIPathTeams+"/{namespace}/{teamName}", adaptHandler(s.handlers.Teams.HandleDeleteTeam)).Methods(http.MethodDelete)
+	s.router.HandleFunc(APIPathTeams+"/{namespace}/{teamName}", adaptHandler(s.handlers.Teams.HandlePatchTeam)).Methods(http.MethodPatch)
 
 	// Agents
-	s.router.HandleFunc(APIPathAgents+"/{agentId}/invoke", adaptHandler(s.handlers.Invoke.HandleInvokeAgent)).Methods(http.MethodPost)
-	s.router.HandleFunc(APIPathAgents+"/{agentId}/invoke/stream", adaptHandler(s.handlers.Invoke.HandleInvokeAgentStream)).Methods(http.MethodPost)
+	s.router.HandleFunc(APIPathAgents, adaptHandler(s.handlers.Teams.HandleListTeams)).Methods(http.MethodGet)
+	s.router.HandleFunc(APIPathAgents+"/validate", adaptHandler(s.handlers.Teams.HandleValidateTeam)).Methods(http.MethodPost)
+	s.router.HandleFunc(APIPathAgents+"/batchGet", adaptHandler(s.handlers.Teams.HandleBatchGetTeams)).Methods(http.MethodPost)
+	s.router.HandleFunc(APIPathAgents+"/{agentId}/invoke", s.invoke(adaptHandler(s.handlers.Invoke.HandleInvokeAgent))).Methods(http.MethodPost)
+	s.router.HandleFunc(APIPathAgents+"/{namespace}/{teamName}", s.strictJSON(adaptHandler(s.handlers.Teams.HandleUpsertTeam))).Methods(http.MethodPut)
+	s.router.HandleFunc(APIPathAgents+"/{namespace}/{teamName}/skills", adaptHandler(s.handlers.Teams.HandleGetTeamSkills)).Methods(http.MethodGet)
+	s.router.HandleFunc(APIPathAgents+"/{namespace}/{teamName}/tools", adaptHandler(s.handlers.Teams.HandleListAgentTools)).Methods(http.MethodGet)
+	s.router.HandleFunc(APIPathAgents+"/{namespace}/{teamName}/ping", s.invoke(adaptHandler(s.handlers.Invoke.HandlePingAgent))).Methods(http.MethodPost)
+	s.router.HandleFunc(APIPathAgents+"/{agentId}/invoke/stream", s.invoke(adaptHandler(s.handlers.Invoke.HandleInvokeAgentStream))).Methods(http.MethodPost)
 
 	// Providers
 	s.router.HandleFunc(APIPathProviders+"/models", adaptHandler(s.handlers.Provider.HandleListSupportedModelProviders)).Methods(http.MethodGet)
@@ -163,27 +240,60 @@ func (s *HTTPServer) setupRoutes() {
 
 	// Memories
 	s.router.HandleFunc(APIPathMemories, adaptHandler(s.handlers.Memory.HandleListMemories)).Methods(http.MethodGet)
-	s.router.HandleFunc(APIPathMemories, adaptHandler(s.handlers.Memory.HandleCreateMemory)).Methods(http.MethodPost)
+	s.router.HandleFunc(APIPathMemories, s.strictJSON(adaptHandler(s.handlers.Memory.HandleCreateMemory))).Methods(http.MethodPost)
 	s.router.HandleFunc(APIPathMemories+"/{namespace}/{memoryName}", adaptHandler(s.handlers.Memory.HandleDeleteMemory)).Methods(http.MethodDelete)
 	s.router.HandleFunc(APIPathMemories+"/{namespace}/{memoryName}", adaptHandler(s.handlers.Memory.HandleGetMemory)).Methods(http.MethodGet)
-	s.router.HandleFunc(APIPathMemories+"/{namespace}/{memoryName}", adaptHandler(s.handlers.Memory.HandleUpdateMemory)).Methods(http.MethodPut)
+	s.router.HandleFunc(APIPathMemories+"/{namespace}/{memoryName}", s.strictJSON(adaptHandler(s.handlers.Memory.HandleUpdateMemory))).Methods(http.MethodPut)
 
 	// Namespaces
 	s.router.HandleFunc(APIPathNamespaces, adaptHandler(s.handlers.Namespaces.HandleListNamespaces)).Methods(http.MethodGet)
 
 	// Feedback
-	s.router.HandleFunc(APIPathFeedback, adaptHandler(s.handlers.Feedback.HandleCreateFeedback)).Methods(http.MethodPost)
+	s.router.HandleFunc(APIPathFeedback, s.strictJSON(adaptHandler(s.handlers.Feedback.HandleCreateFeedback))).Methods(http.MethodPost)
 	s.router.HandleFunc(APIPathFeedback, adaptHandler(s.handlers.Feedback.HandleListFeedback)).Methods(http.MethodGet)
+	s.router.HandleFunc(APIPathFeedback+"/batch", s.strictJSON(adaptHandler(s.handlers.Feedback.HandleCreateFeedbackBatch))).Methods(http.MethodPost)
+
+	// Quota
+	s.router.HandleFunc(APIPathQuota, adaptHandler(s.handlers.Quota.HandleGetQuota)).Methods(http.MethodGet)
+
+	// A2A conversations, registered ahead of the A2A handler that serves the rest of its path
+	s.router.HandleFunc(APIPathA2A+"/conversations/{contextID}/messages", adaptHandler(s.handlers.Conversations.HandleListConversationMessages)).Methods(http.MethodGet)
 
 	// A2A
-	s.router.PathPrefix(APIPathA2A).Handler(s.config.A2AHandler)
+	s.router.PathPrefix(APIPathA2A).Handler(withoutTimeouts(s.config.A2AHandler))
 
 	// Use middleware for common functionality
 	s.router.Use(contentTypeMiddleware)
+	s.router.Use(bodyLimitMiddleware(s.config.MaxBodyBytes))
+	s.router.Use(s.config.UserIDSource.Middleware)
 	s.router.Use(loggingMiddleware)
+	s.router.Use(recoveryMiddleware)
 	s.router.Use(errorHandlerMiddleware)
 }
 
+// strictJSON rejects unknown request body fields in h if StrictJSON is set
+func (s *HTTPServer) strictJSON(h http.HandlerFunc) http.HandlerFunc {
+	if !s.config.StrictJSON {
+		return h
+	}
+	return handlers.WithStrictJSON(h)
+}
+
+// invokeBodyLimit applies the invoke request body limit to h
+func (s *HTTPServer) invokeBodyLimit(h http.HandlerFunc) http.HandlerFunc {
+	limit := s.config.MaxInvokeBodyBytes
+	if limit <= 0 {
+		limit = handlers.DefaultMaxInvokeBodyBytes
+	}
+	return handlers.WithMaxBodyBytes(limit, h)
+}
+
+// invoke applies the invoke body limit and concurrency limits to h, and lifts the server's
+// read and write timeouts
+func (s *HTTPServer) invoke(h http.HandlerFunc) http.HandlerFunc {
+	return withoutTimeouts(s.invokeBodyLimit(s.invokes.Limit(h))).ServeHTTP
+}
+
 func adaptHandler(h func(handlers.ErrorResponseWriter, *http.Request)) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		h(w.(handlers.ErrorResponseWriter), r)
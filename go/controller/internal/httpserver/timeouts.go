@@ -0,0 +1,58 @@
+package httpserver
+
+import (
+	"net/http"
+	"time"
+
+	ctrllog "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const (
+	// DefaultReadHeaderTimeout bounds how long a client can take to send the request headers,
+	// so that slow clients can't hold connections open
+	DefaultReadHeaderTimeout = 10 * time.Second
+	// DefaultReadTimeout bounds how long a client can take to send the whole request
+	DefaultReadTimeout = time.Minute
+	// DefaultWriteTimeout bounds how long the response of a request can take, from the end of
+	// its headers
+	DefaultWriteTimeout = time.Minute
+	// DefaultIdleTimeout bounds how long a keep-alive connection waits for the next request
+	DefaultIdleTimeout = 2 * time.Minute
+)
+
+// timeoutOrDefault returns timeout, or fallback if it's not set
+func timeoutOrDefault(timeout, fallback time.Duration) time.Duration {
+	if timeout <= 0 {
+		return fallback
+	}
+	return timeout
+}
+
+// newHTTPServer creates the server of the router with the configured timeouts
+func (s *HTTPServer) newHTTPServer() *http.Server {
+	return &http.Server{
+		Addr:              s.config.BindAddr,
+		Handler:           s.router,
+		ReadHeaderTimeout: timeoutOrDefault(s.config.ReadHeaderTimeout, DefaultReadHeaderTimeout),
+		ReadTimeout:       timeoutOrDefault(s.config.ReadTimeout, DefaultReadTimeout),
+		WriteTimeout:      timeoutOrDefault(s.config.WriteTimeout, DefaultWriteTimeout),
+		IdleTimeout:       timeoutOrDefault(s.config.IdleTimeout, DefaultIdleTimeout),
+	}
+}
+
+// withoutTimeouts lifts the read and write timeouts of the server for h. Invocations respond
+// once the agent is done and streams write for as long as it runs, which both the write
+// timeout and the read timeout, whose expiry cancels the request context, would cut short.
+// The header timeout still applies, and the body is bounded by the body limits.
+func withoutTimeouts(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rc := http.NewResponseController(w)
+		if err := rc.SetReadDeadline(time.Time{}); err != nil {
+			ctrllog.FromContext(r.Context()).V(1).Info("Failed to lift the read timeout", "error", err.Error())
+		}
+		if err := rc.SetWriteDeadline(time.Time{}); err != nil {
+			ctrllog.FromContext(r.Context()).V(1).Info("Failed to lift the write timeout", "error", err.Error())
+		}
+		h.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,73 @@
+package httpserver
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// serveWithTimeouts serves the router with the timeouts of config and returns its address
+func serveWithTimeouts(t *testing.T, config ServerConfig, router *mux.Router) string {
+	t.Helper()
+	s := &HTTPServer{config: config, router: router}
+	server := s.newHTTPServer()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	go func() { _ = server.Serve(listener) }()
+	t.Cleanup(func() { _ = server.Close() })
+	return listener.Addr().String()
+}
+
+func TestServerTimeouts(t *testing.T) {
+	slow := func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(200 * time.Millisecond)
+		_, _ = w.Write([]byte("done"))
+	}
+	router := mux.NewRouter()
+	router.HandleFunc("/api/slow", slow)
+	router.Handle("/api/slow/stream", withoutTimeouts(http.HandlerFunc(slow)))
+	router.Use(loggingMiddleware)
+	router.Use(errorHandlerMiddleware)
+
+	addr := serveWithTimeouts(t, ServerConfig{
+		ReadHeaderTimeout: 100 * time.Millisecond,
+		WriteTimeout:      100 * time.Millisecond,
+	}, router)
+
+	t.Run("closes connections with slow headers", func(t *testing.T) {
+		conn, err := net.Dial("tcp", addr)
+		require.NoError(t, err)
+		defer conn.Close()
+
+		// Send part of the headers and never finish them
+		_, err = conn.Write([]byte("GET /api/slow HTTP/1.1\r\nHost: localhost\r\n"))
+		require.NoError(t, err)
+
+		// The read ends when the server closes the connection, well before the client deadline
+		require.NoError(t, conn.SetReadDeadline(time.Now().Add(2*time.Second)))
+		_, err = io.ReadAll(conn)
+		assert.NoError(t, err, "expected the server to close the connection")
+	})
+
+	t.Run("cuts off slow responses", func(t *testing.T) {
+		_, err := http.Get("http://" + addr + "/api/slow")
+		assert.Error(t, err)
+	})
+
+	t.Run("exempt routes outlast the write timeout", func(t *testing.T) {
+		resp, err := http.Get("http://" + addr + "/api/slow/stream")
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		body, err := io.ReadAll(bufio.NewReader(resp.Body))
+		require.NoError(t, err)
+		assert.Equal(t, "done", string(body))
+	})
+}
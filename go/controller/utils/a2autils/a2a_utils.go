@@ -16,3 +16,13 @@ func ExtractText(message protocol.Message) string {
 	}
 	return builder.String()
 }
+
+// AgentURL returns the URL of an agent's A2A server, base/namespace/name. A trailing slash of
+// base is ignored, and agents without a namespace are served at base/name.
+func AgentURL(base, namespace, name string) string {
+	base = strings.TrimRight(base, "/")
+	if namespace == "" {
+		return base + "/" + name
+	}
+	return base + "/" + namespace + "/" + name
+}
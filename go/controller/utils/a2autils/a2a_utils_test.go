@@ -0,0 +1,26 @@
+package a2autils
+
+import "testing"
+
+func TestAgentURL(t *testing.T) {
+	tests := []struct {
+		name      string
+		base      string
+		namespace string
+		agent     string
+		expected  string
+	}{
+		{"namespaced", "http://127.0.0.1:8083/api/a2a", "kagent", "k8s-agent", "http://127.0.0.1:8083/api/a2a/kagent/k8s-agent"},
+		{"trailing slash", "http://127.0.0.1:8083/api/a2a/", "kagent", "k8s-agent", "http://127.0.0.1:8083/api/a2a/kagent/k8s-agent"},
+		{"trailing slashes", "http://127.0.0.1:8083/api/a2a//", "kagent", "k8s-agent", "http://127.0.0.1:8083/api/a2a/kagent/k8s-agent"},
+		{"without namespace", "http://127.0.0.1:8083/api/a2a", "", "k8s-agent", "http://127.0.0.1:8083/api/a2a/k8s-agent"},
+		{"without namespace and trailing slash", "http://127.0.0.1:8083/api/a2a/", "", "k8s-agent", "http://127.0.0.1:8083/api/a2a/k8s-agent"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := AgentURL(tt.base, tt.namespace, tt.agent); got != tt.expected {
+				t.Errorf("expected %s, got %s", tt.expected, got)
+			}
+		})
+	}
+}
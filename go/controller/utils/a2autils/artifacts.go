@@ -0,0 +1,105 @@
+package a2autils
+
+import (
+	"strings"
+
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+)
+
+// Artifact is a task artifact with its parts grouped by type.
+type Artifact struct {
+	ID          string                 `json:"id"`
+	Name        string                 `json:"name,omitempty"`
+	Description string                 `json:"description,omitempty"`
+	Metadata    map[string]interface{} `json:"metadata,omitempty"`
+	// Text is the concatenation of all text parts.
+	Text  string         `json:"text,omitempty"`
+	Files []ArtifactFile `json:"files,omitempty"`
+	Data  []ArtifactData `json:"data,omitempty"`
+}
+
+// ArtifactFile is a file part of an artifact. Exactly one of Bytes or URI is set.
+type ArtifactFile struct {
+	Name     string `json:"name,omitempty"`
+	MimeType string `json:"mime_type,omitempty"`
+	// Bytes is the base64-encoded file content.
+	Bytes    string                 `json:"bytes,omitempty"`
+	URI      string                 `json:"uri,omitempty"`
+	Metadata map[string]interface{} `json:"metadata,omitempty"`
+}
+
+// ArtifactData is a structured data part of an artifact.
+type ArtifactData struct {
+	Data     interface{}            `json:"data"`
+	Metadata map[string]interface{} `json:"metadata,omitempty"`
+}
+
+// ExtractArtifacts groups the parts of each artifact of a task by type.
+func ExtractArtifacts(task *protocol.Task) []Artifact {
+	if task == nil {
+		return nil
+	}
+
+	artifacts := make([]Artifact, 0, len(task.Artifacts))
+	for _, artifact := range task.Artifacts {
+		artifacts = append(artifacts, extractArtifact(artifact))
+	}
+	return artifacts
+}
+
+func extractArtifact(artifact protocol.Artifact) Artifact {
+	result := Artifact{
+		ID:       artifact.ArtifactID,
+		Metadata: artifact.Metadata,
+	}
+	if artifact.Name != nil {
+		result.Name = *artifact.Name
+	}
+	if artifact.Description != nil {
+		result.Description = *artifact.Description
+	}
+
+	text := strings.Builder{}
+	for _, part := range artifact.Parts {
+		// Parts are values when built locally and pointers when unmarshalled
+		switch typed := part.(type) {
+		case protocol.TextPart:
+			text.WriteString(typed.Text)
+		case *protocol.TextPart:
+			text.WriteString(typed.Text)
+		case protocol.FilePart:
+			result.Files = append(result.Files, extractFile(typed))
+		case *protocol.FilePart:
+			result.Files = append(result.Files, extractFile(*typed))
+		case protocol.DataPart:
+			result.Data = append(result.Data, ArtifactData{Data: typed.Data, Metadata: typed.Metadata})
+		case *protocol.DataPart:
+			result.Data = append(result.Data, ArtifactData{Data: typed.Data, Metadata: typed.Metadata})
+		}
+	}
+	result.Text = text.String()
+
+	return result
+}
+
+func extractFile(part protocol.FilePart) ArtifactFile {
+	file := ArtifactFile{Metadata: part.Metadata}
+	switch typed := part.File.(type) {
+	case *protocol.FileWithBytes:
+		file.Name = stringValue(typed.Name)
+		file.MimeType = stringValue(typed.MimeType)
+		file.Bytes = typed.Bytes
+	case *protocol.FileWithURI:
+		file.Name = stringValue(typed.Name)
+		file.MimeType = stringValue(typed.MimeType)
+		file.URI = typed.URI
+	}
+	return file
+}
+
+func stringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
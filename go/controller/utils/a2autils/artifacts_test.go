@@ -0,0 +1,78 @@
+package a2autils
+
+import (
+	"encoding/json"
+	"testing"
+
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+)
+
+func TestExtractArtifacts(t *testing.T) {
+	name := "report"
+	task := &protocol.Task{
+		ID: "task-1",
+		Artifacts: []protocol.Artifact{
+			{
+				ArtifactID: "artifact-1",
+				Name:       &name,
+				Parts: []protocol.Part{
+					protocol.NewTextPart("hello "),
+					protocol.NewTextPart("world"),
+					protocol.NewFilePartWithBytes("report.pdf", "application/pdf", "ZGF0YQ=="),
+					protocol.NewFilePartWithURI("logs.txt", "text/plain", "https://example.com/logs.txt"),
+					protocol.NewDataPart(map[string]interface{}{"status": "ok"}),
+				},
+			},
+		},
+	}
+
+	check := func(t *testing.T, artifacts []Artifact) {
+		if len(artifacts) != 1 {
+			t.Fatalf("expected 1 artifact, got %d", len(artifacts))
+		}
+		artifact := artifacts[0]
+		if artifact.ID != "artifact-1" || artifact.Name != "report" {
+			t.Errorf("unexpected artifact identity: %+v", artifact)
+		}
+		if artifact.Text != "hello world" {
+			t.Errorf("expected text %q, got %q", "hello world", artifact.Text)
+		}
+		if len(artifact.Files) != 2 {
+			t.Fatalf("expected 2 files, got %d", len(artifact.Files))
+		}
+		if artifact.Files[0].Name != "report.pdf" || artifact.Files[0].MimeType != "application/pdf" || artifact.Files[0].Bytes != "ZGF0YQ==" {
+			t.Errorf("unexpected bytes file: %+v", artifact.Files[0])
+		}
+		if artifact.Files[1].URI != "https://example.com/logs.txt" || artifact.Files[1].Bytes != "" {
+			t.Errorf("unexpected uri file: %+v", artifact.Files[1])
+		}
+		if len(artifact.Data) != 1 {
+			t.Fatalf("expected 1 data part, got %d", len(artifact.Data))
+		}
+		if data, _ := artifact.Data[0].Data.(map[string]interface{}); data["status"] != "ok" {
+			t.Errorf("unexpected data: %+v", artifact.Data[0].Data)
+		}
+	}
+
+	t.Run("value parts", func(t *testing.T) {
+		check(t, ExtractArtifacts(task))
+	})
+
+	t.Run("unmarshalled parts", func(t *testing.T) {
+		raw, err := json.Marshal(task)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var decoded protocol.Task
+		if err := json.Unmarshal(raw, &decoded); err != nil {
+			t.Fatal(err)
+		}
+		check(t, ExtractArtifacts(&decoded))
+	})
+
+	t.Run("nil task", func(t *testing.T) {
+		if artifacts := ExtractArtifacts(nil); artifacts != nil {
+			t.Errorf("expected nil, got %+v", artifacts)
+		}
+	})
+}
@@ -78,7 +78,7 @@ func TestE2E(t *testing.T) {
 	runAgentInteraction := func(agentLabel, prompt string) string {
 		sess, team := createOrFetchAgentSession(agentLabel)
 
-		result, err := agentClient.InvokeSession(sess.ID, GlobalUserID, &autogen_client.InvokeRequest{
+		result, err := agentClient.InvokeSession(context.Background(), sess.ID, GlobalUserID, &autogen_client.InvokeRequest{
 			Task:       prompt + `\nComplete the task without asking for confirmation, even if the task involves creating or deleting namespaces or other critical resources.`,
 			TeamConfig: team.Component,
 		})
@@ -21,7 +21,9 @@ import (
 	"github.com/kagent-dev/kagent/go/tools/pkg/helm"
 	"github.com/kagent-dev/kagent/go/tools/pkg/istio"
 	"github.com/kagent-dev/kagent/go/tools/pkg/k8s"
+	"github.com/kagent-dev/kagent/go/tools/pkg/k8sgpt"
 	"github.com/kagent-dev/kagent/go/tools/pkg/prometheus"
+	"github.com/kagent-dev/kagent/go/tools/pkg/registry"
 	"github.com/mark3labs/mcp-go/server"
 	"github.com/spf13/cobra"
 )
@@ -31,6 +33,9 @@ var (
 	stdio bool
 	tools []string
 
+	toolConfig      string
+	allowedCommands []string
+
 	// These variables should be set during build time using -ldflags
 	Name      = "kagent-tools-server"
 	Version   = version.Version
@@ -48,6 +53,8 @@ func init() {
 	rootCmd.Flags().IntVarP(&port, "port", "p", 8084, "Port to run the server on")
 	rootCmd.Flags().BoolVar(&stdio, "stdio", false, "Use stdio for communication instead of HTTP")
 	rootCmd.Flags().StringSliceVar(&tools, "tools", []string{}, "List of tools to register. If empty, all tools are registered.")
+	rootCmd.Flags().StringVar(&toolConfig, "tool-config", "", "Path to a YAML or JSON file of custom command tools to register.")
+	rootCmd.Flags().StringSliceVar(&allowedCommands, "allowed-commands", registry.DefaultAllowedCommands, "Commands that custom tools are allowed to run.")
 }
 
 func main() {
@@ -74,6 +81,12 @@ func run(cmd *cobra.Command, args []string) {
 
 	// Register tools
 	registerMCP(mcp, tools)
+	if toolConfig != "" {
+		if err := registerCustomTools(mcp, toolConfig, allowedCommands); err != nil {
+			logger.Get().Error(err, "Failed to register custom tools", "config", toolConfig)
+			os.Exit(1)
+		}
+	}
 
 	// Create wait group for server goroutines
 	var wg sync.WaitGroup
@@ -150,6 +163,7 @@ func registerMCP(mcp *server.MCPServer, enabledToolProviders []string) {
 		"istio":      istio.RegisterIstioTools,
 		"argo":       argo.RegisterArgoTools,
 		"cilium":     cilium.RegisterCiliumTools,
+		"k8sgpt":     k8sgpt.RegisterK8sgptTools,
 	}
 
 	// If no tools specified, register all tools
@@ -173,3 +187,16 @@ func registerMCP(mcp *server.MCPServer, enabledToolProviders []string) {
 		}
 	}
 }
+
+func registerCustomTools(mcp *server.MCPServer, path string, allowedCommands []string) error {
+	config, err := registry.LoadConfig(path)
+	if err != nil {
+		return err
+	}
+
+	if err := registry.NewRegistry(allowedCommands).Register(mcp, config.Tools); err != nil {
+		return err
+	}
+	logger.Get().Info("Registered custom tools", "config", path, "count", len(config.Tools))
+	return nil
+}
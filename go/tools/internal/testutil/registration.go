@@ -0,0 +1,50 @@
+// Package testutil holds helpers for the tests of the tool packages
+package testutil
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// ListRegisteredTools registers tools into a throwaway MCP server and returns
+// the tools it advertises through tools/list, sorted by name
+func ListRegisteredTools(register func(*server.MCPServer)) ([]mcp.Tool, error) {
+	s := server.NewMCPServer("registration-test", "0.0.0")
+	register(s)
+
+	request := []byte(`{"jsonrpc":"2.0","id":1,"method":"tools/list"}`)
+	response := s.HandleMessage(context.Background(), request)
+
+	// Round-trip through JSON so we read exactly what a client would see
+	raw, err := json.Marshal(response)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal tools/list response: %w", err)
+	}
+
+	var parsed struct {
+		Result *mcp.ListToolsResult `json:"result"`
+		Error  *struct {
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal(raw, &parsed); err != nil {
+		return nil, fmt.Errorf("failed to parse tools/list response: %w", err)
+	}
+	if parsed.Error != nil {
+		return nil, fmt.Errorf("tools/list failed: %s", parsed.Error.Message)
+	}
+	if parsed.Result == nil {
+		return nil, fmt.Errorf("tools/list returned no result")
+	}
+
+	tools := parsed.Result.Tools
+	sort.Slice(tools, func(i, j int) bool {
+		return tools[i].Name < tools[j].Name
+	})
+	return tools, nil
+}
@@ -0,0 +1,32 @@
+package testutil
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func noopHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return mcp.NewToolResultText(""), nil
+}
+
+func TestListRegisteredTools(t *testing.T) {
+	tools, err := ListRegisteredTools(func(s *server.MCPServer) {
+		s.AddTool(mcp.NewTool("b_tool", mcp.WithDescription("Second tool")), noopHandler)
+		s.AddTool(mcp.NewTool("a_tool", mcp.WithDescription("First tool")), noopHandler)
+	})
+	require.NoError(t, err)
+	require.Len(t, tools, 2)
+	assert.Equal(t, "a_tool", tools[0].Name)
+	assert.Equal(t, "First tool", tools[0].Description)
+	assert.Equal(t, "b_tool", tools[1].Name)
+}
+
+func TestListRegisteredToolsEmpty(t *testing.T) {
+	_, err := ListRegisteredTools(func(s *server.MCPServer) {})
+	assert.Error(t, err)
+}
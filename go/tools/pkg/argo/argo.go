@@ -21,7 +21,10 @@ import (
 // Argo Rollouts tools
 
 func handleVerifyArgoRolloutsControllerInstall(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-	ns := mcp.ParseString(request, "namespace", "argo-rollouts")
+	ns, err := utils.ParseNamespace(request, "argo-rollouts")
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
 	label := mcp.ParseString(request, "label", "app.kubernetes.io/component=rollouts-controller")
 
 	cmd := []string{"get", "pods", "-n", ns, "-l", label, "-o", "jsonpath={.items[*].status.phase}"}
@@ -74,7 +77,10 @@ func handleVerifyKubectlPluginInstall(ctx context.Context, request mcp.CallToolR
 
 func handlePromoteRollout(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	rolloutName := mcp.ParseString(request, "rollout_name", "")
-	ns := mcp.ParseString(request, "namespace", "")
+	ns, err := utils.ParseNamespace(request, "")
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
 	fullStr := mcp.ParseString(request, "full", "false")
 	full := fullStr == "true"
 
@@ -101,7 +107,10 @@ func handlePromoteRollout(ctx context.Context, request mcp.CallToolRequest) (*mc
 
 func handlePauseRollout(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	rolloutName := mcp.ParseString(request, "rollout_name", "")
-	ns := mcp.ParseString(request, "namespace", "")
+	ns, err := utils.ParseNamespace(request, "")
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
 
 	if rolloutName == "" {
 		return mcp.NewToolResultError("rollout_name parameter is required"), nil
@@ -124,7 +133,10 @@ func handlePauseRollout(ctx context.Context, request mcp.CallToolRequest) (*mcp.
 func handleSetRolloutImage(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	rolloutName := mcp.ParseString(request, "rollout_name", "")
 	containerImage := mcp.ParseString(request, "container_image", "")
-	ns := mcp.ParseString(request, "namespace", "")
+	ns, err := utils.ParseNamespace(request, "")
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
 
 	if rolloutName == "" {
 		return mcp.NewToolResultError("rollout_name parameter is required"), nil
@@ -270,7 +282,10 @@ data:
 
 func handleVerifyGatewayPlugin(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	version := mcp.ParseString(request, "version", "")
-	namespace := mcp.ParseString(request, "namespace", "argo-rollouts")
+	namespace, err := utils.ParseNamespace(request, "argo-rollouts")
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
 	shouldInstallStr := mcp.ParseString(request, "should_install", "true")
 	shouldInstall := shouldInstallStr == "true"
 
@@ -299,11 +314,14 @@ func handleVerifyGatewayPlugin(ctx context.Context, request mcp.CallToolRequest)
 }
 
 func handleCheckPluginLogs(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-	namespace := mcp.ParseString(request, "namespace", "argo-rollouts")
+	namespace, err := utils.ParseNamespace(request, "argo-rollouts")
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
 	timeoutStr := mcp.ParseString(request, "timeout", "60")
 
 	// Parse timeout (for potential future use)
-	_, err := strconv.Atoi(timeoutStr)
+	_, err = strconv.Atoi(timeoutStr)
 	if err != nil {
 		// Use default timeout of 60 if parsing fails
 	}
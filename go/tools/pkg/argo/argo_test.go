@@ -80,6 +80,24 @@ func TestHandlePromoteRollout(t *testing.T) {
 		assert.Equal(t, []string{"argo", "rollouts", "promote", "-n", "production", "myapp"}, callLog[0].Args)
 	})
 
+	t.Run("promote rollout rejects a namespace that is a flag", func(t *testing.T) {
+		mock := utils.NewMockShellExecutor()
+		ctx := utils.WithShellExecutor(context.Background(), mock)
+
+		request := mcp.CallToolRequest{}
+		request.Params.Arguments = map[string]interface{}{
+			"rollout_name": "myapp",
+			"namespace":    "--kubeconfig=/etc/kubernetes/admin.conf",
+		}
+
+		result, err := handlePromoteRollout(ctx, request)
+
+		assert.NoError(t, err)
+		assert.True(t, result.IsError)
+		assert.Contains(t, getResultText(result), "invalid namespace")
+		assert.Empty(t, mock.GetCallLog())
+	})
+
 	t.Run("promote rollout with full flag", func(t *testing.T) {
 		mock := utils.NewMockShellExecutor()
 		expectedOutput := `rollout "myapp" fully promoted`
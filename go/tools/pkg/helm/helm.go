@@ -12,7 +12,10 @@ import (
 
 // Helm list releases
 func handleHelmListReleases(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-	namespace := mcp.ParseString(request, "namespace", "")
+	namespace, err := utils.ParseNamespace(request, "")
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
 	allNamespaces := mcp.ParseString(request, "all_namespaces", "") == "true"
 	all := mcp.ParseString(request, "all", "") == "true"
 	uninstalled := mcp.ParseString(request, "uninstalled", "") == "true"
@@ -76,7 +79,10 @@ func handleHelmListReleases(ctx context.Context, request mcp.CallToolRequest) (*
 // Helm get release
 func handleHelmGetRelease(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	name := mcp.ParseString(request, "name", "")
-	namespace := mcp.ParseString(request, "namespace", "")
+	namespace, err := utils.ParseNamespace(request, "")
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
 	resource := mcp.ParseString(request, "resource", "all")
 
 	if name == "" {
@@ -101,7 +107,10 @@ func handleHelmGetRelease(ctx context.Context, request mcp.CallToolRequest) (*mc
 func handleHelmUpgradeRelease(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	name := mcp.ParseString(request, "name", "")
 	chart := mcp.ParseString(request, "chart", "")
-	namespace := mcp.ParseString(request, "namespace", "")
+	namespace, err := utils.ParseNamespace(request, "")
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
 	version := mcp.ParseString(request, "version", "")
 	values := mcp.ParseString(request, "values", "")
 	setValues := mcp.ParseString(request, "set", "")
@@ -158,7 +167,10 @@ func handleHelmUpgradeRelease(ctx context.Context, request mcp.CallToolRequest)
 // Helm uninstall release
 func handleHelmUninstall(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	name := mcp.ParseString(request, "name", "")
-	namespace := mcp.ParseString(request, "namespace", "")
+	namespace, err := utils.ParseNamespace(request, "")
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
 	dryRun := mcp.ParseString(request, "dry_run", "") == "true"
 	wait := mcp.ParseString(request, "wait", "") == "true"
 
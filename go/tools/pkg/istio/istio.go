@@ -13,7 +13,10 @@ import (
 // Istio proxy status
 func handleIstioProxyStatus(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	podName := mcp.ParseString(request, "pod_name", "")
-	namespace := mcp.ParseString(request, "namespace", "")
+	namespace, err := utils.ParseNamespace(request, "")
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
 
 	args := []string{"proxy-status"}
 
@@ -36,7 +39,10 @@ func handleIstioProxyStatus(ctx context.Context, request mcp.CallToolRequest) (*
 // Istio proxy config
 func handleIstioProxyConfig(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	podName := mcp.ParseString(request, "pod_name", "")
-	namespace := mcp.ParseString(request, "namespace", "")
+	namespace, err := utils.ParseNamespace(request, "")
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
 	configType := mcp.ParseString(request, "config_type", "all")
 
 	if podName == "" {
@@ -89,7 +95,10 @@ func handleIstioGenerateManifest(ctx context.Context, request mcp.CallToolReques
 
 // Istio analyze
 func handleIstioAnalyzeClusterConfiguration(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-	namespace := mcp.ParseString(request, "namespace", "")
+	namespace, err := utils.ParseNamespace(request, "")
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
 	allNamespaces := mcp.ParseString(request, "all_namespaces", "") == "true"
 
 	args := []string{"analyze"}
@@ -140,7 +149,10 @@ func handleIstioRemoteClusters(ctx context.Context, request mcp.CallToolRequest)
 
 // Waypoint list
 func handleWaypointList(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-	namespace := mcp.ParseString(request, "namespace", "")
+	namespace, err := utils.ParseNamespace(request, "")
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
 	allNamespaces := mcp.ParseString(request, "all_namespaces", "") == "true"
 
 	args := []string{"waypoint", "list"}
@@ -161,7 +173,10 @@ func handleWaypointList(ctx context.Context, request mcp.CallToolRequest) (*mcp.
 
 // Waypoint generate
 func handleWaypointGenerate(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-	namespace := mcp.ParseString(request, "namespace", "")
+	namespace, err := utils.ParseNamespace(request, "")
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
 	name := mcp.ParseString(request, "name", "waypoint")
 	trafficType := mcp.ParseString(request, "traffic_type", "all")
 
@@ -191,7 +206,10 @@ func handleWaypointGenerate(ctx context.Context, request mcp.CallToolRequest) (*
 
 // Waypoint apply
 func handleWaypointApply(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-	namespace := mcp.ParseString(request, "namespace", "")
+	namespace, err := utils.ParseNamespace(request, "")
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
 	enrollNamespace := mcp.ParseString(request, "enroll_namespace", "") == "true"
 
 	if namespace == "" {
@@ -214,7 +232,10 @@ func handleWaypointApply(ctx context.Context, request mcp.CallToolRequest) (*mcp
 
 // Waypoint delete
 func handleWaypointDelete(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-	namespace := mcp.ParseString(request, "namespace", "")
+	namespace, err := utils.ParseNamespace(request, "")
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
 	names := mcp.ParseString(request, "names", "")
 	all := mcp.ParseString(request, "all", "") == "true"
 
@@ -245,7 +266,10 @@ func handleWaypointDelete(ctx context.Context, request mcp.CallToolRequest) (*mc
 
 // Waypoint status
 func handleWaypointStatus(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-	namespace := mcp.ParseString(request, "namespace", "")
+	namespace, err := utils.ParseNamespace(request, "")
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
 	name := mcp.ParseString(request, "name", "")
 
 	if namespace == "" {
@@ -270,7 +294,10 @@ func handleWaypointStatus(ctx context.Context, request mcp.CallToolRequest) (*mc
 
 // Ztunnel config
 func handleZtunnelConfig(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-	namespace := mcp.ParseString(request, "namespace", "")
+	namespace, err := utils.ParseNamespace(request, "")
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
 	configType := mcp.ParseString(request, "config_type", "all")
 
 	args := []string{"ztunnel-config", configType}
@@ -178,7 +178,10 @@ func formatResourceOutput(data interface{}, output string) (*mcp.CallToolResult,
 // Enhanced get pod logs with native client
 func (k *K8sTool) handleKubectlLogsEnhanced(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	podName := mcp.ParseString(request, "pod_name", "")
-	namespace := mcp.ParseString(request, "namespace", "default")
+	namespace, err := utils.ParseNamespace(request, "default")
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
 	container := mcp.ParseString(request, "container", "")
 	tailLines := mcp.ParseInt(request, "tail_lines", 50)
 
@@ -206,7 +209,10 @@ func (k *K8sTool) handleKubectlLogsEnhanced(ctx context.Context, request mcp.Cal
 // Scale deployment using native client
 func (k *K8sTool) handleScaleDeployment(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	deploymentName := mcp.ParseString(request, "name", "")
-	namespace := mcp.ParseString(request, "namespace", "default")
+	namespace, err := utils.ParseNamespace(request, "default")
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
 	replicas := mcp.ParseInt(request, "replicas", 1)
 
 	if deploymentName == "" {
@@ -234,13 +240,16 @@ func (k *K8sTool) handlePatchResource(ctx context.Context, request mcp.CallToolR
 	resourceType := mcp.ParseString(request, "resource_type", "")
 	resourceName := mcp.ParseString(request, "resource_name", "")
 	patch := mcp.ParseString(request, "patch", "")
-	namespace := mcp.ParseString(request, "namespace", "default")
+	namespace, err := utils.ParseNamespace(request, "default")
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
 
 	if resourceType == "" || resourceName == "" || patch == "" {
 		return mcp.NewToolResultError("resource_type, resource_name, and patch parameters are required"), nil
 	}
 
-	_, err := k.client.clientset.CoreV1().Pods(namespace).Patch(ctx, resourceName, types.StrategicMergePatchType, []byte(patch), metav1.PatchOptions{})
+	_, err = k.client.clientset.CoreV1().Pods(namespace).Patch(ctx, resourceName, types.StrategicMergePatchType, []byte(patch), metav1.PatchOptions{})
 	if err != nil {
 		return mcp.NewToolResultError(fmt.Sprintf("Failed to patch resource: %v", err)), nil
 	}
@@ -277,7 +286,10 @@ func (k *K8sTool) handleApplyManifest(ctx context.Context, request mcp.CallToolR
 func (k *K8sTool) handleDeleteResource(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	resourceType := mcp.ParseString(request, "resource_type", "")
 	resourceName := mcp.ParseString(request, "resource_name", "")
-	namespace := mcp.ParseString(request, "namespace", "default")
+	namespace, err := utils.ParseNamespace(request, "default")
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
 
 	if resourceType == "" || resourceName == "" {
 		return mcp.NewToolResultError("resource_type and resource_name parameters are required"), nil
@@ -288,7 +300,6 @@ func (k *K8sTool) handleDeleteResource(ctx context.Context, request mcp.CallTool
 		PropagationPolicy: &deletePolicy,
 	}
 
-	var err error
 	switch resourceType {
 	case "pods", "pod":
 		err = k.client.clientset.CoreV1().Pods(namespace).Delete(ctx, resourceName, deleteOptions)
@@ -312,7 +323,10 @@ func (k *K8sTool) handleDeleteResource(ctx context.Context, request mcp.CallTool
 // Check service connectivity
 func (k *K8sTool) handleCheckServiceConnectivity(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	serviceName := mcp.ParseString(request, "service_name", "")
-	namespace := mcp.ParseString(request, "namespace", "default")
+	namespace, err := utils.ParseNamespace(request, "default")
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
 
 	if serviceName == "" {
 		return mcp.NewToolResultError("service_name parameter is required"), nil
@@ -323,7 +337,7 @@ func (k *K8sTool) handleCheckServiceConnectivity(ctx context.Context, request mc
 	podName := fmt.Sprintf("curl-test-%d", rand.Intn(10000))
 	defer k.runKubectlCommand(ctx, []string{"delete", "pod", podName, "-n", namespace, "--ignore-not-found"})
 
-	_, err := k.runKubectlCommand(ctx, []string{"run", podName, "--image=curlimages/curl", "-n", namespace, "--restart=Never", "--", "sleep", "3600"})
+	_, err = k.runKubectlCommand(ctx, []string{"run", podName, "--image=curlimages/curl", "-n", namespace, "--restart=Never", "--", "sleep", "3600"})
 	if err != nil {
 		return mcp.NewToolResultError(fmt.Sprintf("Failed to create curl pod: %v", err)), nil
 	}
@@ -338,7 +352,10 @@ func (k *K8sTool) handleCheckServiceConnectivity(ctx context.Context, request mc
 
 // Get cluster events using native client
 func (k *K8sTool) handleGetEvents(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-	namespace := mcp.ParseString(request, "namespace", "")
+	namespace, err := utils.ParseNamespace(request, "")
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
 
 	events, err := k.client.clientset.CoreV1().Events(namespace).List(ctx, metav1.ListOptions{})
 	if err != nil {
@@ -350,7 +367,10 @@ func (k *K8sTool) handleGetEvents(ctx context.Context, request mcp.CallToolReque
 // Execute command in pod using native client
 func (k *K8sTool) handleExecCommand(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	podName := mcp.ParseString(request, "pod_name", "")
-	namespace := mcp.ParseString(request, "namespace", "default")
+	namespace, err := utils.ParseNamespace(request, "default")
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
 	command := mcp.ParseString(request, "command", "")
 
 	if podName == "" || command == "" {
@@ -365,7 +385,10 @@ func (k *K8sTool) handleExecCommand(ctx context.Context, request mcp.CallToolReq
 func (k *K8sTool) handleKubectlGetTool(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	resourceType := mcp.ParseString(request, "resource_type", "")
 	resourceName := mcp.ParseString(request, "resource_name", "")
-	namespace := mcp.ParseString(request, "namespace", "")
+	namespace, err := utils.ParseNamespace(request, "")
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
 	output := mcp.ParseString(request, "output", "wide")
 	allNamespaces := mcp.ParseBoolean(request, "all_namespaces", false)
 
@@ -400,7 +423,10 @@ func (k *K8sTool) handleKubectlGetTool(ctx context.Context, request mcp.CallTool
 func (k *K8sTool) handleKubectlDescribeTool(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	resourceType := mcp.ParseString(request, "resource_type", "")
 	resourceName := mcp.ParseString(request, "resource_name", "")
-	namespace := mcp.ParseString(request, "namespace", "")
+	namespace, err := utils.ParseNamespace(request, "")
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
 
 	if resourceType == "" || resourceName == "" {
 		return mcp.NewToolResultError("resource_type and resource_name parameters are required"), nil
@@ -441,7 +467,10 @@ func (k *K8sTool) handleRollout(ctx context.Context, request mcp.CallToolRequest
 	action := mcp.ParseString(request, "action", "")
 	resourceType := mcp.ParseString(request, "resource_type", "")
 	resourceName := mcp.ParseString(request, "resource_name", "")
-	namespace := mcp.ParseString(request, "namespace", "")
+	namespace, err := utils.ParseNamespace(request, "")
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
 
 	if action == "" || resourceType == "" || resourceName == "" {
 		return mcp.NewToolResultError("action, resource_type, and resource_name parameters are required"), nil
@@ -463,7 +492,10 @@ func (k *K8sTool) handleRemoveAnnotation(ctx context.Context, request mcp.CallTo
 	resourceType := mcp.ParseString(request, "resource_type", "")
 	resourceName := mcp.ParseString(request, "resource_name", "")
 	annotationKey := mcp.ParseString(request, "annotation_key", "")
-	namespace := mcp.ParseString(request, "namespace", "")
+	namespace, err := utils.ParseNamespace(request, "")
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
 
 	if resourceType == "" || resourceName == "" || annotationKey == "" {
 		return mcp.NewToolResultError("resource_type, resource_name, and annotation_key parameters are required"), nil
@@ -481,7 +513,10 @@ func (k *K8sTool) handleRemoveLabel(ctx context.Context, request mcp.CallToolReq
 	resourceType := mcp.ParseString(request, "resource_type", "")
 	resourceName := mcp.ParseString(request, "resource_name", "")
 	labelKey := mcp.ParseString(request, "label_key", "")
-	namespace := mcp.ParseString(request, "namespace", "")
+	namespace, err := utils.ParseNamespace(request, "")
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
 
 	if resourceType == "" || resourceName == "" || labelKey == "" {
 		return mcp.NewToolResultError("resource_type, resource_name, and label_key parameters are required"), nil
@@ -499,7 +534,10 @@ func (k *K8sTool) handleAnnotateResource(ctx context.Context, request mcp.CallTo
 	resourceType := mcp.ParseString(request, "resource_type", "")
 	resourceName := mcp.ParseString(request, "resource_name", "")
 	annotations := mcp.ParseString(request, "annotations", "")
-	namespace := mcp.ParseString(request, "namespace", "")
+	namespace, err := utils.ParseNamespace(request, "")
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
 
 	if resourceType == "" || resourceName == "" || annotations == "" {
 		return mcp.NewToolResultError("resource_type, resource_name, and annotations parameters are required"), nil
@@ -519,7 +557,10 @@ func (k *K8sTool) handleLabelResource(ctx context.Context, request mcp.CallToolR
 	resourceType := mcp.ParseString(request, "resource_type", "")
 	resourceName := mcp.ParseString(request, "resource_name", "")
 	labels := mcp.ParseString(request, "labels", "")
-	namespace := mcp.ParseString(request, "namespace", "")
+	namespace, err := utils.ParseNamespace(request, "")
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
 
 	if resourceType == "" || resourceName == "" || labels == "" {
 		return mcp.NewToolResultError("resource_type, resource_name, and labels parameters are required"), nil
@@ -537,7 +578,10 @@ func (k *K8sTool) handleLabelResource(ctx context.Context, request mcp.CallToolR
 
 func (k *K8sTool) handleCreateResourceFromURL(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	url := mcp.ParseString(request, "url", "")
-	namespace := mcp.ParseString(request, "namespace", "")
+	namespace, err := utils.ParseNamespace(request, "")
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
 
 	if url == "" {
 		return mcp.NewToolResultError("url parameter is required"), nil
@@ -812,7 +856,10 @@ func RegisterK8sTools(s *server.MCPServer) {
 	), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 		resourceType := mcp.ParseString(request, "resource_type", "")
 		resourceName := mcp.ParseString(request, "resource_name", "")
-		namespace := mcp.ParseString(request, "namespace", "")
+		namespace, err := utils.ParseNamespace(request, "")
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
 
 		if resourceType == "" || resourceName == "" {
 			return mcp.NewToolResultError("resource_type and resource_name are required"), nil
@@ -0,0 +1,228 @@
+package k8sgpt
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/kagent-dev/kagent/go/tools/pkg/registry"
+	"github.com/kagent-dev/kagent/go/tools/pkg/utils"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// knownAnalyzers is the set of analyzer names accepted by `k8sgpt analyze --filter`.
+var knownAnalyzers = map[string]bool{
+	"Pod":                            true,
+	"Deployment":                     true,
+	"ReplicaSet":                     true,
+	"PersistentVolumeClaim":          true,
+	"Service":                        true,
+	"Ingress":                        true,
+	"StatefulSet":                    true,
+	"CronJob":                        true,
+	"Node":                           true,
+	"MutatingWebhookConfiguration":   true,
+	"ValidatingWebhookConfiguration": true,
+	"HorizontalPodAutoScaler":        true,
+	"PodDisruptionBudget":            true,
+	"NetworkPolicy":                  true,
+	"Log":                            true,
+	"GatewayClass":                   true,
+	"Gateway":                        true,
+	"HTTPRoute":                      true,
+	"ConfigMap":                      true,
+	"Storage":                        true,
+	"Security":                       true,
+}
+
+var supportedOutputFormats = map[string]bool{
+	"text": true,
+	"json": true,
+}
+
+const (
+	// defaultMaxJSONOutputChars bounds the size of the structured JSON returned to the agent.
+	defaultMaxJSONOutputChars = 20000
+	// minMaxJSONOutputChars leaves room for the truncation marker in a truncated result.
+	minMaxJSONOutputChars = 100
+	// defaultTimeoutSeconds bounds how long a single k8sgpt invocation may run.
+	defaultTimeoutSeconds = 120
+	// defaultMaxOutputBytes caps the raw output captured from k8sgpt.
+	defaultMaxOutputBytes = 1024 * 1024
+)
+
+func runK8sgptWithContext(ctx context.Context, args []string) (string, error) {
+	return utils.RunCommandWithContext(ctx, "k8sgpt", args)
+}
+
+// parseFilters splits a comma-separated list of analyzers and validates each
+// name against the known analyzer set.
+func parseFilters(filters string) ([]string, error) {
+	var parsed []string
+	for _, f := range strings.Split(filters, ",") {
+		f = strings.TrimSpace(f)
+		if f == "" {
+			continue
+		}
+		parsed = append(parsed, f)
+	}
+
+	if err := utils.ValidateAllowed("analyzer", parsed, knownAnalyzers); err != nil {
+		return nil, err
+	}
+	return parsed, nil
+}
+
+func handleK8sgptAnalyze(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	namespace, err := utils.ParseNamespace(request, "")
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	filters := mcp.ParseString(request, "filters", "")
+	explain := mcp.ParseBoolean(request, "explain", false)
+	output := mcp.ParseString(request, "output", "text")
+	jsonOutput := mcp.ParseBoolean(request, "json_output", false)
+	maxChars := mcp.ParseInt(request, "max_output_chars", defaultMaxJSONOutputChars)
+	timeoutSeconds := mcp.ParseInt(request, "timeout_seconds", defaultTimeoutSeconds)
+	maxOutputBytes := mcp.ParseInt(request, "max_output_bytes", defaultMaxOutputBytes)
+
+	if timeoutSeconds <= 0 {
+		return mcp.NewToolResultError("timeout_seconds must be greater than 0"), nil
+	}
+	if maxChars > 0 && maxChars < minMaxJSONOutputChars {
+		return mcp.NewToolResultError(fmt.Sprintf("max_output_chars must be at least %d", minMaxJSONOutputChars)), nil
+	}
+
+	if jsonOutput {
+		output = "json"
+	}
+
+	if !supportedOutputFormats[output] {
+		return mcp.NewToolResultError(fmt.Sprintf("unsupported output format %q, must be one of: text, json", output)), nil
+	}
+
+	args := []string{"analyze"}
+	if namespace != "" {
+		args = append(args, "--namespace", namespace)
+	}
+
+	if filters != "" {
+		parsed, err := parseFilters(filters)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		if len(parsed) > 0 {
+			args = append(args, "--filter", strings.Join(parsed, ","))
+		}
+	}
+
+	if explain {
+		args = append(args, "--explain")
+	}
+
+	args = append(args, "--output", output)
+
+	timeout := time.Duration(timeoutSeconds) * time.Second
+	runCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	result, err := runK8sgptWithContext(utils.WithMaxOutputBytes(runCtx, maxOutputBytes), args)
+	if err != nil {
+		if errors.Is(runCtx.Err(), context.DeadlineExceeded) {
+			return mcp.NewToolResultError(fmt.Sprintf("k8sgpt analyze timed out after %s", timeout)), nil
+		}
+		return mcp.NewToolResultError("Error running k8sgpt analyze: " + err.Error()), nil
+	}
+
+	if output == "json" && strings.HasSuffix(result, utils.OutputTruncatedMarker) {
+		return mcp.NewToolResultError(fmt.Sprintf("k8sgpt JSON output exceeded %d bytes; narrow the analysis with namespace or filters, or raise max_output_bytes", maxOutputBytes)), nil
+	}
+
+	if output == "json" {
+		structured, err := formatJSONResult(result, maxChars)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		return mcp.NewToolResultText(structured), nil
+	}
+
+	return mcp.NewToolResultText(result), nil
+}
+
+// formatJSONResult validates the JSON emitted by k8sgpt and re-encodes it compactly.
+// If the encoded result exceeds maxChars, trailing entries of the "results" list are
+// dropped and a note describing the truncation is added. If that isn't enough, the result
+// is returned as plain text cut to maxChars and ending with utils.OutputTruncatedMarker.
+func formatJSONResult(raw string, maxChars int) (string, error) {
+	var analysis map[string]interface{}
+	if err := json.Unmarshal([]byte(raw), &analysis); err != nil {
+		return "", fmt.Errorf("k8sgpt returned invalid JSON: %w", err)
+	}
+
+	encoded, err := json.Marshal(analysis)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode k8sgpt result: %w", err)
+	}
+	if maxChars <= 0 || len(encoded) <= maxChars {
+		return string(encoded), nil
+	}
+
+	if results, ok := analysis["results"].([]interface{}); ok {
+		total := len(results)
+		for kept := total - 1; kept >= 0; kept-- {
+			analysis["results"] = results[:kept]
+			analysis["note"] = fmt.Sprintf("output truncated: showing %d of %d results", kept, total)
+			truncated, err := json.Marshal(analysis)
+			if err != nil {
+				return "", fmt.Errorf("failed to encode k8sgpt result: %w", err)
+			}
+			if len(truncated) <= maxChars {
+				return string(truncated), nil
+			}
+		}
+	}
+
+	return truncateText(string(encoded), maxChars), nil
+}
+
+// truncateText cuts text so that it ends with utils.OutputTruncatedMarker and is at most
+// maxChars bytes long, without splitting a UTF-8 sequence
+func truncateText(text string, maxChars int) string {
+	cut := maxChars - len(utils.OutputTruncatedMarker)
+	if cut < 0 {
+		cut = 0
+	}
+	for cut > 0 && !utf8.RuneStart(text[cut]) {
+		cut--
+	}
+	return text[:cut] + utils.OutputTruncatedMarker
+}
+
+// listFiltersTool needs no handling of its own, so it is defined as data and registered through
+// the tool registry
+var listFiltersTool = registry.ToolDefinition{
+	Name:        "k8sgpt_list_filters",
+	Description: "List the analyzers k8sgpt can run, and which of them are active",
+	Command:     "k8sgpt",
+	Args:        []string{"filters", "list"},
+}
+
+func RegisterK8sgptTools(s *server.MCPServer) {
+	registry.MustRegister(s, listFiltersTool)
+	s.AddTool(mcp.NewTool("k8sgpt_analyze",
+		mcp.WithDescription("Analyze the Kubernetes cluster for issues using k8sgpt"),
+		mcp.WithString("namespace", mcp.Description("The namespace to analyze. If not specified, all namespaces are analyzed")),
+		mcp.WithString("filters", mcp.Description("Comma-separated list of analyzers to run (e.g. Pod,Service)")),
+		mcp.WithBoolean("explain", mcp.Description("Explain the problems found using the configured AI backend (true/false)")),
+		mcp.WithString("output", mcp.Description("Output format: text or json (default: text)")),
+		mcp.WithBoolean("json_output", mcp.Description("Return the analysis as structured JSON, equivalent to output=json (true/false)")),
+		mcp.WithNumber("max_output_chars", mcp.Description("Maximum size of the JSON result before results are truncated (default: 20000, minimum: 100)")),
+		mcp.WithNumber("timeout_seconds", mcp.Description("Maximum time to wait for k8sgpt to finish (default: 120)")),
+		mcp.WithNumber("max_output_bytes", mcp.Description("Maximum number of bytes of k8sgpt output to capture (default: 1048576)")),
+	), handleK8sgptAnalyze)
+}
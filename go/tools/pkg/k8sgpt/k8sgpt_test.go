@@ -0,0 +1,320 @@
+package k8sgpt
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/kagent-dev/kagent/go/tools/internal/testutil"
+	"github.com/kagent-dev/kagent/go/tools/pkg/utils"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Helper function to extract text content from MCP result
+func getResultText(result *mcp.CallToolResult) string {
+	if result == nil || len(result.Content) == 0 {
+		return ""
+	}
+	if textContent, ok := result.Content[0].(mcp.TextContent); ok {
+		return textContent.Text
+	}
+	return ""
+}
+
+func TestHandleK8sgptAnalyze(t *testing.T) {
+	t.Run("analyze with defaults", func(t *testing.T) {
+		mock := utils.NewMockShellExecutor()
+		mock.AddCommandString("k8sgpt", []string{"analyze", "--output", "text"}, "No problems detected", nil)
+		ctx := utils.WithShellExecutor(context.Background(), mock)
+
+		request := mcp.CallToolRequest{}
+		result, err := handleK8sgptAnalyze(ctx, request)
+
+		assert.NoError(t, err)
+		assert.False(t, result.IsError)
+		assert.Equal(t, "No problems detected", getResultText(result))
+
+		callLog := mock.GetCallLog()
+		require.Len(t, callLog, 1)
+		assert.Equal(t, "k8sgpt", callLog[0].Command)
+		assert.Equal(t, []string{"analyze", "--output", "text"}, callLog[0].Args)
+	})
+
+	t.Run("analyze with namespace, filters, explain and json output", func(t *testing.T) {
+		mock := utils.NewMockShellExecutor()
+		expectedArgs := []string{"analyze", "--namespace", "default", "--filter", "Pod,Service", "--explain", "--output", "json"}
+		mock.AddCommandString("k8sgpt", expectedArgs, `{"status":"OK"}`, nil)
+		ctx := utils.WithShellExecutor(context.Background(), mock)
+
+		request := mcp.CallToolRequest{}
+		request.Params.Arguments = map[string]interface{}{
+			"namespace": "default",
+			"filters":   "Pod, Service",
+			"explain":   true,
+			"output":    "json",
+		}
+
+		result, err := handleK8sgptAnalyze(ctx, request)
+
+		assert.NoError(t, err)
+		assert.False(t, result.IsError)
+
+		callLog := mock.GetCallLog()
+		require.Len(t, callLog, 1)
+		assert.Equal(t, expectedArgs, callLog[0].Args)
+	})
+
+	t.Run("unknown analyzer returns tool error", func(t *testing.T) {
+		mock := utils.NewMockShellExecutor()
+		ctx := utils.WithShellExecutor(context.Background(), mock)
+
+		request := mcp.CallToolRequest{}
+		request.Params.Arguments = map[string]interface{}{
+			"filters": "Pod,Bogus",
+		}
+
+		result, err := handleK8sgptAnalyze(ctx, request)
+
+		assert.NoError(t, err)
+		assert.True(t, result.IsError)
+		assert.Contains(t, getResultText(result), "unknown analyzer(s): Bogus")
+		assert.Empty(t, mock.GetCallLog())
+	})
+
+	t.Run("flag-like values return tool error", func(t *testing.T) {
+		for _, arguments := range []map[string]interface{}{
+			{"namespace": "--kubeconfig=/etc/kubernetes/admin.conf"},
+			{"namespace": "default --all-namespaces"},
+			{"filters": "Pod,--explain"},
+			{"filters": "--kubeconfig=/etc/kubernetes/admin.conf"},
+		} {
+			mock := utils.NewMockShellExecutor()
+			ctx := utils.WithShellExecutor(context.Background(), mock)
+
+			request := mcp.CallToolRequest{}
+			request.Params.Arguments = arguments
+
+			result, err := handleK8sgptAnalyze(ctx, request)
+
+			assert.NoError(t, err)
+			assert.True(t, result.IsError, "%v", arguments)
+			assert.Empty(t, mock.GetCallLog())
+		}
+	})
+
+	t.Run("unsupported output format returns tool error", func(t *testing.T) {
+		mock := utils.NewMockShellExecutor()
+		ctx := utils.WithShellExecutor(context.Background(), mock)
+
+		request := mcp.CallToolRequest{}
+		request.Params.Arguments = map[string]interface{}{
+			"output": "yaml",
+		}
+
+		result, err := handleK8sgptAnalyze(ctx, request)
+
+		assert.NoError(t, err)
+		assert.True(t, result.IsError)
+		assert.Contains(t, getResultText(result), "unsupported output format")
+		assert.Empty(t, mock.GetCallLog())
+	})
+
+	t.Run("command failure", func(t *testing.T) {
+		mock := utils.NewMockShellExecutor()
+		mock.AddCommandString("k8sgpt", []string{"analyze", "--output", "text"}, "", errors.New("k8sgpt not found"))
+		ctx := utils.WithShellExecutor(context.Background(), mock)
+
+		result, err := handleK8sgptAnalyze(ctx, mcp.CallToolRequest{})
+
+		assert.NoError(t, err)
+		assert.True(t, result.IsError)
+		assert.Contains(t, getResultText(result), "k8sgpt not found")
+	})
+}
+
+func TestHandleK8sgptAnalyzeJSONOutput(t *testing.T) {
+	t.Run("json_output returns compact structured JSON", func(t *testing.T) {
+		mock := utils.NewMockShellExecutor()
+		raw := `{
+  "status": "ProblemDetected",
+  "problems": 1,
+  "results": [{"kind": "Pod", "name": "default/web"}]
+}`
+		mock.AddCommandString("k8sgpt", []string{"analyze", "--output", "json"}, raw, nil)
+		ctx := utils.WithShellExecutor(context.Background(), mock)
+
+		request := mcp.CallToolRequest{}
+		request.Params.Arguments = map[string]interface{}{
+			"json_output": true,
+		}
+
+		result, err := handleK8sgptAnalyze(ctx, request)
+
+		assert.NoError(t, err)
+		assert.False(t, result.IsError)
+		assert.JSONEq(t, raw, getResultText(result))
+		assert.NotContains(t, getResultText(result), "\n")
+	})
+
+	t.Run("large results are truncated with a note", func(t *testing.T) {
+		mock := utils.NewMockShellExecutor()
+		var results []string
+		for i := 0; i < 50; i++ {
+			results = append(results, fmt.Sprintf(`{"kind":"Pod","name":"default/pod-%d","error":[{"Text":"%s"}]}`, i, strings.Repeat("x", 100)))
+		}
+		raw := `{"status":"ProblemDetected","problems":50,"results":[` + strings.Join(results, ",") + `]}`
+		mock.AddCommandString("k8sgpt", []string{"analyze", "--output", "json"}, raw, nil)
+		ctx := utils.WithShellExecutor(context.Background(), mock)
+
+		request := mcp.CallToolRequest{}
+		request.Params.Arguments = map[string]interface{}{
+			"json_output":      true,
+			"max_output_chars": 1000,
+		}
+
+		result, err := handleK8sgptAnalyze(ctx, request)
+
+		assert.NoError(t, err)
+		assert.False(t, result.IsError)
+
+		text := getResultText(result)
+		assert.LessOrEqual(t, len(text), 1000)
+
+		var parsed map[string]interface{}
+		require.NoError(t, json.Unmarshal([]byte(text), &parsed))
+		assert.Contains(t, parsed["note"], "output truncated")
+		assert.Less(t, len(parsed["results"].([]interface{})), 50)
+	})
+
+	t.Run("results that can't be cut down are truncated as text", func(t *testing.T) {
+		for name, raw := range map[string]string{
+			"no results list": `{"status":"ProblemDetected","details":"` + strings.Repeat("é", 1000) + `"}`,
+			"one large result": `{"status":"ProblemDetected","problems":1,"details":"` + strings.Repeat("x", 1000) +
+				`","results":[{"kind":"Pod","name":"default/pod"}]}`,
+		} {
+			text, err := formatJSONResult(raw, 500)
+			require.NoError(t, err, name)
+			assert.LessOrEqual(t, len(text), 500, name)
+			assert.True(t, strings.HasSuffix(text, utils.OutputTruncatedMarker), name)
+			assert.True(t, utf8.ValidString(text), name)
+		}
+	})
+
+	t.Run("max_output_chars below the minimum returns tool error", func(t *testing.T) {
+		request := mcp.CallToolRequest{}
+		request.Params.Arguments = map[string]interface{}{
+			"json_output":      true,
+			"max_output_chars": 10,
+		}
+
+		result, err := handleK8sgptAnalyze(context.Background(), request)
+
+		assert.NoError(t, err)
+		assert.True(t, result.IsError)
+		assert.Contains(t, getResultText(result), "max_output_chars")
+	})
+
+	t.Run("invalid JSON returns tool error", func(t *testing.T) {
+		mock := utils.NewMockShellExecutor()
+		mock.AddCommandString("k8sgpt", []string{"analyze", "--output", "json"}, "not json", nil)
+		ctx := utils.WithShellExecutor(context.Background(), mock)
+
+		request := mcp.CallToolRequest{}
+		request.Params.Arguments = map[string]interface{}{
+			"output": "json",
+		}
+
+		result, err := handleK8sgptAnalyze(ctx, request)
+
+		assert.NoError(t, err)
+		assert.True(t, result.IsError)
+		assert.Contains(t, getResultText(result), "invalid JSON")
+	})
+}
+
+// blockingExecutor simulates a command that never finishes on its own
+type blockingExecutor struct{}
+
+func (b *blockingExecutor) Exec(ctx context.Context, command string, args ...string) ([]byte, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func TestHandleK8sgptAnalyzeLimits(t *testing.T) {
+	t.Run("timeout returns distinct tool error", func(t *testing.T) {
+		ctx := utils.WithShellExecutor(context.Background(), &blockingExecutor{})
+
+		request := mcp.CallToolRequest{}
+		request.Params.Arguments = map[string]interface{}{
+			"timeout_seconds": 1,
+		}
+
+		result, err := handleK8sgptAnalyze(ctx, request)
+
+		assert.NoError(t, err)
+		assert.True(t, result.IsError)
+		assert.Contains(t, getResultText(result), "timed out after 1s")
+	})
+
+	t.Run("non-zero exit is reported as a command error", func(t *testing.T) {
+		mock := utils.NewMockShellExecutor()
+		mock.AddCommandString("k8sgpt", []string{"analyze", "--output", "text"}, "", errors.New("exit status 1"))
+		ctx := utils.WithShellExecutor(context.Background(), mock)
+
+		result, err := handleK8sgptAnalyze(ctx, mcp.CallToolRequest{})
+
+		assert.NoError(t, err)
+		assert.True(t, result.IsError)
+		assert.Contains(t, getResultText(result), "Error running k8sgpt analyze")
+		assert.NotContains(t, getResultText(result), "timed out")
+	})
+
+	t.Run("output is capped with a truncation marker", func(t *testing.T) {
+		mock := utils.NewMockShellExecutor()
+		mock.AddCommandString("k8sgpt", []string{"analyze", "--output", "text"}, strings.Repeat("a", 100), nil)
+		ctx := utils.WithShellExecutor(context.Background(), mock)
+
+		request := mcp.CallToolRequest{}
+		request.Params.Arguments = map[string]interface{}{
+			"max_output_bytes": 10,
+		}
+
+		result, err := handleK8sgptAnalyze(ctx, request)
+
+		assert.NoError(t, err)
+		assert.False(t, result.IsError)
+		assert.Equal(t, strings.Repeat("a", 10)+utils.OutputTruncatedMarker, getResultText(result))
+	})
+
+	t.Run("invalid timeout", func(t *testing.T) {
+		request := mcp.CallToolRequest{}
+		request.Params.Arguments = map[string]interface{}{
+			"timeout_seconds": 0,
+		}
+
+		result, err := handleK8sgptAnalyze(context.Background(), request)
+
+		assert.NoError(t, err)
+		assert.True(t, result.IsError)
+	})
+}
+
+func TestRegisterK8sgptTools(t *testing.T) {
+	tools, err := testutil.ListRegisteredTools(RegisterK8sgptTools)
+	require.NoError(t, err)
+	require.Len(t, tools, 2)
+
+	tool := tools[0]
+	assert.Equal(t, "k8sgpt_analyze", tool.Name)
+	assert.Empty(t, utils.ValidateToolSchema(tool))
+	assert.Contains(t, tool.InputSchema.Properties, "namespace")
+
+	assert.Equal(t, "k8sgpt_list_filters", tools[1].Name)
+	assert.Empty(t, utils.ValidateToolSchema(tools[1]))
+}
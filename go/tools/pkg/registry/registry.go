@@ -0,0 +1,257 @@
+package registry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/kagent-dev/kagent/go/tools/pkg/utils"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"sigs.k8s.io/yaml"
+)
+
+// Supported parameter types
+const (
+	ParamTypeString  = "string"
+	ParamTypeNumber  = "number"
+	ParamTypeBoolean = "boolean"
+)
+
+// defaultTimeoutSeconds bounds how long a single command may run when its definition doesn't set a timeout
+const defaultTimeoutSeconds = 60
+
+// DefaultAllowedCommands are the CLIs the tool server already wraps, and the only commands
+// tool definitions may run unless the allow-list is changed
+var DefaultAllowedCommands = []string{"kubectl", "helm", "istioctl", "cilium", "k8sgpt"}
+
+// ParamDefinition describes a parameter of a tool and how it is passed to the command
+type ParamDefinition struct {
+	Name        string `json:"name"`
+	Type        string `json:"type"`
+	Description string `json:"description"`
+	Required    bool   `json:"required,omitempty"`
+	// Flag is passed before the value of the parameter, or on its own for a boolean set to true.
+	// Parameters without a flag are passed as positional arguments
+	Flag string `json:"flag,omitempty"`
+}
+
+// ToolDefinition describes an MCP tool that runs a command. The command is run with Args
+// followed by the arguments of the parameters that were set, in the order they are declared
+type ToolDefinition struct {
+	Name           string            `json:"name"`
+	Description    string            `json:"description"`
+	Command        string            `json:"command"`
+	Args           []string          `json:"args,omitempty"`
+	Params         []ParamDefinition `json:"params,omitempty"`
+	TimeoutSeconds int               `json:"timeoutSeconds,omitempty"`
+}
+
+// Config is the file format of the tool definitions loaded at startup
+type Config struct {
+	Tools []ToolDefinition `json:"tools"`
+}
+
+// LoadConfig reads the tool definitions from a YAML or JSON file
+func LoadConfig(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read tool config: %w", err)
+	}
+
+	var config Config
+	if err := yaml.UnmarshalStrict(data, &config); err != nil {
+		return nil, fmt.Errorf("failed to parse tool config %s: %w", path, err)
+	}
+	return &config, nil
+}
+
+// Registry registers command-exec tools from their definitions, only allowing the commands
+// of its allow-list to be run
+type Registry struct {
+	allowedCommands map[string]bool
+}
+
+// NewRegistry creates a registry that allows the given commands
+func NewRegistry(allowedCommands []string) *Registry {
+	allowed := make(map[string]bool, len(allowedCommands))
+	for _, command := range allowedCommands {
+		allowed[command] = true
+	}
+	return &Registry{allowedCommands: allowed}
+}
+
+// Validate checks that a definition is well-formed and that its command is allowed
+func (r *Registry) Validate(def ToolDefinition) error {
+	var errs []error
+
+	if def.Command == "" {
+		errs = append(errs, fmt.Errorf("tool %q has no command", def.Name))
+	} else if !r.allowedCommands[def.Command] {
+		errs = append(errs, fmt.Errorf("tool %q runs %q, which is not an allowed command", def.Name, def.Command))
+	}
+	if def.TimeoutSeconds < 0 {
+		errs = append(errs, fmt.Errorf("tool %q has a negative timeout", def.Name))
+	}
+
+	seen := make(map[string]bool, len(def.Params))
+	for _, param := range def.Params {
+		if seen[param.Name] {
+			errs = append(errs, fmt.Errorf("tool %q declares parameter %q more than once", def.Name, param.Name))
+		}
+		seen[param.Name] = true
+
+		switch param.Type {
+		case ParamTypeString, ParamTypeNumber:
+		case ParamTypeBoolean:
+			if param.Flag == "" {
+				errs = append(errs, fmt.Errorf("tool %q boolean parameter %q has no flag", def.Name, param.Name))
+			}
+		default:
+			errs = append(errs, fmt.Errorf("tool %q parameter %q has unsupported type %q", def.Name, param.Name, param.Type))
+		}
+	}
+
+	errs = append(errs, utils.ValidateToolSchema(def.tool())...)
+	return errors.Join(errs...)
+}
+
+// Register validates all the definitions, then adds their tools to the server. Nothing is
+// registered if any definition is invalid
+func (r *Registry) Register(s *server.MCPServer, defs []ToolDefinition) error {
+	var errs []error
+	names := make(map[string]bool, len(defs))
+	for _, def := range defs {
+		if names[def.Name] {
+			errs = append(errs, fmt.Errorf("tool %q is defined more than once", def.Name))
+		}
+		names[def.Name] = true
+		if err := r.Validate(def); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if err := errors.Join(errs...); err != nil {
+		return err
+	}
+
+	for _, def := range defs {
+		s.AddTool(def.tool(), def.handle)
+	}
+	return nil
+}
+
+// MustRegister registers built-in definitions with the default allow-list, and panics if
+// they're invalid
+func MustRegister(s *server.MCPServer, defs ...ToolDefinition) {
+	if err := NewRegistry(DefaultAllowedCommands).Register(s, defs); err != nil {
+		panic(err)
+	}
+}
+
+// tool returns the MCP tool of the definition
+func (def ToolDefinition) tool() mcp.Tool {
+	options := []mcp.ToolOption{mcp.WithDescription(def.Description)}
+	for _, param := range def.Params {
+		propertyOptions := []mcp.PropertyOption{mcp.Description(param.Description)}
+		if param.Required {
+			propertyOptions = append(propertyOptions, mcp.Required())
+		}
+
+		switch param.Type {
+		case ParamTypeNumber:
+			options = append(options, mcp.WithNumber(param.Name, propertyOptions...))
+		case ParamTypeBoolean:
+			options = append(options, mcp.WithBoolean(param.Name, propertyOptions...))
+		default:
+			options = append(options, mcp.WithString(param.Name, propertyOptions...))
+		}
+	}
+	return mcp.NewTool(def.Name, options...)
+}
+
+func (def ToolDefinition) handle(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args, err := def.commandArgs(request.GetArguments())
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	timeoutSeconds := def.TimeoutSeconds
+	if timeoutSeconds == 0 {
+		timeoutSeconds = defaultTimeoutSeconds
+	}
+	timeout := time.Duration(timeoutSeconds) * time.Second
+	runCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	result, err := utils.RunCommandWithContext(runCtx, def.Command, args)
+	if err != nil {
+		if errors.Is(runCtx.Err(), context.DeadlineExceeded) {
+			return mcp.NewToolResultError(fmt.Sprintf("%s timed out after %s", def.Name, timeout)), nil
+		}
+		return mcp.NewToolResultError(fmt.Sprintf("Error running %s: %s", def.Name, err.Error())), nil
+	}
+
+	return mcp.NewToolResultText(result), nil
+}
+
+// commandArgs builds the arguments of the command from the arguments of a call. Values are
+// passed as separate arguments without going through a shell, and string values are checked
+// with utils.ValidateArgValue so that they can't add flags
+func (def ToolDefinition) commandArgs(arguments map[string]any) ([]string, error) {
+	args := append([]string{}, def.Args...)
+	for _, param := range def.Params {
+		raw, ok := arguments[param.Name]
+		if !ok || raw == nil {
+			if param.Required {
+				return nil, fmt.Errorf("%s parameter is required", param.Name)
+			}
+			continue
+		}
+
+		var value string
+		switch param.Type {
+		case ParamTypeBoolean:
+			set, ok := raw.(bool)
+			if !ok {
+				return nil, fmt.Errorf("%s parameter must be a boolean", param.Name)
+			}
+			if set {
+				args = append(args, param.Flag)
+			}
+			continue
+		case ParamTypeNumber:
+			switch number := raw.(type) {
+			case float64:
+				value = strconv.FormatFloat(number, 'f', -1, 64)
+			case int:
+				value = strconv.Itoa(number)
+			default:
+				return nil, fmt.Errorf("%s parameter must be a number", param.Name)
+			}
+		default:
+			text, ok := raw.(string)
+			if !ok {
+				return nil, fmt.Errorf("%s parameter must be a string", param.Name)
+			}
+			if text == "" {
+				if param.Required {
+					return nil, fmt.Errorf("%s parameter is required", param.Name)
+				}
+				continue
+			}
+			if err := utils.ValidateArgValue(param.Name+" parameter", text); err != nil {
+				return nil, err
+			}
+			value = text
+		}
+
+		if param.Flag != "" {
+			args = append(args, param.Flag)
+		}
+		args = append(args, value)
+	}
+	return args, nil
+}
@@ -0,0 +1,188 @@
+package registry
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/kagent-dev/kagent/go/tools/internal/testutil"
+	"github.com/kagent-dev/kagent/go/tools/pkg/utils"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var rolloutHistoryTool = ToolDefinition{
+	Name:        "kubectl_rollout_history",
+	Description: "Show the rollout history of a deployment",
+	Command:     "kubectl",
+	Args:        []string{"rollout", "history"},
+	Params: []ParamDefinition{
+		{Name: "deployment", Type: ParamTypeString, Description: "The deployment to show, e.g. deployment/web", Required: true},
+		{Name: "namespace", Type: ParamTypeString, Description: "The namespace of the deployment", Flag: "-n"},
+		{Name: "revision", Type: ParamTypeNumber, Description: "Show the details of a revision", Flag: "--revision"},
+		{Name: "json", Type: ParamTypeBoolean, Description: "Output as JSON (true/false)", Flag: "-ojson"},
+	},
+}
+
+func getResultText(result *mcp.CallToolResult) string {
+	if result == nil || len(result.Content) == 0 {
+		return ""
+	}
+	if textContent, ok := result.Content[0].(mcp.TextContent); ok {
+		return textContent.Text
+	}
+	return ""
+}
+
+func TestLoadConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tools.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(`
+tools:
+- name: helm_history
+  description: Show the history of a release
+  command: helm
+  args: [history]
+  params:
+  - name: release
+    type: string
+    description: The release to show
+    required: true
+`), 0o600))
+
+	config, err := LoadConfig(path)
+	require.NoError(t, err)
+	require.Len(t, config.Tools, 1)
+	assert.Equal(t, "helm", config.Tools[0].Command)
+	assert.Equal(t, []string{"history"}, config.Tools[0].Args)
+	assert.True(t, config.Tools[0].Params[0].Required)
+
+	t.Run("unknown field", func(t *testing.T) {
+		require.NoError(t, os.WriteFile(path, []byte("tools:\n- name: x\n  shell: true\n"), 0o600))
+		_, err := LoadConfig(path)
+		assert.Error(t, err)
+	})
+}
+
+func TestValidate(t *testing.T) {
+	r := NewRegistry(DefaultAllowedCommands)
+	assert.NoError(t, r.Validate(rolloutHistoryTool))
+
+	tests := []struct {
+		name   string
+		modify func(def *ToolDefinition)
+	}{
+		{"command not allowed", func(def *ToolDefinition) { def.Command = "bash" }},
+		{"command path", func(def *ToolDefinition) { def.Command = "/usr/bin/kubectl" }},
+		{"no command", func(def *ToolDefinition) { def.Command = "" }},
+		{"invalid name", func(def *ToolDefinition) { def.Name = "rollout history" }},
+		{"no description", func(def *ToolDefinition) { def.Description = "" }},
+		{"unsupported type", func(def *ToolDefinition) { def.Params[1].Type = "object" }},
+		{"boolean without flag", func(def *ToolDefinition) { def.Params[3].Flag = "" }},
+		{"duplicate parameter", func(def *ToolDefinition) { def.Params[1].Name = "deployment" }},
+		{"negative timeout", func(def *ToolDefinition) { def.TimeoutSeconds = -1 }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			def := rolloutHistoryTool
+			def.Params = append([]ParamDefinition{}, rolloutHistoryTool.Params...)
+			tt.modify(&def)
+			assert.Error(t, r.Validate(def))
+		})
+	}
+}
+
+func TestRegister(t *testing.T) {
+	tools, err := testutil.ListRegisteredTools(func(s *server.MCPServer) {
+		require.NoError(t, NewRegistry(DefaultAllowedCommands).Register(s, []ToolDefinition{rolloutHistoryTool}))
+	})
+	require.NoError(t, err)
+	require.Len(t, tools, 1)
+	assert.Equal(t, "kubectl_rollout_history", tools[0].Name)
+	assert.Empty(t, utils.ValidateToolSchema(tools[0]))
+	assert.Equal(t, []string{"deployment"}, tools[0].InputSchema.Required)
+
+	t.Run("invalid definitions register nothing", func(t *testing.T) {
+		disallowed := rolloutHistoryTool
+		disallowed.Name = "run_bash"
+		disallowed.Command = "bash"
+
+		s := server.NewMCPServer("test", "0.0.0")
+		err := NewRegistry(DefaultAllowedCommands).Register(s, []ToolDefinition{rolloutHistoryTool, disallowed})
+		assert.ErrorContains(t, err, "not an allowed command")
+
+		_, err = testutil.ListRegisteredTools(func(s *server.MCPServer) {
+			_ = NewRegistry(DefaultAllowedCommands).Register(s, []ToolDefinition{rolloutHistoryTool, disallowed})
+		})
+		assert.Error(t, err)
+	})
+
+	t.Run("duplicate tool", func(t *testing.T) {
+		s := server.NewMCPServer("test", "0.0.0")
+		err := NewRegistry(DefaultAllowedCommands).Register(s, []ToolDefinition{rolloutHistoryTool, rolloutHistoryTool})
+		assert.ErrorContains(t, err, "defined more than once")
+	})
+}
+
+func TestHandle(t *testing.T) {
+	t.Run("builds the command from the arguments", func(t *testing.T) {
+		mock := utils.NewMockShellExecutor()
+		mock.AddCommandString("kubectl", []string{"rollout", "history", "deployment/web", "-n", "prod", "--revision", "3", "-ojson"}, "revision 3", nil)
+		ctx := utils.WithShellExecutor(context.Background(), mock)
+
+		request := mcp.CallToolRequest{}
+		request.Params.Arguments = map[string]interface{}{
+			"deployment": "deployment/web",
+			"namespace":  "prod",
+			"revision":   float64(3),
+			"json":       true,
+		}
+
+		result, err := rolloutHistoryTool.handle(ctx, request)
+		require.NoError(t, err)
+		assert.False(t, result.IsError)
+		assert.Equal(t, "revision 3", getResultText(result))
+	})
+
+	t.Run("skips optional parameters", func(t *testing.T) {
+		mock := utils.NewMockShellExecutor()
+		mock.AddCommandString("kubectl", []string{"rollout", "history", "deployment/web"}, "history", nil)
+		ctx := utils.WithShellExecutor(context.Background(), mock)
+
+		request := mcp.CallToolRequest{}
+		request.Params.Arguments = map[string]interface{}{
+			"deployment": "deployment/web",
+			"json":       false,
+		}
+
+		result, err := rolloutHistoryTool.handle(ctx, request)
+		require.NoError(t, err)
+		assert.False(t, result.IsError)
+		assert.Equal(t, "history", getResultText(result))
+	})
+
+	rejected := []struct {
+		name      string
+		arguments map[string]interface{}
+	}{
+		{"missing required parameter", map[string]interface{}{"namespace": "prod"}},
+		{"flag injection", map[string]interface{}{"deployment": "deployment/web", "namespace": "--kubeconfig=/tmp/config"}},
+		{"wrong type", map[string]interface{}{"deployment": "deployment/web", "revision": "3"}},
+	}
+	for _, tt := range rejected {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := utils.NewMockShellExecutor()
+			ctx := utils.WithShellExecutor(context.Background(), mock)
+
+			request := mcp.CallToolRequest{}
+			request.Params.Arguments = tt.arguments
+
+			result, err := rolloutHistoryTool.handle(ctx, request)
+			require.NoError(t, err)
+			assert.True(t, result.IsError)
+			assert.Empty(t, mock.GetCallLog())
+		})
+	}
+}
@@ -0,0 +1,71 @@
+package utils
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// Commands are run without a shell, so user-provided values can't run other commands, but a
+// value that starts with '-' is still read as a flag by the command (e.g. a namespace of
+// `--kubeconfig=/etc/...`). Tools should validate user-provided values with these helpers
+// before adding them to the arguments of a command.
+
+// ValidateArgValue checks that the value of the named parameter can't be read as a flag and
+// doesn't contain control characters
+func ValidateArgValue(name, value string) error {
+	if strings.HasPrefix(value, "-") {
+		return fmt.Errorf("%s must not start with '-'", name)
+	}
+	if strings.IndexFunc(value, unicode.IsControl) >= 0 {
+		return fmt.Errorf("%s must not contain control characters", name)
+	}
+	return nil
+}
+
+// ValidateNamespace checks that namespace is a valid Kubernetes namespace name
+func ValidateNamespace(namespace string) error {
+	if errs := validation.IsDNS1123Label(namespace); len(errs) > 0 {
+		return fmt.Errorf("invalid namespace %q: %s", namespace, strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// ParseNamespace reads the namespace parameter of the request, defaultValue if it isn't set,
+// and validates it with ValidateNamespace. An empty namespace isn't validated, as tools use it
+// for all namespaces or the current one.
+func ParseNamespace(request mcp.CallToolRequest, defaultValue string) (string, error) {
+	namespace := mcp.ParseString(request, "namespace", defaultValue)
+	if namespace == "" {
+		return "", nil
+	}
+	if err := ValidateNamespace(namespace); err != nil {
+		return "", err
+	}
+	return namespace, nil
+}
+
+// ValidateAllowed checks that every value is in the allow-list, and lists the allowed values
+// if not
+func ValidateAllowed(kind string, values []string, allowed map[string]bool) error {
+	var unknown []string
+	for _, value := range values {
+		if !allowed[value] {
+			unknown = append(unknown, value)
+		}
+	}
+	if len(unknown) == 0 {
+		return nil
+	}
+
+	known := make([]string, 0, len(allowed))
+	for value := range allowed {
+		known = append(known, value)
+	}
+	sort.Strings(known)
+	return fmt.Errorf("unknown %s(s): %s. Known %ss: %s", kind, strings.Join(unknown, ", "), kind, strings.Join(known, ", "))
+}
@@ -0,0 +1,73 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateArgValue(t *testing.T) {
+	for _, value := range []string{"web", "deployment/web", "app=web,tier!=db", "a-b", ""} {
+		assert.NoError(t, ValidateArgValue("value", value), value)
+	}
+
+	for _, value := range []string{"--kubeconfig=/etc/kubernetes/admin.conf", "-n", "-", "web\n--all", "web\x00"} {
+		assert.Error(t, ValidateArgValue("value", value), value)
+	}
+}
+
+func TestValidateNamespace(t *testing.T) {
+	for _, namespace := range []string{"default", "kube-system", "team-1"} {
+		assert.NoError(t, ValidateNamespace(namespace), namespace)
+	}
+
+	for _, namespace := range []string{
+		"--kubeconfig=/etc/kubernetes/admin.conf",
+		"-A",
+		"default --all-namespaces",
+		"default;rm -rf /",
+		"../etc",
+		"Default",
+		"",
+	} {
+		assert.Error(t, ValidateNamespace(namespace), namespace)
+	}
+}
+
+func TestParseNamespace(t *testing.T) {
+	request := func(namespace interface{}) mcp.CallToolRequest {
+		arguments := map[string]interface{}{}
+		if namespace != nil {
+			arguments["namespace"] = namespace
+		}
+		request := mcp.CallToolRequest{}
+		request.Params.Arguments = arguments
+		return request
+	}
+
+	namespace, err := ParseNamespace(request(nil), "default")
+	assert.NoError(t, err)
+	assert.Equal(t, "default", namespace)
+
+	namespace, err = ParseNamespace(request("kube-system"), "default")
+	assert.NoError(t, err)
+	assert.Equal(t, "kube-system", namespace)
+
+	namespace, err = ParseNamespace(request(nil), "")
+	assert.NoError(t, err)
+	assert.Empty(t, namespace)
+
+	_, err = ParseNamespace(request("-A"), "")
+	assert.Error(t, err)
+}
+
+func TestValidateAllowed(t *testing.T) {
+	allowed := map[string]bool{"Pod": true, "Service": true}
+
+	assert.NoError(t, ValidateAllowed("analyzer", []string{"Pod", "Service"}, allowed))
+	assert.NoError(t, ValidateAllowed("analyzer", nil, allowed))
+
+	err := ValidateAllowed("analyzer", []string{"Pod", "--explain", "Bogus"}, allowed)
+	assert.EqualError(t, err, "unknown analyzer(s): --explain, Bogus. Known analyzers: Pod, Service")
+}
@@ -1,6 +1,7 @@
 package utils
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"os/exec"
@@ -28,7 +29,34 @@ type DefaultShellExecutor struct{}
 // Exec executes a command using os/exec.CommandContext
 func (e *DefaultShellExecutor) Exec(ctx context.Context, command string, args ...string) ([]byte, error) {
 	cmd := exec.CommandContext(ctx, command, args...)
-	return cmd.CombinedOutput()
+	limit := GetMaxOutputBytes(ctx)
+	if limit <= 0 {
+		return cmd.CombinedOutput()
+	}
+
+	// Keep one byte past the limit so callers can tell the output was truncated
+	buf := &limitedBuffer{max: limit + 1}
+	cmd.Stdout = buf
+	cmd.Stderr = buf
+	err := cmd.Run()
+	return buf.buf.Bytes(), err
+}
+
+// limitedBuffer is an io.Writer that silently discards anything written past max bytes
+type limitedBuffer struct {
+	buf bytes.Buffer
+	max int
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	if remaining := b.max - b.buf.Len(); remaining > 0 {
+		if len(p) > remaining {
+			b.buf.Write(p[:remaining])
+		} else {
+			b.buf.Write(p)
+		}
+	}
+	return len(p), nil
 }
 
 // MockShellExecutor implements ShellExecutor for testing
@@ -166,6 +194,24 @@ type contextKey string
 
 const shellExecutorKey contextKey = "shellExecutor"
 
+const maxOutputBytesKey contextKey = "maxOutputBytes"
+
+// OutputTruncatedMarker is appended to command output that exceeded the configured byte limit
+const OutputTruncatedMarker = "\n... [output truncated]"
+
+// WithMaxOutputBytes returns a context that caps the command output captured by RunCommandWithContext
+func WithMaxOutputBytes(ctx context.Context, maxBytes int) context.Context {
+	return context.WithValue(ctx, maxOutputBytesKey, maxBytes)
+}
+
+// GetMaxOutputBytes retrieves the output byte limit from context, or 0 if unlimited
+func GetMaxOutputBytes(ctx context.Context) int {
+	if maxBytes, ok := ctx.Value(maxOutputBytesKey).(int); ok {
+		return maxBytes
+	}
+	return 0
+}
+
 // WithShellExecutor returns a context with the given shell executor
 func WithShellExecutor(ctx context.Context, executor ShellExecutor) context.Context {
 	return context.WithValue(ctx, shellExecutorKey, executor)
@@ -310,6 +356,10 @@ func RunCommandWithContext(ctx context.Context, command string, args []string) (
 		"caller", caller,
 	)
 
+	if limit := GetMaxOutputBytes(ctx); limit > 0 && len(output) > limit {
+		return strings.TrimSpace(string(output[:limit])) + OutputTruncatedMarker, nil
+	}
+
 	return strings.TrimSpace(string(output)), nil
 }
 
@@ -21,6 +21,26 @@ func TestDefaultShellExecutor(t *testing.T) {
 	output, err = executor.Exec(context.Background(), "nonexistent-command")
 	assert.Error(t, err)
 	assert.Empty(t, output)
+
+	// Test output limit
+	ctx := WithMaxOutputBytes(context.Background(), 3)
+	output, err = executor.Exec(ctx, "echo", "hello")
+	assert.NoError(t, err)
+	assert.Equal(t, "hell", string(output))
+}
+
+func TestRunCommandWithOutputLimit(t *testing.T) {
+	mock := NewMockShellExecutor()
+	mock.AddCommandString("echo", []string{"hello world"}, "hello world", nil)
+	ctx := WithShellExecutor(context.Background(), mock)
+
+	output, err := RunCommandWithContext(WithMaxOutputBytes(ctx, 5), "echo", []string{"hello world"})
+	assert.NoError(t, err)
+	assert.Equal(t, "hello"+OutputTruncatedMarker, output)
+
+	output, err = RunCommandWithContext(WithMaxOutputBytes(ctx, 100), "echo", []string{"hello world"})
+	assert.NoError(t, err)
+	assert.Equal(t, "hello world", output)
 }
 
 func TestMockShellExecutor(t *testing.T) {
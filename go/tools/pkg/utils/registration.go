@@ -0,0 +1,49 @@
+package utils
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+var toolNamePattern = regexp.MustCompile(`^[a-zA-Z0-9_-]+$`)
+
+// ValidateToolSchema checks that a tool definition is well-formed: it has a
+// valid name and a description, every parameter is described, and every
+// required parameter is declared
+func ValidateToolSchema(tool mcp.Tool) []error {
+	var errs []error
+
+	if !toolNamePattern.MatchString(tool.Name) {
+		errs = append(errs, fmt.Errorf("tool name %q must match %s", tool.Name, toolNamePattern.String()))
+	}
+	if tool.Description == "" {
+		errs = append(errs, fmt.Errorf("tool %q has no description", tool.Name))
+	}
+	if tool.InputSchema.Type != "object" {
+		errs = append(errs, fmt.Errorf("tool %q input schema type is %q, expected object", tool.Name, tool.InputSchema.Type))
+	}
+
+	for name, prop := range tool.InputSchema.Properties {
+		propMap, ok := prop.(map[string]any)
+		if !ok {
+			errs = append(errs, fmt.Errorf("tool %q parameter %q has an invalid schema", tool.Name, name))
+			continue
+		}
+		if description, _ := propMap["description"].(string); description == "" {
+			errs = append(errs, fmt.Errorf("tool %q parameter %q has no description", tool.Name, name))
+		}
+		if _, ok := propMap["type"].(string); !ok {
+			errs = append(errs, fmt.Errorf("tool %q parameter %q has no type", tool.Name, name))
+		}
+	}
+
+	for _, required := range tool.InputSchema.Required {
+		if _, ok := tool.InputSchema.Properties[required]; !ok {
+			errs = append(errs, fmt.Errorf("tool %q requires undeclared parameter %q", tool.Name, required))
+		}
+	}
+
+	return errs
+}
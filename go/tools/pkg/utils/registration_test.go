@@ -0,0 +1,62 @@
+package utils
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kagent-dev/kagent/go/tools/internal/testutil"
+)
+
+func noopHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return mcp.NewToolResultText(""), nil
+}
+
+func TestListRegisteredTools(t *testing.T) {
+	tools, err := testutil.ListRegisteredTools(func(s *server.MCPServer) {
+		RegisterDateTimeTools(s)
+		RegisterCommonTools(s)
+	})
+	require.NoError(t, err)
+	require.NotEmpty(t, tools)
+
+	var names []string
+	for _, tool := range tools {
+		names = append(names, tool.Name)
+		assert.Empty(t, ValidateToolSchema(tool), "tool %s", tool.Name)
+	}
+	assert.Contains(t, names, "shell")
+	assert.IsIncreasing(t, names)
+}
+
+func TestValidateToolSchema(t *testing.T) {
+	tools, err := testutil.ListRegisteredTools(func(s *server.MCPServer) {
+		s.AddTool(mcp.NewTool("bad tool",
+			mcp.WithString("undescribed"),
+		), noopHandler)
+		s.AddTool(mcp.Tool{
+			Name:        "missing_required",
+			Description: "Declares a required parameter it does not define",
+			InputSchema: mcp.ToolInputSchema{
+				Type:     "object",
+				Required: []string{"ghost"},
+			},
+		}, noopHandler)
+	})
+	require.NoError(t, err)
+	require.Len(t, tools, 2)
+
+	errs := ValidateToolSchema(tools[0])
+	require.Len(t, errs, 3)
+	assert.Contains(t, errs[0].Error(), "tool name")
+	assert.Contains(t, errs[1].Error(), "no description")
+	assert.Contains(t, errs[2].Error(), `parameter "undescribed" has no description`)
+
+	errs = ValidateToolSchema(tools[1])
+	require.Len(t, errs, 1)
+	assert.Contains(t, errs[0].Error(), `undeclared parameter "ghost"`)
+}
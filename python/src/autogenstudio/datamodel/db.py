@@ -119,6 +119,10 @@ class Run(BaseDBModel, table=True):
     team_result: Union[TeamResult, dict] = Field(default=None, sa_column=Column(JSON))
 
     error_message: Optional[str] = None
+    # The run this run re-runs, if any. The parent run is left unchanged.
+    parent_run_id: Optional[int] = Field(
+        default=None, sa_column=Column(Integer, ForeignKey("run.id", ondelete="SET NULL"), nullable=True)
+    )
     version: Optional[str] = "0.0.1"
     messages: Union[List[Message], List[dict]] = Field(default_factory=list, sa_column=Column(JSON))
 
@@ -107,6 +107,13 @@ api.include_router(
     responses={404: {"description": "Not found"}},
 )
 
+api.include_router(
+    runs.internal_router,
+    prefix="/internal/runs",
+    tags=["internal"],
+    responses={404: {"description": "Not found"}},
+)
+
 api.include_router(
     teams.router,
     prefix="/teams",
@@ -1,5 +1,5 @@
 # /api/runs routes
-from typing import Dict
+from typing import Dict, Optional
 
 from fastapi import APIRouter, Depends, HTTPException
 from pydantic import BaseModel
@@ -9,6 +9,10 @@ from ..deps import get_db
 
 router = APIRouter()
 
+# Routes of the runs of all users, for the controller only. They are mounted under /internal/runs,
+# which the UI proxy doesn't expose.
+internal_router = APIRouter()
+
 
 class CreateRunRequest(BaseModel):
     session_id: int
@@ -44,6 +48,29 @@ async def create_run(
         raise HTTPException(status_code=500, detail=str(e)) from e
 
 
+@router.get("/")
+async def list_runs(user_id: str, status: Optional[RunStatus] = None, db=Depends(get_db)) -> Dict:
+    """List the runs of a user, optionally only those with the given status"""
+    filters = {"user_id": user_id}
+    if status:
+        filters["status"] = status
+    response = db.get(Run, filters=filters, return_json=False)
+    if not response.status:
+        raise HTTPException(status_code=500, detail=response.message)
+    return {"status": True, "data": response.data}
+
+
+@internal_router.get("/")
+async def list_all_runs(status: Optional[RunStatus] = None, db=Depends(get_db)) -> Dict:
+    """List the runs of all users, optionally only those with the given status, e.g. to find the
+    runs left active by a restart"""
+    filters = {"status": status} if status else None
+    response = db.get(Run, filters=filters, return_json=False)
+    if not response.status:
+        raise HTTPException(status_code=500, detail=response.message)
+    return {"status": True, "data": response.data}
+
+
 # We might want to add these endpoints:
 
 
@@ -65,6 +92,29 @@ async def get_run_messages(run_id: int, db=Depends(get_db)) -> Dict:
     return {"status": True, "data": messages.data}
 
 
+class UpdateRunStatusRequest(BaseModel):
+    status: RunStatus
+    error_message: Optional[str] = None
+
+
+@internal_router.put("/{run_id}/status")
+async def update_run_status(run_id: int, request: UpdateRunStatusRequest, db=Depends(get_db)) -> Dict:
+    """Update the status of a run, e.g. to fail a run interrupted by a restart"""
+    run_response = db.get(Run, filters={"id": run_id}, return_json=False)
+    if not run_response.status or not run_response.data:
+        raise HTTPException(status_code=404, detail="Run not found")
+
+    run = run_response.data[0]
+    run.status = request.status
+    if request.error_message is not None:
+        run.error_message = request.error_message
+
+    response = db.upsert(run, return_json=False)
+    if not response.status:
+        raise HTTPException(status_code=400, detail=response.message)
+    return {"status": True, "data": {"run_id": run_id, "status": run.status}}
+
+
 @router.delete("/{run_id}")
 async def delete_run(run_id: int, db=Depends(get_db)) -> Dict:
     """Delete a run"""
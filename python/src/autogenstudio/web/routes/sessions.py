@@ -1,6 +1,6 @@
 # api/routes/sessions.py
 import json
-from typing import Dict, Union
+from typing import Dict, Optional, Union
 
 from autogen_core import ComponentModel
 from fastapi import APIRouter, Depends, HTTPException
@@ -54,9 +54,10 @@ async def update_session(session_id: int, user_id: str, session: Session, db=Dep
     if not existing_response.status or not existing_response.data:
         raise HTTPException(status_code=404, detail="Session not found")
 
-    # Get the existing session
+    # Get the existing session and replace its mutable fields, the id and user stay as they are
     existing_session = existing_response.data[0]
     existing_session.name = session.name
+    existing_session.team_id = session.team_id
 
     try:
         response = db.upsert(existing_session)
@@ -65,7 +66,7 @@ async def update_session(session_id: int, user_id: str, session: Session, db=Dep
 
         return {"status": True, "data": response.data, "message": "Session updated successfully"}
     except Exception as e:
-        logger.error(f"Error updating session name: {str(e)}")
+        logger.error(f"Error updating session: {str(e)}")
         raise HTTPException(status_code=400, detail=f"Invalid session data: {str(e)}") from e
 
 
@@ -112,6 +113,8 @@ async def list_session_runs(session_id: int, user_id: str, db: DatabaseManager =
                             "status": run.status,
                             "task": run.task,
                             "team_result": run.team_result,
+                            "error_message": run.error_message,
+                            "parent_run_id": run.parent_run_id,
                             "messages": messages.data or [],
                         }
                     )
@@ -163,11 +166,14 @@ async def invoke(
         raise HTTPException(status_code=500, detail=f"Internal server error while invoking run: {str(e)}") from e
 
 
-def _create_run(session_id: int, user_id: str, db: DatabaseManager, task: str) -> Run:
+def _create_run(
+    session_id: int, user_id: str, db: DatabaseManager, task: str, parent_run_id: Optional[int] = None
+) -> Run:
     run = Run(
         session_id=session_id,
         user_id=user_id,
         status=RunStatus.CREATED,
+        parent_run_id=parent_run_id,
         task=MessageConfig(
             content=task,
             source="user",
@@ -180,6 +186,48 @@ def _create_run(session_id: int, user_id: str, db: DatabaseManager, task: str) -
     return response.data
 
 
+class RerunRequest(BaseModel):
+    task: Optional[str] = None
+    team_config: Union[ComponentModel, dict]
+
+
+@router.post("/{session_id}/runs/{run_id}/rerun")
+async def rerun(
+    session_id: int,
+    run_id: int,
+    user_id: str,
+    request: RerunRequest,
+    db: DatabaseManager = Depends(get_db),
+    session_mgr: SessionManager = Depends(get_session_manager),
+) -> Response:
+    """Run the task of a run again in a new run, optionally with an edited task"""
+    session = db.get(Session, filters={"id": session_id, "user_id": user_id}, return_json=False)
+    if not session.status or not session.data:
+        raise HTTPException(status_code=404, detail="Session not found")
+    parent = db.get(Run, filters={"id": run_id, "session_id": session_id}, return_json=False)
+    if not parent.status or not parent.data:
+        raise HTTPException(status_code=404, detail="Run not found")
+
+    task = request.task
+    if not task:
+        parent_task = parent.data[0].task
+        task = parent_task.get("content") if isinstance(parent_task, dict) else parent_task.content
+    if not isinstance(task, str) or not task:
+        raise HTTPException(status_code=400, detail="Run has no text task to re-run")
+
+    run = _create_run(session_id, user_id, db, task, parent_run_id=run_id)
+    try:
+        await session_mgr.start(user_id, run.id, task, request.team_config)
+    except Exception as e:
+        # The failure is recorded on the new run, which is returned like a completed one
+        logger.error(f"Error re-running run {run_id}: {str(e)}")
+
+    rerun_response = db.get(Run, filters={"id": run.id}, return_json=True)
+    if not rerun_response.status or not rerun_response.data:
+        raise HTTPException(status_code=500, detail="Failed to get the new run")
+    return Response(status=True, data=rerun_response.data[0], message="Run re-run successfully")
+
+
 @router.post("/{session_id}/invoke/stream")
 async def stream(
     session_id: int,
@@ -36,11 +36,20 @@ export interface TokenStats {
   output: number;
 }
 
+export interface ProviderParam {
+  name: string;
+  type: "string" | "integer" | "number" | "boolean" | "array" | "object";
+  required: boolean;
+  secret: boolean;
+  description?: string;
+}
+
 export interface Provider {
   name: string;
   type: string;
   requiredParams: string[];
   optionalParams: string[];
+  params?: ProviderParam[];
 }
 
 // Export OpenAIConfigPayload